@@ -167,6 +167,94 @@ func (mr *MockACMAPIMockRecorder) DescribeCertificateRequest(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCertificateRequest", reflect.TypeOf((*MockACMAPI)(nil).DescribeCertificateRequest), arg0)
 }
 
+// ExportCertificate mocks base method
+func (m *MockACMAPI) ExportCertificate(arg0 *acm.ExportCertificateInput) (*acm.ExportCertificateOutput, error) {
+	ret := m.ctrl.Call(m, "ExportCertificate", arg0)
+	ret0, _ := ret[0].(*acm.ExportCertificateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportCertificate indicates an expected call of ExportCertificate
+func (mr *MockACMAPIMockRecorder) ExportCertificate(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCertificate", reflect.TypeOf((*MockACMAPI)(nil).ExportCertificate), arg0)
+}
+
+// ExportCertificateWithContext mocks base method
+func (m *MockACMAPI) ExportCertificateWithContext(arg0 aws.Context, arg1 *acm.ExportCertificateInput, arg2 ...request.Option) (*acm.ExportCertificateOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExportCertificateWithContext", varargs...)
+	ret0, _ := ret[0].(*acm.ExportCertificateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportCertificateWithContext indicates an expected call of ExportCertificateWithContext
+func (mr *MockACMAPIMockRecorder) ExportCertificateWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCertificateWithContext", reflect.TypeOf((*MockACMAPI)(nil).ExportCertificateWithContext), varargs...)
+}
+
+// ExportCertificateRequest mocks base method
+func (m *MockACMAPI) ExportCertificateRequest(arg0 *acm.ExportCertificateInput) (*request.Request, *acm.ExportCertificateOutput) {
+	ret := m.ctrl.Call(m, "ExportCertificateRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*acm.ExportCertificateOutput)
+	return ret0, ret1
+}
+
+// ExportCertificateRequest indicates an expected call of ExportCertificateRequest
+func (mr *MockACMAPIMockRecorder) ExportCertificateRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCertificateRequest", reflect.TypeOf((*MockACMAPI)(nil).ExportCertificateRequest), arg0)
+}
+
+// GetAccountConfiguration mocks base method
+func (m *MockACMAPI) GetAccountConfiguration(arg0 *acm.GetAccountConfigurationInput) (*acm.GetAccountConfigurationOutput, error) {
+	ret := m.ctrl.Call(m, "GetAccountConfiguration", arg0)
+	ret0, _ := ret[0].(*acm.GetAccountConfigurationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountConfiguration indicates an expected call of GetAccountConfiguration
+func (mr *MockACMAPIMockRecorder) GetAccountConfiguration(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountConfiguration", reflect.TypeOf((*MockACMAPI)(nil).GetAccountConfiguration), arg0)
+}
+
+// GetAccountConfigurationWithContext mocks base method
+func (m *MockACMAPI) GetAccountConfigurationWithContext(arg0 aws.Context, arg1 *acm.GetAccountConfigurationInput, arg2 ...request.Option) (*acm.GetAccountConfigurationOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAccountConfigurationWithContext", varargs...)
+	ret0, _ := ret[0].(*acm.GetAccountConfigurationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountConfigurationWithContext indicates an expected call of GetAccountConfigurationWithContext
+func (mr *MockACMAPIMockRecorder) GetAccountConfigurationWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountConfigurationWithContext", reflect.TypeOf((*MockACMAPI)(nil).GetAccountConfigurationWithContext), varargs...)
+}
+
+// GetAccountConfigurationRequest mocks base method
+func (m *MockACMAPI) GetAccountConfigurationRequest(arg0 *acm.GetAccountConfigurationInput) (*request.Request, *acm.GetAccountConfigurationOutput) {
+	ret := m.ctrl.Call(m, "GetAccountConfigurationRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*acm.GetAccountConfigurationOutput)
+	return ret0, ret1
+}
+
+// GetAccountConfigurationRequest indicates an expected call of GetAccountConfigurationRequest
+func (mr *MockACMAPIMockRecorder) GetAccountConfigurationRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountConfigurationRequest", reflect.TypeOf((*MockACMAPI)(nil).GetAccountConfigurationRequest), arg0)
+}
+
 // GetCertificate mocks base method
 func (m *MockACMAPI) GetCertificate(arg0 *acm.GetCertificateInput) (*acm.GetCertificateOutput, error) {
 	ret := m.ctrl.Call(m, "GetCertificate", arg0)
@@ -372,6 +460,50 @@ func (mr *MockACMAPIMockRecorder) ListTagsForCertificateRequest(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsForCertificateRequest", reflect.TypeOf((*MockACMAPI)(nil).ListTagsForCertificateRequest), arg0)
 }
 
+// PutAccountConfiguration mocks base method
+func (m *MockACMAPI) PutAccountConfiguration(arg0 *acm.PutAccountConfigurationInput) (*acm.PutAccountConfigurationOutput, error) {
+	ret := m.ctrl.Call(m, "PutAccountConfiguration", arg0)
+	ret0, _ := ret[0].(*acm.PutAccountConfigurationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutAccountConfiguration indicates an expected call of PutAccountConfiguration
+func (mr *MockACMAPIMockRecorder) PutAccountConfiguration(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAccountConfiguration", reflect.TypeOf((*MockACMAPI)(nil).PutAccountConfiguration), arg0)
+}
+
+// PutAccountConfigurationWithContext mocks base method
+func (m *MockACMAPI) PutAccountConfigurationWithContext(arg0 aws.Context, arg1 *acm.PutAccountConfigurationInput, arg2 ...request.Option) (*acm.PutAccountConfigurationOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutAccountConfigurationWithContext", varargs...)
+	ret0, _ := ret[0].(*acm.PutAccountConfigurationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutAccountConfigurationWithContext indicates an expected call of PutAccountConfigurationWithContext
+func (mr *MockACMAPIMockRecorder) PutAccountConfigurationWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAccountConfigurationWithContext", reflect.TypeOf((*MockACMAPI)(nil).PutAccountConfigurationWithContext), varargs...)
+}
+
+// PutAccountConfigurationRequest mocks base method
+func (m *MockACMAPI) PutAccountConfigurationRequest(arg0 *acm.PutAccountConfigurationInput) (*request.Request, *acm.PutAccountConfigurationOutput) {
+	ret := m.ctrl.Call(m, "PutAccountConfigurationRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*acm.PutAccountConfigurationOutput)
+	return ret0, ret1
+}
+
+// PutAccountConfigurationRequest indicates an expected call of PutAccountConfigurationRequest
+func (mr *MockACMAPIMockRecorder) PutAccountConfigurationRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAccountConfigurationRequest", reflect.TypeOf((*MockACMAPI)(nil).PutAccountConfigurationRequest), arg0)
+}
+
 // RemoveTagsFromCertificate mocks base method
 func (m *MockACMAPI) RemoveTagsFromCertificate(arg0 *acm.RemoveTagsFromCertificateInput) (*acm.RemoveTagsFromCertificateOutput, error) {
 	ret := m.ctrl.Call(m, "RemoveTagsFromCertificate", arg0)
@@ -416,6 +548,50 @@ func (mr *MockACMAPIMockRecorder) RemoveTagsFromCertificateRequest(arg0 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTagsFromCertificateRequest", reflect.TypeOf((*MockACMAPI)(nil).RemoveTagsFromCertificateRequest), arg0)
 }
 
+// RenewCertificate mocks base method
+func (m *MockACMAPI) RenewCertificate(arg0 *acm.RenewCertificateInput) (*acm.RenewCertificateOutput, error) {
+	ret := m.ctrl.Call(m, "RenewCertificate", arg0)
+	ret0, _ := ret[0].(*acm.RenewCertificateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewCertificate indicates an expected call of RenewCertificate
+func (mr *MockACMAPIMockRecorder) RenewCertificate(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewCertificate", reflect.TypeOf((*MockACMAPI)(nil).RenewCertificate), arg0)
+}
+
+// RenewCertificateWithContext mocks base method
+func (m *MockACMAPI) RenewCertificateWithContext(arg0 aws.Context, arg1 *acm.RenewCertificateInput, arg2 ...request.Option) (*acm.RenewCertificateOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RenewCertificateWithContext", varargs...)
+	ret0, _ := ret[0].(*acm.RenewCertificateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewCertificateWithContext indicates an expected call of RenewCertificateWithContext
+func (mr *MockACMAPIMockRecorder) RenewCertificateWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewCertificateWithContext", reflect.TypeOf((*MockACMAPI)(nil).RenewCertificateWithContext), varargs...)
+}
+
+// RenewCertificateRequest mocks base method
+func (m *MockACMAPI) RenewCertificateRequest(arg0 *acm.RenewCertificateInput) (*request.Request, *acm.RenewCertificateOutput) {
+	ret := m.ctrl.Call(m, "RenewCertificateRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*acm.RenewCertificateOutput)
+	return ret0, ret1
+}
+
+// RenewCertificateRequest indicates an expected call of RenewCertificateRequest
+func (mr *MockACMAPIMockRecorder) RenewCertificateRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewCertificateRequest", reflect.TypeOf((*MockACMAPI)(nil).RenewCertificateRequest), arg0)
+}
+
 // RequestCertificate mocks base method
 func (m *MockACMAPI) RequestCertificate(arg0 *acm.RequestCertificateInput) (*acm.RequestCertificateOutput, error) {
 	ret := m.ctrl.Call(m, "RequestCertificate", arg0)
@@ -503,3 +679,76 @@ func (m *MockACMAPI) ResendValidationEmailRequest(arg0 *acm.ResendValidationEmai
 func (mr *MockACMAPIMockRecorder) ResendValidationEmailRequest(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendValidationEmailRequest", reflect.TypeOf((*MockACMAPI)(nil).ResendValidationEmailRequest), arg0)
 }
+
+// UpdateCertificateOptions mocks base method
+func (m *MockACMAPI) UpdateCertificateOptions(arg0 *acm.UpdateCertificateOptionsInput) (*acm.UpdateCertificateOptionsOutput, error) {
+	ret := m.ctrl.Call(m, "UpdateCertificateOptions", arg0)
+	ret0, _ := ret[0].(*acm.UpdateCertificateOptionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCertificateOptions indicates an expected call of UpdateCertificateOptions
+func (mr *MockACMAPIMockRecorder) UpdateCertificateOptions(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCertificateOptions", reflect.TypeOf((*MockACMAPI)(nil).UpdateCertificateOptions), arg0)
+}
+
+// UpdateCertificateOptionsWithContext mocks base method
+func (m *MockACMAPI) UpdateCertificateOptionsWithContext(arg0 aws.Context, arg1 *acm.UpdateCertificateOptionsInput, arg2 ...request.Option) (*acm.UpdateCertificateOptionsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateCertificateOptionsWithContext", varargs...)
+	ret0, _ := ret[0].(*acm.UpdateCertificateOptionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCertificateOptionsWithContext indicates an expected call of UpdateCertificateOptionsWithContext
+func (mr *MockACMAPIMockRecorder) UpdateCertificateOptionsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCertificateOptionsWithContext", reflect.TypeOf((*MockACMAPI)(nil).UpdateCertificateOptionsWithContext), varargs...)
+}
+
+// UpdateCertificateOptionsRequest mocks base method
+func (m *MockACMAPI) UpdateCertificateOptionsRequest(arg0 *acm.UpdateCertificateOptionsInput) (*request.Request, *acm.UpdateCertificateOptionsOutput) {
+	ret := m.ctrl.Call(m, "UpdateCertificateOptionsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*acm.UpdateCertificateOptionsOutput)
+	return ret0, ret1
+}
+
+// UpdateCertificateOptionsRequest indicates an expected call of UpdateCertificateOptionsRequest
+func (mr *MockACMAPIMockRecorder) UpdateCertificateOptionsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCertificateOptionsRequest", reflect.TypeOf((*MockACMAPI)(nil).UpdateCertificateOptionsRequest), arg0)
+}
+
+// WaitUntilCertificateValidated mocks base method
+func (m *MockACMAPI) WaitUntilCertificateValidated(arg0 *acm.DescribeCertificateInput) error {
+	ret := m.ctrl.Call(m, "WaitUntilCertificateValidated", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitUntilCertificateValidated indicates an expected call of WaitUntilCertificateValidated
+func (mr *MockACMAPIMockRecorder) WaitUntilCertificateValidated(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitUntilCertificateValidated", reflect.TypeOf((*MockACMAPI)(nil).WaitUntilCertificateValidated), arg0)
+}
+
+// WaitUntilCertificateValidatedWithContext mocks base method
+func (m *MockACMAPI) WaitUntilCertificateValidatedWithContext(arg0 aws.Context, arg1 *acm.DescribeCertificateInput, arg2 ...request.WaiterOption) error {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WaitUntilCertificateValidatedWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitUntilCertificateValidatedWithContext indicates an expected call of WaitUntilCertificateValidatedWithContext
+func (mr *MockACMAPIMockRecorder) WaitUntilCertificateValidatedWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitUntilCertificateValidatedWithContext", reflect.TypeOf((*MockACMAPI)(nil).WaitUntilCertificateValidatedWithContext), varargs...)
+}
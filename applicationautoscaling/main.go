@@ -0,0 +1,16 @@
+package applicationautoscaling
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/applicationautoscaling"
+)
+
+type ApplicationAutoScaling struct {
+	svc *applicationautoscaling.ApplicationAutoScaling
+}
+
+func New(sess *session.Session) ApplicationAutoScaling {
+	return ApplicationAutoScaling{
+		svc: applicationautoscaling.New(sess),
+	}
+}
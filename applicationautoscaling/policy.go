@@ -0,0 +1,177 @@
+package applicationautoscaling
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsapplicationautoscaling "github.com/aws/aws-sdk-go/service/applicationautoscaling"
+)
+
+const (
+	MetricCpu      = "cpu"
+	MetricMemory   = "memory"
+	MetricRequests = "requests"
+
+	policyNameFormat = "fargate-%s"
+)
+
+var predefinedMetricTypes = map[string]string{
+	MetricCpu:      awsapplicationautoscaling.MetricTypeEcsserviceAverageCpuutilization,
+	MetricMemory:   awsapplicationautoscaling.MetricTypeEcsserviceAverageMemoryUtilization,
+	MetricRequests: awsapplicationautoscaling.MetricTypeAlbrequestCountPerTarget,
+}
+
+// TargetTrackingPolicy scales a service to keep a metric at the target value:
+// average CPU or memory utilization as a percentage, or the number of load
+// balancer requests per task each minute. Requests require the resource label
+// of the service's target group.
+type TargetTrackingPolicy struct {
+	Metric        string
+	ResourceLabel string
+	Target        float64
+}
+
+// ScalingPolicy is a target tracking policy attached to a service. Policies
+// created outside of fargate may use a metric it doesn't know, in which case
+// Metric is the predefined metric type or, for custom metrics, empty.
+type ScalingPolicy struct {
+	Metric string
+	Name   string
+	Target float64
+}
+
+// ValidateMetric returns an error unless the metric is cpu, memory, or
+// requests.
+func ValidateMetric(metric string) error {
+	if _, ok := predefinedMetricTypes[metric]; !ok {
+		return fmt.Errorf("invalid metric %s: must be %s, %s, or %s", metric, MetricCpu, MetricMemory, MetricRequests)
+	}
+
+	return nil
+}
+
+// PolicyName returns the name of the scaling policy fargate creates for the
+// metric [e.g. fargate-cpu].
+func PolicyName(metric string) string {
+	return fmt.Sprintf(policyNameFormat, metric)
+}
+
+// RequestCountResourceLabel returns the label identifying a target group
+// behind an Application Load Balancer, which request count policies track
+// [e.g. app/web/1234567890abcdef/targetgroup/fargate-web/fedcba0987654321].
+func RequestCountResourceLabel(loadBalancerArn, targetGroupArn string) string {
+	loadBalancer := loadBalancerArn[strings.Index(loadBalancerArn, ":loadbalancer/")+len(":loadbalancer/"):]
+	targetGroup := targetGroupArn[strings.Index(targetGroupArn, ":targetgroup/")+1:]
+
+	return fmt.Sprintf("%s/%s", loadBalancer, targetGroup)
+}
+
+// PutServiceTargetTrackingPolicy creates or replaces the service's policy for
+// the metric. The service must be registered as a scalable target.
+func (aas *ApplicationAutoScaling) PutServiceTargetTrackingPolicy(clusterName, serviceName string, policy TargetTrackingPolicy) error {
+	if err := ValidateMetric(policy.Metric); err != nil {
+		return err
+	}
+
+	if policy.Target <= 0 {
+		return fmt.Errorf("invalid target %g: must be > 0", policy.Target)
+	}
+
+	metricSpecification := &awsapplicationautoscaling.PredefinedMetricSpecification{
+		PredefinedMetricType: aws.String(predefinedMetricTypes[policy.Metric]),
+	}
+
+	if policy.Metric == MetricRequests {
+		if policy.ResourceLabel == "" {
+			return fmt.Errorf("%s policies require a load balancer", MetricRequests)
+		}
+
+		metricSpecification.ResourceLabel = aws.String(policy.ResourceLabel)
+	}
+
+	_, err := aas.svc.PutScalingPolicy(
+		&awsapplicationautoscaling.PutScalingPolicyInput{
+			PolicyName:        aws.String(PolicyName(policy.Metric)),
+			PolicyType:        aws.String(awsapplicationautoscaling.PolicyTypeTargetTrackingScaling),
+			ResourceId:        aws.String(ServiceResourceId(clusterName, serviceName)),
+			ScalableDimension: aws.String(awsapplicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+			ServiceNamespace:  aws.String(awsapplicationautoscaling.ServiceNamespaceEcs),
+			TargetTrackingScalingPolicyConfiguration: &awsapplicationautoscaling.TargetTrackingScalingPolicyConfiguration{
+				PredefinedMetricSpecification: metricSpecification,
+				TargetValue:                   aws.Float64(policy.Target),
+			},
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not put scaling policy %s: %v", PolicyName(policy.Metric), err)
+	}
+
+	return nil
+}
+
+// DescribeServicePolicies returns the service's target tracking policies.
+func (aas *ApplicationAutoScaling) DescribeServicePolicies(clusterName, serviceName string) ([]ScalingPolicy, error) {
+	var policies []ScalingPolicy
+
+	err := aas.svc.DescribeScalingPoliciesPages(
+		&awsapplicationautoscaling.DescribeScalingPoliciesInput{
+			ResourceId:        aws.String(ServiceResourceId(clusterName, serviceName)),
+			ScalableDimension: aws.String(awsapplicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+			ServiceNamespace:  aws.String(awsapplicationautoscaling.ServiceNamespaceEcs),
+		},
+		func(resp *awsapplicationautoscaling.DescribeScalingPoliciesOutput, lastPage bool) bool {
+			for _, p := range resp.ScalingPolicies {
+				if config := p.TargetTrackingScalingPolicyConfiguration; config != nil {
+					policies = append(policies, scalingPolicyFromConfiguration(aws.StringValue(p.PolicyName), config))
+				}
+			}
+
+			return true
+		},
+	)
+
+	if err != nil {
+		return policies, fmt.Errorf("could not describe scaling policies for service %s: %v", serviceName, err)
+	}
+
+	return policies, nil
+}
+
+// DeleteServicePolicy deletes the named scaling policy from the service.
+func (aas *ApplicationAutoScaling) DeleteServicePolicy(clusterName, serviceName, policyName string) error {
+	_, err := aas.svc.DeleteScalingPolicy(
+		&awsapplicationautoscaling.DeleteScalingPolicyInput{
+			PolicyName:        aws.String(policyName),
+			ResourceId:        aws.String(ServiceResourceId(clusterName, serviceName)),
+			ScalableDimension: aws.String(awsapplicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+			ServiceNamespace:  aws.String(awsapplicationautoscaling.ServiceNamespaceEcs),
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not delete scaling policy %s: %v", policyName, err)
+	}
+
+	return nil
+}
+
+func scalingPolicyFromConfiguration(name string, config *awsapplicationautoscaling.TargetTrackingScalingPolicyConfiguration) ScalingPolicy {
+	policy := ScalingPolicy{
+		Name:   name,
+		Target: aws.Float64Value(config.TargetValue),
+	}
+
+	if spec := config.PredefinedMetricSpecification; spec != nil {
+		policy.Metric = aws.StringValue(spec.PredefinedMetricType)
+
+		for metric, metricType := range predefinedMetricTypes {
+			if metricType == policy.Metric {
+				policy.Metric = metric
+			}
+		}
+	}
+
+	return policy
+}
@@ -0,0 +1,64 @@
+package applicationautoscaling
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsapplicationautoscaling "github.com/aws/aws-sdk-go/service/applicationautoscaling"
+)
+
+func TestValidateMetric(t *testing.T) {
+	var tests = []struct {
+		metric string
+		valid  bool
+	}{
+		{"cpu", true},
+		{"memory", true},
+		{"requests", true},
+		{"CPU", false},
+		{"disk", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if err := ValidateMetric(test.metric); (err == nil) != test.valid {
+			t.Errorf("expected valid %t for %q, got %v", test.valid, test.metric, err)
+		}
+	}
+}
+
+func TestRequestCountResourceLabel(t *testing.T) {
+	loadBalancerArn := "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/1234567890abcdef"
+	targetGroupArn := "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/fargate-web/fedcba0987654321"
+	expected := "app/web/1234567890abcdef/targetgroup/fargate-web/fedcba0987654321"
+
+	if label := RequestCountResourceLabel(loadBalancerArn, targetGroupArn); label != expected {
+		t.Errorf("expected %s, got %s", expected, label)
+	}
+}
+
+func TestScalingPolicyFromConfiguration(t *testing.T) {
+	var tests = []struct {
+		metricType string
+		metric     string
+	}{
+		{awsapplicationautoscaling.MetricTypeEcsserviceAverageCpuutilization, MetricCpu},
+		{awsapplicationautoscaling.MetricTypeAlbrequestCountPerTarget, MetricRequests},
+		{"SomeFutureMetric", "SomeFutureMetric"},
+	}
+
+	for _, test := range tests {
+		config := &awsapplicationautoscaling.TargetTrackingScalingPolicyConfiguration{
+			PredefinedMetricSpecification: &awsapplicationautoscaling.PredefinedMetricSpecification{
+				PredefinedMetricType: aws.String(test.metricType),
+			},
+			TargetValue: aws.Float64(60),
+		}
+
+		policy := scalingPolicyFromConfiguration("fargate-test", config)
+
+		if policy.Metric != test.metric || policy.Target != 60 || policy.Name != "fargate-test" {
+			t.Errorf("expected fargate-test %s 60, got %s %s %g", test.metric, policy.Name, policy.Metric, policy.Target)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package applicationautoscaling
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsapplicationautoscaling "github.com/aws/aws-sdk-go/service/applicationautoscaling"
+)
+
+const serviceResourceIdFormat = "service/%s/%s"
+
+// ScalableTarget is the range within which Application Auto Scaling keeps a
+// service's desired count.
+type ScalableTarget struct {
+	MaxCapacity int64
+	MinCapacity int64
+}
+
+// ServiceResourceId returns the resource ID which identifies the service in
+// Application Auto Scaling [e.g. service/fargate/web].
+func ServiceResourceId(clusterName, serviceName string) string {
+	return fmt.Sprintf(serviceResourceIdFormat, clusterName, serviceName)
+}
+
+// RegisterServiceTarget registers the service's desired count as a scalable
+// target, or updates its range if it's already registered.
+func (aas *ApplicationAutoScaling) RegisterServiceTarget(clusterName, serviceName string, target ScalableTarget) error {
+	if target.MinCapacity < 0 || target.MaxCapacity < target.MinCapacity {
+		return fmt.Errorf("invalid capacity %d - %d: minimum must be >= 0 and <= maximum", target.MinCapacity, target.MaxCapacity)
+	}
+
+	_, err := aas.svc.RegisterScalableTarget(
+		&awsapplicationautoscaling.RegisterScalableTargetInput{
+			MaxCapacity:       aws.Int64(target.MaxCapacity),
+			MinCapacity:       aws.Int64(target.MinCapacity),
+			ResourceId:        aws.String(ServiceResourceId(clusterName, serviceName)),
+			ScalableDimension: aws.String(awsapplicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+			ServiceNamespace:  aws.String(awsapplicationautoscaling.ServiceNamespaceEcs),
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not register scalable target for service %s: %v", serviceName, err)
+	}
+
+	return nil
+}
+
+// DescribeServiceTarget returns the service's scalable target and whether it
+// is registered.
+func (aas *ApplicationAutoScaling) DescribeServiceTarget(clusterName, serviceName string) (ScalableTarget, bool, error) {
+	resp, err := aas.svc.DescribeScalableTargets(
+		&awsapplicationautoscaling.DescribeScalableTargetsInput{
+			ResourceIds:       aws.StringSlice([]string{ServiceResourceId(clusterName, serviceName)}),
+			ScalableDimension: aws.String(awsapplicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+			ServiceNamespace:  aws.String(awsapplicationautoscaling.ServiceNamespaceEcs),
+		},
+	)
+
+	if err != nil {
+		return ScalableTarget{}, false, fmt.Errorf("could not describe scalable target for service %s: %v", serviceName, err)
+	}
+
+	if len(resp.ScalableTargets) == 0 {
+		return ScalableTarget{}, false, nil
+	}
+
+	target := ScalableTarget{
+		MaxCapacity: aws.Int64Value(resp.ScalableTargets[0].MaxCapacity),
+		MinCapacity: aws.Int64Value(resp.ScalableTargets[0].MinCapacity),
+	}
+
+	return target, true, nil
+}
+
+// DeregisterServiceTarget stops autoscaling the service, deleting its scaling
+// policies. Services which aren't registered are ignored.
+func (aas *ApplicationAutoScaling) DeregisterServiceTarget(clusterName, serviceName string) error {
+	_, err := aas.svc.DeregisterScalableTarget(
+		&awsapplicationautoscaling.DeregisterScalableTargetInput{
+			ResourceId:        aws.String(ServiceResourceId(clusterName, serviceName)),
+			ScalableDimension: aws.String(awsapplicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+			ServiceNamespace:  aws.String(awsapplicationautoscaling.ServiceNamespaceEcs),
+		},
+	)
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == awsapplicationautoscaling.ErrCodeObjectNotFoundException {
+			return nil
+		}
+
+		return fmt.Errorf("could not deregister scalable target for service %s: %v", serviceName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package applicationautoscaling
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsapplicationautoscaling "github.com/aws/aws-sdk-go/service/applicationautoscaling"
+)
+
+const scheduleExpressionPattern = `^(at\(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\)|rate\(\d+ (minute|minutes|hour|hours|day|days)\)|cron\(\S+( \S+){5}\))$`
+
+// ScheduledAction changes the range within which a service is scaled on a
+// schedule given as an at, rate, or cron expression [e.g. cron(0 8 ? *
+// MON-FRI *)] in the time zone, or UTC if none is given. Either capacity may
+// be omitted to leave it unchanged.
+type ScheduledAction struct {
+	MaxCapacity *int64
+	MinCapacity *int64
+	Name        string
+	Schedule    string
+	Timezone    string
+}
+
+// Capacity describes the range the action scales the service to [e.g. 2 - 10,
+// or >= 2 if only the minimum is changed].
+func (a ScheduledAction) Capacity() string {
+	switch {
+	case a.MinCapacity != nil && a.MaxCapacity != nil && *a.MinCapacity == *a.MaxCapacity:
+		return fmt.Sprintf("%d", *a.MinCapacity)
+	case a.MinCapacity != nil && a.MaxCapacity != nil:
+		return fmt.Sprintf("%d - %d", *a.MinCapacity, *a.MaxCapacity)
+	case a.MinCapacity != nil:
+		return fmt.Sprintf(">= %d", *a.MinCapacity)
+	case a.MaxCapacity != nil:
+		return fmt.Sprintf("<= %d", *a.MaxCapacity)
+	}
+
+	return ""
+}
+
+// ValidateScheduleExpression returns an error unless the expression is a
+// one-time at expression, a rate expression, or a cron expression with six
+// fields.
+func ValidateScheduleExpression(expression string) error {
+	if !regexp.MustCompile(scheduleExpressionPattern).MatchString(expression) {
+		return fmt.Errorf("invalid schedule expression %q: must be at(yyyy-mm-ddThh:mm:ss), rate(value unit), or cron(minutes hours day-of-month month day-of-week year)", expression)
+	}
+
+	return nil
+}
+
+// PutServiceScheduledAction creates or replaces the service's scheduled
+// action with the same name. The service must be registered as a scalable
+// target.
+func (aas *ApplicationAutoScaling) PutServiceScheduledAction(clusterName, serviceName string, action ScheduledAction) error {
+	if err := ValidateScheduleExpression(action.Schedule); err != nil {
+		return err
+	}
+
+	if action.MinCapacity == nil && action.MaxCapacity == nil {
+		return fmt.Errorf("scheduled action %s must change the minimum and/or maximum capacity", action.Name)
+	}
+
+	input := &awsapplicationautoscaling.PutScheduledActionInput{
+		ResourceId:        aws.String(ServiceResourceId(clusterName, serviceName)),
+		ScalableDimension: aws.String(awsapplicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+		ScalableTargetAction: &awsapplicationautoscaling.ScalableTargetAction{
+			MaxCapacity: action.MaxCapacity,
+			MinCapacity: action.MinCapacity,
+		},
+		Schedule:            aws.String(action.Schedule),
+		ScheduledActionName: aws.String(action.Name),
+		ServiceNamespace:    aws.String(awsapplicationautoscaling.ServiceNamespaceEcs),
+	}
+
+	if action.Timezone != "" {
+		input.Timezone = aws.String(action.Timezone)
+	}
+
+	if _, err := aas.svc.PutScheduledAction(input); err != nil {
+		return fmt.Errorf("could not put scheduled action %s: %v", action.Name, err)
+	}
+
+	return nil
+}
+
+// DescribeServiceScheduledActions returns the service's scheduled actions.
+func (aas *ApplicationAutoScaling) DescribeServiceScheduledActions(clusterName, serviceName string) ([]ScheduledAction, error) {
+	var actions []ScheduledAction
+
+	err := aas.svc.DescribeScheduledActionsPages(
+		&awsapplicationautoscaling.DescribeScheduledActionsInput{
+			ResourceId:        aws.String(ServiceResourceId(clusterName, serviceName)),
+			ScalableDimension: aws.String(awsapplicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+			ServiceNamespace:  aws.String(awsapplicationautoscaling.ServiceNamespaceEcs),
+		},
+		func(resp *awsapplicationautoscaling.DescribeScheduledActionsOutput, lastPage bool) bool {
+			for _, a := range resp.ScheduledActions {
+				action := ScheduledAction{
+					Name:     aws.StringValue(a.ScheduledActionName),
+					Schedule: aws.StringValue(a.Schedule),
+					Timezone: aws.StringValue(a.Timezone),
+				}
+
+				if a.ScalableTargetAction != nil {
+					action.MaxCapacity = a.ScalableTargetAction.MaxCapacity
+					action.MinCapacity = a.ScalableTargetAction.MinCapacity
+				}
+
+				actions = append(actions, action)
+			}
+
+			return true
+		},
+	)
+
+	if err != nil {
+		return actions, fmt.Errorf("could not describe scheduled actions for service %s: %v", serviceName, err)
+	}
+
+	return actions, nil
+}
+
+// DeleteServiceScheduledAction deletes the named scheduled action from the
+// service.
+func (aas *ApplicationAutoScaling) DeleteServiceScheduledAction(clusterName, serviceName, name string) error {
+	_, err := aas.svc.DeleteScheduledAction(
+		&awsapplicationautoscaling.DeleteScheduledActionInput{
+			ResourceId:          aws.String(ServiceResourceId(clusterName, serviceName)),
+			ScalableDimension:   aws.String(awsapplicationautoscaling.ScalableDimensionEcsServiceDesiredCount),
+			ScheduledActionName: aws.String(name),
+			ServiceNamespace:    aws.String(awsapplicationautoscaling.ServiceNamespaceEcs),
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not delete scheduled action %s: %v", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package applicationautoscaling
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestValidateScheduleExpression(t *testing.T) {
+	var tests = []struct {
+		expression string
+		valid      bool
+	}{
+		{"cron(0 8 ? * MON-FRI *)", true},
+		{"cron(0 20 * * ? *)", true},
+		{"rate(1 hour)", true},
+		{"at(2026-11-27T06:00:00)", true},
+		{"cron(0 8 * * ?)", false},
+		{"at(2026-11-27)", false},
+		{"rate(1 week)", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if err := ValidateScheduleExpression(test.expression); (err == nil) != test.valid {
+			t.Errorf("expected valid %t for %q, got %v", test.valid, test.expression, err)
+		}
+	}
+}
+
+func TestScheduledActionCapacity(t *testing.T) {
+	var tests = []struct {
+		min      *int64
+		max      *int64
+		capacity string
+	}{
+		{aws.Int64(10), aws.Int64(10), "10"},
+		{aws.Int64(2), aws.Int64(10), "2 - 10"},
+		{aws.Int64(2), nil, ">= 2"},
+		{nil, aws.Int64(4), "<= 4"},
+	}
+
+	for _, test := range tests {
+		action := ScheduledAction{MinCapacity: test.min, MaxCapacity: test.max}
+
+		if capacity := action.Capacity(); capacity != test.capacity {
+			t.Errorf("expected %q, got %q", test.capacity, capacity)
+		}
+	}
+}
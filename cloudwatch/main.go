@@ -0,0 +1,16 @@
+package cloudwatch
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+type CloudWatch struct {
+	svc *cloudwatch.CloudWatch
+}
+
+func New(sess *session.Session) CloudWatch {
+	return CloudWatch{
+		svc: cloudwatch.New(sess),
+	}
+}
@@ -0,0 +1,113 @@
+package cloudwatch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awscw "github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+const (
+	containerInsightsNamespace = "ECS/ContainerInsights"
+	cpuUtilizedMetric          = "CpuUtilized"
+	memoryUtilizedMetric       = "MemoryUtilized"
+
+	// GetMetricStatistics returns at most 1,440 data points per call.
+	maxDataPoints = 1440
+	minPeriod     = 60
+)
+
+// Utilization is a task's CPU usage, in CPU units, and memory usage, in MiB,
+// over a period of time.
+type Utilization struct {
+	AverageCpu    float64
+	AverageMemory float64
+	PeakCpu       float64
+	PeakMemory    float64
+}
+
+// GetTaskUtilization returns the average and peak CPU and memory used by a task
+// between start and end from its Container Insights metrics. Per-task metrics
+// are only published for clusters with Container Insights enhanced
+// observability enabled; if none are found, a "metrics unavailable" error is
+// returned rather than zero utilization.
+func (cw CloudWatch) GetTaskUtilization(clusterName, taskDefinitionFamily, taskId string, start, end time.Time) (Utilization, error) {
+	var utilization Utilization
+
+	dimensions := []*awscw.Dimension{
+		&awscw.Dimension{Name: aws.String("ClusterName"), Value: aws.String(clusterName)},
+		&awscw.Dimension{Name: aws.String("TaskDefinitionFamily"), Value: aws.String(taskDefinitionFamily)},
+		&awscw.Dimension{Name: aws.String("TaskId"), Value: aws.String(taskId)},
+	}
+
+	cpuDatapoints, err := cw.getMetricStatistics(cpuUtilizedMetric, dimensions, start, end)
+
+	if err != nil {
+		return utilization, err
+	}
+
+	memoryDatapoints, err := cw.getMetricStatistics(memoryUtilizedMetric, dimensions, start, end)
+
+	if err != nil {
+		return utilization, err
+	}
+
+	if len(cpuDatapoints) == 0 || len(memoryDatapoints) == 0 {
+		return utilization, fmt.Errorf("metrics unavailable: no Container Insights metrics found for task %s", taskId)
+	}
+
+	utilization.AverageCpu, utilization.PeakCpu = summarize(cpuDatapoints)
+	utilization.AverageMemory, utilization.PeakMemory = summarize(memoryDatapoints)
+
+	return utilization, nil
+}
+
+func (cw CloudWatch) getMetricStatistics(metricName string, dimensions []*awscw.Dimension, start, end time.Time) ([]*awscw.Datapoint, error) {
+	resp, err := cw.svc.GetMetricStatistics(
+		&awscw.GetMetricStatisticsInput{
+			Dimensions: dimensions,
+			EndTime:    aws.Time(end),
+			MetricName: aws.String(metricName),
+			Namespace:  aws.String(containerInsightsNamespace),
+			Period:     aws.Int64(period(start, end)),
+			StartTime:  aws.Time(start),
+			Statistics: aws.StringSlice([]string{awscw.StatisticAverage, awscw.StatisticMaximum}),
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not get %s metrics: %v", metricName, err)
+	}
+
+	return resp.Datapoints, nil
+}
+
+// period returns the shortest period, in whole minutes, which keeps the
+// number of data points between start and end within a single request.
+func period(start, end time.Time) int64 {
+	seconds := int64(end.Sub(start).Seconds())
+	p := int64(minPeriod)
+
+	for seconds/p > maxDataPoints {
+		p += minPeriod
+	}
+
+	return p
+}
+
+// summarize returns the mean of the data points' averages and the highest of
+// their maximums.
+func summarize(datapoints []*awscw.Datapoint) (float64, float64) {
+	var total, peak float64
+
+	for _, datapoint := range datapoints {
+		total += aws.Float64Value(datapoint.Average)
+
+		if maximum := aws.Float64Value(datapoint.Maximum); maximum > peak {
+			peak = maximum
+		}
+	}
+
+	return total / float64(len(datapoints)), peak
+}
@@ -0,0 +1,45 @@
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awscw "github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+func TestPeriod(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		duration time.Duration
+		expected int64
+	}{
+		{time.Minute, 60},
+		{24 * time.Hour, 60},
+		{48 * time.Hour, 120},
+		{7 * 24 * time.Hour, 420},
+	}
+
+	for _, test := range tests {
+		if p := period(start, start.Add(test.duration)); p != test.expected {
+			t.Errorf("expected period %d for %s, got %d", test.expected, test.duration, p)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	datapoints := []*awscw.Datapoint{
+		&awscw.Datapoint{Average: aws.Float64(10), Maximum: aws.Float64(40)},
+		&awscw.Datapoint{Average: aws.Float64(20), Maximum: aws.Float64(25)},
+	}
+
+	average, peak := summarize(datapoints)
+
+	if average != 15 {
+		t.Errorf("expected average 15, got %f", average)
+	}
+
+	if peak != 40 {
+		t.Errorf("expected peak 40, got %f", peak)
+	}
+}
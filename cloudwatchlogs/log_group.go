@@ -1,7 +1,10 @@
 package cloudwatchlogs
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -10,6 +13,13 @@ import (
 	"github.com/jpignata/fargate/console"
 )
 
+const (
+	followMinInterval = time.Second
+	followMaxInterval = 10 * time.Second
+)
+
+var errLogStreamNotFound = errors.New("log stream has not been created yet; the task may not have started")
+
 type GetLogsInput struct {
 	Filter         string
 	LogGroupName   string
@@ -18,6 +28,12 @@ type GetLogsInput struct {
 	StartTime      time.Time
 }
 
+type FollowLogStreamInput struct {
+	Done          func() bool
+	LogGroupName  string
+	LogStreamName string
+}
+
 type LogLine struct {
 	EventId       string
 	LogStreamName string
@@ -96,3 +112,107 @@ func (cwl *CloudWatchLogs) GetLogs(i *GetLogsInput) []LogLine {
 
 	return logLines
 }
+
+func (cwl *CloudWatchLogs) GetLogStreamEvents(logGroupName, logStreamName string) ([]LogLine, error) {
+	var logLines []LogLine
+
+	input := &awscwl.GetLogEventsInput{
+		LogGroupName:  aws.String(logGroupName),
+		LogStreamName: aws.String(logStreamName),
+		StartFromHead: aws.Bool(true),
+	}
+
+	for {
+		lines, nextToken, err := cwl.getLogEvents(context.Background(), input)
+
+		if err == errLogStreamNotFound {
+			return logLines, fmt.Errorf("%s: %v", logStreamName, err)
+		}
+
+		if err != nil {
+			return logLines, err
+		}
+
+		logLines = append(logLines, lines...)
+
+		if aws.StringValue(nextToken) == aws.StringValue(input.NextToken) {
+			break
+		}
+
+		input.NextToken = nextToken
+	}
+
+	return logLines, nil
+}
+
+func (cwl *CloudWatchLogs) FollowLogStream(ctx context.Context, i *FollowLogStreamInput, fn func(LogLine)) error {
+	interval := followMinInterval
+	input := &awscwl.GetLogEventsInput{
+		LogGroupName:  aws.String(i.LogGroupName),
+		LogStreamName: aws.String(i.LogStreamName),
+		StartFromHead: aws.Bool(true),
+	}
+
+	for {
+		done := i.Done != nil && i.Done()
+		lines, nextToken, err := cwl.getLogEvents(ctx, input)
+
+		if err != nil && err != errLogStreamNotFound {
+			return err
+		}
+
+		for _, line := range lines {
+			fn(line)
+		}
+
+		if nextToken != nil {
+			input.NextToken = nextToken
+		}
+
+		if done && len(lines) == 0 {
+			return nil
+		}
+
+		if len(lines) > 0 {
+			interval = followMinInterval
+		} else if interval *= 2; interval > followMaxInterval {
+			interval = followMaxInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (cwl *CloudWatchLogs) getLogEvents(ctx context.Context, input *awscwl.GetLogEventsInput) ([]LogLine, *string, error) {
+	var logLines []LogLine
+
+	resp, err := cwl.svc.GetLogEventsWithContext(ctx, input)
+
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == awscwl.ErrCodeResourceNotFoundException {
+			if strings.Contains(awsErr.Message(), "log group") {
+				return logLines, nil, fmt.Errorf("log group %s does not exist yet", aws.StringValue(input.LogGroupName))
+			}
+
+			return logLines, nil, errLogStreamNotFound
+		}
+
+		return logLines, nil, err
+	}
+
+	for _, event := range resp.Events {
+		logLines = append(logLines,
+			LogLine{
+				LogStreamName: aws.StringValue(input.LogStreamName),
+				Message:       aws.StringValue(event.Message),
+				Timestamp:     time.Unix(0, aws.Int64Value(event.Timestamp)*int64(time.Millisecond)),
+			},
+		)
+	}
+
+	return logLines, resp.NextForwardToken, nil
+}
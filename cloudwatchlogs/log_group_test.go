@@ -0,0 +1,237 @@
+package cloudwatchlogs
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awscwl "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/golang/mock/gomock"
+	"github.com/jpignata/fargate/cloudwatchlogs/mock/sdk"
+)
+
+func TestGetLogStreamEvents(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockClient := sdk.NewMockCloudWatchLogsAPI(mockCtrl)
+	cwl := CloudWatchLogs{svc: mockClient}
+	firstInput := &awscwl.GetLogEventsInput{
+		LogGroupName:  aws.String("/fargate/task/web"),
+		LogStreamName: aws.String("fargate/web/1234"),
+		StartFromHead: aws.Bool(true),
+	}
+	secondInput := &awscwl.GetLogEventsInput{
+		LogGroupName:  aws.String("/fargate/task/web"),
+		LogStreamName: aws.String("fargate/web/1234"),
+		NextToken:     aws.String("f/1"),
+		StartFromHead: aws.Bool(true),
+	}
+	thirdInput := &awscwl.GetLogEventsInput{
+		LogGroupName:  aws.String("/fargate/task/web"),
+		LogStreamName: aws.String("fargate/web/1234"),
+		NextToken:     aws.String("f/2"),
+		StartFromHead: aws.Bool(true),
+	}
+
+	gomock.InOrder(
+		mockClient.EXPECT().GetLogEventsWithContext(gomock.Any(), firstInput).Return(
+			&awscwl.GetLogEventsOutput{
+				Events: []*awscwl.OutputLogEvent{
+					&awscwl.OutputLogEvent{Message: aws.String("starting"), Timestamp: aws.Int64(1000)},
+				},
+				NextForwardToken: aws.String("f/1"),
+			}, nil,
+		),
+		mockClient.EXPECT().GetLogEventsWithContext(gomock.Any(), secondInput).Return(
+			&awscwl.GetLogEventsOutput{
+				Events: []*awscwl.OutputLogEvent{
+					&awscwl.OutputLogEvent{Message: aws.String("done"), Timestamp: aws.Int64(2000)},
+				},
+				NextForwardToken: aws.String("f/2"),
+			}, nil,
+		),
+		mockClient.EXPECT().GetLogEventsWithContext(gomock.Any(), thirdInput).Return(
+			&awscwl.GetLogEventsOutput{NextForwardToken: aws.String("f/2")}, nil,
+		),
+	)
+
+	logLines, err := cwl.GetLogStreamEvents("/fargate/task/web", "fargate/web/1234")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []LogLine{
+		LogLine{LogStreamName: "fargate/web/1234", Message: "starting", Timestamp: time.Unix(1, 0)},
+		LogLine{LogStreamName: "fargate/web/1234", Message: "done", Timestamp: time.Unix(2, 0)},
+	}
+
+	if !reflect.DeepEqual(logLines, expected) {
+		t.Errorf("expected %v, got %v", expected, logLines)
+	}
+}
+
+func TestGetLogStreamEventsStreamNotFound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockClient := sdk.NewMockCloudWatchLogsAPI(mockCtrl)
+	cwl := CloudWatchLogs{svc: mockClient}
+
+	mockClient.EXPECT().GetLogEventsWithContext(gomock.Any(), gomock.Any()).Return(
+		nil,
+		awserr.New(awscwl.ErrCodeResourceNotFoundException, "The specified log stream does not exist.", nil),
+	)
+
+	_, err := cwl.GetLogStreamEvents("/fargate/task/web", "fargate/web/1234")
+
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	if !strings.HasPrefix(err.Error(), "fargate/web/1234: log stream has not been created yet") {
+		t.Errorf("expected log stream not found error, got %v", err)
+	}
+}
+
+func TestGetLogStreamEventsGroupNotFound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockClient := sdk.NewMockCloudWatchLogsAPI(mockCtrl)
+	cwl := CloudWatchLogs{svc: mockClient}
+
+	mockClient.EXPECT().GetLogEventsWithContext(gomock.Any(), gomock.Any()).Return(
+		nil,
+		awserr.New(awscwl.ErrCodeResourceNotFoundException, "The specified log group does not exist.", nil),
+	)
+
+	_, err := cwl.GetLogStreamEvents("/fargate/task/web", "fargate/web/1234")
+
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	if expected := "log group /fargate/task/web does not exist yet"; err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err)
+	}
+}
+
+func TestFollowLogStreamDone(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var logLines []LogLine
+
+	mockClient := sdk.NewMockCloudWatchLogsAPI(mockCtrl)
+	cwl := CloudWatchLogs{svc: mockClient}
+	input := &FollowLogStreamInput{
+		Done:          func() bool { return true },
+		LogGroupName:  "/fargate/task/web",
+		LogStreamName: "fargate/web/1234",
+	}
+
+	mockClient.EXPECT().GetLogEventsWithContext(gomock.Any(), gomock.Any()).Return(
+		&awscwl.GetLogEventsOutput{NextForwardToken: aws.String("f/1")}, nil,
+	)
+
+	err := cwl.FollowLogStream(context.Background(), input, func(line LogLine) {
+		logLines = append(logLines, line)
+	})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if len(logLines) > 0 {
+		t.Errorf("expected no log lines, got %v", logLines)
+	}
+}
+
+func TestFollowLogStreamCanceled(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var messages []string
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockClient := sdk.NewMockCloudWatchLogsAPI(mockCtrl)
+	cwl := CloudWatchLogs{svc: mockClient}
+	input := &FollowLogStreamInput{
+		LogGroupName:  "/fargate/task/web",
+		LogStreamName: "fargate/web/1234",
+	}
+
+	mockClient.EXPECT().GetLogEventsWithContext(gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awscwl.GetLogEventsInput, opts ...interface{}) {
+			cancel()
+		},
+	).Return(
+		&awscwl.GetLogEventsOutput{
+			Events: []*awscwl.OutputLogEvent{
+				&awscwl.OutputLogEvent{Message: aws.String("listening"), Timestamp: aws.Int64(1000)},
+			},
+			NextForwardToken: aws.String("f/1"),
+		}, nil,
+	)
+
+	err := cwl.FollowLogStream(ctx, input, func(line LogLine) {
+		messages = append(messages, line.Message)
+	})
+
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+
+	if !reflect.DeepEqual(messages, []string{"listening"}) {
+		t.Errorf("expected [listening], got %v", messages)
+	}
+}
+
+func TestFollowLogStreamWaitsForStream(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockClient := sdk.NewMockCloudWatchLogsAPI(mockCtrl)
+	cwl := CloudWatchLogs{svc: mockClient}
+	input := &FollowLogStreamInput{
+		Done:          func() bool { return true },
+		LogGroupName:  "/fargate/task/web",
+		LogStreamName: "fargate/web/1234",
+	}
+
+	mockClient.EXPECT().GetLogEventsWithContext(gomock.Any(), gomock.Any()).Return(
+		nil,
+		awserr.New(awscwl.ErrCodeResourceNotFoundException, "The specified log stream does not exist.", nil),
+	)
+
+	if err := cwl.FollowLogStream(context.Background(), input, func(LogLine) {}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestFollowLogStreamError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockClient := sdk.NewMockCloudWatchLogsAPI(mockCtrl)
+	cwl := CloudWatchLogs{svc: mockClient}
+	input := &FollowLogStreamInput{
+		LogGroupName:  "/fargate/task/web",
+		LogStreamName: "fargate/web/1234",
+	}
+
+	mockClient.EXPECT().GetLogEventsWithContext(gomock.Any(), gomock.Any()).Return(
+		nil,
+		awserr.New(awscwl.ErrCodeResourceNotFoundException, "The specified log group does not exist.", nil),
+	)
+
+	if err := cwl.FollowLogStream(context.Background(), input, func(LogLine) {}); err == nil {
+		t.Error("expected error, got none")
+	}
+}
@@ -1,12 +1,15 @@
 package cloudwatchlogs
 
+//go:generate mockgen -package sdk -source ../vendor/github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface/interface.go -destination=mock/sdk/cloudwatchlogsiface.go github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface CloudWatchLogsAPI
+
 import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
 )
 
 type CloudWatchLogs struct {
-	svc *cloudwatchlogs.CloudWatchLogs
+	svc cloudwatchlogsiface.CloudWatchLogsAPI
 }
 
 func New(sess *session.Session) CloudWatchLogs {
@@ -0,0 +1,3640 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../vendor/github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface/interface.go
+
+// Package sdk is a generated GoMock package.
+package sdk
+
+import (
+	aws "github.com/aws/aws-sdk-go/aws"
+	request "github.com/aws/aws-sdk-go/aws/request"
+	cloudwatchlogs "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockCloudWatchLogsAPI is a mock of CloudWatchLogsAPI interface
+type MockCloudWatchLogsAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloudWatchLogsAPIMockRecorder
+}
+
+// MockCloudWatchLogsAPIMockRecorder is the mock recorder for MockCloudWatchLogsAPI
+type MockCloudWatchLogsAPIMockRecorder struct {
+	mock *MockCloudWatchLogsAPI
+}
+
+// NewMockCloudWatchLogsAPI creates a new mock instance
+func NewMockCloudWatchLogsAPI(ctrl *gomock.Controller) *MockCloudWatchLogsAPI {
+	mock := &MockCloudWatchLogsAPI{ctrl: ctrl}
+	mock.recorder = &MockCloudWatchLogsAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCloudWatchLogsAPI) EXPECT() *MockCloudWatchLogsAPIMockRecorder {
+	return m.recorder
+}
+
+// AssociateKmsKey mocks base method
+func (m *MockCloudWatchLogsAPI) AssociateKmsKey(arg0 *cloudwatchlogs.AssociateKmsKeyInput) (*cloudwatchlogs.AssociateKmsKeyOutput, error) {
+	ret := m.ctrl.Call(m, "AssociateKmsKey", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.AssociateKmsKeyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssociateKmsKey indicates an expected call of AssociateKmsKey
+func (mr *MockCloudWatchLogsAPIMockRecorder) AssociateKmsKey(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateKmsKey", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).AssociateKmsKey), arg0)
+}
+
+// AssociateKmsKeyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) AssociateKmsKeyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.AssociateKmsKeyInput, arg2 ...request.Option) (*cloudwatchlogs.AssociateKmsKeyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AssociateKmsKeyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.AssociateKmsKeyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssociateKmsKeyWithContext indicates an expected call of AssociateKmsKeyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) AssociateKmsKeyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateKmsKeyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).AssociateKmsKeyWithContext), varargs...)
+}
+
+// AssociateKmsKeyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) AssociateKmsKeyRequest(arg0 *cloudwatchlogs.AssociateKmsKeyInput) (*request.Request, *cloudwatchlogs.AssociateKmsKeyOutput) {
+	ret := m.ctrl.Call(m, "AssociateKmsKeyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.AssociateKmsKeyOutput)
+	return ret0, ret1
+}
+
+// AssociateKmsKeyRequest indicates an expected call of AssociateKmsKeyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) AssociateKmsKeyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateKmsKeyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).AssociateKmsKeyRequest), arg0)
+}
+
+// CancelExportTask mocks base method
+func (m *MockCloudWatchLogsAPI) CancelExportTask(arg0 *cloudwatchlogs.CancelExportTaskInput) (*cloudwatchlogs.CancelExportTaskOutput, error) {
+	ret := m.ctrl.Call(m, "CancelExportTask", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.CancelExportTaskOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelExportTask indicates an expected call of CancelExportTask
+func (mr *MockCloudWatchLogsAPIMockRecorder) CancelExportTask(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelExportTask", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CancelExportTask), arg0)
+}
+
+// CancelExportTaskWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) CancelExportTaskWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.CancelExportTaskInput, arg2 ...request.Option) (*cloudwatchlogs.CancelExportTaskOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CancelExportTaskWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.CancelExportTaskOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelExportTaskWithContext indicates an expected call of CancelExportTaskWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) CancelExportTaskWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelExportTaskWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CancelExportTaskWithContext), varargs...)
+}
+
+// CancelExportTaskRequest mocks base method
+func (m *MockCloudWatchLogsAPI) CancelExportTaskRequest(arg0 *cloudwatchlogs.CancelExportTaskInput) (*request.Request, *cloudwatchlogs.CancelExportTaskOutput) {
+	ret := m.ctrl.Call(m, "CancelExportTaskRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.CancelExportTaskOutput)
+	return ret0, ret1
+}
+
+// CancelExportTaskRequest indicates an expected call of CancelExportTaskRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) CancelExportTaskRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelExportTaskRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CancelExportTaskRequest), arg0)
+}
+
+// CreateDelivery mocks base method
+func (m *MockCloudWatchLogsAPI) CreateDelivery(arg0 *cloudwatchlogs.CreateDeliveryInput) (*cloudwatchlogs.CreateDeliveryOutput, error) {
+	ret := m.ctrl.Call(m, "CreateDelivery", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.CreateDeliveryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDelivery indicates an expected call of CreateDelivery
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateDelivery(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelivery", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateDelivery), arg0)
+}
+
+// CreateDeliveryWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) CreateDeliveryWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.CreateDeliveryInput, arg2 ...request.Option) (*cloudwatchlogs.CreateDeliveryOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateDeliveryWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.CreateDeliveryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDeliveryWithContext indicates an expected call of CreateDeliveryWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateDeliveryWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeliveryWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateDeliveryWithContext), varargs...)
+}
+
+// CreateDeliveryRequest mocks base method
+func (m *MockCloudWatchLogsAPI) CreateDeliveryRequest(arg0 *cloudwatchlogs.CreateDeliveryInput) (*request.Request, *cloudwatchlogs.CreateDeliveryOutput) {
+	ret := m.ctrl.Call(m, "CreateDeliveryRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.CreateDeliveryOutput)
+	return ret0, ret1
+}
+
+// CreateDeliveryRequest indicates an expected call of CreateDeliveryRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateDeliveryRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeliveryRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateDeliveryRequest), arg0)
+}
+
+// CreateExportTask mocks base method
+func (m *MockCloudWatchLogsAPI) CreateExportTask(arg0 *cloudwatchlogs.CreateExportTaskInput) (*cloudwatchlogs.CreateExportTaskOutput, error) {
+	ret := m.ctrl.Call(m, "CreateExportTask", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.CreateExportTaskOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExportTask indicates an expected call of CreateExportTask
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateExportTask(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExportTask", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateExportTask), arg0)
+}
+
+// CreateExportTaskWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) CreateExportTaskWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.CreateExportTaskInput, arg2 ...request.Option) (*cloudwatchlogs.CreateExportTaskOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateExportTaskWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.CreateExportTaskOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExportTaskWithContext indicates an expected call of CreateExportTaskWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateExportTaskWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExportTaskWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateExportTaskWithContext), varargs...)
+}
+
+// CreateExportTaskRequest mocks base method
+func (m *MockCloudWatchLogsAPI) CreateExportTaskRequest(arg0 *cloudwatchlogs.CreateExportTaskInput) (*request.Request, *cloudwatchlogs.CreateExportTaskOutput) {
+	ret := m.ctrl.Call(m, "CreateExportTaskRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.CreateExportTaskOutput)
+	return ret0, ret1
+}
+
+// CreateExportTaskRequest indicates an expected call of CreateExportTaskRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateExportTaskRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExportTaskRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateExportTaskRequest), arg0)
+}
+
+// CreateLogAnomalyDetector mocks base method
+func (m *MockCloudWatchLogsAPI) CreateLogAnomalyDetector(arg0 *cloudwatchlogs.CreateLogAnomalyDetectorInput) (*cloudwatchlogs.CreateLogAnomalyDetectorOutput, error) {
+	ret := m.ctrl.Call(m, "CreateLogAnomalyDetector", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.CreateLogAnomalyDetectorOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLogAnomalyDetector indicates an expected call of CreateLogAnomalyDetector
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateLogAnomalyDetector(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogAnomalyDetector", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateLogAnomalyDetector), arg0)
+}
+
+// CreateLogAnomalyDetectorWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) CreateLogAnomalyDetectorWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.CreateLogAnomalyDetectorInput, arg2 ...request.Option) (*cloudwatchlogs.CreateLogAnomalyDetectorOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateLogAnomalyDetectorWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.CreateLogAnomalyDetectorOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLogAnomalyDetectorWithContext indicates an expected call of CreateLogAnomalyDetectorWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateLogAnomalyDetectorWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogAnomalyDetectorWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateLogAnomalyDetectorWithContext), varargs...)
+}
+
+// CreateLogAnomalyDetectorRequest mocks base method
+func (m *MockCloudWatchLogsAPI) CreateLogAnomalyDetectorRequest(arg0 *cloudwatchlogs.CreateLogAnomalyDetectorInput) (*request.Request, *cloudwatchlogs.CreateLogAnomalyDetectorOutput) {
+	ret := m.ctrl.Call(m, "CreateLogAnomalyDetectorRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.CreateLogAnomalyDetectorOutput)
+	return ret0, ret1
+}
+
+// CreateLogAnomalyDetectorRequest indicates an expected call of CreateLogAnomalyDetectorRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateLogAnomalyDetectorRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogAnomalyDetectorRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateLogAnomalyDetectorRequest), arg0)
+}
+
+// CreateLogGroup mocks base method
+func (m *MockCloudWatchLogsAPI) CreateLogGroup(arg0 *cloudwatchlogs.CreateLogGroupInput) (*cloudwatchlogs.CreateLogGroupOutput, error) {
+	ret := m.ctrl.Call(m, "CreateLogGroup", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.CreateLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLogGroup indicates an expected call of CreateLogGroup
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateLogGroup(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogGroup", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateLogGroup), arg0)
+}
+
+// CreateLogGroupWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) CreateLogGroupWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.CreateLogGroupInput, arg2 ...request.Option) (*cloudwatchlogs.CreateLogGroupOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateLogGroupWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.CreateLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLogGroupWithContext indicates an expected call of CreateLogGroupWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateLogGroupWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogGroupWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateLogGroupWithContext), varargs...)
+}
+
+// CreateLogGroupRequest mocks base method
+func (m *MockCloudWatchLogsAPI) CreateLogGroupRequest(arg0 *cloudwatchlogs.CreateLogGroupInput) (*request.Request, *cloudwatchlogs.CreateLogGroupOutput) {
+	ret := m.ctrl.Call(m, "CreateLogGroupRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.CreateLogGroupOutput)
+	return ret0, ret1
+}
+
+// CreateLogGroupRequest indicates an expected call of CreateLogGroupRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateLogGroupRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogGroupRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateLogGroupRequest), arg0)
+}
+
+// CreateLogStream mocks base method
+func (m *MockCloudWatchLogsAPI) CreateLogStream(arg0 *cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	ret := m.ctrl.Call(m, "CreateLogStream", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.CreateLogStreamOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLogStream indicates an expected call of CreateLogStream
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateLogStream(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogStream", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateLogStream), arg0)
+}
+
+// CreateLogStreamWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) CreateLogStreamWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.CreateLogStreamInput, arg2 ...request.Option) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateLogStreamWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.CreateLogStreamOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLogStreamWithContext indicates an expected call of CreateLogStreamWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateLogStreamWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogStreamWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateLogStreamWithContext), varargs...)
+}
+
+// CreateLogStreamRequest mocks base method
+func (m *MockCloudWatchLogsAPI) CreateLogStreamRequest(arg0 *cloudwatchlogs.CreateLogStreamInput) (*request.Request, *cloudwatchlogs.CreateLogStreamOutput) {
+	ret := m.ctrl.Call(m, "CreateLogStreamRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.CreateLogStreamOutput)
+	return ret0, ret1
+}
+
+// CreateLogStreamRequest indicates an expected call of CreateLogStreamRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) CreateLogStreamRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogStreamRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).CreateLogStreamRequest), arg0)
+}
+
+// DeleteAccountPolicy mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteAccountPolicy(arg0 *cloudwatchlogs.DeleteAccountPolicyInput) (*cloudwatchlogs.DeleteAccountPolicyOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteAccountPolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteAccountPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccountPolicy indicates an expected call of DeleteAccountPolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteAccountPolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountPolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteAccountPolicy), arg0)
+}
+
+// DeleteAccountPolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteAccountPolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteAccountPolicyInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteAccountPolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteAccountPolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteAccountPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccountPolicyWithContext indicates an expected call of DeleteAccountPolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteAccountPolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountPolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteAccountPolicyWithContext), varargs...)
+}
+
+// DeleteAccountPolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteAccountPolicyRequest(arg0 *cloudwatchlogs.DeleteAccountPolicyInput) (*request.Request, *cloudwatchlogs.DeleteAccountPolicyOutput) {
+	ret := m.ctrl.Call(m, "DeleteAccountPolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteAccountPolicyOutput)
+	return ret0, ret1
+}
+
+// DeleteAccountPolicyRequest indicates an expected call of DeleteAccountPolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteAccountPolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountPolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteAccountPolicyRequest), arg0)
+}
+
+// DeleteDataProtectionPolicy mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDataProtectionPolicy(arg0 *cloudwatchlogs.DeleteDataProtectionPolicyInput) (*cloudwatchlogs.DeleteDataProtectionPolicyOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteDataProtectionPolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDataProtectionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDataProtectionPolicy indicates an expected call of DeleteDataProtectionPolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDataProtectionPolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataProtectionPolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDataProtectionPolicy), arg0)
+}
+
+// DeleteDataProtectionPolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDataProtectionPolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteDataProtectionPolicyInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteDataProtectionPolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteDataProtectionPolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDataProtectionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDataProtectionPolicyWithContext indicates an expected call of DeleteDataProtectionPolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDataProtectionPolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataProtectionPolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDataProtectionPolicyWithContext), varargs...)
+}
+
+// DeleteDataProtectionPolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDataProtectionPolicyRequest(arg0 *cloudwatchlogs.DeleteDataProtectionPolicyInput) (*request.Request, *cloudwatchlogs.DeleteDataProtectionPolicyOutput) {
+	ret := m.ctrl.Call(m, "DeleteDataProtectionPolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteDataProtectionPolicyOutput)
+	return ret0, ret1
+}
+
+// DeleteDataProtectionPolicyRequest indicates an expected call of DeleteDataProtectionPolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDataProtectionPolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataProtectionPolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDataProtectionPolicyRequest), arg0)
+}
+
+// DeleteDelivery mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDelivery(arg0 *cloudwatchlogs.DeleteDeliveryInput) (*cloudwatchlogs.DeleteDeliveryOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteDelivery", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDeliveryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDelivery indicates an expected call of DeleteDelivery
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDelivery(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDelivery", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDelivery), arg0)
+}
+
+// DeleteDeliveryWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDeliveryWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteDeliveryInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteDeliveryOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteDeliveryWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDeliveryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeliveryWithContext indicates an expected call of DeleteDeliveryWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDeliveryWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliveryWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDeliveryWithContext), varargs...)
+}
+
+// DeleteDeliveryRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDeliveryRequest(arg0 *cloudwatchlogs.DeleteDeliveryInput) (*request.Request, *cloudwatchlogs.DeleteDeliveryOutput) {
+	ret := m.ctrl.Call(m, "DeleteDeliveryRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteDeliveryOutput)
+	return ret0, ret1
+}
+
+// DeleteDeliveryRequest indicates an expected call of DeleteDeliveryRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDeliveryRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliveryRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDeliveryRequest), arg0)
+}
+
+// DeleteDeliveryDestination mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDeliveryDestination(arg0 *cloudwatchlogs.DeleteDeliveryDestinationInput) (*cloudwatchlogs.DeleteDeliveryDestinationOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteDeliveryDestination", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDeliveryDestinationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeliveryDestination indicates an expected call of DeleteDeliveryDestination
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDeliveryDestination(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliveryDestination", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDeliveryDestination), arg0)
+}
+
+// DeleteDeliveryDestinationWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDeliveryDestinationWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteDeliveryDestinationInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteDeliveryDestinationOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteDeliveryDestinationWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDeliveryDestinationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeliveryDestinationWithContext indicates an expected call of DeleteDeliveryDestinationWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDeliveryDestinationWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliveryDestinationWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDeliveryDestinationWithContext), varargs...)
+}
+
+// DeleteDeliveryDestinationRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDeliveryDestinationRequest(arg0 *cloudwatchlogs.DeleteDeliveryDestinationInput) (*request.Request, *cloudwatchlogs.DeleteDeliveryDestinationOutput) {
+	ret := m.ctrl.Call(m, "DeleteDeliveryDestinationRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteDeliveryDestinationOutput)
+	return ret0, ret1
+}
+
+// DeleteDeliveryDestinationRequest indicates an expected call of DeleteDeliveryDestinationRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDeliveryDestinationRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliveryDestinationRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDeliveryDestinationRequest), arg0)
+}
+
+// DeleteDeliveryDestinationPolicy mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDeliveryDestinationPolicy(arg0 *cloudwatchlogs.DeleteDeliveryDestinationPolicyInput) (*cloudwatchlogs.DeleteDeliveryDestinationPolicyOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteDeliveryDestinationPolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDeliveryDestinationPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeliveryDestinationPolicy indicates an expected call of DeleteDeliveryDestinationPolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDeliveryDestinationPolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliveryDestinationPolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDeliveryDestinationPolicy), arg0)
+}
+
+// DeleteDeliveryDestinationPolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDeliveryDestinationPolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteDeliveryDestinationPolicyInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteDeliveryDestinationPolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteDeliveryDestinationPolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDeliveryDestinationPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeliveryDestinationPolicyWithContext indicates an expected call of DeleteDeliveryDestinationPolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDeliveryDestinationPolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliveryDestinationPolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDeliveryDestinationPolicyWithContext), varargs...)
+}
+
+// DeleteDeliveryDestinationPolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDeliveryDestinationPolicyRequest(arg0 *cloudwatchlogs.DeleteDeliveryDestinationPolicyInput) (*request.Request, *cloudwatchlogs.DeleteDeliveryDestinationPolicyOutput) {
+	ret := m.ctrl.Call(m, "DeleteDeliveryDestinationPolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteDeliveryDestinationPolicyOutput)
+	return ret0, ret1
+}
+
+// DeleteDeliveryDestinationPolicyRequest indicates an expected call of DeleteDeliveryDestinationPolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDeliveryDestinationPolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliveryDestinationPolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDeliveryDestinationPolicyRequest), arg0)
+}
+
+// DeleteDeliverySource mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDeliverySource(arg0 *cloudwatchlogs.DeleteDeliverySourceInput) (*cloudwatchlogs.DeleteDeliverySourceOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteDeliverySource", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDeliverySourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeliverySource indicates an expected call of DeleteDeliverySource
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDeliverySource(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliverySource", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDeliverySource), arg0)
+}
+
+// DeleteDeliverySourceWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDeliverySourceWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteDeliverySourceInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteDeliverySourceOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteDeliverySourceWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDeliverySourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeliverySourceWithContext indicates an expected call of DeleteDeliverySourceWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDeliverySourceWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliverySourceWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDeliverySourceWithContext), varargs...)
+}
+
+// DeleteDeliverySourceRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDeliverySourceRequest(arg0 *cloudwatchlogs.DeleteDeliverySourceInput) (*request.Request, *cloudwatchlogs.DeleteDeliverySourceOutput) {
+	ret := m.ctrl.Call(m, "DeleteDeliverySourceRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteDeliverySourceOutput)
+	return ret0, ret1
+}
+
+// DeleteDeliverySourceRequest indicates an expected call of DeleteDeliverySourceRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDeliverySourceRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeliverySourceRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDeliverySourceRequest), arg0)
+}
+
+// DeleteDestination mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDestination(arg0 *cloudwatchlogs.DeleteDestinationInput) (*cloudwatchlogs.DeleteDestinationOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteDestination", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDestinationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDestination indicates an expected call of DeleteDestination
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDestination(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDestination", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDestination), arg0)
+}
+
+// DeleteDestinationWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDestinationWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteDestinationInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteDestinationOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteDestinationWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteDestinationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDestinationWithContext indicates an expected call of DeleteDestinationWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDestinationWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDestinationWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDestinationWithContext), varargs...)
+}
+
+// DeleteDestinationRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteDestinationRequest(arg0 *cloudwatchlogs.DeleteDestinationInput) (*request.Request, *cloudwatchlogs.DeleteDestinationOutput) {
+	ret := m.ctrl.Call(m, "DeleteDestinationRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteDestinationOutput)
+	return ret0, ret1
+}
+
+// DeleteDestinationRequest indicates an expected call of DeleteDestinationRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteDestinationRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDestinationRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteDestinationRequest), arg0)
+}
+
+// DeleteLogAnomalyDetector mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteLogAnomalyDetector(arg0 *cloudwatchlogs.DeleteLogAnomalyDetectorInput) (*cloudwatchlogs.DeleteLogAnomalyDetectorOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteLogAnomalyDetector", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteLogAnomalyDetectorOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLogAnomalyDetector indicates an expected call of DeleteLogAnomalyDetector
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteLogAnomalyDetector(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogAnomalyDetector", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteLogAnomalyDetector), arg0)
+}
+
+// DeleteLogAnomalyDetectorWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteLogAnomalyDetectorWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteLogAnomalyDetectorInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteLogAnomalyDetectorOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteLogAnomalyDetectorWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteLogAnomalyDetectorOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLogAnomalyDetectorWithContext indicates an expected call of DeleteLogAnomalyDetectorWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteLogAnomalyDetectorWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogAnomalyDetectorWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteLogAnomalyDetectorWithContext), varargs...)
+}
+
+// DeleteLogAnomalyDetectorRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteLogAnomalyDetectorRequest(arg0 *cloudwatchlogs.DeleteLogAnomalyDetectorInput) (*request.Request, *cloudwatchlogs.DeleteLogAnomalyDetectorOutput) {
+	ret := m.ctrl.Call(m, "DeleteLogAnomalyDetectorRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteLogAnomalyDetectorOutput)
+	return ret0, ret1
+}
+
+// DeleteLogAnomalyDetectorRequest indicates an expected call of DeleteLogAnomalyDetectorRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteLogAnomalyDetectorRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogAnomalyDetectorRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteLogAnomalyDetectorRequest), arg0)
+}
+
+// DeleteLogGroup mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteLogGroup(arg0 *cloudwatchlogs.DeleteLogGroupInput) (*cloudwatchlogs.DeleteLogGroupOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteLogGroup", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLogGroup indicates an expected call of DeleteLogGroup
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteLogGroup(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogGroup", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteLogGroup), arg0)
+}
+
+// DeleteLogGroupWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteLogGroupWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteLogGroupInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteLogGroupOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteLogGroupWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLogGroupWithContext indicates an expected call of DeleteLogGroupWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteLogGroupWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogGroupWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteLogGroupWithContext), varargs...)
+}
+
+// DeleteLogGroupRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteLogGroupRequest(arg0 *cloudwatchlogs.DeleteLogGroupInput) (*request.Request, *cloudwatchlogs.DeleteLogGroupOutput) {
+	ret := m.ctrl.Call(m, "DeleteLogGroupRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteLogGroupOutput)
+	return ret0, ret1
+}
+
+// DeleteLogGroupRequest indicates an expected call of DeleteLogGroupRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteLogGroupRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogGroupRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteLogGroupRequest), arg0)
+}
+
+// DeleteLogStream mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteLogStream(arg0 *cloudwatchlogs.DeleteLogStreamInput) (*cloudwatchlogs.DeleteLogStreamOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteLogStream", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteLogStreamOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLogStream indicates an expected call of DeleteLogStream
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteLogStream(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogStream", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteLogStream), arg0)
+}
+
+// DeleteLogStreamWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteLogStreamWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteLogStreamInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteLogStreamOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteLogStreamWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteLogStreamOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLogStreamWithContext indicates an expected call of DeleteLogStreamWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteLogStreamWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogStreamWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteLogStreamWithContext), varargs...)
+}
+
+// DeleteLogStreamRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteLogStreamRequest(arg0 *cloudwatchlogs.DeleteLogStreamInput) (*request.Request, *cloudwatchlogs.DeleteLogStreamOutput) {
+	ret := m.ctrl.Call(m, "DeleteLogStreamRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteLogStreamOutput)
+	return ret0, ret1
+}
+
+// DeleteLogStreamRequest indicates an expected call of DeleteLogStreamRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteLogStreamRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogStreamRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteLogStreamRequest), arg0)
+}
+
+// DeleteMetricFilter mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteMetricFilter(arg0 *cloudwatchlogs.DeleteMetricFilterInput) (*cloudwatchlogs.DeleteMetricFilterOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteMetricFilter", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteMetricFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMetricFilter indicates an expected call of DeleteMetricFilter
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteMetricFilter(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMetricFilter", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteMetricFilter), arg0)
+}
+
+// DeleteMetricFilterWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteMetricFilterWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteMetricFilterInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteMetricFilterOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteMetricFilterWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteMetricFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMetricFilterWithContext indicates an expected call of DeleteMetricFilterWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteMetricFilterWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMetricFilterWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteMetricFilterWithContext), varargs...)
+}
+
+// DeleteMetricFilterRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteMetricFilterRequest(arg0 *cloudwatchlogs.DeleteMetricFilterInput) (*request.Request, *cloudwatchlogs.DeleteMetricFilterOutput) {
+	ret := m.ctrl.Call(m, "DeleteMetricFilterRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteMetricFilterOutput)
+	return ret0, ret1
+}
+
+// DeleteMetricFilterRequest indicates an expected call of DeleteMetricFilterRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteMetricFilterRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMetricFilterRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteMetricFilterRequest), arg0)
+}
+
+// DeleteQueryDefinition mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteQueryDefinition(arg0 *cloudwatchlogs.DeleteQueryDefinitionInput) (*cloudwatchlogs.DeleteQueryDefinitionOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteQueryDefinition", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteQueryDefinitionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteQueryDefinition indicates an expected call of DeleteQueryDefinition
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteQueryDefinition(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQueryDefinition", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteQueryDefinition), arg0)
+}
+
+// DeleteQueryDefinitionWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteQueryDefinitionWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteQueryDefinitionInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteQueryDefinitionOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteQueryDefinitionWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteQueryDefinitionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteQueryDefinitionWithContext indicates an expected call of DeleteQueryDefinitionWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteQueryDefinitionWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQueryDefinitionWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteQueryDefinitionWithContext), varargs...)
+}
+
+// DeleteQueryDefinitionRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteQueryDefinitionRequest(arg0 *cloudwatchlogs.DeleteQueryDefinitionInput) (*request.Request, *cloudwatchlogs.DeleteQueryDefinitionOutput) {
+	ret := m.ctrl.Call(m, "DeleteQueryDefinitionRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteQueryDefinitionOutput)
+	return ret0, ret1
+}
+
+// DeleteQueryDefinitionRequest indicates an expected call of DeleteQueryDefinitionRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteQueryDefinitionRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQueryDefinitionRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteQueryDefinitionRequest), arg0)
+}
+
+// DeleteResourcePolicy mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteResourcePolicy(arg0 *cloudwatchlogs.DeleteResourcePolicyInput) (*cloudwatchlogs.DeleteResourcePolicyOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteResourcePolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteResourcePolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteResourcePolicy indicates an expected call of DeleteResourcePolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteResourcePolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResourcePolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteResourcePolicy), arg0)
+}
+
+// DeleteResourcePolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteResourcePolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteResourcePolicyInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteResourcePolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteResourcePolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteResourcePolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteResourcePolicyWithContext indicates an expected call of DeleteResourcePolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteResourcePolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResourcePolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteResourcePolicyWithContext), varargs...)
+}
+
+// DeleteResourcePolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteResourcePolicyRequest(arg0 *cloudwatchlogs.DeleteResourcePolicyInput) (*request.Request, *cloudwatchlogs.DeleteResourcePolicyOutput) {
+	ret := m.ctrl.Call(m, "DeleteResourcePolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteResourcePolicyOutput)
+	return ret0, ret1
+}
+
+// DeleteResourcePolicyRequest indicates an expected call of DeleteResourcePolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteResourcePolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResourcePolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteResourcePolicyRequest), arg0)
+}
+
+// DeleteRetentionPolicy mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteRetentionPolicy(arg0 *cloudwatchlogs.DeleteRetentionPolicyInput) (*cloudwatchlogs.DeleteRetentionPolicyOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteRetentionPolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteRetentionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRetentionPolicy indicates an expected call of DeleteRetentionPolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteRetentionPolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRetentionPolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteRetentionPolicy), arg0)
+}
+
+// DeleteRetentionPolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteRetentionPolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteRetentionPolicyInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteRetentionPolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteRetentionPolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteRetentionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRetentionPolicyWithContext indicates an expected call of DeleteRetentionPolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteRetentionPolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRetentionPolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteRetentionPolicyWithContext), varargs...)
+}
+
+// DeleteRetentionPolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteRetentionPolicyRequest(arg0 *cloudwatchlogs.DeleteRetentionPolicyInput) (*request.Request, *cloudwatchlogs.DeleteRetentionPolicyOutput) {
+	ret := m.ctrl.Call(m, "DeleteRetentionPolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteRetentionPolicyOutput)
+	return ret0, ret1
+}
+
+// DeleteRetentionPolicyRequest indicates an expected call of DeleteRetentionPolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteRetentionPolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRetentionPolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteRetentionPolicyRequest), arg0)
+}
+
+// DeleteSubscriptionFilter mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteSubscriptionFilter(arg0 *cloudwatchlogs.DeleteSubscriptionFilterInput) (*cloudwatchlogs.DeleteSubscriptionFilterOutput, error) {
+	ret := m.ctrl.Call(m, "DeleteSubscriptionFilter", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteSubscriptionFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSubscriptionFilter indicates an expected call of DeleteSubscriptionFilter
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteSubscriptionFilter(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscriptionFilter", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteSubscriptionFilter), arg0)
+}
+
+// DeleteSubscriptionFilterWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteSubscriptionFilterWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DeleteSubscriptionFilterInput, arg2 ...request.Option) (*cloudwatchlogs.DeleteSubscriptionFilterOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteSubscriptionFilterWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteSubscriptionFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSubscriptionFilterWithContext indicates an expected call of DeleteSubscriptionFilterWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteSubscriptionFilterWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscriptionFilterWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteSubscriptionFilterWithContext), varargs...)
+}
+
+// DeleteSubscriptionFilterRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DeleteSubscriptionFilterRequest(arg0 *cloudwatchlogs.DeleteSubscriptionFilterInput) (*request.Request, *cloudwatchlogs.DeleteSubscriptionFilterOutput) {
+	ret := m.ctrl.Call(m, "DeleteSubscriptionFilterRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DeleteSubscriptionFilterOutput)
+	return ret0, ret1
+}
+
+// DeleteSubscriptionFilterRequest indicates an expected call of DeleteSubscriptionFilterRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DeleteSubscriptionFilterRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscriptionFilterRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DeleteSubscriptionFilterRequest), arg0)
+}
+
+// DescribeAccountPolicies mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeAccountPolicies(arg0 *cloudwatchlogs.DescribeAccountPoliciesInput) (*cloudwatchlogs.DescribeAccountPoliciesOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeAccountPolicies", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeAccountPoliciesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAccountPolicies indicates an expected call of DescribeAccountPolicies
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeAccountPolicies(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAccountPolicies", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeAccountPolicies), arg0)
+}
+
+// DescribeAccountPoliciesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeAccountPoliciesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeAccountPoliciesInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeAccountPoliciesOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeAccountPoliciesWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeAccountPoliciesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAccountPoliciesWithContext indicates an expected call of DescribeAccountPoliciesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeAccountPoliciesWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAccountPoliciesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeAccountPoliciesWithContext), varargs...)
+}
+
+// DescribeAccountPoliciesRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeAccountPoliciesRequest(arg0 *cloudwatchlogs.DescribeAccountPoliciesInput) (*request.Request, *cloudwatchlogs.DescribeAccountPoliciesOutput) {
+	ret := m.ctrl.Call(m, "DescribeAccountPoliciesRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeAccountPoliciesOutput)
+	return ret0, ret1
+}
+
+// DescribeAccountPoliciesRequest indicates an expected call of DescribeAccountPoliciesRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeAccountPoliciesRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAccountPoliciesRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeAccountPoliciesRequest), arg0)
+}
+
+// DescribeDeliveries mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliveries(arg0 *cloudwatchlogs.DescribeDeliveriesInput) (*cloudwatchlogs.DescribeDeliveriesOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeDeliveries", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeDeliveriesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDeliveries indicates an expected call of DescribeDeliveries
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliveries(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliveries", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliveries), arg0)
+}
+
+// DescribeDeliveriesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliveriesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeDeliveriesInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeDeliveriesOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDeliveriesWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeDeliveriesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDeliveriesWithContext indicates an expected call of DescribeDeliveriesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliveriesWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliveriesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliveriesWithContext), varargs...)
+}
+
+// DescribeDeliveriesRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliveriesRequest(arg0 *cloudwatchlogs.DescribeDeliveriesInput) (*request.Request, *cloudwatchlogs.DescribeDeliveriesOutput) {
+	ret := m.ctrl.Call(m, "DescribeDeliveriesRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeDeliveriesOutput)
+	return ret0, ret1
+}
+
+// DescribeDeliveriesRequest indicates an expected call of DescribeDeliveriesRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliveriesRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliveriesRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliveriesRequest), arg0)
+}
+
+// DescribeDeliveriesPages mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliveriesPages(arg0 *cloudwatchlogs.DescribeDeliveriesInput, arg1 func(*cloudwatchlogs.DescribeDeliveriesOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "DescribeDeliveriesPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeDeliveriesPages indicates an expected call of DescribeDeliveriesPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliveriesPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliveriesPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliveriesPages), arg0, arg1)
+}
+
+// DescribeDeliveriesPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliveriesPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeDeliveriesInput, arg2 func(*cloudwatchlogs.DescribeDeliveriesOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDeliveriesPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeDeliveriesPagesWithContext indicates an expected call of DescribeDeliveriesPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliveriesPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliveriesPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliveriesPagesWithContext), varargs...)
+}
+
+// DescribeDeliveryDestinations mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliveryDestinations(arg0 *cloudwatchlogs.DescribeDeliveryDestinationsInput) (*cloudwatchlogs.DescribeDeliveryDestinationsOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeDeliveryDestinations", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeDeliveryDestinationsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDeliveryDestinations indicates an expected call of DescribeDeliveryDestinations
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliveryDestinations(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliveryDestinations", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliveryDestinations), arg0)
+}
+
+// DescribeDeliveryDestinationsWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliveryDestinationsWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeDeliveryDestinationsInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeDeliveryDestinationsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDeliveryDestinationsWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeDeliveryDestinationsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDeliveryDestinationsWithContext indicates an expected call of DescribeDeliveryDestinationsWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliveryDestinationsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliveryDestinationsWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliveryDestinationsWithContext), varargs...)
+}
+
+// DescribeDeliveryDestinationsRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliveryDestinationsRequest(arg0 *cloudwatchlogs.DescribeDeliveryDestinationsInput) (*request.Request, *cloudwatchlogs.DescribeDeliveryDestinationsOutput) {
+	ret := m.ctrl.Call(m, "DescribeDeliveryDestinationsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeDeliveryDestinationsOutput)
+	return ret0, ret1
+}
+
+// DescribeDeliveryDestinationsRequest indicates an expected call of DescribeDeliveryDestinationsRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliveryDestinationsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliveryDestinationsRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliveryDestinationsRequest), arg0)
+}
+
+// DescribeDeliveryDestinationsPages mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliveryDestinationsPages(arg0 *cloudwatchlogs.DescribeDeliveryDestinationsInput, arg1 func(*cloudwatchlogs.DescribeDeliveryDestinationsOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "DescribeDeliveryDestinationsPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeDeliveryDestinationsPages indicates an expected call of DescribeDeliveryDestinationsPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliveryDestinationsPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliveryDestinationsPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliveryDestinationsPages), arg0, arg1)
+}
+
+// DescribeDeliveryDestinationsPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliveryDestinationsPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeDeliveryDestinationsInput, arg2 func(*cloudwatchlogs.DescribeDeliveryDestinationsOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDeliveryDestinationsPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeDeliveryDestinationsPagesWithContext indicates an expected call of DescribeDeliveryDestinationsPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliveryDestinationsPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliveryDestinationsPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliveryDestinationsPagesWithContext), varargs...)
+}
+
+// DescribeDeliverySources mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliverySources(arg0 *cloudwatchlogs.DescribeDeliverySourcesInput) (*cloudwatchlogs.DescribeDeliverySourcesOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeDeliverySources", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeDeliverySourcesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDeliverySources indicates an expected call of DescribeDeliverySources
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliverySources(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliverySources", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliverySources), arg0)
+}
+
+// DescribeDeliverySourcesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliverySourcesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeDeliverySourcesInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeDeliverySourcesOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDeliverySourcesWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeDeliverySourcesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDeliverySourcesWithContext indicates an expected call of DescribeDeliverySourcesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliverySourcesWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliverySourcesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliverySourcesWithContext), varargs...)
+}
+
+// DescribeDeliverySourcesRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliverySourcesRequest(arg0 *cloudwatchlogs.DescribeDeliverySourcesInput) (*request.Request, *cloudwatchlogs.DescribeDeliverySourcesOutput) {
+	ret := m.ctrl.Call(m, "DescribeDeliverySourcesRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeDeliverySourcesOutput)
+	return ret0, ret1
+}
+
+// DescribeDeliverySourcesRequest indicates an expected call of DescribeDeliverySourcesRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliverySourcesRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliverySourcesRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliverySourcesRequest), arg0)
+}
+
+// DescribeDeliverySourcesPages mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliverySourcesPages(arg0 *cloudwatchlogs.DescribeDeliverySourcesInput, arg1 func(*cloudwatchlogs.DescribeDeliverySourcesOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "DescribeDeliverySourcesPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeDeliverySourcesPages indicates an expected call of DescribeDeliverySourcesPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliverySourcesPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliverySourcesPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliverySourcesPages), arg0, arg1)
+}
+
+// DescribeDeliverySourcesPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDeliverySourcesPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeDeliverySourcesInput, arg2 func(*cloudwatchlogs.DescribeDeliverySourcesOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDeliverySourcesPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeDeliverySourcesPagesWithContext indicates an expected call of DescribeDeliverySourcesPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDeliverySourcesPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDeliverySourcesPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDeliverySourcesPagesWithContext), varargs...)
+}
+
+// DescribeDestinations mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDestinations(arg0 *cloudwatchlogs.DescribeDestinationsInput) (*cloudwatchlogs.DescribeDestinationsOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeDestinations", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeDestinationsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDestinations indicates an expected call of DescribeDestinations
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDestinations(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDestinations", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDestinations), arg0)
+}
+
+// DescribeDestinationsWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDestinationsWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeDestinationsInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeDestinationsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDestinationsWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeDestinationsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDestinationsWithContext indicates an expected call of DescribeDestinationsWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDestinationsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDestinationsWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDestinationsWithContext), varargs...)
+}
+
+// DescribeDestinationsRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDestinationsRequest(arg0 *cloudwatchlogs.DescribeDestinationsInput) (*request.Request, *cloudwatchlogs.DescribeDestinationsOutput) {
+	ret := m.ctrl.Call(m, "DescribeDestinationsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeDestinationsOutput)
+	return ret0, ret1
+}
+
+// DescribeDestinationsRequest indicates an expected call of DescribeDestinationsRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDestinationsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDestinationsRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDestinationsRequest), arg0)
+}
+
+// DescribeDestinationsPages mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDestinationsPages(arg0 *cloudwatchlogs.DescribeDestinationsInput, arg1 func(*cloudwatchlogs.DescribeDestinationsOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "DescribeDestinationsPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeDestinationsPages indicates an expected call of DescribeDestinationsPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDestinationsPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDestinationsPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDestinationsPages), arg0, arg1)
+}
+
+// DescribeDestinationsPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeDestinationsPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeDestinationsInput, arg2 func(*cloudwatchlogs.DescribeDestinationsOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDestinationsPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeDestinationsPagesWithContext indicates an expected call of DescribeDestinationsPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeDestinationsPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDestinationsPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeDestinationsPagesWithContext), varargs...)
+}
+
+// DescribeExportTasks mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeExportTasks(arg0 *cloudwatchlogs.DescribeExportTasksInput) (*cloudwatchlogs.DescribeExportTasksOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeExportTasks", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeExportTasksOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeExportTasks indicates an expected call of DescribeExportTasks
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeExportTasks(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeExportTasks", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeExportTasks), arg0)
+}
+
+// DescribeExportTasksWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeExportTasksWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeExportTasksInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeExportTasksOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeExportTasksWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeExportTasksOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeExportTasksWithContext indicates an expected call of DescribeExportTasksWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeExportTasksWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeExportTasksWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeExportTasksWithContext), varargs...)
+}
+
+// DescribeExportTasksRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeExportTasksRequest(arg0 *cloudwatchlogs.DescribeExportTasksInput) (*request.Request, *cloudwatchlogs.DescribeExportTasksOutput) {
+	ret := m.ctrl.Call(m, "DescribeExportTasksRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeExportTasksOutput)
+	return ret0, ret1
+}
+
+// DescribeExportTasksRequest indicates an expected call of DescribeExportTasksRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeExportTasksRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeExportTasksRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeExportTasksRequest), arg0)
+}
+
+// DescribeLogGroups mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeLogGroups(arg0 *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeLogGroups", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeLogGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeLogGroups indicates an expected call of DescribeLogGroups
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeLogGroups(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogGroups", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeLogGroups), arg0)
+}
+
+// DescribeLogGroupsWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeLogGroupsWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeLogGroupsInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeLogGroupsWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeLogGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeLogGroupsWithContext indicates an expected call of DescribeLogGroupsWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeLogGroupsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogGroupsWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeLogGroupsWithContext), varargs...)
+}
+
+// DescribeLogGroupsRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeLogGroupsRequest(arg0 *cloudwatchlogs.DescribeLogGroupsInput) (*request.Request, *cloudwatchlogs.DescribeLogGroupsOutput) {
+	ret := m.ctrl.Call(m, "DescribeLogGroupsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeLogGroupsOutput)
+	return ret0, ret1
+}
+
+// DescribeLogGroupsRequest indicates an expected call of DescribeLogGroupsRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeLogGroupsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogGroupsRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeLogGroupsRequest), arg0)
+}
+
+// DescribeLogGroupsPages mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeLogGroupsPages(arg0 *cloudwatchlogs.DescribeLogGroupsInput, arg1 func(*cloudwatchlogs.DescribeLogGroupsOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "DescribeLogGroupsPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeLogGroupsPages indicates an expected call of DescribeLogGroupsPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeLogGroupsPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogGroupsPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeLogGroupsPages), arg0, arg1)
+}
+
+// DescribeLogGroupsPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeLogGroupsPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeLogGroupsInput, arg2 func(*cloudwatchlogs.DescribeLogGroupsOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeLogGroupsPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeLogGroupsPagesWithContext indicates an expected call of DescribeLogGroupsPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeLogGroupsPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogGroupsPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeLogGroupsPagesWithContext), varargs...)
+}
+
+// DescribeLogStreams mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeLogStreams(arg0 *cloudwatchlogs.DescribeLogStreamsInput) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeLogStreams", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeLogStreamsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeLogStreams indicates an expected call of DescribeLogStreams
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeLogStreams(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogStreams", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeLogStreams), arg0)
+}
+
+// DescribeLogStreamsWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeLogStreamsWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeLogStreamsInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeLogStreamsWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeLogStreamsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeLogStreamsWithContext indicates an expected call of DescribeLogStreamsWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeLogStreamsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogStreamsWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeLogStreamsWithContext), varargs...)
+}
+
+// DescribeLogStreamsRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeLogStreamsRequest(arg0 *cloudwatchlogs.DescribeLogStreamsInput) (*request.Request, *cloudwatchlogs.DescribeLogStreamsOutput) {
+	ret := m.ctrl.Call(m, "DescribeLogStreamsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeLogStreamsOutput)
+	return ret0, ret1
+}
+
+// DescribeLogStreamsRequest indicates an expected call of DescribeLogStreamsRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeLogStreamsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogStreamsRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeLogStreamsRequest), arg0)
+}
+
+// DescribeLogStreamsPages mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeLogStreamsPages(arg0 *cloudwatchlogs.DescribeLogStreamsInput, arg1 func(*cloudwatchlogs.DescribeLogStreamsOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "DescribeLogStreamsPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeLogStreamsPages indicates an expected call of DescribeLogStreamsPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeLogStreamsPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogStreamsPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeLogStreamsPages), arg0, arg1)
+}
+
+// DescribeLogStreamsPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeLogStreamsPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeLogStreamsInput, arg2 func(*cloudwatchlogs.DescribeLogStreamsOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeLogStreamsPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeLogStreamsPagesWithContext indicates an expected call of DescribeLogStreamsPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeLogStreamsPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogStreamsPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeLogStreamsPagesWithContext), varargs...)
+}
+
+// DescribeMetricFilters mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeMetricFilters(arg0 *cloudwatchlogs.DescribeMetricFiltersInput) (*cloudwatchlogs.DescribeMetricFiltersOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeMetricFilters", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeMetricFiltersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeMetricFilters indicates an expected call of DescribeMetricFilters
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeMetricFilters(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeMetricFilters", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeMetricFilters), arg0)
+}
+
+// DescribeMetricFiltersWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeMetricFiltersWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeMetricFiltersInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeMetricFiltersOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeMetricFiltersWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeMetricFiltersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeMetricFiltersWithContext indicates an expected call of DescribeMetricFiltersWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeMetricFiltersWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeMetricFiltersWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeMetricFiltersWithContext), varargs...)
+}
+
+// DescribeMetricFiltersRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeMetricFiltersRequest(arg0 *cloudwatchlogs.DescribeMetricFiltersInput) (*request.Request, *cloudwatchlogs.DescribeMetricFiltersOutput) {
+	ret := m.ctrl.Call(m, "DescribeMetricFiltersRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeMetricFiltersOutput)
+	return ret0, ret1
+}
+
+// DescribeMetricFiltersRequest indicates an expected call of DescribeMetricFiltersRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeMetricFiltersRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeMetricFiltersRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeMetricFiltersRequest), arg0)
+}
+
+// DescribeMetricFiltersPages mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeMetricFiltersPages(arg0 *cloudwatchlogs.DescribeMetricFiltersInput, arg1 func(*cloudwatchlogs.DescribeMetricFiltersOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "DescribeMetricFiltersPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeMetricFiltersPages indicates an expected call of DescribeMetricFiltersPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeMetricFiltersPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeMetricFiltersPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeMetricFiltersPages), arg0, arg1)
+}
+
+// DescribeMetricFiltersPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeMetricFiltersPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeMetricFiltersInput, arg2 func(*cloudwatchlogs.DescribeMetricFiltersOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeMetricFiltersPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeMetricFiltersPagesWithContext indicates an expected call of DescribeMetricFiltersPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeMetricFiltersPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeMetricFiltersPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeMetricFiltersPagesWithContext), varargs...)
+}
+
+// DescribeQueries mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeQueries(arg0 *cloudwatchlogs.DescribeQueriesInput) (*cloudwatchlogs.DescribeQueriesOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeQueries", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeQueriesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeQueries indicates an expected call of DescribeQueries
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeQueries(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeQueries", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeQueries), arg0)
+}
+
+// DescribeQueriesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeQueriesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeQueriesInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeQueriesOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeQueriesWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeQueriesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeQueriesWithContext indicates an expected call of DescribeQueriesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeQueriesWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeQueriesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeQueriesWithContext), varargs...)
+}
+
+// DescribeQueriesRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeQueriesRequest(arg0 *cloudwatchlogs.DescribeQueriesInput) (*request.Request, *cloudwatchlogs.DescribeQueriesOutput) {
+	ret := m.ctrl.Call(m, "DescribeQueriesRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeQueriesOutput)
+	return ret0, ret1
+}
+
+// DescribeQueriesRequest indicates an expected call of DescribeQueriesRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeQueriesRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeQueriesRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeQueriesRequest), arg0)
+}
+
+// DescribeQueryDefinitions mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeQueryDefinitions(arg0 *cloudwatchlogs.DescribeQueryDefinitionsInput) (*cloudwatchlogs.DescribeQueryDefinitionsOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeQueryDefinitions", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeQueryDefinitionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeQueryDefinitions indicates an expected call of DescribeQueryDefinitions
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeQueryDefinitions(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeQueryDefinitions", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeQueryDefinitions), arg0)
+}
+
+// DescribeQueryDefinitionsWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeQueryDefinitionsWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeQueryDefinitionsInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeQueryDefinitionsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeQueryDefinitionsWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeQueryDefinitionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeQueryDefinitionsWithContext indicates an expected call of DescribeQueryDefinitionsWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeQueryDefinitionsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeQueryDefinitionsWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeQueryDefinitionsWithContext), varargs...)
+}
+
+// DescribeQueryDefinitionsRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeQueryDefinitionsRequest(arg0 *cloudwatchlogs.DescribeQueryDefinitionsInput) (*request.Request, *cloudwatchlogs.DescribeQueryDefinitionsOutput) {
+	ret := m.ctrl.Call(m, "DescribeQueryDefinitionsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeQueryDefinitionsOutput)
+	return ret0, ret1
+}
+
+// DescribeQueryDefinitionsRequest indicates an expected call of DescribeQueryDefinitionsRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeQueryDefinitionsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeQueryDefinitionsRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeQueryDefinitionsRequest), arg0)
+}
+
+// DescribeResourcePolicies mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeResourcePolicies(arg0 *cloudwatchlogs.DescribeResourcePoliciesInput) (*cloudwatchlogs.DescribeResourcePoliciesOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeResourcePolicies", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeResourcePoliciesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeResourcePolicies indicates an expected call of DescribeResourcePolicies
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeResourcePolicies(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeResourcePolicies", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeResourcePolicies), arg0)
+}
+
+// DescribeResourcePoliciesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeResourcePoliciesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeResourcePoliciesInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeResourcePoliciesOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeResourcePoliciesWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeResourcePoliciesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeResourcePoliciesWithContext indicates an expected call of DescribeResourcePoliciesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeResourcePoliciesWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeResourcePoliciesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeResourcePoliciesWithContext), varargs...)
+}
+
+// DescribeResourcePoliciesRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeResourcePoliciesRequest(arg0 *cloudwatchlogs.DescribeResourcePoliciesInput) (*request.Request, *cloudwatchlogs.DescribeResourcePoliciesOutput) {
+	ret := m.ctrl.Call(m, "DescribeResourcePoliciesRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeResourcePoliciesOutput)
+	return ret0, ret1
+}
+
+// DescribeResourcePoliciesRequest indicates an expected call of DescribeResourcePoliciesRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeResourcePoliciesRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeResourcePoliciesRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeResourcePoliciesRequest), arg0)
+}
+
+// DescribeSubscriptionFilters mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeSubscriptionFilters(arg0 *cloudwatchlogs.DescribeSubscriptionFiltersInput) (*cloudwatchlogs.DescribeSubscriptionFiltersOutput, error) {
+	ret := m.ctrl.Call(m, "DescribeSubscriptionFilters", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeSubscriptionFiltersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeSubscriptionFilters indicates an expected call of DescribeSubscriptionFilters
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeSubscriptionFilters(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSubscriptionFilters", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeSubscriptionFilters), arg0)
+}
+
+// DescribeSubscriptionFiltersWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeSubscriptionFiltersWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeSubscriptionFiltersInput, arg2 ...request.Option) (*cloudwatchlogs.DescribeSubscriptionFiltersOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeSubscriptionFiltersWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeSubscriptionFiltersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeSubscriptionFiltersWithContext indicates an expected call of DescribeSubscriptionFiltersWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeSubscriptionFiltersWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSubscriptionFiltersWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeSubscriptionFiltersWithContext), varargs...)
+}
+
+// DescribeSubscriptionFiltersRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeSubscriptionFiltersRequest(arg0 *cloudwatchlogs.DescribeSubscriptionFiltersInput) (*request.Request, *cloudwatchlogs.DescribeSubscriptionFiltersOutput) {
+	ret := m.ctrl.Call(m, "DescribeSubscriptionFiltersRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DescribeSubscriptionFiltersOutput)
+	return ret0, ret1
+}
+
+// DescribeSubscriptionFiltersRequest indicates an expected call of DescribeSubscriptionFiltersRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeSubscriptionFiltersRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSubscriptionFiltersRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeSubscriptionFiltersRequest), arg0)
+}
+
+// DescribeSubscriptionFiltersPages mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeSubscriptionFiltersPages(arg0 *cloudwatchlogs.DescribeSubscriptionFiltersInput, arg1 func(*cloudwatchlogs.DescribeSubscriptionFiltersOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "DescribeSubscriptionFiltersPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeSubscriptionFiltersPages indicates an expected call of DescribeSubscriptionFiltersPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeSubscriptionFiltersPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSubscriptionFiltersPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeSubscriptionFiltersPages), arg0, arg1)
+}
+
+// DescribeSubscriptionFiltersPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DescribeSubscriptionFiltersPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DescribeSubscriptionFiltersInput, arg2 func(*cloudwatchlogs.DescribeSubscriptionFiltersOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeSubscriptionFiltersPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeSubscriptionFiltersPagesWithContext indicates an expected call of DescribeSubscriptionFiltersPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DescribeSubscriptionFiltersPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSubscriptionFiltersPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DescribeSubscriptionFiltersPagesWithContext), varargs...)
+}
+
+// DisassociateKmsKey mocks base method
+func (m *MockCloudWatchLogsAPI) DisassociateKmsKey(arg0 *cloudwatchlogs.DisassociateKmsKeyInput) (*cloudwatchlogs.DisassociateKmsKeyOutput, error) {
+	ret := m.ctrl.Call(m, "DisassociateKmsKey", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.DisassociateKmsKeyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisassociateKmsKey indicates an expected call of DisassociateKmsKey
+func (mr *MockCloudWatchLogsAPIMockRecorder) DisassociateKmsKey(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisassociateKmsKey", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DisassociateKmsKey), arg0)
+}
+
+// DisassociateKmsKeyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) DisassociateKmsKeyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.DisassociateKmsKeyInput, arg2 ...request.Option) (*cloudwatchlogs.DisassociateKmsKeyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DisassociateKmsKeyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DisassociateKmsKeyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisassociateKmsKeyWithContext indicates an expected call of DisassociateKmsKeyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) DisassociateKmsKeyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisassociateKmsKeyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DisassociateKmsKeyWithContext), varargs...)
+}
+
+// DisassociateKmsKeyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) DisassociateKmsKeyRequest(arg0 *cloudwatchlogs.DisassociateKmsKeyInput) (*request.Request, *cloudwatchlogs.DisassociateKmsKeyOutput) {
+	ret := m.ctrl.Call(m, "DisassociateKmsKeyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.DisassociateKmsKeyOutput)
+	return ret0, ret1
+}
+
+// DisassociateKmsKeyRequest indicates an expected call of DisassociateKmsKeyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) DisassociateKmsKeyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisassociateKmsKeyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).DisassociateKmsKeyRequest), arg0)
+}
+
+// FilterLogEvents mocks base method
+func (m *MockCloudWatchLogsAPI) FilterLogEvents(arg0 *cloudwatchlogs.FilterLogEventsInput) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	ret := m.ctrl.Call(m, "FilterLogEvents", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.FilterLogEventsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterLogEvents indicates an expected call of FilterLogEvents
+func (mr *MockCloudWatchLogsAPIMockRecorder) FilterLogEvents(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterLogEvents", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).FilterLogEvents), arg0)
+}
+
+// FilterLogEventsWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) FilterLogEventsWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.FilterLogEventsInput, arg2 ...request.Option) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FilterLogEventsWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.FilterLogEventsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterLogEventsWithContext indicates an expected call of FilterLogEventsWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) FilterLogEventsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterLogEventsWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).FilterLogEventsWithContext), varargs...)
+}
+
+// FilterLogEventsRequest mocks base method
+func (m *MockCloudWatchLogsAPI) FilterLogEventsRequest(arg0 *cloudwatchlogs.FilterLogEventsInput) (*request.Request, *cloudwatchlogs.FilterLogEventsOutput) {
+	ret := m.ctrl.Call(m, "FilterLogEventsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.FilterLogEventsOutput)
+	return ret0, ret1
+}
+
+// FilterLogEventsRequest indicates an expected call of FilterLogEventsRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) FilterLogEventsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterLogEventsRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).FilterLogEventsRequest), arg0)
+}
+
+// FilterLogEventsPages mocks base method
+func (m *MockCloudWatchLogsAPI) FilterLogEventsPages(arg0 *cloudwatchlogs.FilterLogEventsInput, arg1 func(*cloudwatchlogs.FilterLogEventsOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "FilterLogEventsPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FilterLogEventsPages indicates an expected call of FilterLogEventsPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) FilterLogEventsPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterLogEventsPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).FilterLogEventsPages), arg0, arg1)
+}
+
+// FilterLogEventsPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) FilterLogEventsPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.FilterLogEventsInput, arg2 func(*cloudwatchlogs.FilterLogEventsOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FilterLogEventsPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FilterLogEventsPagesWithContext indicates an expected call of FilterLogEventsPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) FilterLogEventsPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterLogEventsPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).FilterLogEventsPagesWithContext), varargs...)
+}
+
+// GetDataProtectionPolicy mocks base method
+func (m *MockCloudWatchLogsAPI) GetDataProtectionPolicy(arg0 *cloudwatchlogs.GetDataProtectionPolicyInput) (*cloudwatchlogs.GetDataProtectionPolicyOutput, error) {
+	ret := m.ctrl.Call(m, "GetDataProtectionPolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetDataProtectionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDataProtectionPolicy indicates an expected call of GetDataProtectionPolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDataProtectionPolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataProtectionPolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDataProtectionPolicy), arg0)
+}
+
+// GetDataProtectionPolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) GetDataProtectionPolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.GetDataProtectionPolicyInput, arg2 ...request.Option) (*cloudwatchlogs.GetDataProtectionPolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetDataProtectionPolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetDataProtectionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDataProtectionPolicyWithContext indicates an expected call of GetDataProtectionPolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDataProtectionPolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataProtectionPolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDataProtectionPolicyWithContext), varargs...)
+}
+
+// GetDataProtectionPolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) GetDataProtectionPolicyRequest(arg0 *cloudwatchlogs.GetDataProtectionPolicyInput) (*request.Request, *cloudwatchlogs.GetDataProtectionPolicyOutput) {
+	ret := m.ctrl.Call(m, "GetDataProtectionPolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.GetDataProtectionPolicyOutput)
+	return ret0, ret1
+}
+
+// GetDataProtectionPolicyRequest indicates an expected call of GetDataProtectionPolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDataProtectionPolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataProtectionPolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDataProtectionPolicyRequest), arg0)
+}
+
+// GetDelivery mocks base method
+func (m *MockCloudWatchLogsAPI) GetDelivery(arg0 *cloudwatchlogs.GetDeliveryInput) (*cloudwatchlogs.GetDeliveryOutput, error) {
+	ret := m.ctrl.Call(m, "GetDelivery", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetDeliveryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelivery indicates an expected call of GetDelivery
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDelivery(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDelivery), arg0)
+}
+
+// GetDeliveryWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) GetDeliveryWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.GetDeliveryInput, arg2 ...request.Option) (*cloudwatchlogs.GetDeliveryOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetDeliveryWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetDeliveryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryWithContext indicates an expected call of GetDeliveryWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDeliveryWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDeliveryWithContext), varargs...)
+}
+
+// GetDeliveryRequest mocks base method
+func (m *MockCloudWatchLogsAPI) GetDeliveryRequest(arg0 *cloudwatchlogs.GetDeliveryInput) (*request.Request, *cloudwatchlogs.GetDeliveryOutput) {
+	ret := m.ctrl.Call(m, "GetDeliveryRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.GetDeliveryOutput)
+	return ret0, ret1
+}
+
+// GetDeliveryRequest indicates an expected call of GetDeliveryRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDeliveryRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDeliveryRequest), arg0)
+}
+
+// GetDeliveryDestination mocks base method
+func (m *MockCloudWatchLogsAPI) GetDeliveryDestination(arg0 *cloudwatchlogs.GetDeliveryDestinationInput) (*cloudwatchlogs.GetDeliveryDestinationOutput, error) {
+	ret := m.ctrl.Call(m, "GetDeliveryDestination", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetDeliveryDestinationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryDestination indicates an expected call of GetDeliveryDestination
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDeliveryDestination(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryDestination", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDeliveryDestination), arg0)
+}
+
+// GetDeliveryDestinationWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) GetDeliveryDestinationWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.GetDeliveryDestinationInput, arg2 ...request.Option) (*cloudwatchlogs.GetDeliveryDestinationOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetDeliveryDestinationWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetDeliveryDestinationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryDestinationWithContext indicates an expected call of GetDeliveryDestinationWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDeliveryDestinationWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryDestinationWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDeliveryDestinationWithContext), varargs...)
+}
+
+// GetDeliveryDestinationRequest mocks base method
+func (m *MockCloudWatchLogsAPI) GetDeliveryDestinationRequest(arg0 *cloudwatchlogs.GetDeliveryDestinationInput) (*request.Request, *cloudwatchlogs.GetDeliveryDestinationOutput) {
+	ret := m.ctrl.Call(m, "GetDeliveryDestinationRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.GetDeliveryDestinationOutput)
+	return ret0, ret1
+}
+
+// GetDeliveryDestinationRequest indicates an expected call of GetDeliveryDestinationRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDeliveryDestinationRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryDestinationRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDeliveryDestinationRequest), arg0)
+}
+
+// GetDeliveryDestinationPolicy mocks base method
+func (m *MockCloudWatchLogsAPI) GetDeliveryDestinationPolicy(arg0 *cloudwatchlogs.GetDeliveryDestinationPolicyInput) (*cloudwatchlogs.GetDeliveryDestinationPolicyOutput, error) {
+	ret := m.ctrl.Call(m, "GetDeliveryDestinationPolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetDeliveryDestinationPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryDestinationPolicy indicates an expected call of GetDeliveryDestinationPolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDeliveryDestinationPolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryDestinationPolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDeliveryDestinationPolicy), arg0)
+}
+
+// GetDeliveryDestinationPolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) GetDeliveryDestinationPolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.GetDeliveryDestinationPolicyInput, arg2 ...request.Option) (*cloudwatchlogs.GetDeliveryDestinationPolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetDeliveryDestinationPolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetDeliveryDestinationPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryDestinationPolicyWithContext indicates an expected call of GetDeliveryDestinationPolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDeliveryDestinationPolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryDestinationPolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDeliveryDestinationPolicyWithContext), varargs...)
+}
+
+// GetDeliveryDestinationPolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) GetDeliveryDestinationPolicyRequest(arg0 *cloudwatchlogs.GetDeliveryDestinationPolicyInput) (*request.Request, *cloudwatchlogs.GetDeliveryDestinationPolicyOutput) {
+	ret := m.ctrl.Call(m, "GetDeliveryDestinationPolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.GetDeliveryDestinationPolicyOutput)
+	return ret0, ret1
+}
+
+// GetDeliveryDestinationPolicyRequest indicates an expected call of GetDeliveryDestinationPolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDeliveryDestinationPolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryDestinationPolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDeliveryDestinationPolicyRequest), arg0)
+}
+
+// GetDeliverySource mocks base method
+func (m *MockCloudWatchLogsAPI) GetDeliverySource(arg0 *cloudwatchlogs.GetDeliverySourceInput) (*cloudwatchlogs.GetDeliverySourceOutput, error) {
+	ret := m.ctrl.Call(m, "GetDeliverySource", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetDeliverySourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliverySource indicates an expected call of GetDeliverySource
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDeliverySource(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliverySource", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDeliverySource), arg0)
+}
+
+// GetDeliverySourceWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) GetDeliverySourceWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.GetDeliverySourceInput, arg2 ...request.Option) (*cloudwatchlogs.GetDeliverySourceOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetDeliverySourceWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetDeliverySourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliverySourceWithContext indicates an expected call of GetDeliverySourceWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDeliverySourceWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliverySourceWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDeliverySourceWithContext), varargs...)
+}
+
+// GetDeliverySourceRequest mocks base method
+func (m *MockCloudWatchLogsAPI) GetDeliverySourceRequest(arg0 *cloudwatchlogs.GetDeliverySourceInput) (*request.Request, *cloudwatchlogs.GetDeliverySourceOutput) {
+	ret := m.ctrl.Call(m, "GetDeliverySourceRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.GetDeliverySourceOutput)
+	return ret0, ret1
+}
+
+// GetDeliverySourceRequest indicates an expected call of GetDeliverySourceRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetDeliverySourceRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliverySourceRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetDeliverySourceRequest), arg0)
+}
+
+// GetLogAnomalyDetector mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogAnomalyDetector(arg0 *cloudwatchlogs.GetLogAnomalyDetectorInput) (*cloudwatchlogs.GetLogAnomalyDetectorOutput, error) {
+	ret := m.ctrl.Call(m, "GetLogAnomalyDetector", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetLogAnomalyDetectorOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogAnomalyDetector indicates an expected call of GetLogAnomalyDetector
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogAnomalyDetector(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogAnomalyDetector", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogAnomalyDetector), arg0)
+}
+
+// GetLogAnomalyDetectorWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogAnomalyDetectorWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.GetLogAnomalyDetectorInput, arg2 ...request.Option) (*cloudwatchlogs.GetLogAnomalyDetectorOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetLogAnomalyDetectorWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetLogAnomalyDetectorOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogAnomalyDetectorWithContext indicates an expected call of GetLogAnomalyDetectorWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogAnomalyDetectorWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogAnomalyDetectorWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogAnomalyDetectorWithContext), varargs...)
+}
+
+// GetLogAnomalyDetectorRequest mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogAnomalyDetectorRequest(arg0 *cloudwatchlogs.GetLogAnomalyDetectorInput) (*request.Request, *cloudwatchlogs.GetLogAnomalyDetectorOutput) {
+	ret := m.ctrl.Call(m, "GetLogAnomalyDetectorRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.GetLogAnomalyDetectorOutput)
+	return ret0, ret1
+}
+
+// GetLogAnomalyDetectorRequest indicates an expected call of GetLogAnomalyDetectorRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogAnomalyDetectorRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogAnomalyDetectorRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogAnomalyDetectorRequest), arg0)
+}
+
+// GetLogEvents mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogEvents(arg0 *cloudwatchlogs.GetLogEventsInput) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	ret := m.ctrl.Call(m, "GetLogEvents", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetLogEventsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogEvents indicates an expected call of GetLogEvents
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogEvents(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogEvents", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogEvents), arg0)
+}
+
+// GetLogEventsWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogEventsWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.GetLogEventsInput, arg2 ...request.Option) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetLogEventsWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetLogEventsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogEventsWithContext indicates an expected call of GetLogEventsWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogEventsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogEventsWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogEventsWithContext), varargs...)
+}
+
+// GetLogEventsRequest mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogEventsRequest(arg0 *cloudwatchlogs.GetLogEventsInput) (*request.Request, *cloudwatchlogs.GetLogEventsOutput) {
+	ret := m.ctrl.Call(m, "GetLogEventsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.GetLogEventsOutput)
+	return ret0, ret1
+}
+
+// GetLogEventsRequest indicates an expected call of GetLogEventsRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogEventsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogEventsRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogEventsRequest), arg0)
+}
+
+// GetLogEventsPages mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogEventsPages(arg0 *cloudwatchlogs.GetLogEventsInput, arg1 func(*cloudwatchlogs.GetLogEventsOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "GetLogEventsPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetLogEventsPages indicates an expected call of GetLogEventsPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogEventsPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogEventsPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogEventsPages), arg0, arg1)
+}
+
+// GetLogEventsPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogEventsPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.GetLogEventsInput, arg2 func(*cloudwatchlogs.GetLogEventsOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetLogEventsPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetLogEventsPagesWithContext indicates an expected call of GetLogEventsPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogEventsPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogEventsPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogEventsPagesWithContext), varargs...)
+}
+
+// GetLogGroupFields mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogGroupFields(arg0 *cloudwatchlogs.GetLogGroupFieldsInput) (*cloudwatchlogs.GetLogGroupFieldsOutput, error) {
+	ret := m.ctrl.Call(m, "GetLogGroupFields", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetLogGroupFieldsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogGroupFields indicates an expected call of GetLogGroupFields
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogGroupFields(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogGroupFields", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogGroupFields), arg0)
+}
+
+// GetLogGroupFieldsWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogGroupFieldsWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.GetLogGroupFieldsInput, arg2 ...request.Option) (*cloudwatchlogs.GetLogGroupFieldsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetLogGroupFieldsWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetLogGroupFieldsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogGroupFieldsWithContext indicates an expected call of GetLogGroupFieldsWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogGroupFieldsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogGroupFieldsWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogGroupFieldsWithContext), varargs...)
+}
+
+// GetLogGroupFieldsRequest mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogGroupFieldsRequest(arg0 *cloudwatchlogs.GetLogGroupFieldsInput) (*request.Request, *cloudwatchlogs.GetLogGroupFieldsOutput) {
+	ret := m.ctrl.Call(m, "GetLogGroupFieldsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.GetLogGroupFieldsOutput)
+	return ret0, ret1
+}
+
+// GetLogGroupFieldsRequest indicates an expected call of GetLogGroupFieldsRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogGroupFieldsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogGroupFieldsRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogGroupFieldsRequest), arg0)
+}
+
+// GetLogRecord mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogRecord(arg0 *cloudwatchlogs.GetLogRecordInput) (*cloudwatchlogs.GetLogRecordOutput, error) {
+	ret := m.ctrl.Call(m, "GetLogRecord", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetLogRecordOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogRecord indicates an expected call of GetLogRecord
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogRecord(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogRecord", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogRecord), arg0)
+}
+
+// GetLogRecordWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogRecordWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.GetLogRecordInput, arg2 ...request.Option) (*cloudwatchlogs.GetLogRecordOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetLogRecordWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetLogRecordOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogRecordWithContext indicates an expected call of GetLogRecordWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogRecordWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogRecordWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogRecordWithContext), varargs...)
+}
+
+// GetLogRecordRequest mocks base method
+func (m *MockCloudWatchLogsAPI) GetLogRecordRequest(arg0 *cloudwatchlogs.GetLogRecordInput) (*request.Request, *cloudwatchlogs.GetLogRecordOutput) {
+	ret := m.ctrl.Call(m, "GetLogRecordRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.GetLogRecordOutput)
+	return ret0, ret1
+}
+
+// GetLogRecordRequest indicates an expected call of GetLogRecordRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetLogRecordRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogRecordRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetLogRecordRequest), arg0)
+}
+
+// GetQueryResults mocks base method
+func (m *MockCloudWatchLogsAPI) GetQueryResults(arg0 *cloudwatchlogs.GetQueryResultsInput) (*cloudwatchlogs.GetQueryResultsOutput, error) {
+	ret := m.ctrl.Call(m, "GetQueryResults", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetQueryResultsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueryResults indicates an expected call of GetQueryResults
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetQueryResults(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueryResults", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetQueryResults), arg0)
+}
+
+// GetQueryResultsWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) GetQueryResultsWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.GetQueryResultsInput, arg2 ...request.Option) (*cloudwatchlogs.GetQueryResultsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetQueryResultsWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.GetQueryResultsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueryResultsWithContext indicates an expected call of GetQueryResultsWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetQueryResultsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueryResultsWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetQueryResultsWithContext), varargs...)
+}
+
+// GetQueryResultsRequest mocks base method
+func (m *MockCloudWatchLogsAPI) GetQueryResultsRequest(arg0 *cloudwatchlogs.GetQueryResultsInput) (*request.Request, *cloudwatchlogs.GetQueryResultsOutput) {
+	ret := m.ctrl.Call(m, "GetQueryResultsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.GetQueryResultsOutput)
+	return ret0, ret1
+}
+
+// GetQueryResultsRequest indicates an expected call of GetQueryResultsRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) GetQueryResultsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueryResultsRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).GetQueryResultsRequest), arg0)
+}
+
+// ListAnomalies mocks base method
+func (m *MockCloudWatchLogsAPI) ListAnomalies(arg0 *cloudwatchlogs.ListAnomaliesInput) (*cloudwatchlogs.ListAnomaliesOutput, error) {
+	ret := m.ctrl.Call(m, "ListAnomalies", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.ListAnomaliesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAnomalies indicates an expected call of ListAnomalies
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListAnomalies(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnomalies", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListAnomalies), arg0)
+}
+
+// ListAnomaliesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) ListAnomaliesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.ListAnomaliesInput, arg2 ...request.Option) (*cloudwatchlogs.ListAnomaliesOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAnomaliesWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.ListAnomaliesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAnomaliesWithContext indicates an expected call of ListAnomaliesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListAnomaliesWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnomaliesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListAnomaliesWithContext), varargs...)
+}
+
+// ListAnomaliesRequest mocks base method
+func (m *MockCloudWatchLogsAPI) ListAnomaliesRequest(arg0 *cloudwatchlogs.ListAnomaliesInput) (*request.Request, *cloudwatchlogs.ListAnomaliesOutput) {
+	ret := m.ctrl.Call(m, "ListAnomaliesRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.ListAnomaliesOutput)
+	return ret0, ret1
+}
+
+// ListAnomaliesRequest indicates an expected call of ListAnomaliesRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListAnomaliesRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnomaliesRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListAnomaliesRequest), arg0)
+}
+
+// ListAnomaliesPages mocks base method
+func (m *MockCloudWatchLogsAPI) ListAnomaliesPages(arg0 *cloudwatchlogs.ListAnomaliesInput, arg1 func(*cloudwatchlogs.ListAnomaliesOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "ListAnomaliesPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListAnomaliesPages indicates an expected call of ListAnomaliesPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListAnomaliesPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnomaliesPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListAnomaliesPages), arg0, arg1)
+}
+
+// ListAnomaliesPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) ListAnomaliesPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.ListAnomaliesInput, arg2 func(*cloudwatchlogs.ListAnomaliesOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAnomaliesPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListAnomaliesPagesWithContext indicates an expected call of ListAnomaliesPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListAnomaliesPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnomaliesPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListAnomaliesPagesWithContext), varargs...)
+}
+
+// ListLogAnomalyDetectors mocks base method
+func (m *MockCloudWatchLogsAPI) ListLogAnomalyDetectors(arg0 *cloudwatchlogs.ListLogAnomalyDetectorsInput) (*cloudwatchlogs.ListLogAnomalyDetectorsOutput, error) {
+	ret := m.ctrl.Call(m, "ListLogAnomalyDetectors", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.ListLogAnomalyDetectorsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLogAnomalyDetectors indicates an expected call of ListLogAnomalyDetectors
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListLogAnomalyDetectors(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLogAnomalyDetectors", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListLogAnomalyDetectors), arg0)
+}
+
+// ListLogAnomalyDetectorsWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) ListLogAnomalyDetectorsWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.ListLogAnomalyDetectorsInput, arg2 ...request.Option) (*cloudwatchlogs.ListLogAnomalyDetectorsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListLogAnomalyDetectorsWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.ListLogAnomalyDetectorsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLogAnomalyDetectorsWithContext indicates an expected call of ListLogAnomalyDetectorsWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListLogAnomalyDetectorsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLogAnomalyDetectorsWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListLogAnomalyDetectorsWithContext), varargs...)
+}
+
+// ListLogAnomalyDetectorsRequest mocks base method
+func (m *MockCloudWatchLogsAPI) ListLogAnomalyDetectorsRequest(arg0 *cloudwatchlogs.ListLogAnomalyDetectorsInput) (*request.Request, *cloudwatchlogs.ListLogAnomalyDetectorsOutput) {
+	ret := m.ctrl.Call(m, "ListLogAnomalyDetectorsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.ListLogAnomalyDetectorsOutput)
+	return ret0, ret1
+}
+
+// ListLogAnomalyDetectorsRequest indicates an expected call of ListLogAnomalyDetectorsRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListLogAnomalyDetectorsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLogAnomalyDetectorsRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListLogAnomalyDetectorsRequest), arg0)
+}
+
+// ListLogAnomalyDetectorsPages mocks base method
+func (m *MockCloudWatchLogsAPI) ListLogAnomalyDetectorsPages(arg0 *cloudwatchlogs.ListLogAnomalyDetectorsInput, arg1 func(*cloudwatchlogs.ListLogAnomalyDetectorsOutput, bool) bool) error {
+	ret := m.ctrl.Call(m, "ListLogAnomalyDetectorsPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListLogAnomalyDetectorsPages indicates an expected call of ListLogAnomalyDetectorsPages
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListLogAnomalyDetectorsPages(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLogAnomalyDetectorsPages", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListLogAnomalyDetectorsPages), arg0, arg1)
+}
+
+// ListLogAnomalyDetectorsPagesWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) ListLogAnomalyDetectorsPagesWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.ListLogAnomalyDetectorsInput, arg2 func(*cloudwatchlogs.ListLogAnomalyDetectorsOutput, bool) bool, arg3 ...request.Option) error {
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListLogAnomalyDetectorsPagesWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListLogAnomalyDetectorsPagesWithContext indicates an expected call of ListLogAnomalyDetectorsPagesWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListLogAnomalyDetectorsPagesWithContext(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLogAnomalyDetectorsPagesWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListLogAnomalyDetectorsPagesWithContext), varargs...)
+}
+
+// ListTagsForResource mocks base method
+func (m *MockCloudWatchLogsAPI) ListTagsForResource(arg0 *cloudwatchlogs.ListTagsForResourceInput) (*cloudwatchlogs.ListTagsForResourceOutput, error) {
+	ret := m.ctrl.Call(m, "ListTagsForResource", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.ListTagsForResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsForResource indicates an expected call of ListTagsForResource
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListTagsForResource(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsForResource", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListTagsForResource), arg0)
+}
+
+// ListTagsForResourceWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) ListTagsForResourceWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.ListTagsForResourceInput, arg2 ...request.Option) (*cloudwatchlogs.ListTagsForResourceOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTagsForResourceWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.ListTagsForResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsForResourceWithContext indicates an expected call of ListTagsForResourceWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListTagsForResourceWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsForResourceWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListTagsForResourceWithContext), varargs...)
+}
+
+// ListTagsForResourceRequest mocks base method
+func (m *MockCloudWatchLogsAPI) ListTagsForResourceRequest(arg0 *cloudwatchlogs.ListTagsForResourceInput) (*request.Request, *cloudwatchlogs.ListTagsForResourceOutput) {
+	ret := m.ctrl.Call(m, "ListTagsForResourceRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.ListTagsForResourceOutput)
+	return ret0, ret1
+}
+
+// ListTagsForResourceRequest indicates an expected call of ListTagsForResourceRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListTagsForResourceRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsForResourceRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListTagsForResourceRequest), arg0)
+}
+
+// ListTagsLogGroup mocks base method
+func (m *MockCloudWatchLogsAPI) ListTagsLogGroup(arg0 *cloudwatchlogs.ListTagsLogGroupInput) (*cloudwatchlogs.ListTagsLogGroupOutput, error) {
+	ret := m.ctrl.Call(m, "ListTagsLogGroup", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.ListTagsLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsLogGroup indicates an expected call of ListTagsLogGroup
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListTagsLogGroup(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsLogGroup", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListTagsLogGroup), arg0)
+}
+
+// ListTagsLogGroupWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) ListTagsLogGroupWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.ListTagsLogGroupInput, arg2 ...request.Option) (*cloudwatchlogs.ListTagsLogGroupOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTagsLogGroupWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.ListTagsLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsLogGroupWithContext indicates an expected call of ListTagsLogGroupWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListTagsLogGroupWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsLogGroupWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListTagsLogGroupWithContext), varargs...)
+}
+
+// ListTagsLogGroupRequest mocks base method
+func (m *MockCloudWatchLogsAPI) ListTagsLogGroupRequest(arg0 *cloudwatchlogs.ListTagsLogGroupInput) (*request.Request, *cloudwatchlogs.ListTagsLogGroupOutput) {
+	ret := m.ctrl.Call(m, "ListTagsLogGroupRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.ListTagsLogGroupOutput)
+	return ret0, ret1
+}
+
+// ListTagsLogGroupRequest indicates an expected call of ListTagsLogGroupRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) ListTagsLogGroupRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsLogGroupRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).ListTagsLogGroupRequest), arg0)
+}
+
+// PutAccountPolicy mocks base method
+func (m *MockCloudWatchLogsAPI) PutAccountPolicy(arg0 *cloudwatchlogs.PutAccountPolicyInput) (*cloudwatchlogs.PutAccountPolicyOutput, error) {
+	ret := m.ctrl.Call(m, "PutAccountPolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutAccountPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutAccountPolicy indicates an expected call of PutAccountPolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutAccountPolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAccountPolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutAccountPolicy), arg0)
+}
+
+// PutAccountPolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutAccountPolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutAccountPolicyInput, arg2 ...request.Option) (*cloudwatchlogs.PutAccountPolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutAccountPolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutAccountPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutAccountPolicyWithContext indicates an expected call of PutAccountPolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutAccountPolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAccountPolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutAccountPolicyWithContext), varargs...)
+}
+
+// PutAccountPolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutAccountPolicyRequest(arg0 *cloudwatchlogs.PutAccountPolicyInput) (*request.Request, *cloudwatchlogs.PutAccountPolicyOutput) {
+	ret := m.ctrl.Call(m, "PutAccountPolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutAccountPolicyOutput)
+	return ret0, ret1
+}
+
+// PutAccountPolicyRequest indicates an expected call of PutAccountPolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutAccountPolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAccountPolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutAccountPolicyRequest), arg0)
+}
+
+// PutDataProtectionPolicy mocks base method
+func (m *MockCloudWatchLogsAPI) PutDataProtectionPolicy(arg0 *cloudwatchlogs.PutDataProtectionPolicyInput) (*cloudwatchlogs.PutDataProtectionPolicyOutput, error) {
+	ret := m.ctrl.Call(m, "PutDataProtectionPolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDataProtectionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDataProtectionPolicy indicates an expected call of PutDataProtectionPolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDataProtectionPolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDataProtectionPolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDataProtectionPolicy), arg0)
+}
+
+// PutDataProtectionPolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutDataProtectionPolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutDataProtectionPolicyInput, arg2 ...request.Option) (*cloudwatchlogs.PutDataProtectionPolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutDataProtectionPolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDataProtectionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDataProtectionPolicyWithContext indicates an expected call of PutDataProtectionPolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDataProtectionPolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDataProtectionPolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDataProtectionPolicyWithContext), varargs...)
+}
+
+// PutDataProtectionPolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutDataProtectionPolicyRequest(arg0 *cloudwatchlogs.PutDataProtectionPolicyInput) (*request.Request, *cloudwatchlogs.PutDataProtectionPolicyOutput) {
+	ret := m.ctrl.Call(m, "PutDataProtectionPolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutDataProtectionPolicyOutput)
+	return ret0, ret1
+}
+
+// PutDataProtectionPolicyRequest indicates an expected call of PutDataProtectionPolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDataProtectionPolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDataProtectionPolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDataProtectionPolicyRequest), arg0)
+}
+
+// PutDeliveryDestination mocks base method
+func (m *MockCloudWatchLogsAPI) PutDeliveryDestination(arg0 *cloudwatchlogs.PutDeliveryDestinationInput) (*cloudwatchlogs.PutDeliveryDestinationOutput, error) {
+	ret := m.ctrl.Call(m, "PutDeliveryDestination", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDeliveryDestinationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDeliveryDestination indicates an expected call of PutDeliveryDestination
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDeliveryDestination(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDeliveryDestination", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDeliveryDestination), arg0)
+}
+
+// PutDeliveryDestinationWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutDeliveryDestinationWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutDeliveryDestinationInput, arg2 ...request.Option) (*cloudwatchlogs.PutDeliveryDestinationOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutDeliveryDestinationWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDeliveryDestinationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDeliveryDestinationWithContext indicates an expected call of PutDeliveryDestinationWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDeliveryDestinationWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDeliveryDestinationWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDeliveryDestinationWithContext), varargs...)
+}
+
+// PutDeliveryDestinationRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutDeliveryDestinationRequest(arg0 *cloudwatchlogs.PutDeliveryDestinationInput) (*request.Request, *cloudwatchlogs.PutDeliveryDestinationOutput) {
+	ret := m.ctrl.Call(m, "PutDeliveryDestinationRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutDeliveryDestinationOutput)
+	return ret0, ret1
+}
+
+// PutDeliveryDestinationRequest indicates an expected call of PutDeliveryDestinationRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDeliveryDestinationRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDeliveryDestinationRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDeliveryDestinationRequest), arg0)
+}
+
+// PutDeliveryDestinationPolicy mocks base method
+func (m *MockCloudWatchLogsAPI) PutDeliveryDestinationPolicy(arg0 *cloudwatchlogs.PutDeliveryDestinationPolicyInput) (*cloudwatchlogs.PutDeliveryDestinationPolicyOutput, error) {
+	ret := m.ctrl.Call(m, "PutDeliveryDestinationPolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDeliveryDestinationPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDeliveryDestinationPolicy indicates an expected call of PutDeliveryDestinationPolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDeliveryDestinationPolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDeliveryDestinationPolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDeliveryDestinationPolicy), arg0)
+}
+
+// PutDeliveryDestinationPolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutDeliveryDestinationPolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutDeliveryDestinationPolicyInput, arg2 ...request.Option) (*cloudwatchlogs.PutDeliveryDestinationPolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutDeliveryDestinationPolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDeliveryDestinationPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDeliveryDestinationPolicyWithContext indicates an expected call of PutDeliveryDestinationPolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDeliveryDestinationPolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDeliveryDestinationPolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDeliveryDestinationPolicyWithContext), varargs...)
+}
+
+// PutDeliveryDestinationPolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutDeliveryDestinationPolicyRequest(arg0 *cloudwatchlogs.PutDeliveryDestinationPolicyInput) (*request.Request, *cloudwatchlogs.PutDeliveryDestinationPolicyOutput) {
+	ret := m.ctrl.Call(m, "PutDeliveryDestinationPolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutDeliveryDestinationPolicyOutput)
+	return ret0, ret1
+}
+
+// PutDeliveryDestinationPolicyRequest indicates an expected call of PutDeliveryDestinationPolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDeliveryDestinationPolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDeliveryDestinationPolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDeliveryDestinationPolicyRequest), arg0)
+}
+
+// PutDeliverySource mocks base method
+func (m *MockCloudWatchLogsAPI) PutDeliverySource(arg0 *cloudwatchlogs.PutDeliverySourceInput) (*cloudwatchlogs.PutDeliverySourceOutput, error) {
+	ret := m.ctrl.Call(m, "PutDeliverySource", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDeliverySourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDeliverySource indicates an expected call of PutDeliverySource
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDeliverySource(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDeliverySource", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDeliverySource), arg0)
+}
+
+// PutDeliverySourceWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutDeliverySourceWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutDeliverySourceInput, arg2 ...request.Option) (*cloudwatchlogs.PutDeliverySourceOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutDeliverySourceWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDeliverySourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDeliverySourceWithContext indicates an expected call of PutDeliverySourceWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDeliverySourceWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDeliverySourceWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDeliverySourceWithContext), varargs...)
+}
+
+// PutDeliverySourceRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutDeliverySourceRequest(arg0 *cloudwatchlogs.PutDeliverySourceInput) (*request.Request, *cloudwatchlogs.PutDeliverySourceOutput) {
+	ret := m.ctrl.Call(m, "PutDeliverySourceRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutDeliverySourceOutput)
+	return ret0, ret1
+}
+
+// PutDeliverySourceRequest indicates an expected call of PutDeliverySourceRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDeliverySourceRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDeliverySourceRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDeliverySourceRequest), arg0)
+}
+
+// PutDestination mocks base method
+func (m *MockCloudWatchLogsAPI) PutDestination(arg0 *cloudwatchlogs.PutDestinationInput) (*cloudwatchlogs.PutDestinationOutput, error) {
+	ret := m.ctrl.Call(m, "PutDestination", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDestinationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDestination indicates an expected call of PutDestination
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDestination(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDestination", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDestination), arg0)
+}
+
+// PutDestinationWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutDestinationWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutDestinationInput, arg2 ...request.Option) (*cloudwatchlogs.PutDestinationOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutDestinationWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDestinationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDestinationWithContext indicates an expected call of PutDestinationWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDestinationWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDestinationWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDestinationWithContext), varargs...)
+}
+
+// PutDestinationRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutDestinationRequest(arg0 *cloudwatchlogs.PutDestinationInput) (*request.Request, *cloudwatchlogs.PutDestinationOutput) {
+	ret := m.ctrl.Call(m, "PutDestinationRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutDestinationOutput)
+	return ret0, ret1
+}
+
+// PutDestinationRequest indicates an expected call of PutDestinationRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDestinationRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDestinationRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDestinationRequest), arg0)
+}
+
+// PutDestinationPolicy mocks base method
+func (m *MockCloudWatchLogsAPI) PutDestinationPolicy(arg0 *cloudwatchlogs.PutDestinationPolicyInput) (*cloudwatchlogs.PutDestinationPolicyOutput, error) {
+	ret := m.ctrl.Call(m, "PutDestinationPolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDestinationPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDestinationPolicy indicates an expected call of PutDestinationPolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDestinationPolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDestinationPolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDestinationPolicy), arg0)
+}
+
+// PutDestinationPolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutDestinationPolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutDestinationPolicyInput, arg2 ...request.Option) (*cloudwatchlogs.PutDestinationPolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutDestinationPolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutDestinationPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutDestinationPolicyWithContext indicates an expected call of PutDestinationPolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDestinationPolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDestinationPolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDestinationPolicyWithContext), varargs...)
+}
+
+// PutDestinationPolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutDestinationPolicyRequest(arg0 *cloudwatchlogs.PutDestinationPolicyInput) (*request.Request, *cloudwatchlogs.PutDestinationPolicyOutput) {
+	ret := m.ctrl.Call(m, "PutDestinationPolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutDestinationPolicyOutput)
+	return ret0, ret1
+}
+
+// PutDestinationPolicyRequest indicates an expected call of PutDestinationPolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutDestinationPolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDestinationPolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutDestinationPolicyRequest), arg0)
+}
+
+// PutLogEvents mocks base method
+func (m *MockCloudWatchLogsAPI) PutLogEvents(arg0 *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	ret := m.ctrl.Call(m, "PutLogEvents", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutLogEventsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutLogEvents indicates an expected call of PutLogEvents
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutLogEvents(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutLogEvents", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutLogEvents), arg0)
+}
+
+// PutLogEventsWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutLogEventsWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutLogEventsInput, arg2 ...request.Option) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutLogEventsWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutLogEventsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutLogEventsWithContext indicates an expected call of PutLogEventsWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutLogEventsWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutLogEventsWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutLogEventsWithContext), varargs...)
+}
+
+// PutLogEventsRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutLogEventsRequest(arg0 *cloudwatchlogs.PutLogEventsInput) (*request.Request, *cloudwatchlogs.PutLogEventsOutput) {
+	ret := m.ctrl.Call(m, "PutLogEventsRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutLogEventsOutput)
+	return ret0, ret1
+}
+
+// PutLogEventsRequest indicates an expected call of PutLogEventsRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutLogEventsRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutLogEventsRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutLogEventsRequest), arg0)
+}
+
+// PutMetricFilter mocks base method
+func (m *MockCloudWatchLogsAPI) PutMetricFilter(arg0 *cloudwatchlogs.PutMetricFilterInput) (*cloudwatchlogs.PutMetricFilterOutput, error) {
+	ret := m.ctrl.Call(m, "PutMetricFilter", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutMetricFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutMetricFilter indicates an expected call of PutMetricFilter
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutMetricFilter(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutMetricFilter", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutMetricFilter), arg0)
+}
+
+// PutMetricFilterWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutMetricFilterWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutMetricFilterInput, arg2 ...request.Option) (*cloudwatchlogs.PutMetricFilterOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutMetricFilterWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutMetricFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutMetricFilterWithContext indicates an expected call of PutMetricFilterWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutMetricFilterWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutMetricFilterWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutMetricFilterWithContext), varargs...)
+}
+
+// PutMetricFilterRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutMetricFilterRequest(arg0 *cloudwatchlogs.PutMetricFilterInput) (*request.Request, *cloudwatchlogs.PutMetricFilterOutput) {
+	ret := m.ctrl.Call(m, "PutMetricFilterRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutMetricFilterOutput)
+	return ret0, ret1
+}
+
+// PutMetricFilterRequest indicates an expected call of PutMetricFilterRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutMetricFilterRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutMetricFilterRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutMetricFilterRequest), arg0)
+}
+
+// PutQueryDefinition mocks base method
+func (m *MockCloudWatchLogsAPI) PutQueryDefinition(arg0 *cloudwatchlogs.PutQueryDefinitionInput) (*cloudwatchlogs.PutQueryDefinitionOutput, error) {
+	ret := m.ctrl.Call(m, "PutQueryDefinition", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutQueryDefinitionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutQueryDefinition indicates an expected call of PutQueryDefinition
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutQueryDefinition(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutQueryDefinition", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutQueryDefinition), arg0)
+}
+
+// PutQueryDefinitionWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutQueryDefinitionWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutQueryDefinitionInput, arg2 ...request.Option) (*cloudwatchlogs.PutQueryDefinitionOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutQueryDefinitionWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutQueryDefinitionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutQueryDefinitionWithContext indicates an expected call of PutQueryDefinitionWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutQueryDefinitionWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutQueryDefinitionWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutQueryDefinitionWithContext), varargs...)
+}
+
+// PutQueryDefinitionRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutQueryDefinitionRequest(arg0 *cloudwatchlogs.PutQueryDefinitionInput) (*request.Request, *cloudwatchlogs.PutQueryDefinitionOutput) {
+	ret := m.ctrl.Call(m, "PutQueryDefinitionRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutQueryDefinitionOutput)
+	return ret0, ret1
+}
+
+// PutQueryDefinitionRequest indicates an expected call of PutQueryDefinitionRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutQueryDefinitionRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutQueryDefinitionRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutQueryDefinitionRequest), arg0)
+}
+
+// PutResourcePolicy mocks base method
+func (m *MockCloudWatchLogsAPI) PutResourcePolicy(arg0 *cloudwatchlogs.PutResourcePolicyInput) (*cloudwatchlogs.PutResourcePolicyOutput, error) {
+	ret := m.ctrl.Call(m, "PutResourcePolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutResourcePolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutResourcePolicy indicates an expected call of PutResourcePolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutResourcePolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutResourcePolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutResourcePolicy), arg0)
+}
+
+// PutResourcePolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutResourcePolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutResourcePolicyInput, arg2 ...request.Option) (*cloudwatchlogs.PutResourcePolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutResourcePolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutResourcePolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutResourcePolicyWithContext indicates an expected call of PutResourcePolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutResourcePolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutResourcePolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutResourcePolicyWithContext), varargs...)
+}
+
+// PutResourcePolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutResourcePolicyRequest(arg0 *cloudwatchlogs.PutResourcePolicyInput) (*request.Request, *cloudwatchlogs.PutResourcePolicyOutput) {
+	ret := m.ctrl.Call(m, "PutResourcePolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutResourcePolicyOutput)
+	return ret0, ret1
+}
+
+// PutResourcePolicyRequest indicates an expected call of PutResourcePolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutResourcePolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutResourcePolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutResourcePolicyRequest), arg0)
+}
+
+// PutRetentionPolicy mocks base method
+func (m *MockCloudWatchLogsAPI) PutRetentionPolicy(arg0 *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+	ret := m.ctrl.Call(m, "PutRetentionPolicy", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutRetentionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutRetentionPolicy indicates an expected call of PutRetentionPolicy
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutRetentionPolicy(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutRetentionPolicy", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutRetentionPolicy), arg0)
+}
+
+// PutRetentionPolicyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutRetentionPolicyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutRetentionPolicyInput, arg2 ...request.Option) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutRetentionPolicyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutRetentionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutRetentionPolicyWithContext indicates an expected call of PutRetentionPolicyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutRetentionPolicyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutRetentionPolicyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutRetentionPolicyWithContext), varargs...)
+}
+
+// PutRetentionPolicyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutRetentionPolicyRequest(arg0 *cloudwatchlogs.PutRetentionPolicyInput) (*request.Request, *cloudwatchlogs.PutRetentionPolicyOutput) {
+	ret := m.ctrl.Call(m, "PutRetentionPolicyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutRetentionPolicyOutput)
+	return ret0, ret1
+}
+
+// PutRetentionPolicyRequest indicates an expected call of PutRetentionPolicyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutRetentionPolicyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutRetentionPolicyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutRetentionPolicyRequest), arg0)
+}
+
+// PutSubscriptionFilter mocks base method
+func (m *MockCloudWatchLogsAPI) PutSubscriptionFilter(arg0 *cloudwatchlogs.PutSubscriptionFilterInput) (*cloudwatchlogs.PutSubscriptionFilterOutput, error) {
+	ret := m.ctrl.Call(m, "PutSubscriptionFilter", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutSubscriptionFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutSubscriptionFilter indicates an expected call of PutSubscriptionFilter
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutSubscriptionFilter(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSubscriptionFilter", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutSubscriptionFilter), arg0)
+}
+
+// PutSubscriptionFilterWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) PutSubscriptionFilterWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.PutSubscriptionFilterInput, arg2 ...request.Option) (*cloudwatchlogs.PutSubscriptionFilterOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutSubscriptionFilterWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutSubscriptionFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutSubscriptionFilterWithContext indicates an expected call of PutSubscriptionFilterWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutSubscriptionFilterWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSubscriptionFilterWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutSubscriptionFilterWithContext), varargs...)
+}
+
+// PutSubscriptionFilterRequest mocks base method
+func (m *MockCloudWatchLogsAPI) PutSubscriptionFilterRequest(arg0 *cloudwatchlogs.PutSubscriptionFilterInput) (*request.Request, *cloudwatchlogs.PutSubscriptionFilterOutput) {
+	ret := m.ctrl.Call(m, "PutSubscriptionFilterRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.PutSubscriptionFilterOutput)
+	return ret0, ret1
+}
+
+// PutSubscriptionFilterRequest indicates an expected call of PutSubscriptionFilterRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) PutSubscriptionFilterRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSubscriptionFilterRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).PutSubscriptionFilterRequest), arg0)
+}
+
+// StartLiveTail mocks base method
+func (m *MockCloudWatchLogsAPI) StartLiveTail(arg0 *cloudwatchlogs.StartLiveTailInput) (*cloudwatchlogs.StartLiveTailOutput, error) {
+	ret := m.ctrl.Call(m, "StartLiveTail", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.StartLiveTailOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartLiveTail indicates an expected call of StartLiveTail
+func (mr *MockCloudWatchLogsAPIMockRecorder) StartLiveTail(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartLiveTail", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).StartLiveTail), arg0)
+}
+
+// StartLiveTailWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) StartLiveTailWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.StartLiveTailInput, arg2 ...request.Option) (*cloudwatchlogs.StartLiveTailOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartLiveTailWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.StartLiveTailOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartLiveTailWithContext indicates an expected call of StartLiveTailWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) StartLiveTailWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartLiveTailWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).StartLiveTailWithContext), varargs...)
+}
+
+// StartLiveTailRequest mocks base method
+func (m *MockCloudWatchLogsAPI) StartLiveTailRequest(arg0 *cloudwatchlogs.StartLiveTailInput) (*request.Request, *cloudwatchlogs.StartLiveTailOutput) {
+	ret := m.ctrl.Call(m, "StartLiveTailRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.StartLiveTailOutput)
+	return ret0, ret1
+}
+
+// StartLiveTailRequest indicates an expected call of StartLiveTailRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) StartLiveTailRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartLiveTailRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).StartLiveTailRequest), arg0)
+}
+
+// StartQuery mocks base method
+func (m *MockCloudWatchLogsAPI) StartQuery(arg0 *cloudwatchlogs.StartQueryInput) (*cloudwatchlogs.StartQueryOutput, error) {
+	ret := m.ctrl.Call(m, "StartQuery", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.StartQueryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartQuery indicates an expected call of StartQuery
+func (mr *MockCloudWatchLogsAPIMockRecorder) StartQuery(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartQuery", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).StartQuery), arg0)
+}
+
+// StartQueryWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) StartQueryWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.StartQueryInput, arg2 ...request.Option) (*cloudwatchlogs.StartQueryOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartQueryWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.StartQueryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartQueryWithContext indicates an expected call of StartQueryWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) StartQueryWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartQueryWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).StartQueryWithContext), varargs...)
+}
+
+// StartQueryRequest mocks base method
+func (m *MockCloudWatchLogsAPI) StartQueryRequest(arg0 *cloudwatchlogs.StartQueryInput) (*request.Request, *cloudwatchlogs.StartQueryOutput) {
+	ret := m.ctrl.Call(m, "StartQueryRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.StartQueryOutput)
+	return ret0, ret1
+}
+
+// StartQueryRequest indicates an expected call of StartQueryRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) StartQueryRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartQueryRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).StartQueryRequest), arg0)
+}
+
+// StopQuery mocks base method
+func (m *MockCloudWatchLogsAPI) StopQuery(arg0 *cloudwatchlogs.StopQueryInput) (*cloudwatchlogs.StopQueryOutput, error) {
+	ret := m.ctrl.Call(m, "StopQuery", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.StopQueryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopQuery indicates an expected call of StopQuery
+func (mr *MockCloudWatchLogsAPIMockRecorder) StopQuery(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopQuery", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).StopQuery), arg0)
+}
+
+// StopQueryWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) StopQueryWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.StopQueryInput, arg2 ...request.Option) (*cloudwatchlogs.StopQueryOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StopQueryWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.StopQueryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopQueryWithContext indicates an expected call of StopQueryWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) StopQueryWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopQueryWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).StopQueryWithContext), varargs...)
+}
+
+// StopQueryRequest mocks base method
+func (m *MockCloudWatchLogsAPI) StopQueryRequest(arg0 *cloudwatchlogs.StopQueryInput) (*request.Request, *cloudwatchlogs.StopQueryOutput) {
+	ret := m.ctrl.Call(m, "StopQueryRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.StopQueryOutput)
+	return ret0, ret1
+}
+
+// StopQueryRequest indicates an expected call of StopQueryRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) StopQueryRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopQueryRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).StopQueryRequest), arg0)
+}
+
+// TagLogGroup mocks base method
+func (m *MockCloudWatchLogsAPI) TagLogGroup(arg0 *cloudwatchlogs.TagLogGroupInput) (*cloudwatchlogs.TagLogGroupOutput, error) {
+	ret := m.ctrl.Call(m, "TagLogGroup", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.TagLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagLogGroup indicates an expected call of TagLogGroup
+func (mr *MockCloudWatchLogsAPIMockRecorder) TagLogGroup(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagLogGroup", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).TagLogGroup), arg0)
+}
+
+// TagLogGroupWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) TagLogGroupWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.TagLogGroupInput, arg2 ...request.Option) (*cloudwatchlogs.TagLogGroupOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TagLogGroupWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.TagLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagLogGroupWithContext indicates an expected call of TagLogGroupWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) TagLogGroupWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagLogGroupWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).TagLogGroupWithContext), varargs...)
+}
+
+// TagLogGroupRequest mocks base method
+func (m *MockCloudWatchLogsAPI) TagLogGroupRequest(arg0 *cloudwatchlogs.TagLogGroupInput) (*request.Request, *cloudwatchlogs.TagLogGroupOutput) {
+	ret := m.ctrl.Call(m, "TagLogGroupRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.TagLogGroupOutput)
+	return ret0, ret1
+}
+
+// TagLogGroupRequest indicates an expected call of TagLogGroupRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) TagLogGroupRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagLogGroupRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).TagLogGroupRequest), arg0)
+}
+
+// TagResource mocks base method
+func (m *MockCloudWatchLogsAPI) TagResource(arg0 *cloudwatchlogs.TagResourceInput) (*cloudwatchlogs.TagResourceOutput, error) {
+	ret := m.ctrl.Call(m, "TagResource", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.TagResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagResource indicates an expected call of TagResource
+func (mr *MockCloudWatchLogsAPIMockRecorder) TagResource(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResource", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).TagResource), arg0)
+}
+
+// TagResourceWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) TagResourceWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.TagResourceInput, arg2 ...request.Option) (*cloudwatchlogs.TagResourceOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TagResourceWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.TagResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagResourceWithContext indicates an expected call of TagResourceWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) TagResourceWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResourceWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).TagResourceWithContext), varargs...)
+}
+
+// TagResourceRequest mocks base method
+func (m *MockCloudWatchLogsAPI) TagResourceRequest(arg0 *cloudwatchlogs.TagResourceInput) (*request.Request, *cloudwatchlogs.TagResourceOutput) {
+	ret := m.ctrl.Call(m, "TagResourceRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.TagResourceOutput)
+	return ret0, ret1
+}
+
+// TagResourceRequest indicates an expected call of TagResourceRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) TagResourceRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResourceRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).TagResourceRequest), arg0)
+}
+
+// TestMetricFilter mocks base method
+func (m *MockCloudWatchLogsAPI) TestMetricFilter(arg0 *cloudwatchlogs.TestMetricFilterInput) (*cloudwatchlogs.TestMetricFilterOutput, error) {
+	ret := m.ctrl.Call(m, "TestMetricFilter", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.TestMetricFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestMetricFilter indicates an expected call of TestMetricFilter
+func (mr *MockCloudWatchLogsAPIMockRecorder) TestMetricFilter(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestMetricFilter", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).TestMetricFilter), arg0)
+}
+
+// TestMetricFilterWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) TestMetricFilterWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.TestMetricFilterInput, arg2 ...request.Option) (*cloudwatchlogs.TestMetricFilterOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TestMetricFilterWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.TestMetricFilterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestMetricFilterWithContext indicates an expected call of TestMetricFilterWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) TestMetricFilterWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestMetricFilterWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).TestMetricFilterWithContext), varargs...)
+}
+
+// TestMetricFilterRequest mocks base method
+func (m *MockCloudWatchLogsAPI) TestMetricFilterRequest(arg0 *cloudwatchlogs.TestMetricFilterInput) (*request.Request, *cloudwatchlogs.TestMetricFilterOutput) {
+	ret := m.ctrl.Call(m, "TestMetricFilterRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.TestMetricFilterOutput)
+	return ret0, ret1
+}
+
+// TestMetricFilterRequest indicates an expected call of TestMetricFilterRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) TestMetricFilterRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestMetricFilterRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).TestMetricFilterRequest), arg0)
+}
+
+// UntagLogGroup mocks base method
+func (m *MockCloudWatchLogsAPI) UntagLogGroup(arg0 *cloudwatchlogs.UntagLogGroupInput) (*cloudwatchlogs.UntagLogGroupOutput, error) {
+	ret := m.ctrl.Call(m, "UntagLogGroup", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.UntagLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UntagLogGroup indicates an expected call of UntagLogGroup
+func (mr *MockCloudWatchLogsAPIMockRecorder) UntagLogGroup(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagLogGroup", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UntagLogGroup), arg0)
+}
+
+// UntagLogGroupWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) UntagLogGroupWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.UntagLogGroupInput, arg2 ...request.Option) (*cloudwatchlogs.UntagLogGroupOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UntagLogGroupWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.UntagLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UntagLogGroupWithContext indicates an expected call of UntagLogGroupWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) UntagLogGroupWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagLogGroupWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UntagLogGroupWithContext), varargs...)
+}
+
+// UntagLogGroupRequest mocks base method
+func (m *MockCloudWatchLogsAPI) UntagLogGroupRequest(arg0 *cloudwatchlogs.UntagLogGroupInput) (*request.Request, *cloudwatchlogs.UntagLogGroupOutput) {
+	ret := m.ctrl.Call(m, "UntagLogGroupRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.UntagLogGroupOutput)
+	return ret0, ret1
+}
+
+// UntagLogGroupRequest indicates an expected call of UntagLogGroupRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) UntagLogGroupRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagLogGroupRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UntagLogGroupRequest), arg0)
+}
+
+// UntagResource mocks base method
+func (m *MockCloudWatchLogsAPI) UntagResource(arg0 *cloudwatchlogs.UntagResourceInput) (*cloudwatchlogs.UntagResourceOutput, error) {
+	ret := m.ctrl.Call(m, "UntagResource", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.UntagResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UntagResource indicates an expected call of UntagResource
+func (mr *MockCloudWatchLogsAPIMockRecorder) UntagResource(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagResource", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UntagResource), arg0)
+}
+
+// UntagResourceWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) UntagResourceWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.UntagResourceInput, arg2 ...request.Option) (*cloudwatchlogs.UntagResourceOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UntagResourceWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.UntagResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UntagResourceWithContext indicates an expected call of UntagResourceWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) UntagResourceWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagResourceWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UntagResourceWithContext), varargs...)
+}
+
+// UntagResourceRequest mocks base method
+func (m *MockCloudWatchLogsAPI) UntagResourceRequest(arg0 *cloudwatchlogs.UntagResourceInput) (*request.Request, *cloudwatchlogs.UntagResourceOutput) {
+	ret := m.ctrl.Call(m, "UntagResourceRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.UntagResourceOutput)
+	return ret0, ret1
+}
+
+// UntagResourceRequest indicates an expected call of UntagResourceRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) UntagResourceRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagResourceRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UntagResourceRequest), arg0)
+}
+
+// UpdateAnomaly mocks base method
+func (m *MockCloudWatchLogsAPI) UpdateAnomaly(arg0 *cloudwatchlogs.UpdateAnomalyInput) (*cloudwatchlogs.UpdateAnomalyOutput, error) {
+	ret := m.ctrl.Call(m, "UpdateAnomaly", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.UpdateAnomalyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAnomaly indicates an expected call of UpdateAnomaly
+func (mr *MockCloudWatchLogsAPIMockRecorder) UpdateAnomaly(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAnomaly", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UpdateAnomaly), arg0)
+}
+
+// UpdateAnomalyWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) UpdateAnomalyWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.UpdateAnomalyInput, arg2 ...request.Option) (*cloudwatchlogs.UpdateAnomalyOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateAnomalyWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.UpdateAnomalyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAnomalyWithContext indicates an expected call of UpdateAnomalyWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) UpdateAnomalyWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAnomalyWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UpdateAnomalyWithContext), varargs...)
+}
+
+// UpdateAnomalyRequest mocks base method
+func (m *MockCloudWatchLogsAPI) UpdateAnomalyRequest(arg0 *cloudwatchlogs.UpdateAnomalyInput) (*request.Request, *cloudwatchlogs.UpdateAnomalyOutput) {
+	ret := m.ctrl.Call(m, "UpdateAnomalyRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.UpdateAnomalyOutput)
+	return ret0, ret1
+}
+
+// UpdateAnomalyRequest indicates an expected call of UpdateAnomalyRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) UpdateAnomalyRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAnomalyRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UpdateAnomalyRequest), arg0)
+}
+
+// UpdateLogAnomalyDetector mocks base method
+func (m *MockCloudWatchLogsAPI) UpdateLogAnomalyDetector(arg0 *cloudwatchlogs.UpdateLogAnomalyDetectorInput) (*cloudwatchlogs.UpdateLogAnomalyDetectorOutput, error) {
+	ret := m.ctrl.Call(m, "UpdateLogAnomalyDetector", arg0)
+	ret0, _ := ret[0].(*cloudwatchlogs.UpdateLogAnomalyDetectorOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLogAnomalyDetector indicates an expected call of UpdateLogAnomalyDetector
+func (mr *MockCloudWatchLogsAPIMockRecorder) UpdateLogAnomalyDetector(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLogAnomalyDetector", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UpdateLogAnomalyDetector), arg0)
+}
+
+// UpdateLogAnomalyDetectorWithContext mocks base method
+func (m *MockCloudWatchLogsAPI) UpdateLogAnomalyDetectorWithContext(arg0 aws.Context, arg1 *cloudwatchlogs.UpdateLogAnomalyDetectorInput, arg2 ...request.Option) (*cloudwatchlogs.UpdateLogAnomalyDetectorOutput, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateLogAnomalyDetectorWithContext", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.UpdateLogAnomalyDetectorOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLogAnomalyDetectorWithContext indicates an expected call of UpdateLogAnomalyDetectorWithContext
+func (mr *MockCloudWatchLogsAPIMockRecorder) UpdateLogAnomalyDetectorWithContext(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLogAnomalyDetectorWithContext", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UpdateLogAnomalyDetectorWithContext), varargs...)
+}
+
+// UpdateLogAnomalyDetectorRequest mocks base method
+func (m *MockCloudWatchLogsAPI) UpdateLogAnomalyDetectorRequest(arg0 *cloudwatchlogs.UpdateLogAnomalyDetectorInput) (*request.Request, *cloudwatchlogs.UpdateLogAnomalyDetectorOutput) {
+	ret := m.ctrl.Call(m, "UpdateLogAnomalyDetectorRequest", arg0)
+	ret0, _ := ret[0].(*request.Request)
+	ret1, _ := ret[1].(*cloudwatchlogs.UpdateLogAnomalyDetectorOutput)
+	return ret0, ret1
+}
+
+// UpdateLogAnomalyDetectorRequest indicates an expected call of UpdateLogAnomalyDetectorRequest
+func (mr *MockCloudWatchLogsAPIMockRecorder) UpdateLogAnomalyDetectorRequest(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLogAnomalyDetectorRequest", reflect.TypeOf((*MockCloudWatchLogsAPI)(nil).UpdateLogAnomalyDetectorRequest), arg0)
+}
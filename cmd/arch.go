@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/jpignata/fargate/console"
+	"github.com/jpignata/fargate/docker"
+	ECS "github.com/jpignata/fargate/ecs"
+)
+
+// dockerPlatforms maps each CPU architecture supported by Fargate to the
+// Docker platform for which images must be built to run on it.
+var dockerPlatforms = map[string]string{
+	"ARM64":  "linux/arm64",
+	"X86_64": "linux/amd64",
+}
+
+// extractCpuArchitecture normalizes a CPU architecture given on the command
+// line [e.g. arm64], exiting if Fargate doesn't support it.
+func extractCpuArchitecture(inputCpuArchitecture string) string {
+	if inputCpuArchitecture == "" {
+		return ""
+	}
+
+	cpuArchitecture := strings.ToUpper(inputCpuArchitecture)
+
+	if err := ECS.ValidateCpuArchitecture(cpuArchitecture); err != nil {
+		console.ErrorExit(err, "Invalid command line flags")
+	}
+
+	return cpuArchitecture
+}
+
+// buildImage builds the image for the CPU architecture on which it will run,
+// or for the host's architecture if none is given.
+func buildImage(repository docker.Repository, tag, cpuArchitecture string) {
+	if platform, ok := dockerPlatforms[cpuArchitecture]; ok {
+		repository.BuildForPlatform(tag, platform)
+	} else {
+		repository.Build(tag)
+	}
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestExtractCpuArchitecture(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"arm64", "ARM64"},
+		{"ARM64", "ARM64"},
+		{"x86_64", "X86_64"},
+	}
+
+	for _, test := range tests {
+		if cpuArchitecture := extractCpuArchitecture(test.input); cpuArchitecture != test.expected {
+			t.Errorf("expected %q for %q, got %q", test.expected, test.input, cpuArchitecture)
+		}
+	}
+}
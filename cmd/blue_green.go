@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"time"
+
+	CodeDeploy "github.com/jpignata/fargate/codedeploy"
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
+	ELBV2 "github.com/jpignata/fargate/elbv2"
+)
+
+const (
+	blueGreenTargetGroupFormat      = "%s-%s-green"
+	blueGreenTerminationWaitMinutes = 5
+	defaultTestPort                 = 8080
+)
+
+// updateServiceTaskDefinition moves the service to the task definition. For
+// blue/green services this starts a CodeDeploy deployment, which shifts
+// traffic to replacement tasks and rolls back if they fail, and returns its
+// ID. Rolling deployments update the service directly and return "".
+func updateServiceTaskDefinition(ecs ECS.ECS, service ECS.Service, taskDefinitionArn string) string {
+	if service.DeploymentController != ECS.DeploymentControllerCodeDeploy {
+		ecs.UpdateServiceTaskDefinition(service.Name, taskDefinitionArn)
+		return ""
+	}
+
+	codeDeploy := CodeDeploy.New(sess)
+	deploymentId, err := codeDeploy.CreateDeployment(
+		&CodeDeploy.CreateDeploymentInput{
+			ApplicationName:     CodeDeploy.ApplicationName(clusterName, service.Name),
+			ContainerName:       service.TargetContainerName,
+			ContainerPort:       service.TargetContainerPort,
+			DeploymentGroupName: service.Name,
+			TaskDefinitionArn:   taskDefinitionArn,
+		},
+	)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not deploy service %s", service.Name)
+	}
+
+	console.Info("Started blue/green deployment %s", deploymentId)
+
+	return deploymentId
+}
+
+// waitForDeployment blocks until the deployment started by
+// updateServiceTaskDefinition completes, exiting non-zero if it fails or
+// doesn't complete within the timeout.
+func waitForDeployment(ecs ECS.ECS, serviceName, deploymentId string, timeout time.Duration) {
+	if deploymentId == "" {
+		waitForServiceStable(ecs, serviceName, timeout)
+		return
+	}
+
+	console.Info("Waiting for deployment %s to complete", deploymentId)
+
+	codeDeploy := CodeDeploy.New(sess)
+	err := codeDeploy.WaitForDeployment(
+		deploymentId,
+		timeout,
+		func(d CodeDeploy.Deployment) {
+			console.Info("[%s] Deployment %s", time.Now().Format(time.Kitchen), d.Status)
+		},
+	)
+
+	if err != nil {
+		console.ErrorExit(err, "Deployment %s did not complete", deploymentId)
+	}
+
+	console.Info("Deployment %s completed", deploymentId)
+}
+
+// productionListener returns the listener whose traffic CodeDeploy shifts
+// during blue/green deployments, preferring HTTPS.
+func productionListener(listeners []ELBV2.Listener) ELBV2.Listener {
+	for _, listener := range listeners {
+		if listener.Protocol == protocolHttps {
+			return listener
+		}
+	}
+
+	return listeners[0]
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	ELBV2 "github.com/jpignata/fargate/elbv2"
+)
+
+func TestProductionListener(t *testing.T) {
+	var tests = []struct {
+		listeners []ELBV2.Listener
+		expected  string
+	}{
+		{
+			[]ELBV2.Listener{{ARN: "http", Protocol: "HTTP"}, {ARN: "https", Protocol: "HTTPS"}},
+			"https",
+		},
+		{
+			[]ELBV2.Listener{{ARN: "http", Protocol: "HTTP"}, {ARN: "http2", Protocol: "HTTP"}},
+			"http",
+		},
+	}
+
+	for _, test := range tests {
+		if listener := productionListener(test.listeners); listener.ARN != test.expected {
+			t.Errorf("expected listener %s, got %s", test.expected, listener.ARN)
+		}
+	}
+}
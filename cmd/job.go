@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var jobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "Manage jobs",
+	Long: `Manage jobs
+
+Jobs are tasks which are run to completion, such as database migrations or
+batch jobs run from a CI pipeline. Unlike fargate task run, which starts tasks
+and returns, fargate job run waits for the job's task to stop and exits with
+its container's exit code.`,
+}
+
+func init() {
+	rootCmd.AddCommand(jobCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var flagJobRunTimeout time.Duration
+
+var jobRunCmd = &cobra.Command{
+	Use:   "run <job name> [-- <args>...]",
+	Short: "Run a job to completion",
+	Long: `Run a job to completion
+
+Runs a single task, streams its logs from CloudWatch Logs as it runs, waits for
+it to stop, and exits with its container's exit code. A job which exits
+non-zero, or whose task fails to start, fails the command, making it suitable
+for migrations and batch jobs run as a CI step:
+
+  fargate job run migrate --task-definition-arn web --command bin/migrate
+
+Jobs accept the same flags as fargate task run to configure the task, such as
+--image, --cpu, --memory, --env, and --task-definition-arn, and are run as a
+task group of the same name, so their tasks and logs can also be inspected
+with fargate task ps, task info, and task logs.
+
+Pass --timeout with a duration [e.g. 30m] to stop the job and fail if it runs
+for longer. If interrupted with Control-C, fargate stops waiting and prints
+the ID of the job's task, which is left running.`,
+	Args: taskRunCmd.Args,
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := taskRunOperationFromFlags(cmd, args)
+		operation.Logs = true
+		operation.Num = 1
+		operation.Timeout = flagJobRunTimeout
+		operation.Wait = true
+		operation.Validate()
+
+		runTask(operation)
+	},
+}
+
+func init() {
+	jobRunCmd.Flags().DurationVar(&flagJobRunTimeout, "timeout", 0, "Stop the job if it hasn't stopped within this duration [e.g. 30m]")
+
+	addTaskRunFlags(jobRunCmd)
+	jobCmd.AddCommand(jobRunCmd)
+}
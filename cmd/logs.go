@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	lru "github.com/hashicorp/golang-lru"
 	CWL "github.com/jpignata/fargate/cloudwatchlogs"
 	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
 )
 
 const (
@@ -112,6 +114,28 @@ func GetLogs(operation *GetLogsOperation) {
 	}
 }
 
+func followTaskLogs(operation *GetLogsOperation, taskId string) {
+	cwl := CWL.New(sess)
+	ecs := ECS.New(sess, clusterName)
+	input := &CWL.FollowLogStreamInput{
+		LogGroupName:  operation.LogGroupName,
+		LogStreamName: operation.LogStreamNames[0],
+		Done:          func() bool { return ecs.IsTaskStopped(taskId) },
+	}
+
+	err := cwl.FollowLogStream(
+		context.Background(),
+		input,
+		func(logLine CWL.LogLine) {
+			console.LogLine(logLine.LogStreamName, logLine.Message, operation.GetStreamColor(logLine.LogStreamName))
+		},
+	)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not follow logs")
+	}
+}
+
 func followLogs(operation *GetLogsOperation) {
 	ticker := time.NewTicker(time.Second)
 
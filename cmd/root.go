@@ -4,11 +4,12 @@ import (
 	"fmt"
 	"os"
 	"runtime"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/jpignata/fargate/console"
 	ECS "github.com/jpignata/fargate/ecs"
@@ -22,7 +23,6 @@ const (
 	defaultClusterName = "fargate"
 	defaultRegion      = "us-east-1"
 
-	mebibytesInGibibyte   = 1024
 	protocolHttp          = "HTTP"
 	protocolHttps         = "HTTPS"
 	protocolTcp           = "TCP"
@@ -32,23 +32,17 @@ const (
 	validRuleTypesPattern = "(?i)^host|path$"
 
 	describeRequestLimitRate = 10
-)
-
-var InvalidCpuAndMemoryCombination = fmt.Errorf(`Invalid CPU and Memory settings
 
-CPU (CPU Units)    Memory (MiB)
----------------    ------------
-256                512, 1024, or 2048
-512                1024 through 4096 in 1GiB increments
-1024               2048 through 8192 in 1GiB increments
-2048               4096 through 16384 in 1GiB increments
-4096               8192 through 30720 in 1GiB increments
-`)
+	defaultMaxRetries = 8
+	minThrottleDelay  = 500 * time.Millisecond
+	maxThrottleDelay  = 30 * time.Second
+)
 
 var validRegions = []string{"us-east-1","us-east-2","us-west-2","eu-west-1"}
 
 var (
 	clusterName string
+	maxRetries  int
 	noColor     bool
 	noEmoji     bool
 	output      ConsoleOutput
@@ -109,16 +103,8 @@ CloudWatch Logs, and Amazon Route 53 into an easy-to-use CLI.`,
 			console.IssueExit("Invalid region: %s [valid regions: %s]", region, strings.Join(validRegions, ", "))
 		}
 
-		config := &aws.Config{
-			Region: aws.String(region),
-		}
-
-		if verbose {
-			config.LogLevel = aws.LogLevel(aws.LogDebugWithHTTPBody)
-		}
-
 		sess = session.Must(
-			session.NewSession(config),
+			session.NewSession(awsConfig(region, maxRetries, verbose)),
 		)
 
 		_, err := sess.Config.Credentials.Get()
@@ -154,6 +140,26 @@ CloudWatch Logs, and Amazon Route 53 into an easy-to-use CLI.`,
 	},
 }
 
+// awsConfig returns the configuration for AWS sessions in the given region.
+// The default retryer backs off exponentially with jitter on throttling, 5xx,
+// and transient network errors; all other errors fail immediately.
+func awsConfig(region string, maxRetries int, verbose bool) *aws.Config {
+	config := &aws.Config{
+		Region: aws.String(region),
+		Retryer: client.DefaultRetryer{
+			NumMaxRetries:    maxRetries,
+			MinThrottleDelay: minThrottleDelay,
+			MaxThrottleDelay: maxThrottleDelay,
+		},
+	}
+
+	if verbose {
+		config.LogLevel = aws.LogLevel(aws.LogDebugWithHTTPBody)
+	}
+
+	return config
+}
+
 func Execute() {
 	rootCmd.Version = version
 	rootCmd.Execute()
@@ -164,6 +170,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&region, "region", "", `AWS region (default "us-east-1")`)
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable color output")
 	rootCmd.PersistentFlags().StringVar(&clusterName, "cluster", "", `ECS cluster name (default "fargate")`)
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", defaultMaxRetries, "Maximum number of retries for throttled or failed AWS API requests")
 
 	if runtime.GOOS == runtimeMacOS {
 		rootCmd.PersistentFlags().BoolVar(&noEmoji, "no-emoji", false, "Disable emoji output")
@@ -195,47 +202,10 @@ func extractEnvVars(inputEnvVars []string) []ECS.EnvVar {
 	return envVars
 }
 
+// validateCpuAndMemory returns an error naming the valid values if the CPU
+// units and memory aren't a combination Fargate supports.
 func validateCpuAndMemory(inputCpuUnits, inputMebibytes string) error {
-	cpuUnits, err := strconv.ParseInt(inputCpuUnits, 10, 16)
-
-	if err != nil {
-		return err
-	}
-
-	mebibytes, err := strconv.ParseInt(inputMebibytes, 10, 16)
-
-	if err != nil {
-		return err
-	}
-
-	switch cpuUnits {
-	case 256:
-		if mebibytes == 512 || validateMebibytes(mebibytes, 1024, 2048) {
-			return nil
-		}
-	case 512:
-		if validateMebibytes(mebibytes, 1024, 4096) {
-			return nil
-		}
-	case 1024:
-		if validateMebibytes(mebibytes, 2048, 8192) {
-			return nil
-		}
-	case 2048:
-		if validateMebibytes(mebibytes, 4096, 16384) {
-			return nil
-		}
-	case 4096:
-		if validateMebibytes(mebibytes, 8192, 30720) {
-			return nil
-		}
-	}
-
-	return InvalidCpuAndMemoryCombination
-}
-
-func validateMebibytes(mebibytes, min, max int64) bool {
-	return mebibytes >= min && mebibytes <= max && mebibytes%mebibytesInGibibyte == 0
+	return ECS.ValidateCpuAndMemory(inputCpuUnits, inputMebibytes)
 }
 
 func validateRegion(region string) bool {
@@ -1,48 +1,54 @@
 package cmd
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+)
 
 var validateCpuAndMemoryTests = []struct {
 	CpuUnits  string
 	Mebibytes string
-	Out       error
+	Valid     bool
 }{
 	// 0.25 vCpu
-	{"256", "512", nil},
-	{"256", "1024", nil},
-	{"256", "2048", nil},
-	{"256", "0", InvalidCpuAndMemoryCombination},
-	{"256", "768", InvalidCpuAndMemoryCombination},
-	{"256", "2151", InvalidCpuAndMemoryCombination},
-	{"256", "3072", InvalidCpuAndMemoryCombination},
+	{"256", "512", true},
+	{"256", "1024", true},
+	{"256", "2048", true},
+	{"256", "0", false},
+	{"256", "768", false},
+	{"256", "2151", false},
+	{"256", "3072", false},
 
 	// 0.5 vCpu
-	{"512", "1024", nil},
-	{"512", "2048", nil},
-	{"512", "3072", nil},
-	{"512", "4096", nil},
-	{"512", "512", InvalidCpuAndMemoryCombination},
+	{"512", "1024", true},
+	{"512", "2048", true},
+	{"512", "3072", true},
+	{"512", "4096", true},
+	{"512", "512", false},
 
 	// 1 vCpu
-	{"1024", "2048", nil},
-	{"1024", "5120", nil},
-	{"1024", "8192", nil},
-	{"1024", "1024", InvalidCpuAndMemoryCombination},
-	{"1024", "9216", InvalidCpuAndMemoryCombination},
+	{"1024", "2048", true},
+	{"1024", "5120", true},
+	{"1024", "8192", true},
+	{"1024", "1024", false},
+	{"1024", "9216", false},
 
 	// 2 vCpu
-	{"2048", "4096", nil},
-	{"2048", "10240", nil},
-	{"2048", "16384", nil},
-	{"2048", "3072", InvalidCpuAndMemoryCombination},
-	{"2048", "17408", InvalidCpuAndMemoryCombination},
+	{"2048", "4096", true},
+	{"2048", "10240", true},
+	{"2048", "16384", true},
+	{"2048", "3072", false},
+	{"2048", "17408", false},
 
 	// 4 vCpu
-	{"4096", "8192", nil},
-	{"4096", "15360", nil},
-	{"4096", "30720", nil},
-	{"4096", "1024", InvalidCpuAndMemoryCombination},
-	{"4096", "31744", InvalidCpuAndMemoryCombination},
+	{"4096", "8192", true},
+	{"4096", "15360", true},
+	{"4096", "30720", true},
+	{"4096", "1024", false},
+	{"4096", "31744", false},
 }
 
 func TestValidateCpuAndMemoryWithValidParameters(t *testing.T) {
@@ -57,7 +63,11 @@ func TestValidateCpuAndMemoryWithInvalidParameters(t *testing.T) {
 	err := validateCpuAndMemory("5", "23849")
 
 	if err == nil {
-		t.Errorf("Validation failed, got nil, want %s", InvalidCpuAndMemoryCombination)
+		t.Fatal("Validation failed, got nil, want an error")
+	}
+
+	if !strings.Contains(err.Error(), "must be one of") {
+		t.Errorf("Validation failed, got %s, want an error naming the valid values", err)
 	}
 }
 
@@ -65,8 +75,43 @@ func TestValidateCpuAndMemory(t *testing.T) {
 	for _, test := range validateCpuAndMemoryTests {
 		s := validateCpuAndMemory(test.CpuUnits, test.Mebibytes)
 
-		if s != test.Out {
-			t.Errorf("validateCpuAndMemory(%s, %s) => %#v, want %s", test.CpuUnits, test.Mebibytes, s, test.Out)
+		if (s == nil) != test.Valid {
+			t.Errorf("validateCpuAndMemory(%s, %s) => %#v, want valid %t", test.CpuUnits, test.Mebibytes, s, test.Valid)
 		}
 	}
 }
+
+func TestMaxRetriesFlag(t *testing.T) {
+	defer func() { maxRetries = defaultMaxRetries }()
+
+	if err := rootCmd.PersistentFlags().Parse([]string{"--max-retries", "7"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	config := awsConfig("us-east-1", maxRetries, false)
+	retryer, ok := config.Retryer.(client.DefaultRetryer)
+
+	if !ok {
+		t.Fatalf("expected default retryer, got %T", config.Retryer)
+	}
+
+	if retries := retryer.MaxRetries(); retries != 7 {
+		t.Errorf("expected 7 retries, got %d", retries)
+	}
+
+	if region := aws.StringValue(config.Region); region != "us-east-1" {
+		t.Errorf("expected region us-east-1, got %s", region)
+	}
+}
+
+func TestAwsConfigVerbose(t *testing.T) {
+	if config := awsConfig("us-east-1", defaultMaxRetries, false); config.LogLevel != nil {
+		t.Errorf("expected no log level, got %v", config.LogLevel)
+	}
+
+	config := awsConfig("us-east-1", defaultMaxRetries, true)
+
+	if !config.LogLevel.Matches(aws.LogDebugWithHTTPBody) {
+		t.Errorf("expected debug log level with HTTP bodies, got %v", config.LogLevel)
+	}
+}
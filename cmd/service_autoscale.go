@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+
+	AAS "github.com/jpignata/fargate/applicationautoscaling"
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
+	ELBV2 "github.com/jpignata/fargate/elbv2"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultAutoscaleMin = 1
+	defaultAutoscaleMax = 10
+)
+
+type ServiceAutoscaleOperation struct {
+	Max         *int64
+	Metric      string
+	Min         *int64
+	ServiceName string
+	Target      float64
+}
+
+func (o *ServiceAutoscaleOperation) Validate() {
+	if err := AAS.ValidateMetric(o.Metric); err != nil {
+		console.ErrorExit(err, "Invalid command line flags")
+	}
+
+	if o.Target <= 0 {
+		console.ErrorExit(fmt.Errorf("--target must be > 0"), "Invalid command line flags")
+	}
+
+	if o.Min != nil && *o.Min < 0 {
+		console.ErrorExit(fmt.Errorf("--min must be >= 0"), "Invalid command line flags")
+	}
+
+	if o.Min != nil && o.Max != nil && *o.Max < *o.Min {
+		console.ErrorExit(fmt.Errorf("--max must be >= --min"), "Invalid command line flags")
+	}
+}
+
+var (
+	flagServiceAutoscaleMax    int64
+	flagServiceAutoscaleMetric string
+	flagServiceAutoscaleMin    int64
+	flagServiceAutoscaleTarget float64
+)
+
+var serviceAutoscaleCmd = &cobra.Command{
+	Use:   "autoscale <service-name> --metric <metric> --target <value>",
+	Short: "Autoscale a service to track a metric",
+	Long: `Autoscale a service to track a metric
+
+Creates an Application Auto Scaling target tracking policy which adds tasks to
+the service when the metric is above the target value and removes them when
+it's below. Pass --metric with one of:
+
+  cpu       Average CPU utilization of the service's tasks, as a percentage
+  memory    Average memory utilization of the service's tasks, as a percentage
+  requests  Load balancer requests per task each minute (requires a service
+            with an application load balancer)
+
+and --target with the value to track [e.g. --metric cpu --target 60]. Running
+autoscale again with the same metric replaces its target. A service can have a
+policy for each metric, in which case it scales out if any policy calls for it
+and in only if all of them do.
+
+The service is kept between --min and --max tasks, 1 and 10 by default when
+autoscaling is first enabled. If omitted afterwards, the current range is kept.
+
+Use autoscale list to show the service's policies, and autoscale remove to
+remove them. To scale the service on a schedule, see autoscale schedule.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &ServiceAutoscaleOperation{
+			Metric:      flagServiceAutoscaleMetric,
+			ServiceName: args[0],
+			Target:      flagServiceAutoscaleTarget,
+		}
+
+		if cmd.Flags().Changed("min") {
+			operation.Min = &flagServiceAutoscaleMin
+		}
+
+		if cmd.Flags().Changed("max") {
+			operation.Max = &flagServiceAutoscaleMax
+		}
+
+		operation.Validate()
+		autoscaleService(operation)
+	},
+}
+
+func init() {
+	serviceAutoscaleCmd.Flags().StringVar(&flagServiceAutoscaleMetric, "metric", "", "Metric to track [cpu, memory, requests]")
+	serviceAutoscaleCmd.Flags().Float64Var(&flagServiceAutoscaleTarget, "target", 0, "Value of the metric to keep the service at [e.g. 60 for 60% CPU utilization]")
+	serviceAutoscaleCmd.Flags().Int64Var(&flagServiceAutoscaleMin, "min", defaultAutoscaleMin, "Minimum number of tasks")
+	serviceAutoscaleCmd.Flags().Int64Var(&flagServiceAutoscaleMax, "max", defaultAutoscaleMax, "Maximum number of tasks")
+
+	serviceCmd.AddCommand(serviceAutoscaleCmd)
+}
+
+func autoscaleService(operation *ServiceAutoscaleOperation) {
+	aas := AAS.New(sess)
+	ecs := ECS.New(sess, clusterName)
+	service := ecs.DescribeService(operation.ServiceName)
+
+	if service.Status != statusActive {
+		console.IssueExit("Service %s not found", operation.ServiceName)
+	}
+
+	policy := AAS.TargetTrackingPolicy{
+		Metric: operation.Metric,
+		Target: operation.Target,
+	}
+
+	if operation.Metric == AAS.MetricRequests {
+		if service.TargetGroupArn == "" {
+			console.IssueExit("Autoscaling on requests requires a service with a load balancer")
+		}
+
+		elbv2 := ELBV2.New(sess)
+		loadBalancerArn := elbv2.GetTargetGroupLoadBalancerArn(service.TargetGroupArn)
+		policy.ResourceLabel = AAS.RequestCountResourceLabel(loadBalancerArn, service.TargetGroupArn)
+	}
+
+	target, registered, err := aas.DescribeServiceTarget(clusterName, operation.ServiceName)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not autoscale service %s", operation.ServiceName)
+	}
+
+	if !registered {
+		target = AAS.ScalableTarget{MinCapacity: defaultAutoscaleMin, MaxCapacity: defaultAutoscaleMax}
+	}
+
+	if operation.Min != nil {
+		target.MinCapacity = *operation.Min
+	}
+
+	if operation.Max != nil {
+		target.MaxCapacity = *operation.Max
+	}
+
+	if !registered || operation.Min != nil || operation.Max != nil {
+		if err := aas.RegisterServiceTarget(clusterName, operation.ServiceName, target); err != nil {
+			console.ErrorExit(err, "Could not autoscale service %s", operation.ServiceName)
+		}
+	}
+
+	if err := aas.PutServiceTargetTrackingPolicy(clusterName, operation.ServiceName, policy); err != nil {
+		console.ErrorExit(err, "Could not autoscale service %s", operation.ServiceName)
+	}
+
+	console.Info(
+		"Autoscaling service %s between %d and %d tasks to keep %s at %g",
+		operation.ServiceName,
+		target.MinCapacity,
+		target.MaxCapacity,
+		operation.Metric,
+		operation.Target,
+	)
+}
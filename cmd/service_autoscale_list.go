@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	AAS "github.com/jpignata/fargate/applicationautoscaling"
+	"github.com/jpignata/fargate/console"
+	"github.com/spf13/cobra"
+)
+
+type ServiceAutoscaleListOperation struct {
+	ServiceName string
+}
+
+var serviceAutoscaleListCmd = &cobra.Command{
+	Use:   "list <service-name>",
+	Short: "Show autoscaling policies and scheduled actions",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &ServiceAutoscaleListOperation{
+			ServiceName: args[0],
+		}
+
+		serviceAutoscaleList(operation)
+	},
+}
+
+func init() {
+	serviceAutoscaleCmd.AddCommand(serviceAutoscaleListCmd)
+}
+
+func serviceAutoscaleList(operation *ServiceAutoscaleListOperation) {
+	aas := AAS.New(sess)
+	target, registered, err := aas.DescribeServiceTarget(clusterName, operation.ServiceName)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not list autoscaling policies")
+	}
+
+	if !registered {
+		console.InfoExit("Service %s isn't autoscaled", operation.ServiceName)
+	}
+
+	policies, err := aas.DescribeServicePolicies(clusterName, operation.ServiceName)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not list autoscaling policies")
+	}
+
+	actions, err := aas.DescribeServiceScheduledActions(clusterName, operation.ServiceName)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not list scheduled actions")
+	}
+
+	console.KeyValue("Tasks", "%d - %d\n", target.MinCapacity, target.MaxCapacity)
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+
+	if len(policies) > 0 {
+		fmt.Fprintln(w, "POLICY\tMETRIC\tTARGET\t")
+
+		for _, policy := range policies {
+			fmt.Fprintf(w, "%s\t%s\t%g\t\n", policy.Name, policy.Metric, policy.Target)
+		}
+
+		w.Flush()
+	}
+
+	if len(actions) > 0 {
+		if len(policies) > 0 {
+			fmt.Println()
+		}
+
+		fmt.Fprintln(w, "SCHEDULED ACTION\tSCHEDULE\tTIME ZONE\tTASKS\t")
+
+		for _, action := range actions {
+			timezone := action.Timezone
+
+			if timezone == "" {
+				timezone = "UTC"
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", action.Name, action.Schedule, timezone, action.Capacity())
+		}
+
+		w.Flush()
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	AAS "github.com/jpignata/fargate/applicationautoscaling"
+	"github.com/jpignata/fargate/console"
+	"github.com/spf13/cobra"
+)
+
+type ServiceAutoscaleRemoveOperation struct {
+	Metric      string
+	ServiceName string
+}
+
+var flagServiceAutoscaleRemoveMetric string
+
+var serviceAutoscaleRemoveCmd = &cobra.Command{
+	Use:   "remove <service-name> [--metric <metric>]",
+	Short: "Remove autoscaling policies",
+	Long: `Remove autoscaling policies
+
+Removes the service's policy for the metric given via --metric [cpu, memory,
+requests]. If --metric is omitted, autoscaling is turned off for the service
+and all of its policies and scheduled actions are removed. The service keeps
+its current number of tasks.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &ServiceAutoscaleRemoveOperation{
+			Metric:      flagServiceAutoscaleRemoveMetric,
+			ServiceName: args[0],
+		}
+
+		if operation.Metric != "" {
+			if err := AAS.ValidateMetric(operation.Metric); err != nil {
+				console.ErrorExit(err, "Invalid command line flags")
+			}
+		}
+
+		serviceAutoscaleRemove(operation)
+	},
+}
+
+func init() {
+	serviceAutoscaleRemoveCmd.Flags().StringVar(&flagServiceAutoscaleRemoveMetric, "metric", "", "Metric whose policy to remove [cpu, memory, requests] (default: all)")
+
+	serviceAutoscaleCmd.AddCommand(serviceAutoscaleRemoveCmd)
+}
+
+func serviceAutoscaleRemove(operation *ServiceAutoscaleRemoveOperation) {
+	aas := AAS.New(sess)
+
+	if operation.Metric == "" {
+		if err := aas.DeregisterServiceTarget(clusterName, operation.ServiceName); err != nil {
+			console.ErrorExit(err, "Could not remove autoscaling")
+		}
+
+		console.Info("Stopped autoscaling service %s", operation.ServiceName)
+
+		return
+	}
+
+	if err := aas.DeleteServicePolicy(clusterName, operation.ServiceName, AAS.PolicyName(operation.Metric)); err != nil {
+		console.ErrorExit(err, "Could not remove autoscaling policy")
+	}
+
+	console.Info("Removed %s autoscaling policy from service %s", operation.Metric, operation.ServiceName)
+}
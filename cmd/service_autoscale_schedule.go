@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	AAS "github.com/jpignata/fargate/applicationautoscaling"
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
+	"github.com/spf13/cobra"
+)
+
+type ServiceAutoscaleScheduleOperation struct {
+	Action      AAS.ScheduledAction
+	ServiceName string
+}
+
+func (o *ServiceAutoscaleScheduleOperation) Validate() {
+	var msgs []string
+
+	if err := AAS.ValidateScheduleExpression(o.Action.Schedule); err != nil {
+		msgs = append(msgs, err.Error())
+	}
+
+	if o.Action.MinCapacity == nil && o.Action.MaxCapacity == nil {
+		msgs = append(msgs, "--count, --min, and/or --max must be supplied")
+	}
+
+	if o.Action.MinCapacity != nil && *o.Action.MinCapacity < 0 {
+		msgs = append(msgs, "--min must be >= 0")
+	}
+
+	if o.Action.MinCapacity != nil && o.Action.MaxCapacity != nil && *o.Action.MaxCapacity < *o.Action.MinCapacity {
+		msgs = append(msgs, "--max must be >= --min")
+	}
+
+	if len(msgs) > 0 {
+		console.ErrorExit(errors.New(strings.Join(msgs, ", ")), "Invalid command line flags")
+	}
+}
+
+var (
+	flagServiceAutoscaleScheduleCount    int64
+	flagServiceAutoscaleScheduleMax      int64
+	flagServiceAutoscaleScheduleMin      int64
+	flagServiceAutoscaleScheduleSchedule string
+	flagServiceAutoscaleScheduleTimezone string
+)
+
+var serviceAutoscaleScheduleCmd = &cobra.Command{
+	Use:   "schedule <service-name> <action-name> --schedule <expression> --count <num> | --min <num> | --max <num>",
+	Short: "Scale a service on a schedule",
+	Long: `Scale a service on a schedule
+
+Creates an Application Auto Scaling scheduled action which changes the range
+within which the service is scaled, such as ahead of predictable traffic. Pass
+--schedule with a cron expression [e.g. cron(0 8 ? * MON-FRI *) for 08:00 on
+weekdays], a rate expression [e.g. rate(6 hours)], or a one-time at expression
+[e.g. at(2026-11-27T06:00:00)]. Schedules are in UTC unless a time zone is
+given via --timezone [e.g. --timezone America/New_York].
+
+Pass --count to scale the service to a fixed number of tasks, or --min and/or
+--max to change its range, within which any autoscale policies keep scaling it.
+For example, to run 10 tasks during the working week and 2 overnight:
+
+  fargate service autoscale schedule web morning \
+    --schedule "cron(0 8 ? * MON-FRI *)" --count 10
+  fargate service autoscale schedule web evening \
+    --schedule "cron(0 20 ? * MON-FRI *)" --count 2
+
+Scheduling an action with the name of an existing one replaces it. If the
+service isn't autoscaled yet, it's registered with its current desired count as
+both its minimum and maximum, so it isn't scaled until the first action runs.
+
+Use autoscale list to show the service's scheduled actions, and autoscale
+unschedule to remove them.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &ServiceAutoscaleScheduleOperation{
+			Action: AAS.ScheduledAction{
+				Name:     args[1],
+				Schedule: flagServiceAutoscaleScheduleSchedule,
+				Timezone: flagServiceAutoscaleScheduleTimezone,
+			},
+			ServiceName: args[0],
+		}
+
+		if cmd.Flags().Changed("count") {
+			if cmd.Flags().Changed("min") || cmd.Flags().Changed("max") {
+				console.IssueExit("--count can't be used with --min or --max")
+			}
+
+			operation.Action.MinCapacity = &flagServiceAutoscaleScheduleCount
+			operation.Action.MaxCapacity = &flagServiceAutoscaleScheduleCount
+		}
+
+		if cmd.Flags().Changed("min") {
+			operation.Action.MinCapacity = &flagServiceAutoscaleScheduleMin
+		}
+
+		if cmd.Flags().Changed("max") {
+			operation.Action.MaxCapacity = &flagServiceAutoscaleScheduleMax
+		}
+
+		operation.Validate()
+		scheduleServiceAutoscaling(operation)
+	},
+}
+
+func init() {
+	serviceAutoscaleScheduleCmd.Flags().StringVar(&flagServiceAutoscaleScheduleSchedule, "schedule", "", "Schedule expression [e.g. cron(0 8 ? * MON-FRI *), rate(6 hours), at(2026-11-27T06:00:00)]")
+	serviceAutoscaleScheduleCmd.Flags().StringVar(&flagServiceAutoscaleScheduleTimezone, "timezone", "", "Time zone of the schedule [e.g. America/New_York] (default: UTC)")
+	serviceAutoscaleScheduleCmd.Flags().Int64Var(&flagServiceAutoscaleScheduleCount, "count", 0, "Number of tasks to scale to (sets both the minimum and maximum)")
+	serviceAutoscaleScheduleCmd.Flags().Int64Var(&flagServiceAutoscaleScheduleMin, "min", 0, "Minimum number of tasks from the scheduled time")
+	serviceAutoscaleScheduleCmd.Flags().Int64Var(&flagServiceAutoscaleScheduleMax, "max", 0, "Maximum number of tasks from the scheduled time")
+
+	serviceAutoscaleCmd.AddCommand(serviceAutoscaleScheduleCmd)
+}
+
+func scheduleServiceAutoscaling(operation *ServiceAutoscaleScheduleOperation) {
+	aas := AAS.New(sess)
+	_, registered, err := aas.DescribeServiceTarget(clusterName, operation.ServiceName)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not schedule autoscaling for service %s", operation.ServiceName)
+	}
+
+	if !registered {
+		ecs := ECS.New(sess, clusterName)
+		service := ecs.DescribeService(operation.ServiceName)
+
+		if service.Status != statusActive {
+			console.IssueExit("Service %s not found", operation.ServiceName)
+		}
+
+		target := AAS.ScalableTarget{MinCapacity: service.DesiredCount, MaxCapacity: service.DesiredCount}
+
+		if err := aas.RegisterServiceTarget(clusterName, operation.ServiceName, target); err != nil {
+			console.ErrorExit(err, "Could not schedule autoscaling for service %s", operation.ServiceName)
+		}
+	}
+
+	if err := aas.PutServiceScheduledAction(clusterName, operation.ServiceName, operation.Action); err != nil {
+		console.ErrorExit(err, "Could not schedule autoscaling for service %s", operation.ServiceName)
+	}
+
+	console.Info(
+		"Scheduled service %s to scale to %s tasks at %s",
+		operation.ServiceName,
+		operation.Action.Capacity(),
+		operation.Action.Schedule,
+	)
+}
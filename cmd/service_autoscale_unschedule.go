@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	AAS "github.com/jpignata/fargate/applicationautoscaling"
+	"github.com/jpignata/fargate/console"
+	"github.com/spf13/cobra"
+)
+
+type ServiceAutoscaleUnscheduleOperation struct {
+	ActionName  string
+	ServiceName string
+}
+
+var serviceAutoscaleUnscheduleCmd = &cobra.Command{
+	Use:   "unschedule <service-name> <action-name>",
+	Short: "Remove a scheduled scaling action",
+	Long: `Remove a scheduled scaling action
+
+Removes the named scheduled action from the service. The service keeps the
+range within which it's currently scaled.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &ServiceAutoscaleUnscheduleOperation{
+			ActionName:  args[1],
+			ServiceName: args[0],
+		}
+
+		unscheduleServiceAutoscaling(operation)
+	},
+}
+
+func init() {
+	serviceAutoscaleCmd.AddCommand(serviceAutoscaleUnscheduleCmd)
+}
+
+func unscheduleServiceAutoscaling(operation *ServiceAutoscaleUnscheduleOperation) {
+	aas := AAS.New(sess)
+
+	if err := aas.DeleteServiceScheduledAction(clusterName, operation.ServiceName, operation.ActionName); err != nil {
+		console.ErrorExit(err, "Could not remove scheduled action")
+	}
+
+	console.Info("Removed scheduled action %s from service %s", operation.ActionName, operation.ServiceName)
+}
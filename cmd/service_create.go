@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 
 	CWL "github.com/jpignata/fargate/cloudwatchlogs"
+	CodeDeploy "github.com/jpignata/fargate/codedeploy"
 	"github.com/jpignata/fargate/console"
 	"github.com/jpignata/fargate/docker"
 	EC2 "github.com/jpignata/fargate/ec2"
@@ -14,25 +16,41 @@ import (
 	ELBV2 "github.com/jpignata/fargate/elbv2"
 	"github.com/jpignata/fargate/git"
 	IAM "github.com/jpignata/fargate/iam"
+	SD "github.com/jpignata/fargate/servicediscovery"
 	"github.com/spf13/cobra"
 )
 
 const typeService = "service"
 
 type ServiceCreateOperation struct {
-	Cpu              string
-	EnvVars          []ECS.EnvVar
-	Image            string
-	LoadBalancerArn  string
-	LoadBalancerName string
-	Memory           string
-	Num              int64
-	Port             Port
-	Rules            []ELBV2.Rule
-	SecurityGroupIds []string
-	ServiceName      string
-	SubnetIds        []string
-	TaskRole         string
+	BlueGreen         bool
+	CapacityProviders []ECS.CapacityProviderStrategyItem
+	CircuitBreaker    ECS.CircuitBreaker
+	Cpu               string
+	CpuArchitecture   string
+	DeploymentConfig  string
+	EnableExec        bool
+	EnvVars           []ECS.EnvVar
+	EphemeralStorage  int64
+	GracePeriod       int64
+	Image             string
+	LoadBalancerArn   string
+	LoadBalancerName  string
+	Memory            string
+	NoPublicIp        bool
+	Num               int64
+	PlatformVersion   string
+	Port              Port
+	Rules             []ELBV2.Rule
+	Secrets           []ECS.Secret
+	SecurityGroupIds  []string
+	ServiceDiscovery  string
+	ServiceName       string
+	Sidecars          []ECS.Sidecar
+	SubnetIds         []string
+	TaskRole          string
+	TestPort          int64
+	Volumes           []ECS.Volume
 }
 
 func (o *ServiceCreateOperation) SetPort(inputPort string) {
@@ -65,6 +83,58 @@ func (o *ServiceCreateOperation) Validate() {
 	if o.Num < 1 {
 		console.ErrorExit(err, "Invalid number of tasks to keep running: %d, num must be > 1", o.Num)
 	}
+
+	if o.EphemeralStorage != 0 {
+		if err := ECS.ValidateEphemeralStorage(o.EphemeralStorage); err != nil {
+			console.ErrorExit(err, "Invalid command line flags")
+		}
+	}
+
+	if o.PlatformVersion != "" {
+		if err := ECS.ValidatePlatformVersion(o.PlatformVersion); err != nil {
+			console.ErrorExit(err, "Invalid command line flags")
+		}
+	}
+
+	if o.GracePeriod != 0 {
+		if err := ECS.ValidateHealthCheckGracePeriod(o.GracePeriod); err != nil {
+			console.ErrorExit(err, "Invalid command line flags")
+		}
+
+		if o.LoadBalancerArn == "" {
+			console.IssueExit("Setting a health check grace period requires a load balancer")
+		}
+	}
+
+	if o.ServiceDiscovery != "" {
+		if err := SD.ValidateNamespaceName(o.ServiceDiscovery); err != nil {
+			console.ErrorExit(err, "Invalid command line flags")
+		}
+	}
+
+	if o.BlueGreen {
+		var msgs []string
+
+		if o.LoadBalancerArn == "" {
+			msgs = append(msgs, "--blue-green requires --lb")
+		}
+
+		if len(o.Rules) > 0 {
+			msgs = append(msgs, "--blue-green services must be the load balancer's default route and can't have rules")
+		}
+
+		if o.CircuitBreaker.Enable {
+			msgs = append(msgs, "--circuit-breaker can't be used with --blue-green, which rolls back failed deployments itself")
+		}
+
+		if o.TestPort < 1 || o.TestPort > 65535 {
+			msgs = append(msgs, fmt.Sprintf("Invalid test port %d [specify within 1 - 65535]", o.TestPort))
+		}
+
+		if len(msgs) > 0 {
+			console.ErrorExit(errors.New(strings.Join(msgs, ", ")), "Invalid command line flags")
+		}
+	}
 }
 
 func (o *ServiceCreateOperation) SetLoadBalancer(lb string) {
@@ -131,22 +201,64 @@ func (o *ServiceCreateOperation) SetEnvVars(inputEnvVars []string) {
 	o.EnvVars = extractEnvVars(inputEnvVars)
 }
 
+func (o *ServiceCreateOperation) SetSecrets(inputSecrets []string) {
+	o.Secrets = extractSecrets(inputSecrets)
+}
+
+func (o *ServiceCreateOperation) SetVolumes(inputVolumes []string) {
+	o.Volumes = extractVolumes(inputVolumes)
+}
+
+func (o *ServiceCreateOperation) SetSidecars(expressions []string) {
+	o.Sidecars = extractSidecars(expressions)
+}
+
+func (o *ServiceCreateOperation) SetCapacityProviderStrategy(spot bool, expressions []string) {
+	o.CapacityProviders = extractCapacityProviderStrategy(spot, expressions)
+}
+
+func (o *ServiceCreateOperation) SetCircuitBreaker(mode string) {
+	circuitBreaker, err := ECS.ParseCircuitBreaker(mode)
+
+	if err != nil {
+		console.ErrorExit(err, "Invalid command line flags")
+	}
+
+	o.CircuitBreaker = circuitBreaker
+}
+
 func (o *ServiceCreateOperation) SetSecurityGroupIds(securityGroupIds []string) {
 	o.SecurityGroupIds = securityGroupIds
 }
 
 var (
-	flagServiceCreateCpu              string
-	flagServiceCreateEnvVars          []string
-	flagServiceCreateImage            string
-	flagServiceCreateLb               string
-	flagServiceCreateMemory           string
-	flagServiceCreateNum              int64
-	flagServiceCreatePort             string
-	flagServiceCreateRules            []string
-	flagServiceCreateSecurityGroupIds []string
-	flagServiceCreateSubnetIds        []string
-	flagServiceCreateTaskRole         string
+	flagServiceCreateArch              string
+	flagServiceCreateBlueGreen         bool
+	flagServiceCreateCapacityProviders []string
+	flagServiceCreateCircuitBreaker    string
+	flagServiceCreateCpu               string
+	flagServiceCreateDeploymentConfig  string
+	flagServiceCreateEnableExec        bool
+	flagServiceCreateEnvVars           []string
+	flagServiceCreateGracePeriod       int64
+	flagServiceCreateImage             string
+	flagServiceCreateLb                string
+	flagServiceCreateMemory            string
+	flagServiceCreateNoPublicIp        bool
+	flagServiceCreateNum               int64
+	flagServiceCreatePlatformVersion   string
+	flagServiceCreatePort              string
+	flagServiceCreateRules             []string
+	flagServiceCreateSecrets           []string
+	flagServiceCreateSecurityGroupIds  []string
+	flagServiceCreateServiceDiscovery  string
+	flagServiceCreateSidecars          []string
+	flagServiceCreateSpot              bool
+	flagServiceCreateStorage           int64
+	flagServiceCreateSubnetIds         []string
+	flagServiceCreateTaskRole          string
+	flagServiceCreateTestPort          int64
+	flagServiceCreateVolumes           []string
 )
 
 var serviceCreateCmd = &cobra.Command{
@@ -170,6 +282,31 @@ CPU and memory configurations:
 If not specified, fargate will launch minimally sized tasks at 0.25 vCPU (256
 CPU units) and 0.5GB (512 MiB) of memory.
 
+The service's tasks run on x86_64 processors by default. Pass --arch arm64 to
+run them on Graviton (ARM64) processors instead, which cost less. When fargate
+builds the image, it's built for the given architecture using docker buildx,
+and later deploys build for the same architecture. An image given via --image
+must support the architecture.
+
+Tasks receive 20 GiB of ephemeral storage by default. A larger amount, from 21
+up to 200 GiB, can be requested via the --storage flag [e.g. --storage 50].
+
+Sidecar containers can be run alongside each task's main container via the
+--sidecar flag, in the form of NAME,image=IMAGE[,port=N][,env=KEY=VALUE]
+[,essential] [e.g. --sidecar nginx,image=nginx:1.25,port=80]. Specify --sidecar
+once per container. Sidecars reach the main container via localhost, and
+aren't essential unless marked so. The load balancer, if any, routes to the
+main container's port, and service deploy updates only the main container's
+image.
+
+EFS file systems can be mounted into each task's main container via the
+--volume flag, in the form of FILE_SYSTEM_ID:CONTAINER_PATH[:ro] [e.g. --volume
+fs-12345678:/mnt/data]. Specify --volume multiple times to mount several.
+Before creating the service, fargate checks that the file system has a mount
+target in the availability zone of each subnet and that the mount targets'
+security groups allow NFS traffic (TCP 2049) from the service's security
+groups. Traffic to the file system is encrypted in transit.
+
 The Docker container image to use in the service can be optionally specified
 via the --image flag. If not specified, fargate will build a new Docker
 container image from the current working directory and push it to Amazon ECR in
@@ -196,9 +333,23 @@ request. Both PATH and HOST types can include up to three wildcard characters:
 * to match multiple characters and ? to match a single character. If rules are
 omitted, the service will be the load balancer's default action.
 
+Applications which are slow to start can fail the load balancer's health checks
+and be stopped before they're ready. Pass --grace-period with a number of
+seconds [e.g. --grace-period 120] to have ECS ignore failing health checks of
+newly started tasks for that long.
+
 Environment variables can be specified via the --env flag. Specify --env with a
 key=value parameter multiple times to add multiple variables.
 
+Secrets can be injected into the environment of the service's tasks from
+Secrets Manager or SSM Parameter Store via the --secret flag, in the form of
+NAME=SOURCE where the source is a secret ARN, a parameter ARN, or a parameter
+name [e.g. --secret DB_PASSWORD=arn:aws:secretsmanager:us-east-1:123456789012:
+secret:db-AbCdEf or --secret API_KEY=/prod/api-key]. Unlike --env, secret
+values never appear in the task definition or service output. The
+ecsTaskExecutionRole is granted read access to each secret; secrets encrypted
+with a customer managed KMS key also need kms:Decrypt on that key.
+
 Specify the desired count of tasks the service should maintain by passing the
 --num flag with a number. If you omit this flag, fargate will configure a
 service with a desired number of tasks of 1.
@@ -209,24 +360,74 @@ groups, pass --security-group-id with a security group ID multiple times. If
 --security-group-id is omitted, a permissive security group will be applied to
 the service.
 
+The service's tasks are assigned a public IP so that they can reach the internet from a
+public subnet. Pass --no-public-ip to run them without one, such as in private
+subnets behind a NAT gateway or VPC endpoints. If none of the subnets route to
+an internet gateway, they're treated as private and no public IP is assigned.
+
 By default, the service will be created in the default VPC and attached
 to the default VPC subnets for each availability zone. You can override this by
 specifying explicit subnets by passing the --subnet-id flag with a subnet ID.
 
 A task role can be optionally specified via the --task-role flag by providing
 eith a full IAM role ARN or the name of an IAM role. The tasks run by the
-service will be able to assume this role.`,
+service will be able to assume this role.
+
+The service's tasks run on the LATEST platform version unless a specific
+version is given via the --platform-version flag [e.g. 1.4.0].
+
+To run the service's tasks at a discount on spare capacity, pass --spot to
+place them on Fargate Spot. Spot tasks may be stopped with two minutes' notice,
+after which the service replaces them. To keep a baseline on regular Fargate,
+pass --capacity-provider once per provider with an optional weight and base
+[e.g. --capacity-provider FARGATE,weight=1,base=2 --capacity-provider
+FARGATE_SPOT,weight=4].
+
+To have ECS fail a deployment whose tasks can't reach a steady state, pass
+--circuit-breaker on. Pass --circuit-breaker rollback to also roll the service
+back to its last completed deployment when one fails.
+
+To let other services in the VPC reach the service's tasks by DNS without a
+load balancer, pass --service-discovery with a private DNS namespace [e.g.
+--service-discovery myapp.internal]. The namespace is created in AWS Cloud Map
+if it doesn't exist, and each task is registered as an A record at
+<service-name>.<namespace> [e.g. web.myapp.internal]. The security groups must
+allow traffic from the calling services.
+
+To deploy the service blue/green via CodeDeploy, pass --blue-green along with
+--lb. The service must be the load balancer's default route. fargate creates a
+second target group and a test listener on --test-port [default 8080]. On each
+deploy, CodeDeploy starts replacement tasks, which can be reached on the test
+listener, then shifts production traffic to them as set by --deployment-config
+[default CodeDeployDefault.ECSAllAtOnce, or e.g.
+CodeDeployDefault.ECSCanary10Percent5Minutes]. A failed deployment is rolled
+back automatically, and the original tasks are stopped 5 minutes after a
+successful one.
+
+To be able to open a shell in the service's tasks via service exec, pass
+--enable-exec. The task role must allow the SSM Messages actions used by ECS
+Exec; see service exec for details.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		operation := &ServiceCreateOperation{
+			BlueGreen:        flagServiceCreateBlueGreen,
 			Cpu:              flagServiceCreateCpu,
+			CpuArchitecture:  extractCpuArchitecture(flagServiceCreateArch),
+			DeploymentConfig: flagServiceCreateDeploymentConfig,
+			EnableExec:       flagServiceCreateEnableExec,
+			EphemeralStorage: flagServiceCreateStorage,
+			GracePeriod:      flagServiceCreateGracePeriod,
 			Image:            flagServiceCreateImage,
 			Memory:           flagServiceCreateMemory,
+			NoPublicIp:       flagServiceCreateNoPublicIp,
 			Num:              flagServiceCreateNum,
+			PlatformVersion:  flagServiceCreatePlatformVersion,
 			SecurityGroupIds: flagServiceCreateSecurityGroupIds,
+			ServiceDiscovery: flagServiceCreateServiceDiscovery,
 			ServiceName:      args[0],
 			SubnetIds:        flagServiceCreateSubnetIds,
 			TaskRole:         flagServiceCreateTaskRole,
+			TestPort:         flagServiceCreateTestPort,
 		}
 
 		if flagServiceCreatePort != "" {
@@ -241,6 +442,15 @@ service will be able to assume this role.`,
 			operation.SetRules(flagServiceCreateRules)
 		}
 
+		if flagServiceCreateCircuitBreaker != "" {
+			operation.SetCircuitBreaker(flagServiceCreateCircuitBreaker)
+		}
+
+		operation.SetCapacityProviderStrategy(flagServiceCreateSpot, flagServiceCreateCapacityProviders)
+		operation.SetSecrets(flagServiceCreateSecrets)
+		operation.SetSidecars(flagServiceCreateSidecars)
+		operation.SetVolumes(flagServiceCreateVolumes)
+
 		if len(flagServiceCreateEnvVars) > 0 {
 			operation.SetEnvVars(flagServiceCreateEnvVars)
 		}
@@ -251,23 +461,40 @@ service will be able to assume this role.`,
 }
 
 func init() {
+	serviceCreateCmd.Flags().StringVar(&flagServiceCreateArch, "arch", "", "CPU architecture on which to run the service's tasks [x86_64, arm64] (default: x86_64)")
 	serviceCreateCmd.Flags().StringVarP(&flagServiceCreateCpu, "cpu", "c", "256", "Amount of cpu units to allocate for each task")
 	serviceCreateCmd.Flags().StringVarP(&flagServiceCreateMemory, "memory", "m", "512", "Amount of MiB to allocate for each task")
 	serviceCreateCmd.Flags().StringSliceVarP(&flagServiceCreateEnvVars, "env", "e", []string{}, "Environment variables to set [e.g. KEY=value] (can be specified multiple times)")
+	serviceCreateCmd.Flags().StringArrayVar(&flagServiceCreateSecrets, "secret", []string{}, "Secret to inject as an environment variable from Secrets Manager or SSM Parameter Store [e.g. DB_PASSWORD=arn:aws:secretsmanager:...] (can be specified multiple times)")
 	serviceCreateCmd.Flags().StringVarP(&flagServiceCreatePort, "port", "p", "", "Port to listen on [e.g., 80, 443, http:8080, https:8443, tcp:1935]")
 	serviceCreateCmd.Flags().StringVarP(&flagServiceCreateImage, "image", "i", "", "Docker image to run in the service; if omitted Fargate will build an image from the Dockerfile in the current directory")
 	serviceCreateCmd.Flags().StringVarP(&flagServiceCreateLb, "lb", "l", "", "Name of a load balancer to use")
+	serviceCreateCmd.Flags().Int64Var(&flagServiceCreateGracePeriod, "grace-period", 0, "Seconds to ignore failing load balancer health checks of newly started tasks, such as for slow-starting applications")
 	serviceCreateCmd.Flags().StringSliceVarP(&flagServiceCreateRules, "rule", "r", []string{}, "Routing rule for the load balancer [e.g. host=api.example.com, path=/api/*]; if omitted service will be the default route (can be specified multiple times)")
 	serviceCreateCmd.Flags().Int64VarP(&flagServiceCreateNum, "num", "n", 1, "Number of tasks instances to keep running")
 	serviceCreateCmd.Flags().StringSliceVar(&flagServiceCreateSecurityGroupIds, "security-group-id", []string{}, "ID of a security group to apply to the service (can be specified multiple times)")
 	serviceCreateCmd.Flags().StringSliceVar(&flagServiceCreateSubnetIds, "subnet-id", []string{}, "ID of a subnet in which to place the service (can be specified multiple times)")
+	serviceCreateCmd.Flags().BoolVar(&flagServiceCreateNoPublicIp, "no-public-ip", false, "Don't assign the service's tasks public IPs, such as for private subnets")
+	serviceCreateCmd.Flags().BoolVar(&flagServiceCreateEnableExec, "enable-exec", false, "Enable ECS Exec so that commands can be run in the service's tasks via service exec")
+	serviceCreateCmd.Flags().StringVar(&flagServiceCreatePlatformVersion, "platform-version", "", "Fargate platform version on which to run the service's tasks [e.g. 1.4.0] (default: LATEST)")
+	serviceCreateCmd.Flags().BoolVar(&flagServiceCreateSpot, "spot", false, "Run the service's tasks on Fargate Spot")
+	serviceCreateCmd.Flags().StringArrayVar(&flagServiceCreateCapacityProviders, "capacity-provider", []string{}, "Capacity provider on which to place tasks [e.g. FARGATE_SPOT,weight=4,base=1] (can be specified multiple times)")
+	serviceCreateCmd.Flags().StringVar(&flagServiceCreateCircuitBreaker, "circuit-breaker", "", "Deployment circuit breaker mode [off, on, rollback] (default: off)")
+	serviceCreateCmd.Flags().BoolVar(&flagServiceCreateBlueGreen, "blue-green", false, "Deploy the service blue/green via CodeDeploy, shifting traffic to replacement tasks (requires --lb)")
+	serviceCreateCmd.Flags().Int64Var(&flagServiceCreateTestPort, "test-port", defaultTestPort, "Load balancer port on which replacement tasks can be tested before traffic is shifted (with --blue-green)")
+	serviceCreateCmd.Flags().StringVar(&flagServiceCreateDeploymentConfig, "deployment-config", CodeDeploy.DefaultDeploymentConfigName, "CodeDeploy deployment configuration controlling how traffic is shifted (with --blue-green)")
+	serviceCreateCmd.Flags().StringVar(&flagServiceCreateServiceDiscovery, "service-discovery", "", "Private DNS namespace in which to register the service's tasks via Cloud Map [e.g. myapp.internal]")
+	serviceCreateCmd.Flags().StringArrayVar(&flagServiceCreateSidecars, "sidecar", []string{}, "Sidecar container to run alongside the main container [e.g. nginx,image=nginx:1.25,port=80] (can be specified multiple times)")
+	serviceCreateCmd.Flags().StringArrayVar(&flagServiceCreateVolumes, "volume", []string{}, "EFS file system to mount [e.g. fs-12345678:/mnt/data] (can be specified multiple times)")
+	serviceCreateCmd.Flags().Int64Var(&flagServiceCreateStorage, "storage", 0, "Amount of GiB of ephemeral storage to allocate for each task (21-200)")
 	serviceCreateCmd.Flags().StringVarP(&flagServiceCreateTaskRole, "task-role", "", "", "Name or ARN of an IAM role that the service's tasks can assume")
 
 	serviceCmd.AddCommand(serviceCreateCmd)
 }
 
 func createService(operation *ServiceCreateOperation) {
-	var targetGroupArn string
+	var targetGroupArn, serviceRegistryArn string
+	var deploymentGroup *CodeDeploy.DeploymentGroup
 
 	cwl := CWL.New(sess)
 	ec2 := EC2.New(sess)
@@ -275,9 +502,16 @@ func createService(operation *ServiceCreateOperation) {
 	elbv2 := ELBV2.New(sess)
 	ecs := ECS.New(sess, clusterName)
 	iam := IAM.New(sess)
+
+	ensureCapacityProviders(ecs, operation.CapacityProviders)
+
 	ecsTaskExecutionRoleArn := iam.CreateEcsTaskExecutionRole()
 	logGroupName := cwl.CreateLogGroup(serviceLogGroupFormat, operation.ServiceName)
 
+	if len(operation.Secrets) > 0 {
+		grantSecretAccess(iam, operation.Secrets)
+	}
+
 	if len(operation.SecurityGroupIds) == 0 {
 		defaultSecurityGroupID, _ := ec2.GetDefaultSecurityGroupID()
 		operation.SecurityGroupIds = []string{defaultSecurityGroupID}
@@ -287,6 +521,12 @@ func createService(operation *ServiceCreateOperation) {
 		operation.SubnetIds, _ = ec2.GetDefaultSubnetIDs()
 	}
 
+	operation.NoPublicIp = detectNoPublicIp(ec2, operation.NoPublicIp, operation.SubnetIds)
+
+	if len(operation.Volumes) > 0 {
+		checkVolumesReachable(ec2, operation.Volumes, operation.SubnetIds, operation.SecurityGroupIds)
+	}
+
 	if operation.Image == "" {
 		var tag, repositoryUri string
 
@@ -306,7 +546,7 @@ func createService(operation *ServiceCreateOperation) {
 		username, password := ecr.GetUsernameAndPassword()
 
 		repository.Login(username, password)
-		repository.Build(tag)
+		buildImage(repository, tag, operation.CpuArchitecture)
 		repository.Push(tag)
 
 		operation.Image = repository.UriFor(tag)
@@ -330,36 +570,156 @@ func createService(operation *ServiceCreateOperation) {
 		} else {
 			elbv2.ModifyLoadBalancerDefaultAction(operation.LoadBalancerArn, targetGroupArn)
 		}
+
+		if operation.BlueGreen {
+			deploymentGroup = createBlueGreenResources(elbv2, operation, vpcId, targetGroupArn)
+		}
+	}
+
+	if operation.ServiceDiscovery != "" {
+		serviceRegistryArn = createServiceRegistry(ec2, operation)
 	}
 
 	taskDefinitionArn := ecs.CreateTaskDefinition(
 		&ECS.CreateTaskDefinitionInput{
-			Cpu:              operation.Cpu,
-			EnvVars:          operation.EnvVars,
-			ExecutionRoleArn: ecsTaskExecutionRoleArn,
-			Image:            operation.Image,
-			Memory:           operation.Memory,
-			Name:             operation.ServiceName,
-			Port:             operation.Port.Number,
-			LogGroupName:     logGroupName,
-			LogRegion:        region,
-			TaskRole:         operation.TaskRole,
-			Type:             typeService,
+			Cpu:                 operation.Cpu,
+			CpuArchitecture:     operation.CpuArchitecture,
+			EnvVars:             operation.EnvVars,
+			EphemeralStorageGiB: operation.EphemeralStorage,
+			ExecutionRoleArn:    ecsTaskExecutionRoleArn,
+			Image:               operation.Image,
+			Memory:              operation.Memory,
+			Name:                operation.ServiceName,
+			Port:                operation.Port.Number,
+			LogGroupName:        logGroupName,
+			LogRegion:           region,
+			Secrets:             operation.Secrets,
+			Sidecars:            operation.Sidecars,
+			TaskRole:            operation.TaskRole,
+			Volumes:             operation.Volumes,
+			Type:                typeService,
 		},
 	)
 
+	deploymentController := ECS.DeploymentControllerEcs
+
+	if deploymentGroup != nil {
+		deploymentController = ECS.DeploymentControllerCodeDeploy
+	}
+
 	ecs.CreateService(
 		&ECS.CreateServiceInput{
-			Cluster:           clusterName,
-			DesiredCount:      operation.Num,
-			Name:              operation.ServiceName,
-			Port:              operation.Port.Number,
-			SecurityGroupIds:  operation.SecurityGroupIds,
-			SubnetIds:         operation.SubnetIds,
-			TargetGroupArn:    targetGroupArn,
-			TaskDefinitionArn: taskDefinitionArn,
+			CircuitBreaker:         operation.CircuitBreaker,
+			Cluster:                clusterName,
+			DeploymentController:   deploymentController,
+			DesiredCount:           operation.Num,
+			EnableExecuteCommand:   operation.EnableExec,
+			HealthCheckGracePeriod: operation.GracePeriod,
+			Name:                   operation.ServiceName,
+			NoPublicIp:             operation.NoPublicIp,
+			PlatformVersion:        operation.PlatformVersion,
+			Port:                   operation.Port.Number,
+			SecurityGroupIds:       operation.SecurityGroupIds,
+			ServiceRegistryArn:     serviceRegistryArn,
+			SubnetIds:              operation.SubnetIds,
+			TargetGroupArn:         targetGroupArn,
+			TaskDefinitionArn:      taskDefinitionArn,
 		},
 	)
 
 	console.Info("Created service %s", operation.ServiceName)
+
+	if deploymentGroup != nil {
+		codeDeploy := CodeDeploy.New(sess)
+		deploymentGroup.RoleArn = iam.CreateEcsCodeDeployRole()
+
+		if err := codeDeploy.CreateDeploymentGroup(deploymentGroup); err != nil {
+			console.ErrorExit(err, "Could not configure blue/green deployments for service %s", operation.ServiceName)
+		}
+
+		console.Info("Created CodeDeploy application %s", deploymentGroup.ApplicationName)
+	}
+}
+
+// createServiceRegistry creates the Cloud Map private DNS namespace, if it
+// doesn't already exist in the service's VPC, and a service registry in it in
+// which ECS registers the service's tasks.
+func createServiceRegistry(ec2 EC2.SDKClient, operation *ServiceCreateOperation) string {
+	sd := SD.New(sess)
+	vpcId, err := ec2.GetSubnetVPCID(operation.SubnetIds[0])
+
+	if err != nil {
+		console.ErrorExit(err, "Could not determine VPC of subnet %s", operation.SubnetIds[0])
+	}
+
+	namespaceId, err := sd.CreatePrivateDnsNamespace(operation.ServiceDiscovery, vpcId)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not configure service discovery")
+	}
+
+	serviceRegistryArn, err := sd.CreateService(namespaceId, operation.ServiceName)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not configure service discovery")
+	}
+
+	console.Info("Registering tasks at %s.%s", operation.ServiceName, operation.ServiceDiscovery)
+
+	return serviceRegistryArn
+}
+
+// createBlueGreenResources creates the replacement target group and the test
+// listener, which initially routes to the service's target group, and returns
+// the deployment group to create once the service exists.
+func createBlueGreenResources(elbv2 ELBV2.SDKClient, operation *ServiceCreateOperation, vpcId, targetGroupArn string) *CodeDeploy.DeploymentGroup {
+	listeners := elbv2.GetListeners(operation.LoadBalancerArn)
+
+	if len(listeners) == 0 {
+		console.IssueExit("Load balancer %s has no listeners", operation.LoadBalancerName)
+	}
+
+	prodListener := productionListener(listeners)
+	blueGreenTargetGroupName := fmt.Sprintf(blueGreenTargetGroupFormat, clusterName, operation.ServiceName)
+	_, err := elbv2.CreateTargetGroup(
+		ELBV2.CreateTargetGroupParameters{
+			Name:     blueGreenTargetGroupName,
+			Port:     operation.Port.Number,
+			Protocol: operation.Port.Protocol,
+			VPCID:    vpcId,
+		},
+	)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not create target group %s", blueGreenTargetGroupName)
+	}
+
+	testListenerArn, err := elbv2.CreateListener(
+		ELBV2.CreateListenerParameters{
+			CertificateARNs:       prodListener.CertificateARNs,
+			DefaultTargetGroupARN: targetGroupArn,
+			LoadBalancerARN:       operation.LoadBalancerArn,
+			Port:                  operation.TestPort,
+			Protocol:              prodListener.Protocol,
+		},
+	)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not create test listener on port %d", operation.TestPort)
+	}
+
+	return &CodeDeploy.DeploymentGroup{
+		ApplicationName:      CodeDeploy.ApplicationName(clusterName, operation.ServiceName),
+		ClusterName:          clusterName,
+		DeploymentConfigName: operation.DeploymentConfig,
+		Name:                 operation.ServiceName,
+		ProdListenerArn:      prodListener.ARN,
+		ServiceName:          operation.ServiceName,
+		TargetGroupNames: []string{
+			fmt.Sprintf("%s-%s", clusterName, operation.ServiceName),
+			blueGreenTargetGroupName,
+		},
+		TerminationWaitMinutes: blueGreenTerminationWaitMinutes,
+		TestListenerArn:        testListenerArn,
+	}
 }
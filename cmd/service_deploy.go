@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/jpignata/fargate/console"
 	"github.com/jpignata/fargate/docker"
 	ECR "github.com/jpignata/fargate/ecr"
@@ -10,11 +12,19 @@ import (
 )
 
 type ServiceDeployOperation struct {
-	ServiceName string
-	Image       string
+	EphemeralStorage int64
+	ServiceName      string
+	Image            string
+	Timeout          time.Duration
+	Wait             bool
 }
 
-var flagServiceDeployImage string
+var (
+	flagServiceDeployImage   string
+	flagServiceDeployStorage int64
+	flagServiceDeployTimeout time.Duration
+	flagServiceDeployWait    bool
+)
 
 var serviceDeployCmd = &cobra.Command{
 	Use:   "deploy <service-name>",
@@ -26,12 +36,36 @@ via the --image flag. If not specified, fargate will build a new Docker
 container image from the current working directory and push it to Amazon ECR in
 a repository named for the task group. If the current working directory is a
 git repository, the container image will be tagged with the short ref of the
-HEAD commit. If not, a timestamp in the format of YYYYMMDDHHMMSS will be used.`,
+HEAD commit. If not, a timestamp in the format of YYYYMMDDHHMMSS will be used.
+The image is built for the CPU architecture of the service's task definition
+[e.g. arm64 for services created with --arch arm64].
+
+The amount of ephemeral storage for each task, from 21 up to 200 GiB, can be
+changed as part of the deploy via the --storage flag [e.g. --storage 50].
+
+To block until the deployment completes, such as in a deploy script, pass
+--wait. Service events are printed as they happen, and fargate exits non-zero
+if the deployment fails or the service hasn't reached a steady state within
+--timeout [default 10m].
+
+Services created with --blue-green are deployed via CodeDeploy, which shifts
+traffic to the replacement tasks and rolls back automatically if the
+deployment fails. With --wait, fargate waits for the CodeDeploy deployment to
+complete.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		operation := &ServiceDeployOperation{
-			ServiceName: args[0],
-			Image:       flagServiceDeployImage,
+			EphemeralStorage: flagServiceDeployStorage,
+			ServiceName:      args[0],
+			Image:            flagServiceDeployImage,
+			Timeout:          flagServiceDeployTimeout,
+			Wait:             flagServiceDeployWait,
+		}
+
+		if operation.EphemeralStorage != 0 {
+			if err := ECS.ValidateEphemeralStorage(operation.EphemeralStorage); err != nil {
+				console.ErrorExit(err, "Invalid command line flags")
+			}
 		}
 
 		deployService(operation)
@@ -41,6 +75,11 @@ HEAD commit. If not, a timestamp in the format of YYYYMMDDHHMMSS will be used.`,
 func init() {
 	serviceDeployCmd.Flags().StringVarP(&flagServiceDeployImage, "image", "i", "", "Docker image to run in the service; if omitted Fargate will build an image from the Dockerfile in the current directory")
 
+	serviceDeployCmd.Flags().Int64Var(&flagServiceDeployStorage, "storage", 0, "Amount of GiB of ephemeral storage to allocate for each task (21-200); if omitted the current amount is kept")
+
+	serviceDeployCmd.Flags().BoolVar(&flagServiceDeployWait, "wait", false, "Wait for the service to reach a steady state, printing service events")
+	serviceDeployCmd.Flags().DurationVar(&flagServiceDeployTimeout, "timeout", defaultWaitTimeout, "Amount of time to wait for the service to reach a steady state (with --wait)")
+
 	serviceCmd.AddCommand(serviceDeployCmd)
 }
 
@@ -63,13 +102,22 @@ func deployService(operation *ServiceDeployOperation) {
 		}
 
 		repository.Login(username, password)
-		repository.Build(tag)
+		buildImage(repository, tag, ecs.GetCpuArchitectureFromTaskDefinition(service.TaskDefinitionArn))
 		repository.Push(tag)
 
 		operation.Image = repository.UriFor(tag)
 	}
 
 	taskDefinitionArn := ecs.UpdateTaskDefinitionImage(service.TaskDefinitionArn, operation.Image)
-	ecs.UpdateServiceTaskDefinition(operation.ServiceName, taskDefinitionArn)
+
+	if operation.EphemeralStorage != 0 {
+		taskDefinitionArn = ecs.UpdateTaskDefinitionEphemeralStorage(taskDefinitionArn, operation.EphemeralStorage)
+	}
+
+	deploymentId := updateServiceTaskDefinition(ecs, service, taskDefinitionArn)
 	console.Info("Deployed %s to service %s", operation.Image, operation.ServiceName)
+
+	if operation.Wait {
+		waitForDeployment(ecs, operation.ServiceName, deploymentId, operation.Timeout)
+	}
 }
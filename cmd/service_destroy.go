@@ -3,9 +3,12 @@ package cmd
 import (
 	"fmt"
 
+	AAS "github.com/jpignata/fargate/applicationautoscaling"
+	CodeDeploy "github.com/jpignata/fargate/codedeploy"
 	"github.com/jpignata/fargate/console"
 	ECS "github.com/jpignata/fargate/ecs"
 	ELBV2 "github.com/jpignata/fargate/elbv2"
+	SD "github.com/jpignata/fargate/servicediscovery"
 	"github.com/spf13/cobra"
 )
 
@@ -43,6 +46,12 @@ func destroyService(operation *ServiceDestroyOperation) {
 		console.ErrorExit(err, "Cannot destroy service %s", operation.ServiceName)
 	}
 
+	targetGroupArns := []string{service.TargetGroupArn}
+
+	if service.DeploymentController == ECS.DeploymentControllerCodeDeploy {
+		targetGroupArns = append(targetGroupArns, destroyBlueGreenResources(elbv2, service)...)
+	}
+
 	if service.TargetGroupArn != "" {
 		loadBalancerArn := elbv2.GetTargetGroupLoadBalancerArn(service.TargetGroupArn)
 		loadBalancer := elbv2.DescribeLoadBalancerByARN(loadBalancerArn)
@@ -50,7 +59,7 @@ func destroyService(operation *ServiceDestroyOperation) {
 
 		for _, listener := range listeners {
 			for _, rule := range elbv2.DescribeRules(listener.ARN) {
-				if rule.TargetGroupARN == service.TargetGroupArn {
+				if Contains(targetGroupArns, rule.TargetGroupARN) {
 					if rule.IsDefault {
 						defaultTargetGroupName := fmt.Sprintf(defaultTargetGroupFormat, loadBalancer.Name)
 						defaultTargetGroupArn := elbv2.GetTargetGroupArn(defaultTargetGroupName)
@@ -74,9 +83,55 @@ func destroyService(operation *ServiceDestroyOperation) {
 			}
 		}
 
-		elbv2.DeleteTargetGroupByArn(service.TargetGroupArn)
+		for _, targetGroupArn := range targetGroupArns {
+			elbv2.DeleteTargetGroupByArn(targetGroupArn)
+		}
+	}
+
+	aas := AAS.New(sess)
+
+	if err := aas.DeregisterServiceTarget(clusterName, operation.ServiceName); err != nil {
+		console.ErrorExit(err, "Cannot destroy service %s", operation.ServiceName)
 	}
 
 	ecs.DestroyService(operation.ServiceName)
+
+	if service.ServiceRegistryArn != "" {
+		sd := SD.New(sess)
+
+		if err := sd.DeleteService(service.ServiceRegistryArn); err != nil {
+			console.ErrorExit(err, "Could not remove service discovery for service %s", operation.ServiceName)
+		}
+	}
 	console.Info("Destroyed service %s", operation.ServiceName)
 }
+
+// destroyBlueGreenResources deletes the service's CodeDeploy application and
+// test listener, and returns the ARNs of the service's other target groups.
+func destroyBlueGreenResources(elbv2 ELBV2.SDKClient, service ECS.Service) []string {
+	var targetGroupArns []string
+
+	codeDeploy := CodeDeploy.New(sess)
+	applicationName := CodeDeploy.ApplicationName(clusterName, service.Name)
+	deploymentGroup, err := codeDeploy.DescribeDeploymentGroup(applicationName, service.Name)
+
+	if err != nil {
+		console.ErrorExit(err, "Cannot destroy service %s", service.Name)
+	}
+
+	if deploymentGroup.TestListenerArn != "" {
+		elbv2.DeleteListener(deploymentGroup.TestListenerArn)
+	}
+
+	for _, targetGroupName := range deploymentGroup.TargetGroupNames {
+		if targetGroupArn := elbv2.GetTargetGroupArn(targetGroupName); targetGroupArn != "" && targetGroupArn != service.TargetGroupArn {
+			targetGroupArns = append(targetGroupArns, targetGroupArn)
+		}
+	}
+
+	if err := codeDeploy.DeleteApplication(applicationName); err != nil {
+		console.ErrorExit(err, "Cannot destroy service %s", service.Name)
+	}
+
+	return targetGroupArns
+}
@@ -53,7 +53,7 @@ func serviceEnvSet(operation *ServiceEnvSetOperation) {
 	service := ecs.DescribeService(operation.ServiceName)
 	taskDefinitionArn := ecs.AddEnvVarsToTaskDefinition(service.TaskDefinitionArn, operation.EnvVars)
 
-	ecs.UpdateServiceTaskDefinition(operation.ServiceName, taskDefinitionArn)
+	updateServiceTaskDefinition(ecs, service, taskDefinitionArn)
 
 	console.Info("Set %s environment variables:", operation.ServiceName)
 
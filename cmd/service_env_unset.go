@@ -55,7 +55,7 @@ func serviceEnvUnset(operation *ServiceEnvUnsetOperation) {
 	service := ecs.DescribeService(operation.ServiceName)
 	taskDefinitionArn := ecs.RemoveEnvVarsFromTaskDefinition(service.TaskDefinitionArn, operation.Keys)
 
-	ecs.UpdateServiceTaskDefinition(operation.ServiceName, taskDefinitionArn)
+	updateServiceTaskDefinition(ecs, service, taskDefinitionArn)
 
 	console.Info("Unset %s environment variables:", operation.ServiceName)
 
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
+	"github.com/spf13/cobra"
+)
+
+const serviceEventsPollInterval = 10 * time.Second
+
+type ServiceEventsOperation struct {
+	Follow      bool
+	ServiceName string
+}
+
+var flagServiceEventsFollow bool
+
+var serviceEventsCmd = &cobra.Command{
+	Use:   "events <service-name>",
+	Short: "Show service events and task stops",
+	Long: `Show service events and task stops
+
+Service events, such as tasks being started or failing to be placed, are shown
+in chronological order alongside the service's recently stopped tasks and why
+they stopped, and the service's deployments starting, completing, or failing.
+This is useful for following what went wrong during a deployment whose tasks
+keep failing. ECS retains stopped tasks for about an hour.
+
+Pass --follow to keep polling the service and print new events as they happen,
+until interrupted.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &ServiceEventsOperation{
+			Follow:      flagServiceEventsFollow,
+			ServiceName: args[0],
+		}
+
+		getServiceEvents(operation)
+	},
+}
+
+func init() {
+	serviceEventsCmd.Flags().BoolVarP(&flagServiceEventsFollow, "follow", "f", false, "Poll the service and continuously print new events")
+
+	serviceCmd.AddCommand(serviceEventsCmd)
+}
+
+func getServiceEvents(operation *ServiceEventsOperation) {
+	ecs := ECS.New(sess, clusterName)
+	events := ecs.DescribeServiceEventFeed(operation.ServiceName)
+
+	if len(events) == 0 && !operation.Follow {
+		console.InfoExit("No events found")
+	}
+
+	seen := make(map[string]bool)
+
+	for {
+		for _, event := range unseenEvents(events, seen) {
+			fmt.Printf("[%s] %-10s %s\n", event.CreatedAt, event.Source, event.Message)
+		}
+
+		if !operation.Follow {
+			return
+		}
+
+		time.Sleep(serviceEventsPollInterval)
+
+		events = ecs.DescribeServiceEventFeed(operation.ServiceName)
+	}
+}
+
+// unseenEvents returns the events whose IDs aren't in seen, adding them to it.
+func unseenEvents(events []ECS.Event, seen map[string]bool) []ECS.Event {
+	var unseen []ECS.Event
+
+	for _, event := range events {
+		if seen[event.Id] {
+			continue
+		}
+
+		seen[event.Id] = true
+		unseen = append(unseen, event)
+	}
+
+	return unseen
+}
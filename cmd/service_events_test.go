@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	ECS "github.com/jpignata/fargate/ecs"
+)
+
+func TestUnseenEvents(t *testing.T) {
+	seen := make(map[string]bool)
+	first := []ECS.Event{
+		ECS.Event{Id: "a", Message: "has started 1 tasks"},
+		ECS.Event{Id: "task/1/stopped", Message: "task 1 stopped"},
+	}
+	second := append(first, ECS.Event{Id: "b", Message: "has reached a steady state"})
+
+	if unseen := unseenEvents(first, seen); !reflect.DeepEqual(unseen, first) {
+		t.Errorf("expected %v, got %v", first, unseen)
+	}
+
+	if unseen := unseenEvents(second, seen); !reflect.DeepEqual(unseen, second[2:]) {
+		t.Errorf("expected %v, got %v", second[2:], unseen)
+	}
+
+	if unseen := unseenEvents(second, seen); len(unseen) != 0 {
+		t.Errorf("expected no events, got %v", unseen)
+	}
+}
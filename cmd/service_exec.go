@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
+	"github.com/spf13/cobra"
+)
+
+type ServiceExecOperation struct {
+	Command       string
+	ContainerName string
+	ServiceName   string
+	TaskId        string
+}
+
+var (
+	flagServiceExecCommand   string
+	flagServiceExecContainer string
+	flagServiceExecTask      string
+)
+
+var serviceExecCmd = &cobra.Command{
+	Use:   "exec <service name>",
+	Short: "Open a shell in a service's task",
+	Long: `Open a shell in a service's task
+
+Runs a command interactively within one of a service's running tasks using
+ECS Exec, by default a shell (/bin/sh). Pass --command to run something else
+[e.g. --command "rails console"]. The service's first running task is used
+unless a specific task is given via the --task flag, and the task's first
+container unless another is named via the --container flag.
+
+The service must have been created with ECS Exec enabled by passing
+--enable-exec to service create, and its task role must allow the
+ssmmessages:CreateControlChannel, ssmmessages:CreateDataChannel,
+ssmmessages:OpenControlChannel, and ssmmessages:OpenDataChannel actions. The
+Session Manager plugin for the AWS CLI must also be installed locally.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &ServiceExecOperation{
+			Command:       flagServiceExecCommand,
+			ContainerName: flagServiceExecContainer,
+			ServiceName:   args[0],
+			TaskId:        flagServiceExecTask,
+		}
+
+		execService(operation)
+	},
+}
+
+func init() {
+	serviceExecCmd.Flags().StringVar(&flagServiceExecCommand, "command", defaultExecCommand, "Command to run in the container")
+	serviceExecCmd.Flags().StringVar(&flagServiceExecContainer, "container", "", "Name of the container in which to run the command (default: the task's first container)")
+	serviceExecCmd.Flags().StringVarP(&flagServiceExecTask, "task", "t", "", "ID of the task in which to run the command (default: the service's first running task)")
+
+	serviceCmd.AddCommand(serviceExecCmd)
+}
+
+func execService(operation *ServiceExecOperation) {
+	ecs := ECS.New(sess, clusterName)
+	taskId := operation.TaskId
+
+	if taskId == "" {
+		tasks := ecs.DescribeServiceTasks(operation.ServiceName, ECS.TaskFilter{}).Tasks
+
+		if len(tasks) == 0 {
+			console.IssueExit("No running tasks found for service %s", operation.ServiceName)
+		}
+
+		taskId = tasks[0].TaskId
+	}
+
+	startExecSession(ecs, taskId, operation.ContainerName, operation.Command)
+}
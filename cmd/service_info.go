@@ -8,10 +8,12 @@ import (
 	"text/tabwriter"
 
 	ACM "github.com/jpignata/fargate/acm"
+	CodeDeploy "github.com/jpignata/fargate/codedeploy"
 	"github.com/jpignata/fargate/console"
 	EC2 "github.com/jpignata/fargate/ec2"
 	ECS "github.com/jpignata/fargate/ecs"
 	ELBV2 "github.com/jpignata/fargate/elbv2"
+	SD "github.com/jpignata/fargate/servicediscovery"
 	"github.com/spf13/cobra"
 )
 
@@ -54,7 +56,7 @@ func getServiceInfo(operation *ServiceInfoOperation) {
 	ec2 := EC2.New(sess)
 	elbv2 := ELBV2.New(sess)
 	service := ecs.DescribeService(operation.ServiceName)
-	tasks := ecs.DescribeTasksForService(operation.ServiceName)
+	tasks := ecs.DescribeTasksForService(operation.ServiceName, ECS.TaskFilter{})
 
 	if service.Status != statusActive {
 		console.InfoExit("Service not found")
@@ -73,9 +75,65 @@ func getServiceInfo(operation *ServiceInfoOperation) {
 		console.KeyValue("Task Role", "%s\n", service.TaskRole)
 	}
 
+	if len(service.Containers) > 1 {
+		console.KeyValue("Containers", "\n")
+
+		for i, container := range service.Containers {
+			role := "sidecar"
+
+			if i == 0 {
+				role = "main"
+			} else if container.Essential {
+				role = "essential sidecar"
+			}
+
+			console.KeyValue("  "+container.Name, "%s (%s)\n", container.Image, role)
+
+			if len(container.PortMappings) > 0 {
+				var ports []string
+
+				for _, portMapping := range container.PortMappings {
+					ports = append(ports, fmt.Sprintf("%d/%s", portMapping.ContainerPort, portMapping.Protocol))
+				}
+
+				console.KeyValue("    Ports", "%s\n", strings.Join(ports, ", "))
+			}
+
+			if i > 0 && len(container.EnvVars) > 0 {
+				console.KeyValue("    Environment Variables", "\n")
+
+				for _, envVar := range container.EnvVars {
+					fmt.Printf("       %s=%s\n", envVar.Key, envVar.Value)
+				}
+			}
+		}
+	}
+
 	console.KeyValue("Subnets", "%s\n", strings.Join(service.SubnetIds, ", "))
 	console.KeyValue("Security Groups", "%s\n", strings.Join(service.SecurityGroupIds, ", "))
 
+	if service.ServiceRegistryArn != "" {
+		sd := SD.New(sess)
+
+		if hostname, err := sd.Hostname(service.ServiceRegistryArn); err == nil {
+			console.KeyValue("Service Discovery", "%s\n", hostname)
+		} else {
+			console.Debug("Could not describe service discovery: %v", err)
+		}
+	}
+
+	if service.DeploymentController == ECS.DeploymentControllerCodeDeploy {
+		console.KeyValue("Deployment", "blue/green via CodeDeploy application %s\n", CodeDeploy.ApplicationName(clusterName, service.Name))
+	}
+
+	if service.HealthCheckGracePeriod > 0 {
+		console.KeyValue("Health Check Grace Period", "%ds\n", service.HealthCheckGracePeriod)
+	}
+
+	if service.CircuitBreaker.Enable {
+		console.KeyValue("Circuit Breaker", "%s\n", service.CircuitBreaker)
+	}
+
 	if service.TargetGroupArn != "" {
 		if loadBalancerArn := elbv2.GetTargetGroupLoadBalancerArn(service.TargetGroupArn); loadBalancerArn != "" {
 			loadBalancer := elbv2.DescribeLoadBalancerByARN(loadBalancerArn)
@@ -134,15 +192,16 @@ func getServiceInfo(operation *ServiceInfoOperation) {
 		w := new(tabwriter.Writer)
 
 		w.Init(os.Stdout, 0, 8, 1, '\t', 0)
-		fmt.Fprintln(w, "ID\tIMAGE\tSTATUS\tRUNNING\tIP\tCPU\tMEMORY\tDEPLOYMENT\t")
+		fmt.Fprintln(w, "ID\tIMAGE\tSTATUS\tCONTAINERS\tRUNNING\tIP\tCPU\tMEMORY\tDEPLOYMENT\t")
 
 		for _, t := range tasks {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 				t.TaskId,
 				t.Image,
 				Humanize(t.LastStatus),
-				t.RunningFor(),
-				enis[t.EniId].PublicIpAddress,
+				containerCounts(t),
+				HumanizeDuration(t.RunningFor()),
+				taskIp(t, enis),
 				t.Cpu,
 				t.Memory,
 				t.DeploymentId,
@@ -12,16 +12,25 @@ import (
 )
 
 type ServiceProcessListOperation struct {
-	ServiceName string
+	IncludeStopped bool
+	ServiceName    string
 }
 
+var flagServicePsIncludeStopped bool
+
 var servicePsCmd = &cobra.Command{
 	Use:   "ps <service-name>",
 	Short: "List running tasks for a service",
-	Args:  cobra.ExactArgs(1),
+	Long: `List running tasks for a service
+
+Pass --include-stopped to also list recently stopped tasks, which ECS retains
+for about an hour, along with why they stopped. This is useful for finding
+tasks which keep failing during a deployment.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		operation := &ServiceProcessListOperation{
-			ServiceName: args[0],
+			IncludeStopped: flagServicePsIncludeStopped,
+			ServiceName:    args[0],
 		}
 
 		getServiceProcessList(operation)
@@ -29,6 +38,8 @@ var servicePsCmd = &cobra.Command{
 }
 
 func init() {
+	servicePsCmd.Flags().BoolVar(&flagServicePsIncludeStopped, "include-stopped", false, "Include recently stopped tasks")
+
 	serviceCmd.AddCommand(servicePsCmd)
 }
 
@@ -37,7 +48,10 @@ func getServiceProcessList(operation *ServiceProcessListOperation) {
 
 	ecs := ECS.New(sess, clusterName)
 	ec2 := EC2.New(sess)
-	tasks := ecs.DescribeTasksForService(operation.ServiceName)
+	serviceTasks := ecs.DescribeServiceTasks(operation.ServiceName, ECS.TaskFilter{IncludeStopped: operation.IncludeStopped})
+	tasks := serviceTasks.Tasks
+
+	console.KeyValue("Tasks", "%d/%d running, %d pending\n", serviceTasks.RunningCount, serviceTasks.DesiredCount, serviceTasks.PendingCount)
 
 	for _, task := range tasks {
 		if task.EniId != "" {
@@ -50,18 +64,28 @@ func getServiceProcessList(operation *ServiceProcessListOperation) {
 
 		w := new(tabwriter.Writer)
 		w.Init(os.Stdout, 0, 8, 1, '\t', 0)
-		fmt.Fprintln(w, "ID\tIMAGE\tSTATUS\tRUNNING\tIP\tCPU\tMEMORY\t")
+		if operation.IncludeStopped {
+			fmt.Fprintln(w, "ID\tIMAGE\tSTATUS\tRUNNING\tIP\tCPU\tMEMORY\tSTOPPED REASON\t")
+		} else {
+			fmt.Fprintln(w, "ID\tIMAGE\tSTATUS\tRUNNING\tIP\tCPU\tMEMORY\t")
+		}
 
 		for _, t := range tasks {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s",
 				t.TaskId,
 				t.Image,
 				Humanize(t.LastStatus),
-				t.RunningFor(),
-				enis[t.EniId].PublicIpAddress,
+				HumanizeDuration(t.RunningFor()),
+				taskIp(t, enis),
 				t.Cpu,
 				t.Memory,
 			)
+
+			if operation.IncludeStopped {
+				fmt.Fprintf(w, "\t%s", t.StopSummary())
+			}
+
+			fmt.Fprintln(w)
 		}
 
 		w.Flush()
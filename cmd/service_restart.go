@@ -17,7 +17,10 @@ var serviceRestartCmd = &cobra.Command{
 
 Creates a new set of tasks for the service and stops the previous tasks. This
 is useful if your service needs to reload data cached from an external source,
-for example.`,
+for example.
+
+Blue/green services deployed by CodeDeploy can't be restarted; deploy them
+again with fargate service deploy instead.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		operation := &ServiceRestartOperation{
@@ -34,6 +37,11 @@ func init() {
 
 func restartService(operation *ServiceRestartOperation) {
 	ecs := ECS.New(sess, clusterName)
+	service := ecs.DescribeService(operation.ServiceName)
+
+	if service.DeploymentController == ECS.DeploymentControllerCodeDeploy {
+		console.IssueExit("Blue/green service %s can't be restarted as its deployments are made by CodeDeploy; run fargate service deploy to replace its tasks", operation.ServiceName)
+	}
 
 	ecs.RestartService(operation.ServiceName)
 	console.Info("Restarted %s", operation.ServiceName)
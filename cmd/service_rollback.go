@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
+	"github.com/spf13/cobra"
+)
+
+type ServiceRollbackOperation struct {
+	Revision    int64
+	ServiceName string
+	Timeout     time.Duration
+	Wait        bool
+}
+
+var (
+	flagServiceRollbackRevision int64
+	flagServiceRollbackTimeout  time.Duration
+	flagServiceRollbackWait     bool
+)
+
+var serviceRollbackCmd = &cobra.Command{
+	Use:   "rollback <service-name>",
+	Short: "Roll back service to a previous task definition revision",
+	Long: `Roll back service to a previous task definition revision
+
+Redeploys the service with the revision of its task definition preceding the
+one it currently runs, restoring the image, environment variables, and
+settings it ran with before the last deploy. A specific revision can be rolled
+back to via the --revision flag [e.g. --revision 12]. Only active revisions
+can be rolled back to.
+
+Pass --wait to block until the rollback completes, printing service events as
+they happen. fargate exits non-zero if the service hasn't reached a steady
+state within --timeout [default 10m].`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &ServiceRollbackOperation{
+			Revision:    flagServiceRollbackRevision,
+			ServiceName: args[0],
+			Timeout:     flagServiceRollbackTimeout,
+			Wait:        flagServiceRollbackWait,
+		}
+
+		if operation.Revision < 0 {
+			console.IssueExit("Invalid revision %d: must be > 0", operation.Revision)
+		}
+
+		rollbackService(operation)
+	},
+}
+
+func init() {
+	serviceRollbackCmd.Flags().Int64Var(&flagServiceRollbackRevision, "revision", 0, "Task definition revision to roll back to (default: the previous revision)")
+	serviceRollbackCmd.Flags().BoolVar(&flagServiceRollbackWait, "wait", false, "Wait for the service to reach a steady state, printing service events")
+	serviceRollbackCmd.Flags().DurationVar(&flagServiceRollbackTimeout, "timeout", defaultWaitTimeout, "Amount of time to wait for the service to reach a steady state (with --wait)")
+
+	serviceCmd.AddCommand(serviceRollbackCmd)
+}
+
+func rollbackService(operation *ServiceRollbackOperation) {
+	ecs := ECS.New(sess, clusterName)
+	service := ecs.DescribeService(operation.ServiceName)
+	taskDefinitionArn, err := ecs.RollbackTaskDefinitionArn(service.TaskDefinitionArn, operation.Revision)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not roll back service %s", operation.ServiceName)
+	}
+
+	taskDefinition := ecs.DescribeTaskDefinition(taskDefinitionArn)
+
+	deploymentId := updateServiceTaskDefinition(ecs, service, taskDefinitionArn)
+	console.Info(
+		"Rolled back service %s to revision %d [%s]",
+		operation.ServiceName,
+		aws.Int64Value(taskDefinition.Revision),
+		aws.StringValue(taskDefinition.ContainerDefinitions[0].Image),
+	)
+
+	if operation.Wait {
+		waitForDeployment(ecs, operation.ServiceName, deploymentId, operation.Timeout)
+	}
+}
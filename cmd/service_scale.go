@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/jpignata/fargate/console"
 	ECS "github.com/jpignata/fargate/ecs"
@@ -15,8 +16,15 @@ const validScalePattern = "[-\\+]?[0-9]+"
 type ScaleServiceOperation struct {
 	ServiceName  string
 	DesiredCount int64
+	Timeout      time.Duration
+	Wait         bool
 }
 
+var (
+	flagServiceScaleTimeout time.Duration
+	flagServiceScaleWait    bool
+)
+
 func (o *ScaleServiceOperation) SetScale(scaleExpression string) {
 	ecs := ECS.New(sess, clusterName)
 	validScale := regexp.MustCompile(validScalePattern)
@@ -52,11 +60,17 @@ var serviceScaleCmd = &cobra.Command{
 
 Changes the number of desired tasks to be run in a service by the given scale
 expression. A scale expression can either be an absolute number or a delta
-specified with a sign such as +5 or -2.`,
+specified with a sign such as +5 or -2.
+
+Pass --wait to block until the service has the desired number of tasks
+running, printing service events as they happen. fargate exits non-zero if the
+service hasn't reached a steady state within --timeout [default 10m].`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		operation := &ScaleServiceOperation{
 			ServiceName: args[0],
+			Timeout:     flagServiceScaleTimeout,
+			Wait:        flagServiceScaleWait,
 		}
 
 		operation.SetScale(args[1])
@@ -66,6 +80,9 @@ specified with a sign such as +5 or -2.`,
 }
 
 func init() {
+	serviceScaleCmd.Flags().BoolVar(&flagServiceScaleWait, "wait", false, "Wait for the service to reach a steady state, printing service events")
+	serviceScaleCmd.Flags().DurationVar(&flagServiceScaleTimeout, "timeout", defaultWaitTimeout, "Amount of time to wait for the service to reach a steady state (with --wait)")
+
 	serviceCmd.AddCommand(serviceScaleCmd)
 }
 
@@ -74,4 +91,8 @@ func scaleService(operation *ScaleServiceOperation) {
 
 	ecs.SetDesiredCount(operation.ServiceName, operation.DesiredCount)
 	console.Info("Scaled service %s to %d", operation.ServiceName, operation.DesiredCount)
+
+	if operation.Wait {
+		waitForServiceStable(ecs, operation.ServiceName, operation.Timeout)
+	}
 }
@@ -9,20 +9,53 @@ import (
 )
 
 type ServiceUpdateOperation struct {
-	ServiceName string
-	Cpu         string
-	Memory      string
-	Service     ECS.Service
+	ServiceName    string
+	CircuitBreaker *ECS.CircuitBreaker
+	Cpu            string
+	GracePeriod    *int64
+	Memory         string
+	Service        ECS.Service
+}
+
+func (o *ServiceUpdateOperation) SetCircuitBreaker(mode string) {
+	circuitBreaker, err := ECS.ParseCircuitBreaker(mode)
+
+	if err != nil {
+		console.ErrorExit(err, "Invalid command line flags")
+	}
+
+	o.CircuitBreaker = &circuitBreaker
+}
+
+func (o *ServiceUpdateOperation) SetGracePeriod(seconds int64) {
+	if err := ECS.ValidateHealthCheckGracePeriod(seconds); err != nil {
+		console.ErrorExit(err, "Invalid command line flags")
+	}
+
+	o.GracePeriod = &seconds
 }
 
 func (o *ServiceUpdateOperation) Validate() {
 	ecs := ECS.New(sess, clusterName)
 
-	if o.Cpu == "" && o.Memory == "" {
-		console.ErrorExit(fmt.Errorf("--cpu and/or --memory must be supplied"), "Invalid command line arguments")
+	if o.Cpu == "" && o.Memory == "" && o.CircuitBreaker == nil && o.GracePeriod == nil {
+		console.ErrorExit(fmt.Errorf("--cpu, --memory, --circuit-breaker, and/or --grace-period must be supplied"), "Invalid command line arguments")
 	}
 
 	o.Service = ecs.DescribeService(o.ServiceName)
+
+	if o.CircuitBreaker != nil && o.Service.DeploymentController == ECS.DeploymentControllerCodeDeploy {
+		console.IssueExit("The deployment circuit breaker can't be used with blue/green service %s, whose failed deployments CodeDeploy rolls back itself", o.ServiceName)
+	}
+
+	if o.GracePeriod != nil && o.Service.TargetGroupArn == "" {
+		console.IssueExit("Setting a health check grace period requires a service with a load balancer")
+	}
+
+	if o.Cpu == "" && o.Memory == "" {
+		return
+	}
+
 	cpu, memory := ecs.GetCpuAndMemoryFromTaskDefinition(o.Service.TaskDefinitionArn)
 
 	if o.Cpu == "" {
@@ -41,12 +74,14 @@ func (o *ServiceUpdateOperation) Validate() {
 }
 
 var (
-	flagServiceUpdateCpu    string
-	flagServiceUpdateMemory string
+	flagServiceUpdateCircuitBreaker string
+	flagServiceUpdateCpu            string
+	flagServiceUpdateGracePeriod    int64
+	flagServiceUpdateMemory         string
 )
 
 var serviceUpdateCmd = &cobra.Command{
-	Use:   "update <service-name> --cpu <cpu-units> | --memory <MiB>",
+	Use:   "update <service-name> --cpu <cpu-units> | --memory <MiB> | --circuit-breaker <mode> | --grace-period <seconds>",
 	Short: "Update service configuration",
 	Long: `Update service configuration
 
@@ -63,7 +98,19 @@ configurations:
 | 2048            | 4096 through 16384 in 1GiB increments |
 | 4096            | 8192 through 30720 in 1GiB increments |
 
-At least one of --cpu or --memory must be specified.`,
+The deployment circuit breaker fails a deployment whose tasks can't reach a
+steady state. Pass --circuit-breaker on to enable it, rollback to also roll the
+service back to its last completed deployment when one fails, or off to disable
+it. Blue/green services can't use the circuit breaker, as CodeDeploy rolls
+back their failed deployments itself.
+
+The health check grace period is the number of seconds for which ECS ignores
+failing load balancer health checks of newly started tasks, so that slow to
+start applications aren't stopped before they're ready. Set it with
+--grace-period [e.g. --grace-period 120], or remove it with --grace-period 0.
+
+At least one of --cpu, --memory, --circuit-breaker, or --grace-period must be
+specified.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		operation := &ServiceUpdateOperation{
@@ -72,6 +119,14 @@ At least one of --cpu or --memory must be specified.`,
 			Memory:      flagServiceUpdateMemory,
 		}
 
+		if flagServiceUpdateCircuitBreaker != "" {
+			operation.SetCircuitBreaker(flagServiceUpdateCircuitBreaker)
+		}
+
+		if cmd.Flags().Changed("grace-period") {
+			operation.SetGracePeriod(flagServiceUpdateGracePeriod)
+		}
+
 		operation.Validate()
 
 		updateService(operation)
@@ -83,17 +138,39 @@ func init() {
 
 	serviceUpdateCmd.Flags().StringVarP(&flagServiceUpdateCpu, "cpu", "c", "", "Amount of cpu units to allocate for each task")
 	serviceUpdateCmd.Flags().StringVarP(&flagServiceUpdateMemory, "memory", "m", "", "Amount of MiB to allocate for each task")
+	serviceUpdateCmd.Flags().Int64Var(&flagServiceUpdateGracePeriod, "grace-period", 0, "Seconds to ignore failing load balancer health checks of newly started tasks")
+	serviceUpdateCmd.Flags().StringVar(&flagServiceUpdateCircuitBreaker, "circuit-breaker", "", "Deployment circuit breaker mode [off, on, rollback]")
 }
 
 func updateService(operation *ServiceUpdateOperation) {
 	ecs := ECS.New(sess, clusterName)
 
+	if operation.CircuitBreaker != nil {
+		if err := ecs.UpdateServiceCircuitBreaker(operation.ServiceName, *operation.CircuitBreaker); err != nil {
+			console.ErrorExit(err, "Could not update service %s", operation.ServiceName)
+		}
+
+		console.Info("Set service %s circuit breaker to %s", operation.ServiceName, operation.CircuitBreaker)
+	}
+
+	if operation.GracePeriod != nil {
+		if err := ecs.UpdateServiceHealthCheckGracePeriod(operation.ServiceName, *operation.GracePeriod); err != nil {
+			console.ErrorExit(err, "Could not update service %s", operation.ServiceName)
+		}
+
+		console.Info("Set service %s health check grace period to %ds", operation.ServiceName, *operation.GracePeriod)
+	}
+
+	if operation.Cpu == "" && operation.Memory == "" {
+		return
+	}
+
 	newTaskDefinitionArn := ecs.UpdateTaskDefinitionCpuAndMemory(
 		operation.Service.TaskDefinitionArn,
 		operation.Cpu,
 		operation.Memory,
 	)
 
-	ecs.UpdateServiceTaskDefinition(operation.ServiceName, newTaskDefinitionArn)
+	updateServiceTaskDefinition(ecs, operation.Service, newTaskDefinitionArn)
 	console.Info("Updated service %s to %s CPU units / %s MiB", operation.ServiceName, operation.Cpu, operation.Memory)
 }
@@ -1,6 +1,10 @@
 package cmd
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Humanize takes strings intended for machines and prettifies them for humans.
 func Humanize(s string) string {
@@ -28,3 +32,39 @@ func Map(vs []string, f func(string) string) []string {
 
 	return vsm
 }
+
+// Contains returns whether a slice of strings includes the given string.
+func Contains(vs []string, s string) bool {
+	for _, v := range vs {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HumanizeDuration renders a duration using its two most significant units
+// [e.g. 3d 0h, 5m 12s]. Durations under a minute are shown in seconds, and
+// negative or sub-second durations as 0s.
+func HumanizeDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+
+	days := d / (24 * time.Hour)
+	hours := (d % (24 * time.Hour)) / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	seconds := (d % time.Minute) / time.Second
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm %ds", minutes, seconds)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
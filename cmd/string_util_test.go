@@ -1,6 +1,9 @@
 package cmd
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 func ExampleHumanize() {
 	fmt.Println(Humanize("HELLO_COMPUTER"))
@@ -24,3 +27,25 @@ func ExampleMap() {
 	fmt.Printf("%v", Map([]string{"Pippin", "Merry"}, reverse))
 	// Output: [nippiP yrreM]
 }
+
+func ExampleContains() {
+	fmt.Println(Contains([]string{"Pippin", "Merry"}, "Merry"))
+	fmt.Println(Contains([]string{"Pippin", "Merry"}, "Sam"))
+	// Output:
+	// true
+	// false
+}
+
+func ExampleHumanizeDuration() {
+	fmt.Println(HumanizeDuration(72*time.Hour + 3*time.Minute + 9*time.Second))
+	fmt.Println(HumanizeDuration(5*time.Hour + 30*time.Second))
+	fmt.Println(HumanizeDuration(5*time.Minute + 12*time.Second))
+	fmt.Println(HumanizeDuration(10 * time.Second))
+	fmt.Println(HumanizeDuration(300 * time.Millisecond))
+	// Output:
+	// 3d 0h
+	// 5h 0m
+	// 5m 12s
+	// 10s
+	// 0s
+}
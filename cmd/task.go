@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
 	"github.com/spf13/cobra"
 )
 
@@ -19,3 +21,27 @@ Console, or fargate task stop, or until they are interrupted for any reason.`,
 func init() {
 	rootCmd.AddCommand(taskCmd)
 }
+
+// ensureClusterExists exits with an actionable message if the cluster is
+// missing, rather than letting the first task operation fail with a raw API
+// error.
+func ensureClusterExists(ecs ECS.ECS) {
+	if err := ecs.EnsureClusterExists(); err != nil {
+		console.ErrorExit(err, "Invalid cluster")
+	}
+}
+
+// ensureCapacityProviders exits if the capacity providers of the strategy
+// can't be associated with the cluster, such as Fargate Spot on a cluster
+// created before it was supported.
+func ensureCapacityProviders(ecs ECS.ECS, strategy []ECS.CapacityProviderStrategyItem) {
+	if err := ecs.EnsureCapacityProviders(strategy); err != nil {
+		console.ErrorExit(err, "Invalid capacity providers")
+	}
+}
+
+// validateLaunchType returns whether the launch type, in upper case, is one
+// tasks can be run on or listed by.
+func validateLaunchType(launchType string) bool {
+	return launchType == launchTypeFargate || launchType == launchTypeEc2
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultExecCommand   = "/bin/sh"
+	sessionManagerPlugin = "session-manager-plugin"
+)
+
+type TaskExecOperation struct {
+	Command       string
+	ContainerName string
+	TaskGroupName string
+	TaskId        string
+}
+
+var (
+	flagTaskExecCommand   string
+	flagTaskExecContainer string
+	flagTaskExecTask      string
+)
+
+var taskExecCmd = &cobra.Command{
+	Use:   "exec <task group name>",
+	Short: "Open a shell in a running task",
+	Long: `Open a shell in a running task
+
+Runs a command interactively within a task's container using ECS Exec, by
+default a shell (/bin/sh). Pass --command to run something else [e.g.
+--command "rails console"]. The first running task in the task group is used
+unless a specific task is given via the --task flag, and the task's first
+container unless another is named via the --container flag.
+
+The task must have been started with ECS Exec enabled by passing --enable-exec
+to task run, and its task role must allow the ssmmessages:CreateControlChannel,
+ssmmessages:CreateDataChannel, ssmmessages:OpenControlChannel, and
+ssmmessages:OpenDataChannel actions. The Session Manager plugin for the AWS CLI
+must also be installed locally.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &TaskExecOperation{
+			Command:       flagTaskExecCommand,
+			ContainerName: flagTaskExecContainer,
+			TaskGroupName: args[0],
+			TaskId:        flagTaskExecTask,
+		}
+
+		execTask(operation)
+	},
+}
+
+func init() {
+	taskExecCmd.Flags().StringVar(&flagTaskExecCommand, "command", defaultExecCommand, "Command to run in the container")
+	taskExecCmd.Flags().StringVar(&flagTaskExecContainer, "container", "", "Name of the container in which to run the command (default: the task's first container)")
+	taskExecCmd.Flags().StringVarP(&flagTaskExecTask, "task", "t", "", "ID of the task in which to run the command (default: the task group's first running task)")
+
+	taskCmd.AddCommand(taskExecCmd)
+}
+
+func execTask(operation *TaskExecOperation) {
+	ecs := ECS.New(sess, clusterName)
+
+	ensureClusterExists(ecs)
+
+	taskId := operation.TaskId
+
+	if taskId == "" {
+		tasks := ecs.DescribeTasksForTaskGroup(operation.TaskGroupName, ECS.TaskFilter{})
+
+		if len(tasks) == 0 {
+			console.IssueExit("No running tasks found in task group %s", operation.TaskGroupName)
+		}
+
+		taskId = tasks[0].TaskId
+	}
+
+	startExecSession(ecs, taskId, operation.ContainerName, operation.Command)
+}
+
+// startExecSession runs a command interactively within a task's container,
+// handing the terminal over to the Session Manager plugin until the session
+// ends.
+func startExecSession(ecs ECS.ECS, taskId, containerName, command string) {
+	plugin, err := exec.LookPath(sessionManagerPlugin)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not find the Session Manager plugin; install it to use ECS Exec")
+	}
+
+	session, err := ecs.ExecuteCommand(
+		&ECS.ExecuteCommandInput{
+			Command:       command,
+			ContainerName: containerName,
+			Interactive:   true,
+			TaskId:        taskId,
+		},
+	)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not start ECS Exec session")
+	}
+
+	sessionJSON, err := json.Marshal(session)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not serialize ECS Exec session")
+	}
+
+	parametersJSON, err := json.Marshal(map[string]string{"Target": session.Target})
+
+	if err != nil {
+		console.ErrorExit(err, "Could not serialize ECS Exec session")
+	}
+
+	console.Info("Starting session in %s (container %s)", taskId, session.ContainerName)
+
+	cmd := exec.Command(plugin, string(sessionJSON), session.Region, "StartSession", "", string(parametersJSON), session.Endpoint)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Control-C is meant for the remote command, which the plugin forwards,
+	// rather than for fargate.
+	signal.Ignore(os.Interrupt)
+
+	if err := cmd.Run(); err != nil {
+		console.ErrorExit(err, "ECS Exec session failed")
+	}
+}
@@ -2,20 +2,33 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	CW "github.com/jpignata/fargate/cloudwatch"
 	"github.com/jpignata/fargate/console"
 	EC2 "github.com/jpignata/fargate/ec2"
 	ECS "github.com/jpignata/fargate/ecs"
 	"github.com/spf13/cobra"
 )
 
+const taskUtilizationWindow = time.Hour
+
 type TaskInfoOperation struct {
+	CheckNetwork  bool
 	TaskGroupName string
 	TaskIds       []string
+	Timeline      bool
+	Utilization   bool
 }
 
-var flagTaskInfoTasks []string
+var (
+	flagTaskInfoCheckNetwork bool
+	flagTaskInfoTasks        []string
+	flagTaskInfoTimeline     bool
+	flagTaskInfoUtilization  bool
+)
 
 var taskInfoCmd = &cobra.Command{
 	Use:   "info <task group name>",
@@ -26,12 +39,28 @@ Shows extended information for each running task within a task group or for
 specific tasks specified with the --task flag. Information includes environment
 variables which could differ between tasks in a task group. To inspect multiple
 specific tasks within a task group specific --task with a task ID multiple
-times.`,
+times.
+
+Pass --check-network to verify that each task's subnet and security groups
+still exist. Tasks running on since-deleted network resources will fail to
+relaunch.
+
+Pass --timeline to show each task's lifecycle transitions, network
+connectivity, image pulls, and the last status and reason reported for each
+container. Use this to explain why a task is stuck in pending or stopped
+unexpectedly.
+
+Pass --utilization to show each task's average and peak CPU and memory usage
+over the last hour, to help spot over-provisioned tasks. This requires
+Container Insights with enhanced observability to be enabled on the cluster.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		operation := &TaskInfoOperation{
+			CheckNetwork:  flagTaskInfoCheckNetwork,
 			TaskGroupName: args[0],
 			TaskIds:       flagTaskInfoTasks,
+			Timeline:      flagTaskInfoTimeline,
+			Utilization:   flagTaskInfoUtilization,
 		}
 
 		getTaskInfo(operation)
@@ -42,6 +71,9 @@ func init() {
 	taskCmd.AddCommand(taskInfoCmd)
 
 	taskInfoCmd.Flags().StringSliceVarP(&flagTaskInfoTasks, "task", "t", []string{}, "Get info for specific task instances (can be specified multiple times)")
+	taskInfoCmd.Flags().BoolVar(&flagTaskInfoCheckNetwork, "check-network", false, "Verify that each task's subnet and security groups still exist")
+	taskInfoCmd.Flags().BoolVar(&flagTaskInfoTimeline, "timeline", false, "Show lifecycle transitions and container reasons for each task")
+	taskInfoCmd.Flags().BoolVar(&flagTaskInfoUtilization, "utilization", false, "Show CPU and memory usage from Container Insights")
 }
 
 func getTaskInfo(operation *TaskInfoOperation) {
@@ -51,10 +83,21 @@ func getTaskInfo(operation *TaskInfoOperation) {
 	ecs := ECS.New(sess, clusterName)
 	ec2 := EC2.New(sess)
 
-	if len(operation.TaskIds) > 0 {
+	ensureClusterExists(ecs)
+
+	switch {
+	case len(operation.TaskIds) == 1:
+		task, err := ecs.DescribeTask(operation.TaskIds[0])
+
+		if err != nil {
+			console.ErrorExit(err, "Could not find task")
+		}
+
+		tasks = []ECS.Task{*task}
+	case len(operation.TaskIds) > 1:
 		tasks = ecs.DescribeTasks(operation.TaskIds)
-	} else {
-		tasks = ecs.DescribeTasksForTaskGroup(operation.TaskGroupName)
+	default:
+		tasks = ecs.DescribeTasksForTaskGroup(operation.TaskGroupName, ECS.TaskFilter{})
 	}
 
 	if len(tasks) == 0 {
@@ -69,6 +112,22 @@ func getTaskInfo(operation *TaskInfoOperation) {
 
 	enis := ec2.DescribeNetworkInterfaces(eniIds)
 
+	addNetworkInterfaceDetails(tasks, enis)
+
+	if operation.CheckNetwork {
+		if err := ECS.ValidateTaskNetworks(tasks, ec2); err != nil {
+			console.ErrorExit(err, "Could not verify task network resources")
+		}
+	}
+
+	var cw CW.CloudWatch
+	var utilizationErr error
+
+	if operation.Utilization {
+		cw = CW.New(sess)
+		utilizationErr = checkContainerInsights(ecs)
+	}
+
 	console.KeyValue("Task Group Name", "%s\n", operation.TaskGroupName)
 	console.KeyValue("Task Instances", "%d\n", len(tasks))
 
@@ -77,12 +136,71 @@ func getTaskInfo(operation *TaskInfoOperation) {
 
 		console.KeyValue("  "+task.TaskId, "\n")
 		console.KeyValue("    Image", "%s\n", task.Image)
+
+		if task.TaskDefinitionFamily != "" {
+			console.KeyValue("    Task Definition", "%s:%d\n", task.TaskDefinitionFamily, task.TaskDefinitionRevision)
+		}
+
 		console.KeyValue("    Status", "%s\n", Humanize(task.LastStatus))
+
+		if task.PlatformVersion != "" {
+			console.KeyValue("    Platform Version", "%s\n", task.PlatformVersion)
+		}
+
+		console.KeyValue("    Health", "%s\n", Humanize(task.HealthStatus))
+
+		if summary := task.StopSummary(); summary != "" {
+			console.KeyValue("    Stopped", "%s\n", summary)
+		}
+
+		if task.Stopped() {
+			console.KeyValue("    Exit Code", "%s\n", task.ExitCodes())
+		}
+
 		console.KeyValue("    Started At", "%s\n", task.CreatedAt)
-		console.KeyValue("    IP", "%s\n", eni.PublicIpAddress)
+		ip := taskIp(task, enis)
+
+		if task.PublicIpAddress != "" {
+			console.KeyValue("    Public IP", "%s\n", task.PublicIpAddress)
+		}
+
+		if task.PublicDnsName != "" {
+			console.KeyValue("    Public DNS", "%s\n", task.PublicDnsName)
+		}
+
+		console.KeyValue("    Private IP", "%s\n", task.PrivateIpAddress)
+
+		if len(task.PortMappings) > 0 {
+			var ports []string
+
+			for _, portMapping := range task.PortMappings {
+				ports = append(ports, fmt.Sprintf("%s:%d/%s", ip, portMapping.ContainerPort, portMapping.Protocol))
+			}
+
+			console.KeyValue("    Ports", "%s\n", strings.Join(ports, ", "))
+		}
+
+		if task.CapacityProviderName != "" {
+			console.KeyValue("    Capacity Provider", "%s\n", task.CapacityProviderName)
+		}
+
 		console.KeyValue("    CPU", "%s\n", task.Cpu)
 		console.KeyValue("    Memory", "%s\n", task.Memory)
 
+		if rates, ok := ECS.FargateRatesForRegion(region); ok {
+			if cost, err := task.EstimatedCost(rates); err == nil {
+				console.KeyValue("    Estimated Cost", "$%.4f\n", cost)
+			}
+		}
+
+		if operation.Utilization {
+			printTaskUtilization(cw, task, utilizationErr)
+		}
+
+		if task.EphemeralStorageGiB > 0 {
+			console.KeyValue("    Ephemeral Storage", "%d GiB\n", task.EphemeralStorageGiB)
+		}
+
 		if task.TaskRole != "" {
 			console.KeyValue("    Task Role", "%s\n", task.TaskRole)
 		}
@@ -92,14 +210,136 @@ func getTaskInfo(operation *TaskInfoOperation) {
 		}
 
 		console.KeyValue("    Subnet", "%s\n", task.SubnetId)
+
+		if task.AttachmentStatus != "" {
+			console.KeyValue("    Network Interface", "%s (%s)\n", task.EniId, Humanize(task.AttachmentStatus))
+		}
+
+		if task.Connectivity != "" && task.ConnectivityAt != nil {
+			console.KeyValue("    Connectivity", "%s (since %s)\n", Humanize(task.Connectivity), *task.ConnectivityAt)
+		} else if task.Connectivity != "" {
+			console.KeyValue("    Connectivity", "%s\n", Humanize(task.Connectivity))
+		} else if task.AttachmentStatus != "" {
+			console.KeyValue("    Connectivity", "%s\n", "Not yet connected")
+		}
+
 		console.KeyValue("    Security Groups", "%s\n", strings.Join(eni.SecurityGroupIds, ", "))
 
-		if len(task.EnvVars) > 0 {
+		if len(task.Containers) > 1 {
+			console.KeyValue("    Containers", "\n")
+
+			for _, container := range task.Containers {
+				var essential string
+
+				if !container.Essential {
+					essential = ", non-essential"
+				}
+
+				fmt.Printf("      %s: %s (%s%s) %s\n", container.Name, Humanize(container.LastStatus), Humanize(container.HealthStatus), essential, container.Image)
+			}
+		}
+
+		if operation.Timeline {
+			printTaskTimeline(ecs, task.TaskId)
+		}
+
+		if operation.CheckNetwork {
+			if task.NetworkValid {
+				console.KeyValue("    Network", "%s\n", "OK")
+			} else {
+				console.KeyValue("    Network", "%s\n", "Subnet or security group no longer exists")
+			}
+		}
+
+		if env := task.EffectiveEnv(); len(env) > 0 {
+			var keys []string
+
+			for key := range env {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
 			console.KeyValue("    Environment Variables", "\n")
 
-			for _, envVar := range task.EnvVars {
-				fmt.Printf("      %s=%s\n", envVar.Key, envVar.Value)
+			for _, key := range keys {
+				fmt.Printf("      %s=%s\n", key, env[key])
 			}
 		}
 	}
 }
+
+func printTaskTimeline(ecs ECS.ECS, taskId string) {
+	timeline, err := ecs.DescribeTaskTimeline(taskId)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not describe task timeline")
+	}
+
+	if timeline.Connectivity != "" {
+		console.KeyValue("    Connectivity", "%s\n", Humanize(timeline.Connectivity))
+	}
+
+	if timeline.StopCode != "" {
+		console.KeyValue("    Stop Code", "%s\n", timeline.StopCode)
+	}
+
+	console.KeyValue("    Timeline", "\n")
+
+	for _, transition := range timeline.Transitions {
+		fmt.Printf("      %s  %-12s %s\n", transition.At.Format(time.RFC3339), transition.Status, transition.Description)
+	}
+
+	if len(timeline.Containers) > 0 {
+		console.KeyValue("    Container States", "\n")
+	}
+
+	for _, container := range timeline.Containers {
+		if container.Reason != "" {
+			fmt.Printf("      %s: %s (%s)\n", container.Name, Humanize(container.LastStatus), container.Reason)
+		} else {
+			fmt.Printf("      %s: %s\n", container.Name, Humanize(container.LastStatus))
+		}
+	}
+}
+
+// checkContainerInsights returns an error if the cluster doesn't publish the
+// per-task metrics needed to show utilization.
+func checkContainerInsights(ecs ECS.ECS) error {
+	setting, err := ecs.ContainerInsights()
+
+	if err != nil {
+		return err
+	}
+
+	if setting != ECS.ContainerInsightsEnhanced {
+		return fmt.Errorf("metrics unavailable: Container Insights with enhanced observability is not enabled on cluster %s", clusterName)
+	}
+
+	return nil
+}
+
+func printTaskUtilization(cw CW.CloudWatch, task ECS.Task, err error) {
+	if err == nil {
+		end := time.Now()
+		start := end.Add(-taskUtilizationWindow)
+
+		if !task.StoppedAt.IsZero() {
+			end = task.StoppedAt
+		}
+
+		if task.CreatedAt.After(start) {
+			start = task.CreatedAt
+		}
+
+		var utilization CW.Utilization
+
+		if utilization, err = cw.GetTaskUtilization(clusterName, task.TaskDefinitionFamily, task.TaskId, start, end); err == nil {
+			console.KeyValue("    CPU Used", "%.1f average, %.1f peak (of %s)\n", utilization.AverageCpu, utilization.PeakCpu, task.Cpu)
+			console.KeyValue("    Memory Used", "%.0f average, %.0f peak MiB (of %s)\n", utilization.AverageMemory, utilization.PeakMemory, task.Memory)
+
+			return
+		}
+	}
+
+	console.KeyValue("    Utilization", "%s\n", err)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/jpignata/fargate/console"
@@ -10,21 +11,67 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type TaskListOperation struct {
+	IncludeStopped bool
+	LaunchType     string
+	Prefix         string
+}
+
+var (
+	flagTaskListIncludeStopped bool
+	flagTaskListLaunchType     string
+	flagTaskListPrefix         string
+)
+
 var taskListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List running task groups",
+	Long: `List running task groups
+
+Task groups are listed with the number of running task instances. Pass
+--include-stopped to also count recently stopped tasks, so that task groups
+whose tasks have all exited are still listed.
+
+On shared clusters, pass --prefix to only list task groups whose names start
+with the given prefix [e.g. data-].
+
+Only tasks on AWS Fargate are listed by default. To list the tasks running on
+the container instances of an EC2-backed cluster instead, pass --launch-type
+EC2.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		listTaskGroups()
+		operation := &TaskListOperation{
+			IncludeStopped: flagTaskListIncludeStopped,
+			LaunchType:     strings.ToUpper(flagTaskListLaunchType),
+			Prefix:         flagTaskListPrefix,
+		}
+
+		if !validateLaunchType(operation.LaunchType) {
+			console.IssueExit("Invalid launch type: %s [valid launch types: %s, %s]", operation.LaunchType, launchTypeFargate, launchTypeEc2)
+		}
+
+		listTaskGroups(operation)
 	},
 }
 
 func init() {
+	taskListCmd.Flags().BoolVar(&flagTaskListIncludeStopped, "include-stopped", false, "Include recently stopped tasks")
+	taskListCmd.Flags().StringVar(&flagTaskListLaunchType, "launch-type", launchTypeFargate, "Only list tasks with this launch type [FARGATE, EC2]")
+	taskListCmd.Flags().StringVar(&flagTaskListPrefix, "prefix", "", "Only list task groups whose names start with this prefix")
+
 	taskCmd.AddCommand(taskListCmd)
 }
 
-func listTaskGroups() {
+func listTaskGroups(operation *TaskListOperation) {
 	ecs := ECS.New(sess, clusterName)
-	taskGroups := ecs.ListTaskGroups()
+	ecs.LaunchType = operation.LaunchType
+	ecs.Lightweight = true
+
+	ensureClusterExists(ecs)
+
+	taskGroups := ecs.ListTaskGroups(
+		&ECS.ListTaskGroupsInput{Prefix: operation.Prefix},
+		ECS.TaskFilter{IncludeStopped: operation.IncludeStopped},
+	)
 
 	if len(taskGroups) == 0 {
 		console.InfoExit("No tasks running")
@@ -32,13 +79,28 @@ func listTaskGroups() {
 
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
-	fmt.Fprintln(w, "NAME\tINSTANCES")
+
+	if operation.IncludeStopped {
+		fmt.Fprintln(w, "NAME\tINSTANCES\tSTOPPED\tFAMILIES")
+	} else {
+		fmt.Fprintln(w, "NAME\tINSTANCES\tFAMILIES")
+	}
 
 	for _, taskGroup := range taskGroups {
-		fmt.Fprintf(w, "%s\t%d\n",
-			taskGroup.TaskGroupName,
-			taskGroup.Instances,
-		)
+		if operation.IncludeStopped {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\n",
+				taskGroup.TaskGroupName,
+				taskGroup.Instances,
+				taskGroup.Stopped,
+				strings.Join(taskGroup.Families, ", "),
+			)
+		} else {
+			fmt.Fprintf(w, "%s\t%d\t%s\n",
+				taskGroup.TaskGroupName,
+				taskGroup.Instances,
+				strings.Join(taskGroup.Families, ", "),
+			)
+		}
 	}
 
 	w.Flush()
@@ -25,7 +25,9 @@ the --follow option. Logs are prefixed by their log stream name which is in the
 format of "fargate/<task-group-name>/<task-id>."
 
 Follow will continue to run and return logs until interrupted by Control-C. If
---follow is passed --end cannot be specified.
+--follow is passed --end cannot be specified. When following a single task
+specified via --task, fargate will stop following and exit once the task has
+stopped.
 
 Logs can be returned for specific tasks within a task group by passing a task
 ID via the --task flag. Pass --task with a task ID multiple times in order to
@@ -60,7 +62,11 @@ to search for log messages that include all terms.`,
 		operation.AddStartTime(flagTaskLogsStartTime)
 		operation.AddEndTime(flagTaskLogsEndTime)
 
-		GetLogs(operation)
+		if operation.Follow && len(flagTaskLogsTasks) == 1 && operation.Filter == "" {
+			followTaskLogs(operation, flagTaskLogsTasks[0])
+		} else {
+			GetLogs(operation)
+		}
 	},
 }
 
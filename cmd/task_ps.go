@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/jpignata/fargate/console"
@@ -12,16 +13,68 @@ import (
 )
 
 type TaskProcessListOperation struct {
-	TaskName string
+	DesiredStatus  string
+	IncludeStopped bool
+	JSON           bool
+	LastStatuses   []string
+	LaunchType     string
+	TaskName       string
 }
 
+var (
+	flagTaskPsDesiredStatus  string
+	flagTaskPsIncludeStopped bool
+	flagTaskPsJSON           bool
+	flagTaskPsLaunchType     string
+	flagTaskPsStatuses       []string
+)
+
 var taskPsCmd = &cobra.Command{
 	Use:   "ps <task name>",
 	Short: "List running tasks",
-	Args:  cobra.ExactArgs(1),
+	Long: `List running tasks
+
+Tasks can be filtered by their desired status (running, pending, or stopped)
+via the --desired-status flag, and by their last reported status [e.g.
+provisioning, running, deprovisioning] via the --status flag. Pass --status
+multiple times to match any of several statuses.
+
+The CAPACITY column shows the capacity provider a task is running on [e.g.
+FARGATE, FARGATE_SPOT], or its launch type if it wasn't placed by a capacity
+provider. Tasks on FARGATE_SPOT may be interrupted with two minutes' notice.
+
+To see why tasks died, pass --include-stopped to list recently stopped tasks
+alongside running ones. ECS keeps stopped tasks for about an hour. Stopped
+tasks are listed with their containers' exit codes and the reason they
+stopped [e.g. essential container exited: Essential container in task
+exited].
+
+Only tasks on AWS Fargate are listed by default. To list the tasks running on
+the container instances of an EC2-backed cluster instead, pass --launch-type
+EC2.
+
+Pass --json to print the tasks as a JSON array, including all task details,
+for use in scripts.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		operation := &TaskProcessListOperation{
-			TaskName: args[0],
+			DesiredStatus:  strings.ToUpper(flagTaskPsDesiredStatus),
+			IncludeStopped: flagTaskPsIncludeStopped,
+			JSON:           flagTaskPsJSON,
+			LaunchType:     strings.ToUpper(flagTaskPsLaunchType),
+			TaskName:       args[0],
+		}
+
+		if !validateLaunchType(operation.LaunchType) {
+			console.IssueExit("Invalid launch type: %s [valid launch types: %s, %s]", operation.LaunchType, launchTypeFargate, launchTypeEc2)
+		}
+
+		if operation.IncludeStopped && operation.DesiredStatus != "" {
+			console.IssueExit("--include-stopped cannot be used with --desired-status")
+		}
+
+		for _, status := range flagTaskPsStatuses {
+			operation.LastStatuses = append(operation.LastStatuses, strings.ToUpper(status))
 		}
 
 		getTaskProcessList(operation)
@@ -29,6 +82,12 @@ var taskPsCmd = &cobra.Command{
 }
 
 func init() {
+	taskPsCmd.Flags().StringVar(&flagTaskPsDesiredStatus, "desired-status", "", "Only list tasks with this desired status [running, pending, stopped]")
+	taskPsCmd.Flags().BoolVar(&flagTaskPsIncludeStopped, "include-stopped", false, "Also list recently stopped tasks, with their exit codes and stopped reasons")
+	taskPsCmd.Flags().BoolVar(&flagTaskPsJSON, "json", false, "Output tasks as JSON")
+	taskPsCmd.Flags().StringVar(&flagTaskPsLaunchType, "launch-type", launchTypeFargate, "Only list tasks with this launch type [FARGATE, EC2]")
+	taskPsCmd.Flags().StringSliceVar(&flagTaskPsStatuses, "status", []string{}, "Only list tasks with this last status (can be specified multiple times)")
+
 	taskCmd.AddCommand(taskPsCmd)
 }
 
@@ -36,8 +95,18 @@ func getTaskProcessList(operation *TaskProcessListOperation) {
 	var eniIds []string
 
 	ecs := ECS.New(sess, clusterName)
+	ecs.LaunchType = operation.LaunchType
 	ec2 := EC2.New(sess)
-	tasks := ecs.DescribeTasksForTaskGroup(operation.TaskName)
+
+	ensureClusterExists(ecs)
+
+	filter := ECS.TaskFilter{
+		DesiredStatus:  operation.DesiredStatus,
+		IncludeStopped: operation.IncludeStopped,
+		LastStatuses:   operation.LastStatuses,
+	}
+
+	tasks := ecs.DescribeTasksForTaskGroup(operation.TaskName, filter)
 
 	for _, task := range tasks {
 		if task.EniId != "" {
@@ -46,26 +115,111 @@ func getTaskProcessList(operation *TaskProcessListOperation) {
 	}
 
 	if len(tasks) == 0 {
+		if operation.JSON {
+			fmt.Println("[]")
+			return
+		}
+
 		console.InfoExit("No tasks found")
 	}
 
-	enis := ec2.DescribeNetworkInterfaces(eniIds)
+	addNetworkInterfaceDetails(tasks, ec2.DescribeNetworkInterfaces(eniIds))
+
+	if operation.JSON {
+		out, err := ECS.MarshalTasks(tasks)
+
+		if err != nil {
+			console.ErrorExit(err, "Could not serialize tasks")
+		}
+
+		fmt.Println(string(out))
+		return
+	}
+
+	// Exit codes and stopped reasons are only shown once tasks have stopped.
+	var anyStopped bool
+
+	for _, t := range tasks {
+		if t.Stopped() {
+			anyStopped = true
+		}
+	}
 
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
-	fmt.Fprintln(w, "ID\tIMAGE\tSTATUS\tRUNNING\tIP\tCPU\tMEMORY\t")
+
+	if anyStopped {
+		fmt.Fprintln(w, "ID\tIMAGE\tSTATUS\tHEALTH\tCONTAINERS\tRUNNING\tPUBLIC IP\tPRIVATE IP\tCPU\tMEMORY\tCAPACITY\tEXIT\tREASON\t")
+	} else {
+		fmt.Fprintln(w, "ID\tIMAGE\tSTATUS\tHEALTH\tCONTAINERS\tRUNNING\tPUBLIC IP\tPRIVATE IP\tCPU\tMEMORY\tCAPACITY\t")
+	}
 
 	for _, t := range tasks {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		capacity := t.CapacityProviderName
+
+		if capacity == "" {
+			capacity = t.LaunchType
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s",
 			t.TaskId,
 			t.Image,
 			Humanize(t.LastStatus),
-			t.RunningFor(),
-			enis[t.EniId].PublicIpAddress,
+			Humanize(t.HealthStatus),
+			containerCounts(t),
+			HumanizeDuration(t.RunningFor()),
+			t.PublicIpAddress,
+			t.PrivateIpAddress,
 			t.Cpu,
 			t.Memory,
+			capacity,
 		)
+
+		if anyStopped && t.Stopped() {
+			fmt.Fprintf(w, "\t%s\t%s", t.ExitCodes(), t.StopSummary())
+		} else if anyStopped {
+			fmt.Fprint(w, "\t\t")
+		}
+
+		fmt.Fprintln(w)
 	}
 
 	w.Flush()
 }
+
+// containerCounts summarizes how many of a task's containers are running
+// [e.g. 2/3].
+func containerCounts(t ECS.Task) string {
+	return fmt.Sprintf("%d/%d", t.RunningContainers(), len(t.Containers))
+}
+
+// addNetworkInterfaceDetails fills in the addresses and security groups of
+// tasks from their network interfaces. Tasks whose interfaces are gone, such as
+// stopped tasks, keep the private IP reported by ECS.
+func addNetworkInterfaceDetails(tasks []ECS.Task, enis map[string]EC2.Eni) {
+	for i := range tasks {
+		eni, ok := enis[tasks[i].EniId]
+
+		if !ok {
+			continue
+		}
+
+		tasks[i].PublicDnsName = eni.PublicDnsName
+		tasks[i].PublicIpAddress = eni.PublicIpAddress
+		tasks[i].SecurityGroupIds = eni.SecurityGroupIds
+
+		if tasks[i].PrivateIpAddress == "" {
+			tasks[i].PrivateIpAddress = eni.PrivateIpAddress
+		}
+	}
+}
+
+// taskIp returns a task's public IP or, for tasks without one such as those in
+// private subnets, its private IP.
+func taskIp(t ECS.Task, enis map[string]EC2.Eni) string {
+	if ip := enis[t.EniId].PublicIpAddress; ip != "" {
+		return ip
+	}
+
+	return t.PrivateIpAddress
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"github.com/jpignata/fargate/console"
+	EC2 "github.com/jpignata/fargate/ec2"
+	ECS "github.com/jpignata/fargate/ecs"
+	"github.com/spf13/cobra"
+)
+
+type TaskRestartOperation struct {
+	TaskGroupName string
+	TaskIds       []string
+}
+
+var flagTaskRestartTasks []string
+
+var taskRestartCmd = &cobra.Command{
+	Use:   "restart <task group name>",
+	Short: "Restart tasks",
+	Long: `Restart tasks
+
+Replaces all tasks within a task group, or individual tasks passed via the
+--task flag, with new tasks using the same task definition, command,
+environment variables, subnet, and security groups.
+
+Tasks are restarted one at a time: a replacement task is run and fargate waits
+for it to reach the running state before stopping the original task. If a
+replacement fails to start, the original task is left running. A summary of
+which tasks were and were not restarted is printed when complete.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &TaskRestartOperation{
+			TaskGroupName: args[0],
+			TaskIds:       flagTaskRestartTasks,
+		}
+
+		restartTasks(operation)
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(taskRestartCmd)
+
+	taskRestartCmd.Flags().StringSliceVarP(&flagTaskRestartTasks, "task", "t", []string{}, "Restart specific task instances (can be specified multiple times)")
+}
+
+func restartTasks(operation *TaskRestartOperation) {
+	var tasks []ECS.Task
+	var failed bool
+
+	ecs := ECS.New(sess, clusterName)
+	ecs.NetworkInterfaces = EC2.New(sess)
+
+	if len(operation.TaskIds) > 0 {
+		tasks = ecs.DescribeTasks(operation.TaskIds)
+	} else {
+		tasks = ecs.DescribeTasksForTaskGroup(operation.TaskGroupName, ECS.TaskFilter{})
+	}
+
+	if len(tasks) == 0 {
+		console.InfoExit("No tasks found")
+	}
+
+	for _, restart := range ecs.RestartTasks(tasks) {
+		switch {
+		case restart.Err == nil:
+			console.Info("Restarted task %s as %s", restart.TaskId, restart.NewTaskId)
+		case restart.NewTaskId != "":
+			failed = true
+			console.Error(restart.Err, "Started task %s but could not replace task %s", restart.NewTaskId, restart.TaskId)
+		default:
+			failed = true
+			console.Error(restart.Err, "Could not restart task %s", restart.TaskId)
+		}
+	}
+
+	if failed {
+		console.Exit(1)
+	}
+}
@@ -1,6 +1,16 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
 	CWL "github.com/jpignata/fargate/cloudwatchlogs"
 	"github.com/jpignata/fargate/console"
 	"github.com/jpignata/fargate/docker"
@@ -12,53 +22,282 @@ import (
 	"github.com/spf13/cobra"
 )
 
-const typeTask string = "task"
+const (
+	typeTask string = "task"
+
+	launchTypeEc2     = "EC2"
+	launchTypeFargate = "FARGATE"
+)
 
 type TaskRunOperation struct {
-	Cpu               string
-	EnvVars           []ECS.EnvVar
-	Image             string
-	Memory            string
-	Num               int64
-	SecurityGroupIds  []string
-	SubnetIds         []string
-	Command           []string
-	TaskName          string
-	TaskDefinitionArn string
-	TaskRole          string
+	AppendArgs           []string
+	CapacityProviders    []ECS.CapacityProviderStrategyItem
+	CheckEgress          bool
+	ContainerName        string
+	Cpu                  string
+	CpuArchitecture      string
+	DnsSearchDomains     []string
+	DnsServers           []string
+	DryRun               bool
+	EgressChecker        ECS.EgressChecker
+	EnableExec           bool
+	EntryPoint           []string
+	EnvVars              []ECS.EnvVar
+	EphemeralStorageGiB  int64
+	ExpandCommand        bool
+	IdempotencyKey       string
+	Image                string
+	LaunchType           string
+	Logs                 bool
+	Memory               string
+	MemoryReservation    int64
+	NoPublicIp           bool
+	Num                  int64
+	PlacementConstraints []string
+	PlatformVersion      string
+	Secrets              []ECS.Secret
+	SecurityGroupIds     []string
+	SecurityGroupNames   []string
+	Sidecars             []ECS.Sidecar
+	Spread               bool
+	SubnetIds            []string
+	SubnetNames          []string
+	Command              []string
+	TaskName             string
+	TaskDefinitionArn    string
+	TaskRole             string
+	Volumes              []ECS.Volume
+	Timeout              time.Duration
+	Wait                 bool
+	WaitRunning          bool
 }
 
 func (o *TaskRunOperation) Validate() {
-	err := validateCpuAndMemory(o.Cpu, o.Memory)
+	var err error
 
-	if err != nil {
-		console.ErrorExit(err, "Invalid settings: %s CPU units / %s MiB", o.Cpu, o.Memory)
+	// With an existing task definition, CPU and memory are overrides which are
+	// validated once any omitted value is filled in from the task definition.
+	if o.TaskDefinitionArn == "" {
+		err = validateCpuAndMemory(o.Cpu, o.Memory)
+
+		if err != nil {
+			console.ErrorExit(err, "Invalid settings: %s CPU units / %s MiB", o.Cpu, o.Memory)
+		}
 	}
 
 	if o.Num < 1 {
 		console.ErrorExit(err, "Invalid number of tasks: %d, num must be > 1", o.Num)
 	}
+
+	o.LaunchType = strings.ToUpper(o.LaunchType)
+
+	if !validateLaunchType(o.LaunchType) {
+		console.IssueExit("Invalid launch type: %s [valid launch types: %s, %s]", o.LaunchType, launchTypeFargate, launchTypeEc2)
+	}
+
+	if o.LaunchType == launchTypeFargate && len(o.PlacementConstraints) > 0 {
+		console.IssueExit("Placement constraints can only be used with the %s launch type", launchTypeEc2)
+	}
+
+	if o.LaunchType == launchTypeFargate && o.MemoryReservation != 0 {
+		console.IssueExit("A memory reservation can only be used with the %s launch type", launchTypeEc2)
+	}
+
+	if len(o.Command) > 0 && len(o.AppendArgs) > 0 {
+		console.IssueExit("--command and arguments after -- cannot be used together")
+	}
+
+	if o.Wait && o.Num != 1 {
+		console.IssueExit("Only a single task can be run with --wait")
+	}
+
+	if o.Wait && o.WaitRunning {
+		console.IssueExit("--wait and --wait-running cannot be used together")
+	}
+
+	if o.Logs && !o.Wait {
+		console.IssueExit("--logs can only be used with --wait")
+	}
+
+	if o.Timeout > 0 && !o.Wait && !o.WaitRunning {
+		console.IssueExit("--timeout can only be used with --wait or --wait-running")
+	}
+
+	if o.LaunchType == launchTypeFargate && (len(o.DnsServers) > 0 || len(o.DnsSearchDomains) > 0) {
+		console.IssueExit("DNS servers and search domains can only be used with the %s launch type", launchTypeEc2)
+	}
+
+	if o.PlatformVersion != "" {
+		if o.LaunchType == launchTypeEc2 {
+			console.IssueExit("A platform version can only be used with the %s launch type", launchTypeFargate)
+		}
+
+		if err := ECS.ValidatePlatformVersion(o.PlatformVersion); err != nil {
+			console.ErrorExit(err, "Invalid command line flags")
+		}
+	}
+
+	if o.CpuArchitecture != "" && o.TaskDefinitionArn != "" {
+		console.IssueExit("--arch cannot be used with --task-definition-arn; the task definition sets its own CPU architecture")
+	}
+
+	if len(o.Volumes) > 0 && o.TaskDefinitionArn != "" {
+		console.IssueExit("--volume cannot be used with --task-definition-arn; the task definition sets its own volumes")
+	}
+
+	if len(o.Sidecars) > 0 && o.TaskDefinitionArn != "" {
+		console.IssueExit("--sidecar cannot be used with --task-definition-arn; the task definition sets its own containers")
+	}
+
+	if o.LaunchType == launchTypeEc2 && len(o.CapacityProviders) > 0 {
+		console.IssueExit("Capacity providers can only be used with the %s launch type", launchTypeFargate)
+	}
+}
+
+func (o *TaskRunOperation) RunTaskInput() *ECS.RunTaskInput {
+	input := &ECS.RunTaskInput{
+		AppendArgs:           o.AppendArgs,
+		CapacityProviders:    o.CapacityProviders,
+		ClusterName:          clusterName,
+		ContainerName:        o.ContainerName,
+		Count:                o.Num,
+		Cpu:                  o.Cpu,
+		DnsSearchDomains:     o.DnsSearchDomains,
+		DnsServers:           o.DnsServers,
+		EgressChecker:        o.EgressChecker,
+		EnableExecuteCommand: o.EnableExec,
+		EnvVars:              o.EnvVars,
+		EphemeralStorageGiB:  o.EphemeralStorageGiB,
+		IdempotencyKey:       o.IdempotencyKey,
+		LaunchType:           o.LaunchType,
+		Memory:               o.Memory,
+		MemoryReservation:    o.MemoryReservation,
+		NoPublicIp:           o.NoPublicIp,
+		PlacementConstraints: o.PlacementConstraints,
+		PlatformVersion:      o.PlatformVersion,
+		Secrets:              o.Secrets,
+		TaskName:             o.TaskName,
+		TaskDefinitionArn:    o.TaskDefinitionArn,
+		SubnetIds:            o.SubnetIds,
+		SecurityGroupIds:     o.SecurityGroupIds,
+		SpreadAcrossSubnets:  o.Spread,
+		Command:              o.Command,
+		EntryPoint:           o.EntryPoint,
+	}
+
+	if o.ExpandCommand {
+		input.CommandTemplate = &ECS.CommandTemplate{}
+	}
+
+	return input
+}
+
+// CreateTaskDefinitionInput returns the input to register a task definition
+// for the run when no existing task definition is given.
+func (o *TaskRunOperation) CreateTaskDefinitionInput(executionRoleArn, logGroupName string) *ECS.CreateTaskDefinitionInput {
+	return &ECS.CreateTaskDefinitionInput{
+		Cpu:                 o.Cpu,
+		CpuArchitecture:     o.CpuArchitecture,
+		EnvVars:             o.EnvVars,
+		EphemeralStorageGiB: o.EphemeralStorageGiB,
+		ExecutionRoleArn:    executionRoleArn,
+		Image:               o.Image,
+		LaunchType:          o.LaunchType,
+		LogGroupName:        logGroupName,
+		LogRegion:           region,
+		Memory:              o.Memory,
+		Name:                o.TaskName,
+		Secrets:             o.Secrets,
+		Sidecars:            o.Sidecars,
+		Type:                typeTask,
+		TaskRole:            o.TaskRole,
+		Volumes:             o.Volumes,
+	}
+}
+
+func (o *TaskRunOperation) SetCapacityProviderStrategy(spot bool, expressions []string) {
+	o.CapacityProviders = extractCapacityProviderStrategy(spot, expressions)
+}
+
+func (o *TaskRunOperation) SetSecrets(inputSecrets []string) {
+	o.Secrets = extractSecrets(inputSecrets)
+}
+
+func (o *TaskRunOperation) SetVolumes(inputVolumes []string) {
+	o.Volumes = extractVolumes(inputVolumes)
+}
+
+func (o *TaskRunOperation) SetSidecars(expressions []string) {
+	o.Sidecars = extractSidecars(expressions)
 }
 
 func (o *TaskRunOperation) SetEnvVars(inputEnvVars []string) {
 	o.EnvVars = extractEnvVars(inputEnvVars)
 }
 
+func (o *TaskRunOperation) AddEnvFile(path string) {
+	fileEnvVars, err := ECS.ReadEnvFile(path)
+
+	if err != nil {
+		console.ErrorExit(err, "Invalid environment file")
+	}
+
+OUTER:
+	for _, fileEnvVar := range fileEnvVars {
+		for _, envVar := range o.EnvVars {
+			if envVar.Key == fileEnvVar.Key {
+				continue OUTER
+			}
+		}
+
+		o.EnvVars = append(o.EnvVars, fileEnvVar)
+	}
+}
+
 var (
-	flagTaskRunNum              int64
-	flagTaskRunCpu              string
-	flagTaskRunEnvVars          []string
-	flagTaskRunImage            string
-	flagTaskRunMemory           string
-	flagTaskRunSecurityGroupIds []string
-	flagTaskRunSubnetIds        []string
-	flagCommand                 []string
-	flagTaskDefinitionArn       string
-	flagTaskRunTaskRole         string
+	flagTaskRunCapacityProviders    []string
+	flagTaskRunNum                  int64
+	flagTaskRunCheckEgress          bool
+	flagTaskRunContainer            string
+	flagTaskRunArch                 string
+	flagTaskRunCpu                  string
+	flagTaskRunDnsSearchDomains     []string
+	flagTaskRunDnsServers           []string
+	flagTaskRunDryRun               bool
+	flagTaskRunEnableExec           bool
+	flagTaskRunEnvVars              []string
+	flagTaskRunEnvFile              string
+	flagTaskRunExpandCommand        bool
+	flagTaskRunEphemeralStorage     int64
+	flagTaskRunIdempotencyKey       string
+	flagTaskRunImage                string
+	flagTaskRunLaunchType           string
+	flagTaskRunLogs                 bool
+	flagTaskRunMemory               string
+	flagTaskRunMemoryReservation    int64
+	flagTaskRunNoPublicIp           bool
+	flagTaskRunPlacementConstraints []string
+	flagTaskRunPlatformVersion      string
+	flagTaskRunSecrets              []string
+	flagTaskRunSecurityGroupIds     []string
+	flagTaskRunSecurityGroupNames   []string
+	flagTaskRunSidecars             []string
+	flagTaskRunSpot                 bool
+	flagTaskRunSpread               bool
+	flagTaskRunSubnetIds            []string
+	flagTaskRunSubnetNames          []string
+	flagCommand                     []string
+	flagTaskRunEntryPoint           []string
+	flagTaskDefinitionArn           string
+	flagTaskRunTaskRole             string
+	flagTaskRunTimeout              time.Duration
+	flagTaskRunVolumes              []string
+	flagTaskRunWait                 bool
+	flagTaskRunWaitRunning          bool
 )
 
 var taskRunCmd = &cobra.Command{
-	Use:   "run <task name>",
+	Use:   "run <task name> [-- <args>...]",
 	Short: "Run new tasks",
 	Long: `Run new tasks
 
@@ -86,6 +325,38 @@ CPU and memory configurations:
 If not specified, fargate will launch minimally sized tasks at 0.25 vCPU (256
 CPU units) and 0.5GB (512 MiB) of memory.
 
+When running an existing task definition via --task-definition-arn, --cpu and
+--memory override its settings for this run only, without registering a new
+revision. This suits one-off heavy jobs, such as a backfill. If only one is
+given, the other is taken from the task definition.
+
+Tasks run on x86_64 processors by default. Pass --arch arm64 to run them on
+Graviton (ARM64) processors instead, which cost less. When fargate builds the
+image, it's built for the given architecture using docker buildx. An image
+given via --image must support the architecture.
+
+Tasks receive 20 GiB of ephemeral storage by default. A larger amount, from 21
+up to 200 GiB, can be requested via the --ephemeral-storage (or --storage)
+flag [e.g. --storage 50]. It's also set on the task definition registered for
+the task, so later runs of the same revision receive the same amount.
+
+Sidecar containers can be run alongside the task's main container via the
+--sidecar flag, in the form of NAME,image=IMAGE[,port=N][,env=KEY=VALUE]
+[,essential] [e.g. --sidecar nginx,image=nginx:1.25,port=80]. Specify --sidecar
+once per container; env may be given several times within a sidecar. Sidecars
+share the task's network namespace, so they reach the main container and each
+other via localhost. They aren't essential unless marked so, in which case the
+task stops if they exit. Overrides such as --env and --command apply to the
+main container.
+
+EFS file systems can be mounted into the task's container via the --volume
+flag, in the form of FILE_SYSTEM_ID:CONTAINER_PATH[:ro] [e.g. --volume
+fs-12345678:/mnt/data]. Specify --volume multiple times to mount several. Before
+running, fargate checks that the file system has a mount target in the
+availability zone of each subnet and that the mount targets' security groups
+allow NFS traffic (TCP 2049) from the task's security groups. Traffic to the
+file system is encrypted in transit.
+
 The Docker container image to use in the task can be optionally specified via
 the --image flag. If not specified, fargate will build a new Docker container
 image from the current working directory and push it to Amazon ECR in a
@@ -94,61 +365,270 @@ repository, the container image will be tagged with the short ref of the HEAD
 commit. If not, a timestamp in the format of YYYYMMDDHHMMSS will be used.
 
 Environment variables can be specified via the --env flag. Specify --env with a
-key=value parameter multiple times to add multiple variables.
+key=value parameter multiple times to add multiple variables. Variables can also
+be read from a file of KEY=value lines via the --env-file flag; blank lines and
+lines starting with # are ignored and values may be quoted. Variables passed
+via --env take precedence over those read from the file.
+
+Secrets can be injected into the task's environment from Secrets Manager or
+SSM Parameter Store via the --secret flag, in the form of NAME=SOURCE where the
+source is a secret ARN, a parameter ARN, or a parameter name [e.g. --secret
+DB_PASSWORD=arn:aws:secretsmanager:us-east-1:123456789012:secret:db-AbCdEf or
+--secret API_KEY=/prod/api-key]. Unlike --env, secret values never appear in
+the task definition or task output. The ecsTaskExecutionRole is granted read
+access to each secret; secrets encrypted with a customer managed KMS key also
+need kms:Decrypt on that key.
 
 Security groups can optionally be specified for the task by passing the
 --security-group-id flag with a security group ID. To add multiple security
 groups, pass --security-group-id with a security group ID multiple times. If
 --security-group-id is omitted, a permissive security group will be applied to
-the task.
+the task. Pass --check-egress to warn if none of the security groups allow
+outbound HTTPS traffic to 0.0.0.0/0; without it, tasks may fail to pull their
+image unless it's reachable through a VPC endpoint.
+
+Tasks on Fargate are assigned a public IP so that they can reach the internet from a
+public subnet. Pass --no-public-ip to run them without one, such as in private
+subnets behind a NAT gateway or VPC endpoints. If none of the subnets route to
+an internet gateway, they're treated as private and no public IP is assigned.
 
 By default, the task will be created in the default VPC and attached to the
 default VPC subnets for each availability zone. You can override this by
 specifying explicit subnets by passing the --subnet-id flag with a subnet ID.
+When running multiple tasks, pass --spread to divide them evenly across the
+subnets, and so across availability zones; if the number of tasks doesn't
+divide evenly, the first subnets listed receive one extra task each.
 
 A task role can be optionally specified via the --task-role flag by providing
 eith a full IAM role ARN or the name of an IAM role. The tasks will be able to
-assume this role.`,
-	Args: cobra.ExactArgs(1),
+assume this role.
+
+The container's command can be overridden via the --command flag. The
+entrypoint can be overridden via the --entrypoint flag [e.g. --entrypoint
+/bin/sh]; as ECS can't override an entrypoint at run time, a new revision of
+the task definition is registered with the given entrypoint.
+
+Arguments given after -- are appended to the container's existing command
+rather than replacing it [e.g. fargate task run migrate -- --dry-run]. When
+the container has no command, they're passed to the image's entrypoint.
+--command and arguments after -- cannot be used together.
+
+Pass --expand-command to expand ${KEY} and $KEY references in the command and
+arguments from the environment variables given via --env and --env-file [e.g.
+--command 'process,--date,${RUN_DATE}' --env RUN_DATE=2018-01-01]. References
+to variables that aren't given are left as-is.
+
+Overrides apply to the container named after the task by default, or to the
+task definition's first container if none is named after the task. To target
+another container of an existing task definition, pass its name via the
+--container flag [e.g. --container app].
+
+To safely retry a run, for example from a deployment pipeline, pass a unique
+value via the --idempotency-key flag. The key is stored as a tag on the tasks,
+and if any task in the same task group, running or stopped, was tagged with
+the same key in the last 15 minutes, no new tasks are started.
+
+To be able to open a shell in the tasks later via task exec, pass
+--enable-exec. The task role must allow the SSM Messages actions used by ECS
+Exec; see task exec for details.
+
+To preview a run, pass --dry-run. The requests that would be sent to ECS are
+printed, including the resolved subnets, security groups, and overrides, and
+no tasks are started, images built, or task definitions registered.
+
+To run tasks at a discount on spare capacity, pass --spot to place them on
+Fargate Spot. Spot tasks may be stopped with two minutes' notice when AWS needs
+the capacity back, so they suit interruptible work. To split tasks between
+capacity providers, pass --capacity-provider once per provider with an
+optional weight and base [e.g. --capacity-provider FARGATE_SPOT,weight=4
+--capacity-provider FARGATE,weight=1,base=1]. Tasks beyond the base are
+divided between providers in proportion to their weights. Capacity providers
+missing from the cluster are added to it, such as FARGATE_SPOT on a cluster
+created before it was available.
+
+Fargate tasks run on the LATEST platform version unless a specific version is
+given via the --platform-version flag [e.g. 1.4.0], for example to test a new
+version before it becomes LATEST.
+
+Tasks are run on AWS Fargate by default. To run tasks on the container
+instances of an EC2-backed cluster instead, pass --launch-type EC2. Placement
+constraints in the ECS cluster query language can be given for EC2 tasks via
+the --placement-constraint flag [e.g. "attribute:ecs.instance-type =~ t3.*"].
+
+For EC2 tasks, a memory reservation (soft limit) in MiB can be set via the
+--memory-reservation flag. It's applied as a container override on the task's
+container and is used by ECS when placing the task on a container instance;
+the container may use more memory, up to its hard limit, if it's available.
+Fargate tasks are always allotted their full memory, so a reservation isn't
+supported there.
+
+For EC2 tasks not using the awsvpc network mode, DNS servers and search
+domains can be set via the --dns-server and --dns-search-domain flags. As ECS
+can't override DNS settings at run time, a new revision of the task
+definition is registered with them. Fargate tasks always use the VPC's DNS
+resolver; configure DHCP options or Route 53 Resolver rules for the VPC
+instead.
+
+Subnets and security groups can be given by name rather than ID via the
+--subnet-name and --security-group-name flags. Subnets are matched by their
+Name tag and security groups by either their group name or Name tag. Security
+groups are looked up within the VPC of the task's subnets.
+
+To run a one-off job, for example as a CI step, pass --wait. A single task is
+run and fargate waits for it to stop, exiting with its container's exit code.
+Add --logs to stream the task's logs while waiting, and --timeout to stop the
+task and fail if it runs for longer than the given duration [e.g. 30m]. If
+interrupted with Control-C while waiting, fargate stops waiting and prints the
+ID of the task, which is left running.
+
+To instead wait only until the tasks have started, pass --wait-running.
+fargate exits non-zero if any of the tasks stops before it's running or they
+aren't all running within --timeout [default 10m].`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			if dash != 1 {
+				return fmt.Errorf("accepts 1 arg before --, received %d", dash)
+			}
+
+			return nil
+		}
+
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		operation := &TaskRunOperation{
-			Cpu:               flagTaskRunCpu,
-			Image:             flagTaskRunImage,
-			Memory:            flagTaskRunMemory,
-			Num:               flagTaskRunNum,
-			SecurityGroupIds:  flagTaskRunSecurityGroupIds,
-			SubnetIds:         flagTaskRunSubnetIds,
-			TaskName:          args[0],
-			Command:           flagCommand,
-			TaskDefinitionArn: flagTaskDefinitionArn,
-			TaskRole:          flagTaskRunTaskRole,
-		}
-
-		operation.SetEnvVars(flagTaskRunEnvVars)
+		operation := taskRunOperationFromFlags(cmd, args)
 		operation.Validate()
 
 		runTask(operation)
 	},
 }
 
+// taskRunOperationFromFlags builds a task run operation from the flags added by
+// addTaskRunFlags, with args holding the task name and any arguments to append
+// to the container's command after --.
+func taskRunOperationFromFlags(cmd *cobra.Command, args []string) *TaskRunOperation {
+	operation := &TaskRunOperation{
+		AppendArgs:           args[1:],
+		CheckEgress:          flagTaskRunCheckEgress,
+		ContainerName:        flagTaskRunContainer,
+		Cpu:                  flagTaskRunCpu,
+		CpuArchitecture:      extractCpuArchitecture(flagTaskRunArch),
+		DnsSearchDomains:     flagTaskRunDnsSearchDomains,
+		DnsServers:           flagTaskRunDnsServers,
+		DryRun:               flagTaskRunDryRun,
+		EnableExec:           flagTaskRunEnableExec,
+		EphemeralStorageGiB:  flagTaskRunEphemeralStorage,
+		ExpandCommand:        flagTaskRunExpandCommand,
+		IdempotencyKey:       flagTaskRunIdempotencyKey,
+		Image:                flagTaskRunImage,
+		LaunchType:           flagTaskRunLaunchType,
+		Logs:                 flagTaskRunLogs,
+		Memory:               flagTaskRunMemory,
+		MemoryReservation:    flagTaskRunMemoryReservation,
+		NoPublicIp:           flagTaskRunNoPublicIp,
+		Num:                  flagTaskRunNum,
+		PlacementConstraints: flagTaskRunPlacementConstraints,
+		PlatformVersion:      flagTaskRunPlatformVersion,
+		SecurityGroupIds:     flagTaskRunSecurityGroupIds,
+		SecurityGroupNames:   flagTaskRunSecurityGroupNames,
+		Spread:               flagTaskRunSpread,
+		SubnetIds:            flagTaskRunSubnetIds,
+		SubnetNames:          flagTaskRunSubnetNames,
+		TaskName:             args[0],
+		Command:              flagCommand,
+		EntryPoint:           flagTaskRunEntryPoint,
+		TaskDefinitionArn:    flagTaskDefinitionArn,
+		TaskRole:             flagTaskRunTaskRole,
+		Timeout:              flagTaskRunTimeout,
+		Wait:                 flagTaskRunWait,
+		WaitRunning:          flagTaskRunWaitRunning,
+	}
+
+	// An existing task definition's CPU and memory are only overridden when
+	// asked for; the flags' defaults apply to new task definitions.
+	if operation.TaskDefinitionArn != "" {
+		if !cmd.Flags().Changed("cpu") {
+			operation.Cpu = ""
+		}
+
+		if !cmd.Flags().Changed("memory") {
+			operation.Memory = ""
+		}
+	}
+
+	operation.SetEnvVars(flagTaskRunEnvVars)
+	operation.SetCapacityProviderStrategy(flagTaskRunSpot, flagTaskRunCapacityProviders)
+	operation.SetSecrets(flagTaskRunSecrets)
+	operation.SetSidecars(flagTaskRunSidecars)
+	operation.SetVolumes(flagTaskRunVolumes)
+
+	if flagTaskRunEnvFile != "" {
+		operation.AddEnvFile(flagTaskRunEnvFile)
+	}
+
+	return operation
+}
+
 func init() {
 	taskRunCmd.Flags().Int64VarP(&flagTaskRunNum, "num", "n", 1, "Number of task instances to run")
-	taskRunCmd.Flags().StringSliceVarP(&flagTaskRunEnvVars, "env", "e", []string{}, "Environment variables to set [e.g. KEY=value] (can be specified multiple times)")
-	taskRunCmd.Flags().StringVarP(&flagTaskRunCpu, "cpu", "c", "256", "Amount of cpu units to allocate for each task")
-	taskRunCmd.Flags().StringVarP(&flagTaskRunImage, "image", "i", "", "Docker image to run; if omitted Fargate will build an image from the Dockerfile in the current directory")
-	taskRunCmd.Flags().StringVarP(&flagTaskRunMemory, "memory", "m", "512", "Amount of MiB to allocate for each task")
-	taskRunCmd.Flags().StringSliceVar(&flagTaskRunSecurityGroupIds, "security-group-id", []string{}, "ID of a security group to apply to the task (can be specified multiple times)")
-	taskRunCmd.Flags().StringSliceVar(&flagTaskRunSubnetIds, "subnet-id", []string{}, "ID of a subnet in which to place the task (can be specified multiple times)")
-	taskRunCmd.Flags().StringSliceVar(&flagCommand, "command", []string{}, "Override command to pass to the task definition, (can be specified multiple times)")
-	taskRunCmd.Flags().StringVarP(&flagTaskDefinitionArn, "task-definition-arn", "", "", "The family and revision (family:revision ) or full ARN of the task definition to run")
-	taskRunCmd.Flags().StringVarP(&flagTaskRunTaskRole, "task-role", "", "", "Name or ARN of an IAM role that the tasks can assume")
+	taskRunCmd.Flags().BoolVar(&flagTaskRunWait, "wait", false, "Wait for the task to stop and exit with its container's exit code")
+	taskRunCmd.Flags().BoolVar(&flagTaskRunLogs, "logs", false, "Stream the task's logs while waiting (requires --wait)")
+	taskRunCmd.Flags().BoolVar(&flagTaskRunWaitRunning, "wait-running", false, "Wait for the tasks to be running")
+	taskRunCmd.Flags().DurationVar(&flagTaskRunTimeout, "timeout", 0, "With --wait, stop the task if it hasn't stopped within this duration; with --wait-running, fail if the tasks aren't running within it (default 10m)")
+
+	addTaskRunFlags(taskRunCmd)
 	taskCmd.AddCommand(taskRunCmd)
 }
 
+// addTaskRunFlags adds the flags which configure the tasks to run, shared by
+// task run and job run.
+func addTaskRunFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVarP(&flagTaskRunEnvVars, "env", "e", []string{}, "Environment variables to set [e.g. KEY=value] (can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&flagTaskRunSecrets, "secret", []string{}, "Secret to inject as an environment variable from Secrets Manager or SSM Parameter Store [e.g. DB_PASSWORD=arn:aws:secretsmanager:...] (can be specified multiple times)")
+	cmd.Flags().StringVar(&flagTaskRunEnvFile, "env-file", "", "File of environment variables to set in KEY=value format")
+	cmd.Flags().Int64Var(&flagTaskRunEphemeralStorage, "ephemeral-storage", 0, "Amount of GiB of ephemeral storage to allocate for each task (21-200)")
+	cmd.Flags().Int64Var(&flagTaskRunEphemeralStorage, "storage", 0, "Alias for --ephemeral-storage")
+	cmd.Flags().BoolVar(&flagTaskRunDryRun, "dry-run", false, "Print the requests that would be made to run tasks without running them")
+	cmd.Flags().StringVar(&flagTaskRunArch, "arch", "", "CPU architecture on which to run the tasks [x86_64, arm64] (default: x86_64)")
+	cmd.Flags().StringVarP(&flagTaskRunCpu, "cpu", "c", "256", "Amount of cpu units to allocate for each task")
+	cmd.Flags().StringVarP(&flagTaskRunImage, "image", "i", "", "Docker image to run; if omitted Fargate will build an image from the Dockerfile in the current directory")
+	cmd.Flags().StringVarP(&flagTaskRunMemory, "memory", "m", "512", "Amount of MiB to allocate for each task")
+	cmd.Flags().Int64Var(&flagTaskRunMemoryReservation, "memory-reservation", 0, "Amount of MiB to reserve for the container on EC2 container instances (soft limit)")
+	cmd.Flags().StringSliceVar(&flagTaskRunSecurityGroupIds, "security-group-id", []string{}, "ID of a security group to apply to the task (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&flagTaskRunSecurityGroupNames, "security-group-name", []string{}, "Name or Name tag of a security group to apply to the task (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&flagTaskRunSubnetNames, "subnet-name", []string{}, "Name tag of a subnet in which to place the task (can be specified multiple times)")
+	cmd.Flags().StringVar(&flagTaskRunPlatformVersion, "platform-version", "", "Fargate platform version on which to run the tasks [e.g. 1.4.0] (default: LATEST)")
+	cmd.Flags().BoolVar(&flagTaskRunSpot, "spot", false, "Run the tasks on Fargate Spot")
+	cmd.Flags().StringArrayVar(&flagTaskRunCapacityProviders, "capacity-provider", []string{}, "Capacity provider on which to place tasks [e.g. FARGATE_SPOT,weight=4,base=1] (can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&flagTaskRunSidecars, "sidecar", []string{}, "Sidecar container to run alongside the main container [e.g. nginx,image=nginx:1.25,port=80] (can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&flagTaskRunVolumes, "volume", []string{}, "EFS file system to mount [e.g. fs-12345678:/mnt/data] (can be specified multiple times)")
+	cmd.Flags().BoolVar(&flagTaskRunNoPublicIp, "no-public-ip", false, "Don't assign the tasks public IPs, such as for private subnets")
+	cmd.Flags().BoolVar(&flagTaskRunSpread, "spread", false, "Spread tasks evenly across subnets")
+	cmd.Flags().StringSliceVar(&flagTaskRunSubnetIds, "subnet-id", []string{}, "ID of a subnet in which to place the task (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&flagCommand, "command", []string{}, "Override command to pass to the task definition, (can be specified multiple times)")
+	cmd.Flags().BoolVar(&flagTaskRunExpandCommand, "expand-command", false, "Expand ${KEY} and $KEY in the command from the task's environment variables")
+	cmd.Flags().StringSliceVar(&flagTaskRunEntryPoint, "entrypoint", []string{}, "Override entrypoint of the container image, (can be specified multiple times)")
+	cmd.Flags().BoolVar(&flagTaskRunEnableExec, "enable-exec", false, "Enable ECS Exec so that commands can be run in the tasks via task exec")
+	cmd.Flags().BoolVar(&flagTaskRunCheckEgress, "check-egress", false, "Warn if the security groups don't allow outbound traffic needed to pull the image")
+	cmd.Flags().StringVar(&flagTaskRunContainer, "container", "", "Name of the container to which overrides apply (default: the task name, or the first container)")
+	cmd.Flags().StringVarP(&flagTaskDefinitionArn, "task-definition-arn", "", "", "The family, family and revision (family:revision), or full ARN of the task definition to run; a family alone runs its latest active revision")
+	cmd.Flags().StringVarP(&flagTaskRunTaskRole, "task-role", "", "", "Name or ARN of an IAM role that the tasks can assume")
+	cmd.Flags().StringVar(&flagTaskRunIdempotencyKey, "idempotency-key", "", "Unique key identifying this run; retries with the same key won't start duplicate tasks")
+	cmd.Flags().StringVar(&flagTaskRunLaunchType, "launch-type", launchTypeFargate, "Launch type on which to run the tasks [FARGATE, EC2]")
+	cmd.Flags().StringSliceVar(&flagTaskRunPlacementConstraints, "placement-constraint", []string{}, "Placement constraint expression for EC2 tasks (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&flagTaskRunDnsServers, "dns-server", []string{}, "DNS server for EC2 tasks (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&flagTaskRunDnsSearchDomains, "dns-search-domain", []string{}, "DNS search domain for EC2 tasks (can be specified multiple times)")
+}
+
 func runTask(operation *TaskRunOperation) {
 	ec2 := EC2.New(sess)
 	ecr := ECR.New(sess)
 	ecs := ECS.New(sess, clusterName)
+	ecs.LaunchType = operation.LaunchType
+
+	ensureClusterExists(ecs)
+
+	resolveNetworkNames(ec2, operation)
 
 	if len(operation.SecurityGroupIds) == 0 {
 		defaultSecurityGroupID, _ := ec2.GetDefaultSecurityGroupID()
@@ -159,9 +639,38 @@ func runTask(operation *TaskRunOperation) {
 		operation.SubnetIds, _ = ec2.GetDefaultSubnetIDs()
 	}
 
+	if operation.LaunchType != launchTypeEc2 {
+		operation.NoPublicIp = detectNoPublicIp(ec2, operation.NoPublicIp, operation.SubnetIds)
+	}
+
+	if operation.CheckEgress {
+		operation.EgressChecker = ec2
+	}
+
+	if operation.TaskDefinitionArn != "" && (operation.Cpu != "" || operation.Memory != "") {
+		resolveCpuAndMemoryOverrides(ecs, operation)
+	}
+
+	if len(operation.Volumes) > 0 {
+		checkVolumesReachable(ec2, operation.Volumes, operation.SubnetIds, operation.SecurityGroupIds)
+	}
+
+	if operation.DryRun {
+		dryRunTask(ecs, operation)
+		return
+	}
+
+	ensureCapacityProviders(ecs, operation.CapacityProviders)
+
+	iam := IAM.New(sess)
+
+	if len(operation.Secrets) > 0 {
+		iam.CreateEcsTaskExecutionRole()
+		grantSecretAccess(iam, operation.Secrets)
+	}
+
 	if operation.TaskDefinitionArn == "" {
 		cwl := CWL.New(sess)
-		iam := IAM.New(sess)
 		ecsTaskExecutionRoleArn := iam.CreateEcsTaskExecutionRole()
 		logGroupName := cwl.CreateLogGroup(taskLogGroupFormat, operation.TaskName)
 
@@ -184,41 +693,411 @@ func runTask(operation *TaskRunOperation) {
 			username, password := ecr.GetUsernameAndPassword()
 
 			repository.Login(username, password)
-			repository.Build(tag)
+			buildImage(repository, tag, operation.CpuArchitecture)
 			repository.Push(tag)
 
 			operation.Image = repository.UriFor(tag)
 		}
 
 		operation.TaskDefinitionArn = ecs.CreateTaskDefinition(
-			&ECS.CreateTaskDefinitionInput{
-				Cpu:              operation.Cpu,
-				EnvVars:          operation.EnvVars,
-				ExecutionRoleArn: ecsTaskExecutionRoleArn,
-				Image:            operation.Image,
-				LogGroupName:     logGroupName,
-				LogRegion:        region,
-				Memory:           operation.Memory,
-				Name:             operation.TaskName,
-				Type:             typeTask,
-				TaskRole:         operation.TaskRole,
-			},
+			operation.CreateTaskDefinitionInput(ecsTaskExecutionRoleArn, logGroupName),
 		)
 
+		// The secrets, CPU, and memory are already on the task definition, so
+		// the run needn't register another revision or override them.
+		operation.Secrets = nil
+		operation.Cpu = ""
+		operation.Memory = ""
+	}
+
+	if operation.Wait {
+		runTaskToCompletion(ecs, operation)
+		return
+	}
+
+	taskIds := ecs.RunTask(operation.RunTaskInput())
+
+	console.Info("Running task %s", operation.TaskName)
+
+	if operation.WaitRunning {
+		timeout := operation.Timeout
+
+		if timeout == 0 {
+			timeout = defaultWaitTimeout
+		}
+
+		waitForTasksRunning(ecs, taskIds, timeout)
+	}
+}
+
+func runTaskToCompletion(ecs ECS.ECS, operation *TaskRunOperation) {
+	var cancel context.CancelFunc
+
+	ctx := context.Background()
+
+	if operation.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, operation.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
 	}
 
-	ecs.RunTask(
-		&ECS.RunTaskInput{
-			ClusterName:       clusterName,
-			Count:             operation.Num,
-			EnvVars:           operation.EnvVars,
-			TaskName:          operation.TaskName,
-			TaskDefinitionArn: operation.TaskDefinitionArn,
-			SubnetIds:         operation.SubnetIds,
-			SecurityGroupIds:  operation.SecurityGroupIds,
-			Command:           operation.Command,
-		},
-	)
+	defer cancel()
+
+	// On the first interrupt, stop waiting and report what's still running;
+	// a second interrupt exits immediately.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		signal.Stop(signals)
+		cancel()
+	}()
+
+	cwl := CWL.New(sess)
+	input := operation.RunTaskInput()
+	logsOperation := &GetLogsOperation{}
+	follow := func(taskId string) error {
+		console.Info("Started task %s", taskId)
+
+		if !operation.Logs {
+			return nil
+		}
+
+		// The run has resolved the task definition and container it used by
+		// now. With --task-definition-arn these aren't fargate's own, so their
+		// log group and stream prefix are read from the task definition.
+		logConfiguration, err := ecs.GetLogConfiguration(input.TaskDefinitionArn, input.ContainerName)
+
+		if err != nil {
+			console.Issue("Could not follow logs of task %s: %v", taskId, err)
+			return nil
+		}
+
+		return cwl.FollowLogStream(
+			ctx,
+			&CWL.FollowLogStreamInput{
+				Done:          func() bool { return ecs.IsTaskStopped(taskId) },
+				LogGroupName:  logConfiguration.LogGroupName,
+				LogStreamName: logConfiguration.LogStreamName(taskId),
+			},
+			func(logLine CWL.LogLine) {
+				console.LogLine(logLine.LogStreamName, logLine.Message, logsOperation.GetStreamColor(logLine.LogStreamName))
+			},
+		)
+	}
 
 	console.Info("Running task %s", operation.TaskName)
+
+	exitCode, err := ecs.RunTaskToCompletion(ctx, input, follow)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not run task to completion")
+	}
+
+	console.Info("Task %s exited with code %d", operation.TaskName, exitCode)
+	console.Exit(exitCode)
+}
+
+func dryRunTask(ecs ECS.ECS, operation *TaskRunOperation) {
+	input := operation.RunTaskInput()
+
+	if operation.TaskDefinitionArn != "" {
+		if len(input.Secrets) > 0 {
+			console.Info("Would register a new revision of task definition %s with secrets added", input.TaskDefinitionArn)
+		}
+
+		if len(input.EntryPoint) > 0 {
+			console.Info("Would register a new revision of task definition %s with the entrypoint overridden", input.TaskDefinitionArn)
+		}
+
+		if len(input.DnsServers) > 0 || len(input.DnsSearchDomains) > 0 {
+			console.Info("Would register a new revision of task definition %s with DNS settings overridden", input.TaskDefinitionArn)
+		}
+
+		runTaskInputs, err := ecs.BuildRunTaskInputs(input, nil)
+
+		if err != nil {
+			console.ErrorExit(err, "Invalid task settings")
+		}
+
+		for index, runTaskInput := range runTaskInputs {
+			printRunTaskInput(index+1, len(runTaskInputs), runTaskInput)
+		}
+
+		return
+	}
+
+	// The task definition which would be registered is built locally in place
+	// of the one RunTask would describe. Its execution role is only known once
+	// created, so it's left out.
+	createTaskDefinitionInput := operation.CreateTaskDefinitionInput("", fmt.Sprintf(taskLogGroupFormat, operation.TaskName))
+	taskDefinition, err := createTaskDefinitionInput.TaskDefinition()
+
+	if err != nil {
+		console.ErrorExit(err, "Invalid task settings")
+	}
+
+	if operation.Image == "" {
+		console.Info("Would build and push an image")
+	}
+
+	console.Info("Would register task definition %s", aws.StringValue(taskDefinition.Family))
+
+	// As when running, the secrets, CPU, and memory are on the registered task
+	// definition rather than overridden.
+	input.TaskDefinitionArn = aws.StringValue(taskDefinition.TaskDefinitionArn)
+	input.Secrets = nil
+	input.Cpu = ""
+	input.Memory = ""
+
+	if len(input.EntryPoint) > 0 {
+		console.Info("Would register a new revision of task definition %s with the entrypoint overridden", input.TaskDefinitionArn)
+	}
+
+	runTaskInputs, err := ecs.BuildRunTaskInputs(input, taskDefinition)
+
+	if err != nil {
+		console.ErrorExit(err, "Invalid task settings")
+	}
+
+	for index, runTaskInput := range runTaskInputs {
+		printRunTaskInput(index+1, len(runTaskInputs), runTaskInput)
+	}
+}
+
+// printRunTaskInput prints the settings of a RunTask request a dry run would
+// send.
+func printRunTaskInput(number, count int, runTaskInput *awsecs.RunTaskInput) {
+	console.Header(fmt.Sprintf("RunTask Request %d of %d", number, count))
+	console.KeyValue("Cluster", "%s\n", aws.StringValue(runTaskInput.Cluster))
+	console.KeyValue("Task Definition", "%s\n", aws.StringValue(runTaskInput.TaskDefinition))
+	console.KeyValue("Count", "%d\n", aws.Int64Value(runTaskInput.Count))
+	console.KeyValue("Started By", "%s\n", aws.StringValue(runTaskInput.StartedBy))
+
+	if runTaskInput.LaunchType != nil {
+		console.KeyValue("Launch Type", "%s\n", aws.StringValue(runTaskInput.LaunchType))
+	}
+
+	if len(runTaskInput.CapacityProviderStrategy) > 0 {
+		var capacityProviders []string
+
+		for _, item := range runTaskInput.CapacityProviderStrategy {
+			capacityProviders = append(
+				capacityProviders,
+				fmt.Sprintf("%s (weight %d, base %d)", aws.StringValue(item.CapacityProvider), aws.Int64Value(item.Weight), aws.Int64Value(item.Base)),
+			)
+		}
+
+		console.KeyValue("Capacity Providers", "%s\n", strings.Join(capacityProviders, ", "))
+	}
+
+	if runTaskInput.PlatformVersion != nil {
+		console.KeyValue("Platform Version", "%s\n", aws.StringValue(runTaskInput.PlatformVersion))
+	}
+
+	if runTaskInput.NetworkConfiguration != nil && runTaskInput.NetworkConfiguration.AwsvpcConfiguration != nil {
+		awsvpcConfiguration := runTaskInput.NetworkConfiguration.AwsvpcConfiguration
+
+		console.KeyValue("Subnets", "%s\n", strings.Join(aws.StringValueSlice(awsvpcConfiguration.Subnets), ", "))
+		console.KeyValue("Security Groups", "%s\n", strings.Join(aws.StringValueSlice(awsvpcConfiguration.SecurityGroups), ", "))
+
+		if awsvpcConfiguration.AssignPublicIp != nil {
+			console.KeyValue("Public IP", "%s\n", Humanize(aws.StringValue(awsvpcConfiguration.AssignPublicIp)))
+		}
+	}
+
+	for _, placementConstraint := range runTaskInput.PlacementConstraints {
+		console.KeyValue("Placement Constraint", "%s\n", aws.StringValue(placementConstraint.Expression))
+	}
+
+	overrides := runTaskInput.Overrides
+
+	if overrides == nil {
+		return
+	}
+
+	if overrides.Cpu != nil {
+		console.KeyValue("Cpu", "%s\n", aws.StringValue(overrides.Cpu))
+	}
+
+	if overrides.Memory != nil {
+		console.KeyValue("Memory", "%s\n", aws.StringValue(overrides.Memory))
+	}
+
+	if overrides.EphemeralStorage != nil {
+		console.KeyValue("Ephemeral Storage", "%d GiB\n", aws.Int64Value(overrides.EphemeralStorage.SizeInGiB))
+	}
+
+	for _, containerOverride := range overrides.ContainerOverrides {
+		console.KeyValue("Container", "%s\n", aws.StringValue(containerOverride.Name))
+
+		if len(containerOverride.Command) > 0 {
+			console.KeyValue("  Command", "%s\n", strings.Join(aws.StringValueSlice(containerOverride.Command), " "))
+		}
+
+		if containerOverride.MemoryReservation != nil {
+			console.KeyValue("  Memory Reservation", "%d MiB\n", aws.Int64Value(containerOverride.MemoryReservation))
+		}
+
+		if len(containerOverride.Environment) > 0 {
+			console.KeyValue("  Environment Variables", "\n")
+
+			for _, keyValuePair := range containerOverride.Environment {
+				console.KeyValue("    "+aws.StringValue(keyValuePair.Name), "%s\n", aws.StringValue(keyValuePair.Value))
+			}
+		}
+	}
+}
+
+// resolveCpuAndMemoryOverrides fills in whichever of the CPU and memory
+// overrides wasn't given from the task definition, so that the pair can be
+// validated together before the run.
+func resolveCpuAndMemoryOverrides(ecs ECS.ECS, operation *TaskRunOperation) {
+	cpu, memory := ecs.GetCpuAndMemoryFromTaskDefinition(operation.TaskDefinitionArn)
+
+	if operation.Cpu == "" {
+		operation.Cpu = cpu
+	}
+
+	if operation.Memory == "" {
+		operation.Memory = memory
+	}
+
+	if operation.LaunchType == launchTypeEc2 {
+		return
+	}
+
+	if err := validateCpuAndMemory(operation.Cpu, operation.Memory); err != nil {
+		console.ErrorExit(err, "Invalid settings: %s CPU units / %s MiB", operation.Cpu, operation.Memory)
+	}
+}
+
+// detectNoPublicIp returns whether tasks should run without a public IP:
+// either because --no-public-ip was given or because none of the subnets
+// route to an internet gateway, in which case a public IP would go unused.
+func detectNoPublicIp(ec2 EC2.SDKClient, noPublicIp bool, subnetIds []string) bool {
+	if noPublicIp || len(subnetIds) == 0 {
+		return noPublicIp
+	}
+
+	private, err := ec2.SubnetsArePrivate(subnetIds)
+
+	if err != nil {
+		console.Debug("Could not determine whether subnets are private: %v", err)
+		return false
+	}
+
+	if private {
+		console.Info("Subnets %s are private; tasks won't be assigned public IPs", strings.Join(subnetIds, ", "))
+	}
+
+	return private
+}
+
+// resolveNetworkNames adds the IDs of any subnets and security groups given by
+// name to those given by ID. Security groups are looked up within the VPC of
+// the first subnet, if any.
+func resolveNetworkNames(ec2 EC2.SDKClient, operation *TaskRunOperation) {
+	var vpcId string
+
+	if len(operation.SubnetNames) > 0 {
+		subnetIds, err := ec2.ResolveSubnetNames("", operation.SubnetNames)
+
+		if err != nil {
+			console.ErrorExit(err, "Could not resolve subnet names")
+		}
+
+		operation.SubnetIds = append(operation.SubnetIds, subnetIds...)
+	}
+
+	if len(operation.SecurityGroupNames) > 0 {
+		if len(operation.SubnetIds) > 0 {
+			id, err := ec2.GetSubnetVPCID(operation.SubnetIds[0])
+
+			if err != nil {
+				console.ErrorExit(err, "Could not resolve security group names")
+			}
+
+			vpcId = id
+		}
+
+		securityGroupIds, err := ec2.ResolveSecurityGroupNames(vpcId, operation.SecurityGroupNames)
+
+		if err != nil {
+			console.ErrorExit(err, "Could not resolve security group names")
+		}
+
+		operation.SecurityGroupIds = append(operation.SecurityGroupIds, securityGroupIds...)
+	}
+}
+
+// extractCapacityProviderStrategy parses capacity provider expressions, with
+// spot as a shorthand for placing all tasks on Fargate Spot.
+func extractCapacityProviderStrategy(spot bool, expressions []string) []ECS.CapacityProviderStrategyItem {
+	if spot {
+		if len(expressions) > 0 {
+			console.IssueExit("--spot and --capacity-provider cannot be used together")
+		}
+
+		expressions = []string{ECS.CapacityProviderFargateSpot}
+	}
+
+	strategy, err := ECS.ParseCapacityProviderStrategy(expressions)
+
+	if err != nil {
+		console.ErrorExit(err, "Invalid command line flags")
+	}
+
+	return strategy
+}
+
+// extractSidecars parses sidecar container expressions, exiting on the first
+// that's invalid.
+func extractSidecars(expressions []string) []ECS.Sidecar {
+	var sidecars []ECS.Sidecar
+
+	for _, expression := range expressions {
+		sidecar, err := ECS.ParseSidecar(expression)
+
+		if err != nil {
+			console.ErrorExit(err, "Invalid command line flags")
+		}
+
+		sidecars = append(sidecars, sidecar)
+	}
+
+	return sidecars
+}
+
+// extractSecrets parses secrets in the form of NAME=SOURCE, exiting on the
+// first that's invalid.
+func extractSecrets(inputSecrets []string) []ECS.Secret {
+	var secrets []ECS.Secret
+
+	for _, inputSecret := range inputSecrets {
+		secret, err := ECS.ParseSecret(inputSecret)
+
+		if err != nil {
+			console.ErrorExit(err, "Invalid secret")
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	return secrets
+}
+
+// grantSecretAccess allows the task execution role to read the secrets'
+// sources, exiting if it can't.
+func grantSecretAccess(iam IAM.IAM, secrets []ECS.Secret) {
+	var valueFroms []string
+
+	for _, secret := range secrets {
+		valueFroms = append(valueFroms, secret.ValueFrom)
+	}
+
+	if err := iam.GrantSecretAccess(valueFroms); err != nil {
+		console.ErrorExit(err, "Could not grant access to secrets")
+	}
 }
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/jpignata/fargate/console"
+	EC2 "github.com/jpignata/fargate/ec2"
+	ECS "github.com/jpignata/fargate/ecs"
+	"github.com/spf13/cobra"
+)
+
+type TaskScaleOperation struct {
+	TaskGroupName string
+	DesiredCount  int64
+	Timeout       time.Duration
+	Wait          bool
+}
+
+var (
+	flagTaskScaleTimeout time.Duration
+	flagTaskScaleWait    bool
+)
+
+func (o *TaskScaleOperation) SetScale(scaleExpression string, currentCount int64) {
+	validScale := regexp.MustCompile(validScalePattern)
+
+	if !validScale.MatchString(scaleExpression) {
+		console.ErrorExit(fmt.Errorf("Invalid scale expression %s", scaleExpression), "Invalid command line argument")
+	}
+
+	s, err := strconv.ParseInt(scaleExpression, 10, 64)
+
+	if err != nil {
+		console.ErrorExit(fmt.Errorf("Invalid scale expression %s", scaleExpression), "Invalid command line argument")
+	}
+
+	if scaleExpression[0] == '+' || scaleExpression[0] == '-' {
+		o.DesiredCount = currentCount + s
+	} else {
+		o.DesiredCount = s
+	}
+
+	if o.DesiredCount < 0 {
+		console.ErrorExit(fmt.Errorf("requested scale %d < 0", o.DesiredCount), "Invalid command line argument")
+	}
+}
+
+var taskScaleCmd = &cobra.Command{
+	Use:   "scale <task group name> <scale-expression>",
+	Short: "Changes the number of tasks running in a task group",
+	Long: `Scale number of tasks in a task group
+
+Runs additional tasks or stops surplus tasks so that the task group has the
+number of tasks given by the scale expression. A scale expression can either be
+an absolute number or a delta specified with a sign such as +5 or -2.
+
+New tasks are launched with the task definition, command, and environment
+variables of an existing task in the group, into the subnets used by the
+group's current tasks, and with the security groups attached to the existing
+task's network interface. When scaling down, the most recently started tasks
+are stopped first.
+
+Pass --wait to block until all of the task group's tasks are running. fargate
+exits non-zero if any of them stops or they aren't all running within
+--timeout [default 10m].`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &TaskScaleOperation{
+			TaskGroupName: args[0],
+			Timeout:       flagTaskScaleTimeout,
+			Wait:          flagTaskScaleWait,
+		}
+
+		scaleTaskGroup(operation, args[1])
+	},
+}
+
+func init() {
+	taskScaleCmd.Flags().BoolVar(&flagTaskScaleWait, "wait", false, "Wait for the task group's tasks to be running")
+	taskScaleCmd.Flags().DurationVar(&flagTaskScaleTimeout, "timeout", defaultWaitTimeout, "Amount of time to wait for the tasks to be running (with --wait)")
+
+	taskCmd.AddCommand(taskScaleCmd)
+}
+
+func scaleTaskGroup(operation *TaskScaleOperation, scaleExpression string) {
+	ecs := ECS.New(sess, clusterName)
+	ecs.NetworkInterfaces = EC2.New(sess)
+	tasks := ecs.DescribeTasksForTaskGroup(operation.TaskGroupName, ECS.TaskFilter{})
+
+	operation.SetScale(scaleExpression, int64(len(tasks)))
+
+	if err := ecs.ScaleTaskGroup(operation.TaskGroupName, operation.DesiredCount); err != nil {
+		console.ErrorExit(err, "Could not scale task group")
+	}
+
+	console.Info("Scaled task group %s to %d", operation.TaskGroupName, operation.DesiredCount)
+
+	if operation.Wait {
+		var taskIds []string
+
+		for _, task := range ecs.DescribeTasksForTaskGroup(operation.TaskGroupName, ECS.TaskFilter{}) {
+			taskIds = append(taskIds, task.TaskId)
+		}
+
+		waitForTasksRunning(ecs, taskIds, operation.Timeout)
+	}
+}
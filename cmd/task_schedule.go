@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jpignata/fargate/console"
+	EC2 "github.com/jpignata/fargate/ec2"
+	ECS "github.com/jpignata/fargate/ecs"
+	EB "github.com/jpignata/fargate/eventbridge"
+	IAM "github.com/jpignata/fargate/iam"
+	"github.com/spf13/cobra"
+)
+
+type TaskScheduleOperation struct {
+	NoPublicIp         bool
+	Num                int64
+	Remove             bool
+	ScheduleExpression string
+	SecurityGroupIds   []string
+	SubnetIds          []string
+	TaskDefinitionArn  string
+	TaskName           string
+}
+
+func (o *TaskScheduleOperation) Validate() {
+	if o.Remove {
+		if o.ScheduleExpression != "" {
+			console.IssueExit("A schedule expression cannot be given with --remove")
+		}
+
+		return
+	}
+
+	if o.ScheduleExpression == "" {
+		console.IssueExit("A schedule expression is required [e.g. \"rate(1 hour)\", \"cron(0 12 * * ? *)\"]")
+	}
+
+	if err := EB.ValidateScheduleExpression(o.ScheduleExpression); err != nil {
+		console.ErrorExit(err, "Invalid command line argument")
+	}
+
+	if o.Num < 1 {
+		console.IssueExit("Invalid number of tasks: %d, num must be > 1", o.Num)
+	}
+}
+
+var (
+	flagTaskScheduleNoPublicIp        bool
+	flagTaskScheduleNum               int64
+	flagTaskScheduleRemove            bool
+	flagTaskScheduleSecurityGroupIds  []string
+	flagTaskScheduleSubnetIds         []string
+	flagTaskScheduleTaskDefinitionArn string
+)
+
+var taskScheduleCmd = &cobra.Command{
+	Use:   "schedule <task name> [<schedule expression>]",
+	Short: "Run tasks on a schedule",
+	Long: `Run tasks on a schedule
+
+Creates an EventBridge rule which runs the task on a schedule, or updates the
+rule if the task is already scheduled. Schedules are given as a rate or cron
+expression [e.g. "rate(1 hour)", "cron(0 12 * * ? *)"]; cron expressions are
+evaluated in UTC.
+
+By default, the latest active revision of the task definition registered by
+task run for the task name is scheduled, so run the task once before
+scheduling it. Pass --task-definition-arn to schedule another task definition
+instead, given by family, family and revision, or full ARN. Pass --num to run
+several task instances each time.
+
+Tasks are placed in the default VPC subnets with the default security group
+unless subnets and security groups are given via the --subnet-id and
+--security-group-id flags. They're assigned public IPs unless --no-public-ip is
+given or none of the subnets route to an internet gateway. EventBridge starts
+the tasks using the ecsEventsRole IAM role, which is created if it doesn't
+exist.
+
+Pass --remove to delete the task's schedule. Tasks it has already started are
+left running.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &TaskScheduleOperation{
+			NoPublicIp:        flagTaskScheduleNoPublicIp,
+			Num:               flagTaskScheduleNum,
+			Remove:            flagTaskScheduleRemove,
+			SecurityGroupIds:  flagTaskScheduleSecurityGroupIds,
+			SubnetIds:         flagTaskScheduleSubnetIds,
+			TaskDefinitionArn: flagTaskScheduleTaskDefinitionArn,
+			TaskName:          args[0],
+		}
+
+		if len(args) == 2 {
+			operation.ScheduleExpression = args[1]
+		}
+
+		operation.Validate()
+
+		if operation.Remove {
+			removeTaskSchedule(operation)
+		} else {
+			scheduleTask(operation)
+		}
+	},
+}
+
+func init() {
+	taskScheduleCmd.Flags().BoolVar(&flagTaskScheduleNoPublicIp, "no-public-ip", false, "Don't assign the tasks public IPs, such as for private subnets")
+	taskScheduleCmd.Flags().Int64VarP(&flagTaskScheduleNum, "num", "n", 1, "Number of task instances to run each time")
+	taskScheduleCmd.Flags().BoolVar(&flagTaskScheduleRemove, "remove", false, "Remove the task's schedule")
+	taskScheduleCmd.Flags().StringSliceVar(&flagTaskScheduleSecurityGroupIds, "security-group-id", []string{}, "ID of a security group to apply to the tasks (can be specified multiple times)")
+	taskScheduleCmd.Flags().StringSliceVar(&flagTaskScheduleSubnetIds, "subnet-id", []string{}, "ID of a subnet in which to place the tasks (can be specified multiple times)")
+	taskScheduleCmd.Flags().StringVar(&flagTaskScheduleTaskDefinitionArn, "task-definition-arn", "", "The family, family and revision (family:revision), or full ARN of the task definition to run (default: the task's latest revision)")
+
+	taskCmd.AddCommand(taskScheduleCmd)
+}
+
+func scheduleTask(operation *TaskScheduleOperation) {
+	ec2 := EC2.New(sess)
+	ecs := ECS.New(sess, clusterName)
+	eb := EB.New(sess)
+	iam := IAM.New(sess)
+
+	ensureClusterExists(ecs)
+
+	clusterArn, err := ecs.ClusterArn()
+
+	if err != nil {
+		console.ErrorExit(err, "Could not find cluster")
+	}
+
+	taskDefinition := operation.TaskDefinitionArn
+
+	if taskDefinition == "" {
+		taskDefinition = fmt.Sprintf("%s_%s", typeTask, operation.TaskName)
+	}
+
+	taskDefinitionArn, err := ecs.ResolveTaskDefinitionArn(taskDefinition)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not find task definition")
+	}
+
+	if len(operation.SecurityGroupIds) == 0 {
+		defaultSecurityGroupID, _ := ec2.GetDefaultSecurityGroupID()
+		operation.SecurityGroupIds = []string{defaultSecurityGroupID}
+	}
+
+	if len(operation.SubnetIds) == 0 {
+		operation.SubnetIds, _ = ec2.GetDefaultSubnetIDs()
+	}
+
+	operation.NoPublicIp = detectNoPublicIp(ec2, operation.NoPublicIp, operation.SubnetIds)
+
+	err = eb.PutScheduledTask(
+		&EB.ScheduledTask{
+			ClusterArn:         clusterArn,
+			Count:              operation.Num,
+			NoPublicIp:         operation.NoPublicIp,
+			RoleArn:            iam.CreateEcsEventsRole(),
+			ScheduleExpression: operation.ScheduleExpression,
+			SecurityGroupIds:   operation.SecurityGroupIds,
+			SubnetIds:          operation.SubnetIds,
+			TaskDefinitionArn:  taskDefinitionArn,
+			TaskName:           operation.TaskName,
+		},
+	)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not schedule task")
+	}
+
+	console.Info("Scheduled task %s to run on %s", operation.TaskName, operation.ScheduleExpression)
+}
+
+func removeTaskSchedule(operation *TaskScheduleOperation) {
+	eb := EB.New(sess)
+
+	if err := eb.DeleteScheduledTask(operation.TaskName); err != nil {
+		console.ErrorExit(err, "Could not remove task schedule")
+	}
+
+	console.Info("Removed schedule for task %s", operation.TaskName)
+}
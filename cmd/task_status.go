@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
+	"github.com/spf13/cobra"
+)
+
+type TaskStatusOperation struct {
+	TaskGroupName string
+}
+
+var taskStatusCmd = &cobra.Command{
+	Use:   "status <task group name>",
+	Short: "Show whether tasks are on the latest task definition",
+	Long: `Show whether tasks are on the latest task definition
+
+Compares the revision of each running task in a task group against the latest
+active revision of its task definition family, and lists the tasks which are
+running an older revision along with their image and the latest image. Tasks
+only pick up a newly registered revision once they're restarted.
+
+If the task group's tasks were started from several task definition families,
+each family is compared against its own latest revision.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &TaskStatusOperation{
+			TaskGroupName: args[0],
+		}
+
+		getTaskStatus(operation)
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(taskStatusCmd)
+}
+
+func getTaskStatus(operation *TaskStatusOperation) {
+	ecs := ECS.New(sess, clusterName)
+
+	ensureClusterExists(ecs)
+
+	drifts, err := ecs.DescribeTaskGroupDrift(operation.TaskGroupName)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not compare tasks against the latest task definition")
+	}
+
+	if len(drifts) == 0 {
+		console.InfoExit("No tasks running")
+	}
+
+	for _, drift := range drifts {
+		if len(drift.StaleTasks) == 0 {
+			console.Info("%s: all %d tasks on revision %d, the latest", drift.Family, drift.TaskCount, drift.LatestRevision)
+			continue
+		}
+
+		console.Issue("%s: %d of %d tasks on an older revision, latest is %d", drift.Family, len(drift.StaleTasks), drift.TaskCount, drift.LatestRevision)
+
+		w := new(tabwriter.Writer)
+		w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+		fmt.Fprintln(w, "ID\tREVISION\tIMAGE\tLATEST IMAGE")
+
+		for _, task := range drift.StaleTasks {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", task.TaskId, task.Revision, task.Image, drift.LatestImage)
+		}
+
+		w.Flush()
+	}
+}
@@ -1,35 +1,96 @@
 package cmd
 
 import (
+	"strings"
+	"time"
+
 	"github.com/jpignata/fargate/console"
 	ECS "github.com/jpignata/fargate/ecs"
+	ELBV2 "github.com/jpignata/fargate/elbv2"
 	"github.com/spf13/cobra"
 )
 
+const serviceGroupPrefix = "service:"
+
 type TaskStopOperation struct {
-	TaskGroupName string
-	TaskIds       []string
+	All                 bool
+	IncludeServiceTasks bool
+	OlderThan           time.Duration
+	Reason              string
+	TaskGroupName       string
+	TaskIds             []string
+}
+
+func (o *TaskStopOperation) Validate() {
+	if o.OlderThan > 0 && len(o.TaskIds) > 0 {
+		console.IssueExit("--older-than cannot be used with --task")
+	}
+
+	if o.All {
+		if o.TaskGroupName != "" {
+			console.IssueExit("A task group name cannot be given with --all")
+		}
+
+		if o.OlderThan <= 0 {
+			console.IssueExit("--all requires --older-than")
+		}
+	} else {
+		if o.TaskGroupName == "" {
+			console.IssueExit("A task group name is required unless --all is given")
+		}
+
+		if o.IncludeServiceTasks {
+			console.IssueExit("--include-service-tasks can only be used with --all")
+		}
+	}
 }
 
 var (
-	flagTaskStopTasks []string
+	flagTaskStopAll                 bool
+	flagTaskStopIncludeServiceTasks bool
+	flagTaskStopOlderThan           time.Duration
+	flagTaskStopReason              string
+	flagTaskStopTasks               []string
 )
 
 var taskStopCmd = &cobra.Command{
-	Use:   "stop <task group name>",
+	Use:   "stop [<task group name>]",
 	Short: "Stop tasks",
 	Long: `Stop tasks
 
   Stops all tasks within a task group if run with only a task group name or stops
   individual tasks if one or more tasks are passed via the --task flag. Specify
-  --task with a task ID parameter multiple times to stop multiple specific tasks.`,
-	Args: cobra.ExactArgs(1),
+  --task with a task ID parameter multiple times to stop multiple specific tasks.
+
+  When stopping service tasks which are still registered in their service's
+  load balancer target group, a warning is shown as in-flight requests to those
+  tasks may be dropped.
+
+  To clean up forgotten tasks, pass --older-than with a duration [e.g. 24h] to
+  stop only the tasks in the task group which have been running for longer.
+  To clean up across every task group in the cluster, pass --all instead of a
+  task group name along with --older-than. Tasks managed by a service are left
+  alone unless --include-service-tasks is also given.
+
+  A reason for stopping the tasks can be given via --reason; it's recorded as
+  the tasks' stopped reason and shown by task info. Tasks are stopped several
+  at a time, so large task groups stop quickly.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		operation := &TaskStopOperation{
-			TaskGroupName: args[0],
-			TaskIds:       flagTaskStopTasks,
+			All:                 flagTaskStopAll,
+			IncludeServiceTasks: flagTaskStopIncludeServiceTasks,
+			OlderThan:           flagTaskStopOlderThan,
+			Reason:              flagTaskStopReason,
+			TaskIds:             flagTaskStopTasks,
 		}
 
+		if len(args) > 0 {
+			operation.TaskGroupName = args[0]
+		}
+
+		operation.Validate()
+
 		stopTasks(operation)
 	},
 }
@@ -38,34 +99,114 @@ func init() {
 	taskCmd.AddCommand(taskStopCmd)
 
 	taskStopCmd.Flags().StringSliceVarP(&flagTaskStopTasks, "task", "t", []string{}, "Stop specific task instances (can be specified multiple times)")
+	taskStopCmd.Flags().DurationVar(&flagTaskStopOlderThan, "older-than", 0, "Only stop tasks running for longer than this duration [e.g. 24h]")
+	taskStopCmd.Flags().StringVar(&flagTaskStopReason, "reason", "", "Reason for stopping the tasks, recorded as their stopped reason")
+	taskStopCmd.Flags().BoolVar(&flagTaskStopAll, "all", false, "Stop tasks in every task group in the cluster (requires --older-than)")
+	taskStopCmd.Flags().BoolVar(&flagTaskStopIncludeServiceTasks, "include-service-tasks", false, "Also stop tasks managed by a service when using --all")
 }
 
 func stopTasks(operation *TaskStopOperation) {
-	var taskCount int
+	var taskIds []string
 
 	ecs := ECS.New(sess, clusterName)
 
+	ensureClusterExists(ecs)
+
+	if operation.OlderThan > 0 {
+		stopTasksOlderThan(ecs, operation)
+		return
+	}
+
 	if len(operation.TaskIds) > 0 {
-		taskCount = len(operation.TaskIds)
+		taskIds = operation.TaskIds
 
-		ecs.StopTasks(operation.TaskIds)
+		warnRegisteredTargets(ecs, taskIds)
 	} else {
-		var taskIds []string
-
-		tasks := ecs.DescribeTasksForTaskGroup(operation.TaskGroupName)
+		tasks := ecs.DescribeTasksForTaskGroup(operation.TaskGroupName, ECS.TaskFilter{})
 
 		for _, task := range tasks {
 			taskIds = append(taskIds, task.TaskId)
 		}
-
-		taskCount = len(taskIds)
-
-		ecs.StopTasks(taskIds)
 	}
 
+	errs := ecs.StopTasks(taskIds, operation.Reason)
+	taskCount := len(taskIds) - len(errs)
+
 	if taskCount == 1 {
 		console.Info("Stopped %d task", taskCount)
 	} else {
 		console.Info("Stopped %d tasks", taskCount)
 	}
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			console.Error(err, "Could not stop ECS task")
+		}
+
+		console.Exit(1)
+	}
+}
+
+// warnRegisteredTargets warns about service tasks which are still registered
+// in their service's target group, as stopping them abruptly drops in-flight
+// requests.
+func warnRegisteredTargets(ecs ECS.ECS, taskIds []string) {
+	elbv2 := ELBV2.New(sess)
+	targetGroupArns := make(map[string]string)
+
+	for _, task := range ecs.DescribeTasks(taskIds) {
+		if !strings.HasPrefix(task.Group, serviceGroupPrefix) {
+			continue
+		}
+
+		serviceName := strings.TrimPrefix(task.Group, serviceGroupPrefix)
+
+		if _, ok := targetGroupArns[serviceName]; !ok {
+			targetGroupArns[serviceName] = ecs.DescribeService(serviceName).TargetGroupArn
+		}
+
+		if targetGroupArns[serviceName] == "" {
+			continue
+		}
+
+		registrations, err := ECS.FindTargetRegistrations(task, []string{targetGroupArns[serviceName]}, elbv2)
+
+		if err != nil {
+			console.Debug("Could not check target group registration for task %s: %v", task.TaskId, err)
+			continue
+		}
+
+		for _, registration := range registrations {
+			console.Issue("Task %s is %s in its load balancer target group on port %d; in-flight requests may be dropped", task.TaskId, registration.State, registration.Port)
+		}
+	}
+}
+
+func stopTasksOlderThan(ecs ECS.ECS, operation *TaskStopOperation) {
+	var taskIds []string
+	var errs []error
+
+	if operation.All {
+		taskIds, errs = ecs.StopAllTasksOlderThan(operation.OlderThan, operation.IncludeServiceTasks, operation.Reason)
+	} else {
+		taskIds, errs = ecs.StopTasksOlderThan(operation.TaskGroupName, operation.OlderThan, operation.Reason)
+	}
+
+	for _, taskId := range taskIds {
+		console.Info("Stopped task %s", taskId)
+	}
+
+	if len(taskIds) == 1 {
+		console.Info("Stopped %d task", len(taskIds))
+	} else {
+		console.Info("Stopped %d tasks", len(taskIds))
+	}
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			console.Error(err, "Could not stop ECS task")
+		}
+
+		console.Exit(1)
+	}
 }
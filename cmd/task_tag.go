@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
+	"github.com/spf13/cobra"
+)
+
+type TaskTagOperation struct {
+	Tags          []ECS.Tag
+	TaskGroupName string
+	TaskIds       []string
+}
+
+func (o *TaskTagOperation) SetTags(inputTags []string) {
+	for _, inputTag := range inputTags {
+		splitInputTag := strings.SplitN(inputTag, "=", 2)
+
+		if len(splitInputTag) != 2 || splitInputTag[0] == "" {
+			console.ErrorExit(fmt.Errorf("%s must be in the form of key=value", inputTag), "Invalid tag")
+		}
+
+		o.Tags = append(o.Tags, ECS.Tag{Key: splitInputTag[0], Value: splitInputTag[1]})
+	}
+}
+
+var flagTaskTagTasks []string
+
+var taskTagCmd = &cobra.Command{
+	Use:   "tag <task group name> <key=value>...",
+	Short: "Tag tasks",
+	Long: `Tag tasks
+
+Adds one or more tags, given as key=value pairs, to all tasks within a task
+group or to individual tasks passed via the --task flag. Existing tags with the
+same keys are overwritten.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &TaskTagOperation{
+			TaskGroupName: args[0],
+			TaskIds:       flagTaskTagTasks,
+		}
+
+		operation.SetTags(args[1:])
+
+		tagTasks(operation)
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(taskTagCmd)
+
+	taskTagCmd.Flags().StringSliceVarP(&flagTaskTagTasks, "task", "t", []string{}, "Tag specific task instances (can be specified multiple times)")
+}
+
+func tagTasks(operation *TaskTagOperation) {
+	ecs := ECS.New(sess, clusterName)
+	taskIds := taskIdsForTaskGroup(ecs, operation.TaskGroupName, operation.TaskIds)
+	errs := ecs.TagTasks(taskIds, operation.Tags)
+
+	console.Info("Tagged %d of %d tasks", len(taskIds)-len(errs), len(taskIds))
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			console.Error(err, "Could not tag ECS task")
+		}
+
+		console.Exit(1)
+	}
+}
+
+// taskIdsForTaskGroup returns taskIds if any were given, otherwise the IDs of
+// all tasks within the task group.
+func taskIdsForTaskGroup(ecs ECS.ECS, taskGroupName string, taskIds []string) []string {
+	if len(taskIds) > 0 {
+		return taskIds
+	}
+
+	for _, task := range ecs.DescribeTasksForTaskGroup(taskGroupName, ECS.TaskFilter{}) {
+		taskIds = append(taskIds, task.TaskId)
+	}
+
+	if len(taskIds) == 0 {
+		console.InfoExit("No tasks found")
+	}
+
+	return taskIds
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import "testing"
+
+func TestValidateLaunchType(t *testing.T) {
+	for launchType, valid := range map[string]bool{
+		"FARGATE":  true,
+		"EC2":      true,
+		"fargate":  false,
+		"EXTERNAL": false,
+		"":         false,
+	} {
+		if validateLaunchType(launchType) != valid {
+			t.Errorf("expected validateLaunchType(%q) to be %t", launchType, valid)
+		}
+	}
+}
+
+func TestTaskLaunchTypeFlagDefaults(t *testing.T) {
+	for _, defValue := range []string{
+		taskListCmd.Flags().Lookup("launch-type").DefValue,
+		taskPsCmd.Flags().Lookup("launch-type").DefValue,
+	} {
+		if defValue != launchTypeFargate {
+			t.Errorf("expected launch type to default to %s, got %s", launchTypeFargate, defValue)
+		}
+	}
+}
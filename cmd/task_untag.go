@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
+	"github.com/spf13/cobra"
+)
+
+type TaskUntagOperation struct {
+	Keys          []string
+	TaskGroupName string
+	TaskIds       []string
+}
+
+var flagTaskUntagTasks []string
+
+var taskUntagCmd = &cobra.Command{
+	Use:   "untag <task group name> <key>...",
+	Short: "Remove tags from tasks",
+	Long: `Remove tags from tasks
+
+Removes the tags with the given keys from all tasks within a task group or from
+individual tasks passed via the --task flag.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		operation := &TaskUntagOperation{
+			Keys:          args[1:],
+			TaskGroupName: args[0],
+			TaskIds:       flagTaskUntagTasks,
+		}
+
+		untagTasks(operation)
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(taskUntagCmd)
+
+	taskUntagCmd.Flags().StringSliceVarP(&flagTaskUntagTasks, "task", "t", []string{}, "Untag specific task instances (can be specified multiple times)")
+}
+
+func untagTasks(operation *TaskUntagOperation) {
+	ecs := ECS.New(sess, clusterName)
+	taskIds := taskIdsForTaskGroup(ecs, operation.TaskGroupName, operation.TaskIds)
+	errs := ecs.UntagTasks(taskIds, operation.Keys)
+
+	console.Info("Untagged %d of %d tasks", len(taskIds)-len(errs), len(taskIds))
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			console.Error(err, "Could not untag ECS task")
+		}
+
+		console.Exit(1)
+	}
+}
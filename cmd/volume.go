@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jpignata/fargate/console"
+	EC2 "github.com/jpignata/fargate/ec2"
+	ECS "github.com/jpignata/fargate/ecs"
+	EFS "github.com/jpignata/fargate/efs"
+)
+
+const nfsPort = 2049
+
+// extractVolumes parses volumes in the form of FILE_SYSTEM_ID:CONTAINER_PATH
+// [:ro], exiting on the first that's invalid.
+func extractVolumes(inputVolumes []string) []ECS.Volume {
+	var volumes []ECS.Volume
+
+	for _, inputVolume := range inputVolumes {
+		volume, err := ECS.ParseVolume(inputVolume)
+
+		if err != nil {
+			console.ErrorExit(err, "Invalid volume")
+		}
+
+		volumes = append(volumes, volume)
+	}
+
+	return volumes
+}
+
+// checkVolumesReachable exits unless every volume's file system can be
+// reached by tasks in the given subnets with the given security groups: the
+// file system needs a mount target in each subnet's availability zone, within
+// the same VPC, with a security group allowing NFS traffic from the tasks.
+func checkVolumesReachable(ec2 EC2.SDKClient, volumes []ECS.Volume, subnetIds, securityGroupIds []string) {
+	var issues []string
+
+	efs := EFS.New(sess)
+	checked := make(map[string]bool)
+	subnets, err := ec2.DescribeSubnets(subnetIds)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not check whether volumes are reachable")
+	}
+
+	for _, volume := range volumes {
+		if checked[volume.FileSystemId] {
+			continue
+		}
+
+		checked[volume.FileSystemId] = true
+		mountTargets, err := efs.DescribeMountTargets(volume.FileSystemId)
+
+		if err != nil {
+			console.ErrorExit(err, "Could not check whether volumes are reachable")
+		}
+
+		issues = append(issues, mountTargetIssues(volume.FileSystemId, mountTargets, subnets)...)
+
+		var mountTargetSecurityGroupIds []string
+
+		for _, mountTarget := range mountTargets {
+			mountTargetSecurityGroupIds = append(mountTargetSecurityGroupIds, mountTarget.SecurityGroupIds...)
+		}
+
+		if len(mountTargetSecurityGroupIds) == 0 {
+			continue
+		}
+
+		allowed, err := ec2.SecurityGroupsAllowIngress(mountTargetSecurityGroupIds, nfsPort, securityGroupIds)
+
+		if err != nil {
+			console.ErrorExit(err, "Could not check whether volumes are reachable")
+		}
+
+		if !allowed {
+			issues = append(issues,
+				fmt.Sprintf("The security groups of file system %s's mount targets [%s] don't allow NFS traffic (TCP %d) from the task's security groups [%s]",
+					volume.FileSystemId, strings.Join(mountTargetSecurityGroupIds, ", "), nfsPort, strings.Join(securityGroupIds, ", ")),
+			)
+		}
+	}
+
+	if len(issues) > 0 {
+		console.IssueExit("%s", strings.Join(issues, "\n"))
+	}
+}
+
+// mountTargetIssues describes why a file system can't be mounted from any of
+// the subnets, if it can't: it has no mount targets, its mount targets are in
+// another VPC, or none is in a subnet's availability zone.
+func mountTargetIssues(fileSystemId string, mountTargets []EFS.MountTarget, subnets []EC2.Subnet) []string {
+	var issues []string
+
+	if len(mountTargets) == 0 {
+		return []string{fmt.Sprintf("File system %s has no mount targets", fileSystemId)}
+	}
+
+	availabilityZones := make(map[string]bool)
+
+	for _, mountTarget := range mountTargets {
+		availabilityZones[mountTarget.AvailabilityZone] = true
+	}
+
+	for _, subnet := range subnets {
+		if subnet.VpcId != mountTargets[0].VpcId {
+			issues = append(issues, fmt.Sprintf("File system %s's mount targets are in VPC %s, but subnet %s is in VPC %s", fileSystemId, mountTargets[0].VpcId, subnet.SubnetId, subnet.VpcId))
+		} else if !availabilityZones[subnet.AvailabilityZone] {
+			issues = append(issues, fmt.Sprintf("File system %s has no mount target in %s, the availability zone of subnet %s", fileSystemId, subnet.AvailabilityZone, subnet.SubnetId))
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	EC2 "github.com/jpignata/fargate/ec2"
+	EFS "github.com/jpignata/fargate/efs"
+)
+
+func TestMountTargetIssues(t *testing.T) {
+	mountTargets := []EFS.MountTarget{
+		EFS.MountTarget{AvailabilityZone: "us-east-1a", SubnetId: "subnet-a", VpcId: "vpc-1"},
+		EFS.MountTarget{AvailabilityZone: "us-east-1b", SubnetId: "subnet-b", VpcId: "vpc-1"},
+	}
+
+	var tests = []struct {
+		name    string
+		subnets []EC2.Subnet
+		issues  int
+	}{
+		{"reachable", []EC2.Subnet{EC2.Subnet{AvailabilityZone: "us-east-1a", SubnetId: "subnet-1", VpcId: "vpc-1"}, EC2.Subnet{AvailabilityZone: "us-east-1b", SubnetId: "subnet-2", VpcId: "vpc-1"}}, 0},
+		{"no mount target in zone", []EC2.Subnet{EC2.Subnet{AvailabilityZone: "us-east-1c", SubnetId: "subnet-3", VpcId: "vpc-1"}}, 1},
+		{"other VPC", []EC2.Subnet{EC2.Subnet{AvailabilityZone: "us-east-1a", SubnetId: "subnet-4", VpcId: "vpc-2"}}, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if issues := mountTargetIssues("fs-12345678", mountTargets, test.subnets); len(issues) != test.issues {
+				t.Errorf("expected %d issues, got %v", test.issues, issues)
+			}
+		})
+	}
+
+	if issues := mountTargetIssues("fs-12345678", nil, nil); len(issues) != 1 {
+		t.Errorf("expected an issue for a file system without mount targets, got %v", issues)
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jpignata/fargate/console"
+	ECS "github.com/jpignata/fargate/ecs"
+)
+
+const defaultWaitTimeout = 10 * time.Minute
+
+// waitForServiceStable blocks until the service's deployment reaches a steady
+// state, printing service events as they're posted, and exits non-zero if it
+// doesn't within the timeout.
+func waitForServiceStable(ecs ECS.ECS, serviceName string, timeout time.Duration) {
+	console.Info("Waiting for service %s to reach a steady state", serviceName)
+
+	err := ecs.WaitForServiceStableWithEvents(
+		serviceName,
+		timeout,
+		func(event ECS.Event) {
+			console.Info("[%s] %s", event.CreatedAt.Format(time.Kitchen), event.Message)
+		},
+	)
+
+	if err != nil {
+		console.ErrorExit(err, "Service %s did not reach a steady state", serviceName)
+	}
+
+	console.Info("Service %s reached a steady state", serviceName)
+}
+
+// waitForTasksRunning blocks until the tasks are running, exiting non-zero if
+// any of them stops or they aren't all running within the timeout.
+func waitForTasksRunning(ecs ECS.ECS, taskIds []string, timeout time.Duration) {
+	if len(taskIds) == 0 {
+		return
+	}
+
+	console.Info("Waiting for tasks %s to be running", strings.Join(taskIds, ", "))
+
+	if err := ecs.WaitForTasksRunning(taskIds, timeout); err != nil {
+		console.ErrorExit(err, "Tasks did not start")
+	}
+
+	console.Info("Tasks %s are running", strings.Join(taskIds, ", "))
+}
@@ -0,0 +1,186 @@
+package codedeploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awscodedeploy "github.com/aws/aws-sdk-go/service/codedeploy"
+)
+
+const (
+	appSpecVersion         = "0.0"
+	appSpecTargetType      = "AWS::ECS::Service"
+	deploymentPollInterval = 15 * time.Second
+)
+
+// CreateDeploymentInput describes a blue/green deployment of a new task
+// definition. The container and port are those the load balancer routes to.
+type CreateDeploymentInput struct {
+	ApplicationName     string
+	ContainerName       string
+	ContainerPort       int64
+	DeploymentGroupName string
+	TaskDefinitionArn   string
+}
+
+// Deployment is a CodeDeploy deployment and, if it failed, the reason why.
+type Deployment struct {
+	ErrorMessage string
+	Id           string
+	Status       string
+}
+
+// Done returns whether the deployment has finished, successfully or not.
+func (d Deployment) Done() bool {
+	switch d.Status {
+	case awscodedeploy.DeploymentStatusSucceeded, awscodedeploy.DeploymentStatusFailed, awscodedeploy.DeploymentStatusStopped:
+		return true
+	}
+
+	return false
+}
+
+type appSpec struct {
+	Version   string            `json:"version"`
+	Resources []appSpecResource `json:"Resources"`
+}
+
+type appSpecResource struct {
+	TargetService appSpecTargetService `json:"TargetService"`
+}
+
+type appSpecTargetService struct {
+	Type       string            `json:"Type"`
+	Properties appSpecProperties `json:"Properties"`
+}
+
+type appSpecProperties struct {
+	TaskDefinition   string                  `json:"TaskDefinition"`
+	LoadBalancerInfo appSpecLoadBalancerInfo `json:"LoadBalancerInfo"`
+}
+
+type appSpecLoadBalancerInfo struct {
+	ContainerName string `json:"ContainerName"`
+	ContainerPort int64  `json:"ContainerPort"`
+}
+
+// CreateDeployment starts a blue/green deployment of the task definition and
+// returns its ID.
+func (cd *CodeDeploy) CreateDeployment(i *CreateDeploymentInput) (string, error) {
+	content, err := buildAppSpec(i)
+
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := cd.svc.CreateDeployment(
+		&awscodedeploy.CreateDeploymentInput{
+			ApplicationName:     aws.String(i.ApplicationName),
+			DeploymentGroupName: aws.String(i.DeploymentGroupName),
+			Revision: &awscodedeploy.RevisionLocation{
+				AppSpecContent: &awscodedeploy.AppSpecContent{
+					Content: aws.String(content),
+				},
+				RevisionType: aws.String(awscodedeploy.RevisionLocationTypeAppSpecContent),
+			},
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("could not create deployment for %s: %v", i.DeploymentGroupName, err)
+	}
+
+	return aws.StringValue(resp.DeploymentId), nil
+}
+
+// DescribeDeployment returns the deployment with the given ID.
+func (cd *CodeDeploy) DescribeDeployment(id string) (Deployment, error) {
+	resp, err := cd.svc.GetDeployment(
+		&awscodedeploy.GetDeploymentInput{
+			DeploymentId: aws.String(id),
+		},
+	)
+
+	if err != nil {
+		return Deployment{}, fmt.Errorf("could not describe deployment %s: %v", id, err)
+	}
+
+	d := Deployment{
+		Id:     aws.StringValue(resp.DeploymentInfo.DeploymentId),
+		Status: aws.StringValue(resp.DeploymentInfo.Status),
+	}
+
+	if resp.DeploymentInfo.ErrorInformation != nil {
+		d.ErrorMessage = aws.StringValue(resp.DeploymentInfo.ErrorInformation.Message)
+	}
+
+	return d, nil
+}
+
+// WaitForDeployment polls the deployment until it has finished and returns an
+// error if it failed, was stopped, or is still running after the timeout.
+// Each change in the deployment's status is passed to fn, if given.
+func (cd *CodeDeploy) WaitForDeployment(id string, timeout time.Duration, fn func(Deployment)) error {
+	var status string
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		d, err := cd.DescribeDeployment(id)
+
+		if err != nil {
+			return err
+		}
+
+		if d.Status != status && fn != nil {
+			fn(d)
+		}
+
+		status = d.Status
+
+		if d.Done() {
+			if d.Status != awscodedeploy.DeploymentStatusSucceeded {
+				return fmt.Errorf("deployment %s %s: %s", id, strings.ToLower(d.Status), d.ErrorMessage)
+			}
+
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for deployment %s", timeout, id)
+		}
+
+		time.Sleep(deploymentPollInterval)
+	}
+}
+
+func buildAppSpec(i *CreateDeploymentInput) (string, error) {
+	spec := appSpec{
+		Version: appSpecVersion,
+		Resources: []appSpecResource{
+			appSpecResource{
+				TargetService: appSpecTargetService{
+					Type: appSpecTargetType,
+					Properties: appSpecProperties{
+						TaskDefinition: i.TaskDefinitionArn,
+						LoadBalancerInfo: appSpecLoadBalancerInfo{
+							ContainerName: i.ContainerName,
+							ContainerPort: i.ContainerPort,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	content, err := json.Marshal(spec)
+
+	if err != nil {
+		return "", fmt.Errorf("could not build AppSpec: %v", err)
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,192 @@
+package codedeploy
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awscodedeploy "github.com/aws/aws-sdk-go/service/codedeploy"
+)
+
+const (
+	applicationNameFormat = "fargate-%s-%s"
+
+	// DefaultDeploymentConfigName shifts all traffic to the replacement tasks
+	// at once after they pass the load balancer's health checks.
+	DefaultDeploymentConfigName = "CodeDeployDefault.ECSAllAtOnce"
+)
+
+// DeploymentGroup is the CodeDeploy deployment group which runs blue/green
+// deployments of a service, shifting traffic on the production listener
+// between a pair of target groups. Replacement tasks can be reached on the
+// test listener before traffic is shifted to them.
+type DeploymentGroup struct {
+	ApplicationName        string
+	ClusterName            string
+	DeploymentConfigName   string
+	Name                   string
+	ProdListenerArn        string
+	RoleArn                string
+	ServiceName            string
+	TargetGroupNames       []string
+	TerminationWaitMinutes int64
+	TestListenerArn        string
+}
+
+// ApplicationName returns the name of the CodeDeploy application which
+// deploys the named service in the given cluster.
+func ApplicationName(clusterName, serviceName string) string {
+	return fmt.Sprintf(applicationNameFormat, clusterName, serviceName)
+}
+
+// CreateDeploymentGroup creates the deployment group, and its application if
+// it doesn't already exist. Failed deployments are rolled back automatically.
+func (cd *CodeDeploy) CreateDeploymentGroup(g *DeploymentGroup) error {
+	_, err := cd.svc.CreateApplication(
+		&awscodedeploy.CreateApplicationInput{
+			ApplicationName: aws.String(g.ApplicationName),
+			ComputePlatform: aws.String(awscodedeploy.ComputePlatformEcs),
+		},
+	)
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != awscodedeploy.ErrCodeApplicationAlreadyExistsException {
+			return fmt.Errorf("could not create application %s: %v", g.ApplicationName, err)
+		}
+	}
+
+	var targetGroups []*awscodedeploy.TargetGroupInfo
+
+	for _, name := range g.TargetGroupNames {
+		targetGroups = append(targetGroups, &awscodedeploy.TargetGroupInfo{Name: aws.String(name)})
+	}
+
+	_, err = cd.svc.CreateDeploymentGroup(
+		&awscodedeploy.CreateDeploymentGroupInput{
+			ApplicationName: aws.String(g.ApplicationName),
+			AutoRollbackConfiguration: &awscodedeploy.AutoRollbackConfiguration{
+				Enabled: aws.Bool(true),
+				Events: aws.StringSlice(
+					[]string{
+						awscodedeploy.AutoRollbackEventDeploymentFailure,
+						awscodedeploy.AutoRollbackEventDeploymentStopOnRequest,
+					},
+				),
+			},
+			BlueGreenDeploymentConfiguration: &awscodedeploy.BlueGreenDeploymentConfiguration{
+				DeploymentReadyOption: &awscodedeploy.DeploymentReadyOption{
+					ActionOnTimeout: aws.String(awscodedeploy.DeploymentReadyActionContinueDeployment),
+				},
+				TerminateBlueInstancesOnDeploymentSuccess: &awscodedeploy.BlueInstanceTerminationOption{
+					Action:                       aws.String(awscodedeploy.InstanceActionTerminate),
+					TerminationWaitTimeInMinutes: aws.Int64(g.TerminationWaitMinutes),
+				},
+			},
+			DeploymentConfigName: aws.String(g.DeploymentConfigName),
+			DeploymentGroupName:  aws.String(g.Name),
+			DeploymentStyle: &awscodedeploy.DeploymentStyle{
+				DeploymentOption: aws.String(awscodedeploy.DeploymentOptionWithTrafficControl),
+				DeploymentType:   aws.String(awscodedeploy.DeploymentTypeBlueGreen),
+			},
+			EcsServices: []*awscodedeploy.ECSService{
+				&awscodedeploy.ECSService{
+					ClusterName: aws.String(g.ClusterName),
+					ServiceName: aws.String(g.ServiceName),
+				},
+			},
+			LoadBalancerInfo: &awscodedeploy.LoadBalancerInfo{
+				TargetGroupPairInfoList: []*awscodedeploy.TargetGroupPairInfo{
+					&awscodedeploy.TargetGroupPairInfo{
+						ProdTrafficRoute: &awscodedeploy.TrafficRoute{
+							ListenerArns: aws.StringSlice([]string{g.ProdListenerArn}),
+						},
+						TargetGroups: targetGroups,
+						TestTrafficRoute: &awscodedeploy.TrafficRoute{
+							ListenerArns: aws.StringSlice([]string{g.TestListenerArn}),
+						},
+					},
+				},
+			},
+			ServiceRoleArn: aws.String(g.RoleArn),
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not create deployment group %s: %v", g.Name, err)
+	}
+
+	return nil
+}
+
+// DescribeDeploymentGroup returns the named deployment group of the
+// application.
+func (cd *CodeDeploy) DescribeDeploymentGroup(applicationName, name string) (DeploymentGroup, error) {
+	resp, err := cd.svc.GetDeploymentGroup(
+		&awscodedeploy.GetDeploymentGroupInput{
+			ApplicationName:     aws.String(applicationName),
+			DeploymentGroupName: aws.String(name),
+		},
+	)
+
+	if err != nil {
+		return DeploymentGroup{}, fmt.Errorf("could not describe deployment group %s: %v", name, err)
+	}
+
+	return deploymentGroupFromInfo(resp.DeploymentGroupInfo), nil
+}
+
+// DeleteApplication deletes the application along with its deployment groups.
+// Applications which don't exist are ignored.
+func (cd *CodeDeploy) DeleteApplication(name string) error {
+	_, err := cd.svc.DeleteApplication(
+		&awscodedeploy.DeleteApplicationInput{
+			ApplicationName: aws.String(name),
+		},
+	)
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == awscodedeploy.ErrCodeApplicationDoesNotExistException {
+			return nil
+		}
+
+		return fmt.Errorf("could not delete application %s: %v", name, err)
+	}
+
+	return nil
+}
+
+func deploymentGroupFromInfo(info *awscodedeploy.DeploymentGroupInfo) DeploymentGroup {
+	g := DeploymentGroup{
+		ApplicationName:      aws.StringValue(info.ApplicationName),
+		DeploymentConfigName: aws.StringValue(info.DeploymentConfigName),
+		Name:                 aws.StringValue(info.DeploymentGroupName),
+		RoleArn:              aws.StringValue(info.ServiceRoleArn),
+	}
+
+	if len(info.EcsServices) > 0 {
+		g.ClusterName = aws.StringValue(info.EcsServices[0].ClusterName)
+		g.ServiceName = aws.StringValue(info.EcsServices[0].ServiceName)
+	}
+
+	if config := info.BlueGreenDeploymentConfiguration; config != nil && config.TerminateBlueInstancesOnDeploymentSuccess != nil {
+		g.TerminationWaitMinutes = aws.Int64Value(config.TerminateBlueInstancesOnDeploymentSuccess.TerminationWaitTimeInMinutes)
+	}
+
+	if info.LoadBalancerInfo != nil && len(info.LoadBalancerInfo.TargetGroupPairInfoList) > 0 {
+		pair := info.LoadBalancerInfo.TargetGroupPairInfoList[0]
+
+		for _, targetGroup := range pair.TargetGroups {
+			g.TargetGroupNames = append(g.TargetGroupNames, aws.StringValue(targetGroup.Name))
+		}
+
+		if pair.ProdTrafficRoute != nil && len(pair.ProdTrafficRoute.ListenerArns) > 0 {
+			g.ProdListenerArn = aws.StringValue(pair.ProdTrafficRoute.ListenerArns[0])
+		}
+
+		if pair.TestTrafficRoute != nil && len(pair.TestTrafficRoute.ListenerArns) > 0 {
+			g.TestListenerArn = aws.StringValue(pair.TestTrafficRoute.ListenerArns[0])
+		}
+	}
+
+	return g
+}
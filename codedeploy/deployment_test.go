@@ -0,0 +1,51 @@
+package codedeploy
+
+import (
+	"testing"
+)
+
+func TestBuildAppSpec(t *testing.T) {
+	content, err := buildAppSpec(
+		&CreateDeploymentInput{
+			ContainerName:     "web",
+			ContainerPort:     8080,
+			TaskDefinitionArn: "arn:aws:ecs:us-east-1:123456789012:task-definition/web:3",
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := `{"version":"0.0","Resources":[{"TargetService":{"Type":"AWS::ECS::Service","Properties":{"TaskDefinition":"arn:aws:ecs:us-east-1:123456789012:task-definition/web:3","LoadBalancerInfo":{"ContainerName":"web","ContainerPort":8080}}}}]}`
+
+	if content != expected {
+		t.Errorf("expected %s, got %s", expected, content)
+	}
+}
+
+func TestApplicationName(t *testing.T) {
+	if name := ApplicationName("fargate", "web"); name != "fargate-fargate-web" {
+		t.Errorf("expected fargate-fargate-web, got %s", name)
+	}
+}
+
+func TestDeploymentDone(t *testing.T) {
+	var tests = []struct {
+		status string
+		done   bool
+	}{
+		{"Created", false},
+		{"InProgress", false},
+		{"Ready", false},
+		{"Succeeded", true},
+		{"Failed", true},
+		{"Stopped", true},
+	}
+
+	for _, test := range tests {
+		if done := (Deployment{Status: test.status}).Done(); done != test.done {
+			t.Errorf("expected done %t for %s, got %t", test.done, test.status, done)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package codedeploy
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/codedeploy"
+)
+
+type CodeDeploy struct {
+	svc *codedeploy.CodeDeploy
+}
+
+func New(sess *session.Session) CodeDeploy {
+	return CodeDeploy{
+		svc: codedeploy.New(sess),
+	}
+}
@@ -62,6 +62,27 @@ func (repository *Repository) Build(tag string) {
 	}
 }
 
+// BuildForPlatform builds the image for a target platform [e.g. linux/arm64]
+// using buildx, which can cross-build for an architecture other than the
+// host's. The image is loaded into the local image store so it can be pushed.
+func (repository *Repository) BuildForPlatform(tag, platform string) {
+	console.Debug("Building Docker image for %s [%s]", platform, repository.UriFor(tag))
+	console.Shell("docker buildx build --platform %s --load --tag %s .", platform, repository.UriFor(tag))
+
+	cmd := exec.Command("docker", "buildx", "build", "--platform", platform, "--load", "--tag", repository.UriFor(tag), ".")
+
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		console.ErrorExit(err, "Couldn't build Docker image [%s]", repository.UriFor(tag))
+	}
+
+	if err := cmd.Wait(); err != nil {
+		console.IssueExit("Couldn't build Docker image for %s [%s]", platform, repository.Uri)
+	}
+}
+
 func (repository *Repository) Push(tag string) {
 	console.Debug("Pushing Docker image [%s]", repository.UriFor(tag))
 	console.Shell("docker push %s .", repository.UriFor(tag))
@@ -1,47 +1,145 @@
 package ec2
 
 import (
+	"fmt"
+
 	"github.com/aws/aws-sdk-go/aws"
 	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/jpignata/fargate/console"
 )
 
+// describeNetworkInterfacesBatchSize is the number of network interfaces
+// described per request, kept within the number of values a filter accepts.
+const describeNetworkInterfacesBatchSize = 100
+
 type Eni struct {
-	PublicIpAddress  string
 	EniId            string
+	PrivateIpAddress string
+	PublicDnsName    string
+	PublicIpAddress  string
 	SecurityGroupIds []string
 }
 
+// DescribeNetworkInterfaces describes network interfaces in batches, keyed by
+// network interface ID. Interfaces which no longer exist, such as those of
+// stopped tasks, are left out rather than failing the request.
 func (ec2 SDKClient) DescribeNetworkInterfaces(eniIds []string) map[string]Eni {
-	enis := make(map[string]Eni)
-
-	resp, err := ec2.client.DescribeNetworkInterfaces(
-		&awsec2.DescribeNetworkInterfacesInput{
-			NetworkInterfaceIds: aws.StringSlice(eniIds),
-		},
-	)
+	enis, err := ec2.describeNetworkInterfaces(eniIds)
 
 	if err != nil {
 		console.ErrorExit(err, "Could not describe network interfaces")
 	}
 
-	for _, e := range resp.NetworkInterfaces {
-		var securityGroupIds []*string
+	return enis
+}
+
+// FindNetworkInterfaces returns the security group IDs and public IP of each
+// of the given network interfaces, keyed by network interface ID. Interfaces
+// without a public IP, such as those in private subnets, have an empty one.
+func (ec2 SDKClient) FindNetworkInterfaces(eniIds []string) (map[string][]string, map[string]string, error) {
+	securityGroupIds := make(map[string][]string)
+	publicIps := make(map[string]string)
+
+	enis, err := ec2.describeNetworkInterfaces(eniIds)
+
+	if err != nil {
+		return securityGroupIds, publicIps, fmt.Errorf("could not describe network interfaces: %v", err)
+	}
+
+	for eniId, eni := range enis {
+		securityGroupIds[eniId] = eni.SecurityGroupIds
+		publicIps[eniId] = eni.PublicIpAddress
+	}
+
+	return securityGroupIds, publicIps, nil
+}
+
+func (ec2 SDKClient) describeNetworkInterfaces(eniIds []string) (map[string]Eni, error) {
+	enis := make(map[string]Eni)
+
+	for start := 0; start < len(eniIds); start += describeNetworkInterfacesBatchSize {
+		end := start + describeNetworkInterfacesBatchSize
 
-		for _, group := range e.Groups {
-			securityGroupIds = append(securityGroupIds, group.GroupId)
+		if end > len(eniIds) {
+			end = len(eniIds)
 		}
 
-		if e.Association != nil {
-			eni := Eni{
-				EniId:            aws.StringValue(e.NetworkInterfaceId),
-				PublicIpAddress:  aws.StringValue(e.Association.PublicIp),
-				SecurityGroupIds: aws.StringValueSlice(securityGroupIds),
-			}
+		err := ec2.client.DescribeNetworkInterfacesPages(
+			&awsec2.DescribeNetworkInterfacesInput{
+				Filters: []*awsec2.Filter{
+					&awsec2.Filter{
+						Name:   aws.String("network-interface-id"),
+						Values: aws.StringSlice(eniIds[start:end]),
+					},
+				},
+			},
+			func(resp *awsec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+				for _, e := range resp.NetworkInterfaces {
+					eni := eniFromNetworkInterface(e)
+					enis[eni.EniId] = eni
+				}
 
-			enis[eni.EniId] = eni
+				return true
+			},
+		)
+
+		if err != nil {
+			return enis, err
 		}
 	}
 
-	return enis
+	return enis, nil
+}
+
+func eniFromNetworkInterface(e *awsec2.NetworkInterface) Eni {
+	var securityGroupIds []*string
+
+	for _, group := range e.Groups {
+		securityGroupIds = append(securityGroupIds, group.GroupId)
+	}
+
+	eni := Eni{
+		EniId:            aws.StringValue(e.NetworkInterfaceId),
+		PrivateIpAddress: aws.StringValue(e.PrivateIpAddress),
+		SecurityGroupIds: aws.StringValueSlice(securityGroupIds),
+	}
+
+	// Network interfaces of tasks in private subnets have no public IP.
+	if e.Association != nil {
+		eni.PublicDnsName = aws.StringValue(e.Association.PublicDnsName)
+		eni.PublicIpAddress = aws.StringValue(e.Association.PublicIp)
+	}
+
+	return eni
+}
+
+// ListTaskNetworkInterfaces returns the status [e.g. available, in-use] of each
+// network interface created by ECS for a task, keyed by network interface ID.
+// ECS describes these with the ARN of the task's attachment.
+func (ec2 SDKClient) ListTaskNetworkInterfaces() (map[string]string, error) {
+	statuses := make(map[string]string)
+
+	err := ec2.client.DescribeNetworkInterfacesPages(
+		&awsec2.DescribeNetworkInterfacesInput{
+			Filters: []*awsec2.Filter{
+				&awsec2.Filter{
+					Name:   aws.String("description"),
+					Values: aws.StringSlice([]string{"arn:aws:ecs:*:attachment/*"}),
+				},
+			},
+		},
+		func(resp *awsec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+			for _, e := range resp.NetworkInterfaces {
+				statuses[aws.StringValue(e.NetworkInterfaceId)] = aws.StringValue(e.Status)
+			}
+
+			return true
+		},
+	)
+
+	if err != nil {
+		return statuses, fmt.Errorf("could not describe network interfaces: %v", err)
+	}
+
+	return statuses, nil
 }
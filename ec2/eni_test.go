@@ -0,0 +1,150 @@
+package ec2
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	"github.com/jpignata/fargate/ec2/mock/sdk"
+)
+
+func TestDescribeNetworkInterfaces(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var eniIds []string
+	var batchSizes []int
+
+	for i := 0; i < describeNetworkInterfacesBatchSize+1; i++ {
+		eniIds = append(eniIds, fmt.Sprintf("eni-%d", i))
+	}
+
+	mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+	ec2 := SDKClient{client: mockEC2Client}
+
+	mockEC2Client.EXPECT().DescribeNetworkInterfacesPages(gomock.Any(), gomock.Any()).Do(
+		func(input *awsec2.DescribeNetworkInterfacesInput, fn func(*awsec2.DescribeNetworkInterfacesOutput, bool) bool) {
+			batchSizes = append(batchSizes, len(input.Filters[0].Values))
+
+			// Only the first interface still exists; the rest belonged to
+			// stopped tasks.
+			if aws.StringValue(input.Filters[0].Values[0]) == "eni-0" {
+				fn(
+					&awsec2.DescribeNetworkInterfacesOutput{
+						NetworkInterfaces: []*awsec2.NetworkInterface{
+							&awsec2.NetworkInterface{
+								Association: &awsec2.NetworkInterfaceAssociation{
+									PublicDnsName: aws.String("ec2-54-1-2-3.compute-1.amazonaws.com"),
+									PublicIp:      aws.String("54.1.2.3"),
+								},
+								NetworkInterfaceId: aws.String("eni-0"),
+								PrivateIpAddress:   aws.String("10.0.0.5"),
+							},
+						},
+					},
+					true,
+				)
+			}
+		},
+	).Return(nil).Times(2)
+
+	enis := ec2.DescribeNetworkInterfaces(eniIds)
+
+	if len(batchSizes) != 2 || batchSizes[0] != describeNetworkInterfacesBatchSize || batchSizes[1] != 1 {
+		t.Errorf("expected batches of %d and 1, got %v", describeNetworkInterfacesBatchSize, batchSizes)
+	}
+
+	expected := Eni{
+		EniId:            "eni-0",
+		PrivateIpAddress: "10.0.0.5",
+		PublicDnsName:    "ec2-54-1-2-3.compute-1.amazonaws.com",
+		PublicIpAddress:  "54.1.2.3",
+		SecurityGroupIds: []string{},
+	}
+
+	if len(enis) != 1 || !reflect.DeepEqual(enis["eni-0"], expected) {
+		t.Errorf("expected %+v, got %+v", expected, enis)
+	}
+}
+
+func TestDescribeNetworkInterfacesWithoutIds(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+	ec2 := SDKClient{client: mockEC2Client}
+
+	if enis := ec2.DescribeNetworkInterfaces(nil); len(enis) != 0 {
+		t.Errorf("expected no network interfaces, got %v", enis)
+	}
+}
+
+func TestFindNetworkInterfaces(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+	ec2 := SDKClient{client: mockEC2Client}
+
+	mockEC2Client.EXPECT().DescribeNetworkInterfacesPages(gomock.Any(), gomock.Any()).Do(
+		func(input *awsec2.DescribeNetworkInterfacesInput, fn func(*awsec2.DescribeNetworkInterfacesOutput, bool) bool) {
+			fn(
+				&awsec2.DescribeNetworkInterfacesOutput{
+					NetworkInterfaces: []*awsec2.NetworkInterface{
+						&awsec2.NetworkInterface{
+							Association: &awsec2.NetworkInterfaceAssociation{
+								PublicIp: aws.String("54.1.2.3"),
+							},
+							Groups: []*awsec2.GroupIdentifier{
+								&awsec2.GroupIdentifier{GroupId: aws.String("sg-1")},
+							},
+							NetworkInterfaceId: aws.String("eni-public"),
+						},
+						&awsec2.NetworkInterface{
+							Groups: []*awsec2.GroupIdentifier{
+								&awsec2.GroupIdentifier{GroupId: aws.String("sg-2")},
+							},
+							NetworkInterfaceId: aws.String("eni-private"),
+						},
+					},
+				},
+				true,
+			)
+		},
+	).Return(nil)
+
+	securityGroupIds, publicIps, err := ec2.FindNetworkInterfaces([]string{"eni-public", "eni-private"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expectedSecurityGroupIds := map[string][]string{"eni-public": []string{"sg-1"}, "eni-private": []string{"sg-2"}}
+	expectedPublicIps := map[string]string{"eni-public": "54.1.2.3", "eni-private": ""}
+
+	if !reflect.DeepEqual(securityGroupIds, expectedSecurityGroupIds) {
+		t.Errorf("expected security groups %v, got %v", expectedSecurityGroupIds, securityGroupIds)
+	}
+
+	if !reflect.DeepEqual(publicIps, expectedPublicIps) {
+		t.Errorf("expected public IPs %v, got %v", expectedPublicIps, publicIps)
+	}
+}
+
+func TestFindNetworkInterfacesError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+	ec2 := SDKClient{client: mockEC2Client}
+
+	mockEC2Client.EXPECT().DescribeNetworkInterfacesPages(gomock.Any(), gomock.Any()).Return(errors.New("boom"))
+
+	if _, _, err := ec2.FindNetworkInterfaces([]string{"eni-1"}); err == nil {
+		t.Error("expected error, got none")
+	}
+}
@@ -2,6 +2,8 @@ package ec2
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -13,6 +15,9 @@ const (
 	defaultSecurityGroupDescription     = "Default Fargate CLI SG"
 	defaultSecurityGroupIngressCIDR     = "0.0.0.0/0"
 	defaultSecurityGroupIngressProtocol = "-1"
+
+	egressCIDR = "0.0.0.0/0"
+	egressPort = 443
 )
 
 // GetDefaultSubnetIDs finds and returns the subnet IDs marked as default.
@@ -108,3 +113,426 @@ func (ec2 SDKClient) AuthorizeAllSecurityGroupIngress(groupID string) error {
 
 	return err
 }
+
+// FindSubnetIDs returns the subset of the given subnet IDs that still exist.
+func (ec2 SDKClient) FindSubnetIDs(subnetIDs []string) ([]string, error) {
+	var foundSubnetIDs []string
+
+	if len(subnetIDs) == 0 {
+		return foundSubnetIDs, nil
+	}
+
+	resp, err := ec2.client.DescribeSubnets(
+		&awsec2.DescribeSubnetsInput{
+			Filters: []*awsec2.Filter{
+				&awsec2.Filter{
+					Name:   aws.String("subnet-id"),
+					Values: aws.StringSlice(subnetIDs),
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return foundSubnetIDs, fmt.Errorf("could not describe subnets: %v", err)
+	}
+
+	for _, subnet := range resp.Subnets {
+		foundSubnetIDs = append(foundSubnetIDs, aws.StringValue(subnet.SubnetId))
+	}
+
+	return foundSubnetIDs, nil
+}
+
+// FindSecurityGroupIDs returns the subset of the given security group IDs that still exist.
+func (ec2 SDKClient) FindSecurityGroupIDs(groupIDs []string) ([]string, error) {
+	var foundGroupIDs []string
+
+	if len(groupIDs) == 0 {
+		return foundGroupIDs, nil
+	}
+
+	resp, err := ec2.client.DescribeSecurityGroups(
+		&awsec2.DescribeSecurityGroupsInput{
+			Filters: []*awsec2.Filter{
+				&awsec2.Filter{
+					Name:   aws.String("group-id"),
+					Values: aws.StringSlice(groupIDs),
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return foundGroupIDs, fmt.Errorf("could not describe security groups: %v", err)
+	}
+
+	for _, group := range resp.SecurityGroups {
+		foundGroupIDs = append(foundGroupIDs, aws.StringValue(group.GroupId))
+	}
+
+	return foundGroupIDs, nil
+}
+
+// ResolveSecurityGroupNames returns the IDs of the security groups with the
+// given names, matching either a group's name or its Name tag. Groups are
+// limited to the given VPC unless vpcID is empty. An error is returned if a
+// name matches no groups or more than one group.
+func (ec2 SDKClient) ResolveSecurityGroupNames(vpcID string, names []string) ([]string, error) {
+	var groupIDs []string
+
+	if len(names) == 0 {
+		return groupIDs, nil
+	}
+
+	matches := make(map[string]map[string]bool)
+
+	for _, filterName := range []string{"group-name", "tag:Name"} {
+		resp, err := ec2.client.DescribeSecurityGroups(
+			&awsec2.DescribeSecurityGroupsInput{
+				Filters: vpcFilters(vpcID, filterName, names),
+			},
+		)
+
+		if err != nil {
+			return groupIDs, fmt.Errorf("could not describe security groups: %v", err)
+		}
+
+		for _, group := range resp.SecurityGroups {
+			for _, name := range names {
+				if aws.StringValue(group.GroupName) == name || nameTag(group.Tags) == name {
+					if matches[name] == nil {
+						matches[name] = make(map[string]bool)
+					}
+
+					matches[name][aws.StringValue(group.GroupId)] = true
+				}
+			}
+		}
+	}
+
+	return resolveNames("security group", names, matches)
+}
+
+// ResolveSubnetNames returns the IDs of the subnets with the given Name tags.
+// Subnets are limited to the given VPC unless vpcID is empty. An error is
+// returned if a name matches no subnets or more than one subnet.
+func (ec2 SDKClient) ResolveSubnetNames(vpcID string, names []string) ([]string, error) {
+	var subnetIDs []string
+
+	if len(names) == 0 {
+		return subnetIDs, nil
+	}
+
+	resp, err := ec2.client.DescribeSubnets(
+		&awsec2.DescribeSubnetsInput{
+			Filters: vpcFilters(vpcID, "tag:Name", names),
+		},
+	)
+
+	if err != nil {
+		return subnetIDs, fmt.Errorf("could not describe subnets: %v", err)
+	}
+
+	matches := make(map[string]map[string]bool)
+
+	for _, subnet := range resp.Subnets {
+		name := nameTag(subnet.Tags)
+
+		if matches[name] == nil {
+			matches[name] = make(map[string]bool)
+		}
+
+		matches[name][aws.StringValue(subnet.SubnetId)] = true
+	}
+
+	return resolveNames("subnet", names, matches)
+}
+
+func resolveNames(resourceType string, names []string, matches map[string]map[string]bool) ([]string, error) {
+	var ids []string
+
+	for _, name := range names {
+		var matchedIDs []string
+
+		for id := range matches[name] {
+			matchedIDs = append(matchedIDs, id)
+		}
+
+		sort.Strings(matchedIDs)
+
+		switch len(matchedIDs) {
+		case 0:
+			return ids, fmt.Errorf("could not find %s named %s", resourceType, name)
+		case 1:
+			ids = append(ids, matchedIDs[0])
+		default:
+			return ids, fmt.Errorf("found multiple %ss named %s [%s]", resourceType, name, strings.Join(matchedIDs, ", "))
+		}
+	}
+
+	return ids, nil
+}
+
+func vpcFilters(vpcID, name string, values []string) []*awsec2.Filter {
+	filters := []*awsec2.Filter{
+		&awsec2.Filter{
+			Name:   aws.String(name),
+			Values: aws.StringSlice(values),
+		},
+	}
+
+	if vpcID != "" {
+		filters = append(
+			filters,
+			&awsec2.Filter{
+				Name:   aws.String("vpc-id"),
+				Values: aws.StringSlice([]string{vpcID}),
+			},
+		)
+	}
+
+	return filters
+}
+
+func nameTag(tags []*awsec2.Tag) string {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == "Name" {
+			return aws.StringValue(tag.Value)
+		}
+	}
+
+	return ""
+}
+
+// SecurityGroupsAllowEgress returns whether any of the given security groups
+// allow outbound HTTPS traffic to anywhere [0.0.0.0/0], which is what a task
+// needs to pull its image from ECR or a public registry and to reach other AWS
+// APIs without VPC endpoints. A rule allowing all traffic, or TCP on a port
+// range including 443, is sufficient; rules to prefix lists or narrower CIDR
+// blocks are not considered.
+func (ec2 SDKClient) SecurityGroupsAllowEgress(groupIDs []string) (bool, error) {
+	resp, err := ec2.client.DescribeSecurityGroups(
+		&awsec2.DescribeSecurityGroupsInput{
+			GroupIds: aws.StringSlice(groupIDs),
+		},
+	)
+
+	if err != nil {
+		return false, fmt.Errorf("could not describe security groups: %v", err)
+	}
+
+	for _, group := range resp.SecurityGroups {
+		for _, permission := range group.IpPermissionsEgress {
+			if allowsEgress(permission) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func allowsEgress(permission *awsec2.IpPermission) bool {
+	switch aws.StringValue(permission.IpProtocol) {
+	case "-1":
+	case "tcp", "6":
+		if aws.Int64Value(permission.FromPort) > egressPort || aws.Int64Value(permission.ToPort) < egressPort {
+			return false
+		}
+	default:
+		return false
+	}
+
+	for _, ipRange := range permission.IpRanges {
+		if aws.StringValue(ipRange.CidrIp) == egressCIDR {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Subnet is a subnet along with the availability zone and VPC it's in.
+type Subnet struct {
+	AvailabilityZone string
+	SubnetId         string
+	VpcId            string
+}
+
+// DescribeSubnets returns the given subnets.
+func (ec2 SDKClient) DescribeSubnets(subnetIDs []string) ([]Subnet, error) {
+	var subnets []Subnet
+
+	resp, err := ec2.client.DescribeSubnets(
+		&awsec2.DescribeSubnetsInput{
+			SubnetIds: aws.StringSlice(subnetIDs),
+		},
+	)
+
+	if err != nil {
+		return subnets, fmt.Errorf("could not describe subnets: %v", err)
+	}
+
+	for _, subnet := range resp.Subnets {
+		subnets = append(subnets,
+			Subnet{
+				AvailabilityZone: aws.StringValue(subnet.AvailabilityZone),
+				SubnetId:         aws.StringValue(subnet.SubnetId),
+				VpcId:            aws.StringValue(subnet.VpcId),
+			},
+		)
+	}
+
+	return subnets, nil
+}
+
+// SecurityGroupsAllowIngress returns whether any of the given security groups
+// allow inbound TCP traffic on the port from any of the source security
+// groups. A rule allowing all traffic, or TCP on a port range including the
+// port, from one of the source groups or from any CIDR block is sufficient;
+// whether a CIDR block covers the sources' addresses is not considered.
+func (ec2 SDKClient) SecurityGroupsAllowIngress(groupIDs []string, port int64, sourceGroupIDs []string) (bool, error) {
+	resp, err := ec2.client.DescribeSecurityGroups(
+		&awsec2.DescribeSecurityGroupsInput{
+			GroupIds: aws.StringSlice(groupIDs),
+		},
+	)
+
+	if err != nil {
+		return false, fmt.Errorf("could not describe security groups: %v", err)
+	}
+
+	for _, group := range resp.SecurityGroups {
+		for _, permission := range group.IpPermissions {
+			if allowsIngress(permission, port, sourceGroupIDs) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func allowsIngress(permission *awsec2.IpPermission, port int64, sourceGroupIDs []string) bool {
+	switch aws.StringValue(permission.IpProtocol) {
+	case "-1":
+	case "tcp", "6":
+		if aws.Int64Value(permission.FromPort) > port || aws.Int64Value(permission.ToPort) < port {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if len(permission.IpRanges) > 0 {
+		return true
+	}
+
+	for _, pair := range permission.UserIdGroupPairs {
+		for _, sourceGroupID := range sourceGroupIDs {
+			if aws.StringValue(pair.GroupId) == sourceGroupID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// SubnetsArePrivate returns whether none of the given subnets route to an
+// internet gateway. Tasks in private subnets reach the internet, if at all,
+// through a NAT gateway or VPC endpoints, so a public IP is of no use to them.
+// Subnets without a route table of their own use their VPC's main route table.
+func (ec2 SDKClient) SubnetsArePrivate(subnetIDs []string) (bool, error) {
+	subnets, err := ec2.DescribeSubnets(subnetIDs)
+
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := ec2.client.DescribeRouteTables(
+		&awsec2.DescribeRouteTablesInput{
+			Filters: []*awsec2.Filter{
+				&awsec2.Filter{
+					Name:   aws.String("association.subnet-id"),
+					Values: aws.StringSlice(subnetIDs),
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return false, fmt.Errorf("could not describe route tables: %v", err)
+	}
+
+	routeTables := make(map[string]*awsec2.RouteTable)
+
+	for _, routeTable := range resp.RouteTables {
+		for _, association := range routeTable.Associations {
+			if subnetID := aws.StringValue(association.SubnetId); subnetID != "" {
+				routeTables[subnetID] = routeTable
+			}
+		}
+	}
+
+	mainRouteTables := make(map[string]*awsec2.RouteTable)
+
+	for _, subnet := range subnets {
+		routeTable, ok := routeTables[subnet.SubnetId]
+
+		if !ok {
+			if routeTable, ok = mainRouteTables[subnet.VpcId]; !ok {
+				routeTable, err = ec2.mainRouteTable(subnet.VpcId)
+
+				if err != nil {
+					return false, err
+				}
+
+				mainRouteTables[subnet.VpcId] = routeTable
+			}
+		}
+
+		if routesToInternetGateway(routeTable) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (ec2 SDKClient) mainRouteTable(vpcID string) (*awsec2.RouteTable, error) {
+	resp, err := ec2.client.DescribeRouteTables(
+		&awsec2.DescribeRouteTablesInput{
+			Filters: []*awsec2.Filter{
+				&awsec2.Filter{
+					Name:   aws.String("vpc-id"),
+					Values: aws.StringSlice([]string{vpcID}),
+				},
+				&awsec2.Filter{
+					Name:   aws.String("association.main"),
+					Values: aws.StringSlice([]string{"true"}),
+				},
+			},
+		},
+	)
+
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("could not describe main route table of VPC %s: %v", vpcID, err)
+	case len(resp.RouteTables) == 0:
+		return nil, fmt.Errorf("could not find main route table of VPC %s", vpcID)
+	default:
+		return resp.RouteTables[0], nil
+	}
+}
+
+func routesToInternetGateway(routeTable *awsec2.RouteTable) bool {
+	for _, route := range routeTable.Routes {
+		if strings.HasPrefix(aws.StringValue(route.GatewayId), "igw-") {
+			return true
+		}
+	}
+
+	return false
+}
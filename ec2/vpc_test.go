@@ -2,6 +2,7 @@ package ec2
 
 import (
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -294,3 +295,346 @@ func TestAuthorizeAllSecurityGroupIngress(t *testing.T) {
 		t.Errorf("expected no error, got %v", err)
 	}
 }
+
+func TestFindSubnetIDs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	filter := &awsec2.Filter{
+		Name:   aws.String("subnet-id"),
+		Values: aws.StringSlice([]string{"subnet-abcdef", "subnet-123456"}),
+	}
+	input := &awsec2.DescribeSubnetsInput{
+		Filters: []*awsec2.Filter{filter},
+	}
+	output := &awsec2.DescribeSubnetsOutput{
+		Subnets: []*awsec2.Subnet{
+			&awsec2.Subnet{SubnetId: aws.String("subnet-abcdef")},
+		},
+	}
+
+	mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+	ec2 := SDKClient{client: mockEC2Client}
+
+	mockEC2Client.EXPECT().DescribeSubnets(input).Return(output, nil)
+
+	out, err := ec2.FindSubnetIDs([]string{"subnet-abcdef", "subnet-123456"})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if len(out) != 1 || out[0] != "subnet-abcdef" {
+		t.Errorf("expected [subnet-abcdef], got %v", out)
+	}
+}
+
+func TestFindSecurityGroupIDsError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+	ec2 := SDKClient{client: mockEC2Client}
+
+	mockEC2Client.EXPECT().DescribeSecurityGroups(gomock.Any()).Return(&awsec2.DescribeSecurityGroupsOutput{}, errors.New("boom"))
+
+	out, err := ec2.FindSecurityGroupIDs([]string{"sg-abcdef"})
+
+	if err == nil {
+		t.Errorf("expected error, got none")
+	}
+
+	if len(out) > 0 {
+		t.Errorf("expected no results, got %v", out)
+	}
+}
+
+func TestResolveSecurityGroupNames(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	groupNameInput := &awsec2.DescribeSecurityGroupsInput{
+		Filters: []*awsec2.Filter{
+			&awsec2.Filter{Name: aws.String("group-name"), Values: aws.StringSlice([]string{"web", "db"})},
+			&awsec2.Filter{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{"vpc-123456"})},
+		},
+	}
+	groupNameOutput := &awsec2.DescribeSecurityGroupsOutput{
+		SecurityGroups: []*awsec2.SecurityGroup{
+			&awsec2.SecurityGroup{GroupId: aws.String("sg-abcdef"), GroupName: aws.String("web")},
+		},
+	}
+	tagInput := &awsec2.DescribeSecurityGroupsInput{
+		Filters: []*awsec2.Filter{
+			&awsec2.Filter{Name: aws.String("tag:Name"), Values: aws.StringSlice([]string{"web", "db"})},
+			&awsec2.Filter{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{"vpc-123456"})},
+		},
+	}
+	tagOutput := &awsec2.DescribeSecurityGroupsOutput{
+		SecurityGroups: []*awsec2.SecurityGroup{
+			&awsec2.SecurityGroup{
+				GroupId:   aws.String("sg-abcdef"),
+				GroupName: aws.String("web"),
+				Tags:      []*awsec2.Tag{&awsec2.Tag{Key: aws.String("Name"), Value: aws.String("web")}},
+			},
+			&awsec2.SecurityGroup{
+				GroupId:   aws.String("sg-123456"),
+				GroupName: aws.String("launch-wizard-1"),
+				Tags:      []*awsec2.Tag{&awsec2.Tag{Key: aws.String("Name"), Value: aws.String("db")}},
+			},
+		},
+	}
+
+	mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+	ec2 := SDKClient{client: mockEC2Client}
+
+	mockEC2Client.EXPECT().DescribeSecurityGroups(groupNameInput).Return(groupNameOutput, nil)
+	mockEC2Client.EXPECT().DescribeSecurityGroups(tagInput).Return(tagOutput, nil)
+
+	out, err := ec2.ResolveSecurityGroupNames("vpc-123456", []string{"web", "db"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(out) != 2 || out[0] != "sg-abcdef" || out[1] != "sg-123456" {
+		t.Errorf("expected [sg-abcdef sg-123456], got %v", out)
+	}
+}
+
+func TestResolveSubnetNames(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	output := &awsec2.DescribeSubnetsOutput{
+		Subnets: []*awsec2.Subnet{
+			&awsec2.Subnet{
+				SubnetId: aws.String("subnet-abcdef"),
+				Tags:     []*awsec2.Tag{&awsec2.Tag{Key: aws.String("Name"), Value: aws.String("private-a")}},
+			},
+			&awsec2.Subnet{
+				SubnetId: aws.String("subnet-123456"),
+				Tags:     []*awsec2.Tag{&awsec2.Tag{Key: aws.String("Name"), Value: aws.String("private-b")}},
+			},
+			&awsec2.Subnet{
+				SubnetId: aws.String("subnet-654321"),
+				Tags:     []*awsec2.Tag{&awsec2.Tag{Key: aws.String("Name"), Value: aws.String("private-b")}},
+			},
+		},
+	}
+
+	mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+	ec2 := SDKClient{client: mockEC2Client}
+
+	mockEC2Client.EXPECT().DescribeSubnets(gomock.Any()).Return(output, nil).Times(3)
+
+	if out, err := ec2.ResolveSubnetNames("", []string{"private-a"}); err != nil || len(out) != 1 || out[0] != "subnet-abcdef" {
+		t.Errorf("expected [subnet-abcdef], got %v (%v)", out, err)
+	}
+
+	if _, err := ec2.ResolveSubnetNames("", []string{"private-b"}); err == nil {
+		t.Error("expected error for ambiguous name, got none")
+	}
+
+	if _, err := ec2.ResolveSubnetNames("", []string{"public-a"}); err == nil {
+		t.Error("expected error for unknown name, got none")
+	}
+}
+
+func TestSecurityGroupsAllowEgress(t *testing.T) {
+	tests := []struct {
+		permission *awsec2.IpPermission
+		allowed    bool
+	}{
+		{&awsec2.IpPermission{IpProtocol: aws.String("-1"), IpRanges: []*awsec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}, true},
+		{&awsec2.IpPermission{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(443), ToPort: aws.Int64(443), IpRanges: []*awsec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}, true},
+		{&awsec2.IpPermission{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(0), ToPort: aws.Int64(65535), IpRanges: []*awsec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}, true},
+		{&awsec2.IpPermission{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(80), ToPort: aws.Int64(80), IpRanges: []*awsec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}, false},
+		{&awsec2.IpPermission{IpProtocol: aws.String("udp"), FromPort: aws.Int64(443), ToPort: aws.Int64(443), IpRanges: []*awsec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}, false},
+		{&awsec2.IpPermission{IpProtocol: aws.String("-1"), IpRanges: []*awsec2.IpRange{{CidrIp: aws.String("10.0.0.0/8")}}}, false},
+	}
+
+	for _, test := range tests {
+		mockCtrl := gomock.NewController(t)
+		mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+		ec2 := SDKClient{client: mockEC2Client}
+		input := &awsec2.DescribeSecurityGroupsInput{
+			GroupIds: aws.StringSlice([]string{"sg-abcdef"}),
+		}
+		output := &awsec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*awsec2.SecurityGroup{
+				&awsec2.SecurityGroup{
+					GroupId:             aws.String("sg-abcdef"),
+					IpPermissionsEgress: []*awsec2.IpPermission{test.permission},
+				},
+			},
+		}
+
+		mockEC2Client.EXPECT().DescribeSecurityGroups(input).Return(output, nil)
+
+		allowed, err := ec2.SecurityGroupsAllowEgress([]string{"sg-abcdef"})
+
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+
+		if allowed != test.allowed {
+			t.Errorf("expected allowed to be %t for %v, got %t", test.allowed, test.permission, allowed)
+		}
+
+		mockCtrl.Finish()
+	}
+}
+
+func TestDescribeSubnets(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+	ec2 := SDKClient{client: mockEC2Client}
+	input := &awsec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice([]string{"subnet-1234567"}),
+	}
+	output := &awsec2.DescribeSubnetsOutput{
+		Subnets: []*awsec2.Subnet{
+			&awsec2.Subnet{
+				AvailabilityZone: aws.String("us-east-1a"),
+				SubnetId:         aws.String("subnet-1234567"),
+				VpcId:            aws.String("vpc-1234567"),
+			},
+		},
+	}
+
+	mockEC2Client.EXPECT().DescribeSubnets(input).Return(output, nil)
+
+	subnets, err := ec2.DescribeSubnets([]string{"subnet-1234567"})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	expected := []Subnet{Subnet{AvailabilityZone: "us-east-1a", SubnetId: "subnet-1234567", VpcId: "vpc-1234567"}}
+
+	if !reflect.DeepEqual(subnets, expected) {
+		t.Errorf("expected %+v, got %+v", expected, subnets)
+	}
+}
+
+func TestSecurityGroupsAllowIngress(t *testing.T) {
+	tests := []struct {
+		permission *awsec2.IpPermission
+		allowed    bool
+	}{
+		{&awsec2.IpPermission{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(2049), ToPort: aws.Int64(2049), UserIdGroupPairs: []*awsec2.UserIdGroupPair{{GroupId: aws.String("sg-task")}}}, true},
+		{&awsec2.IpPermission{IpProtocol: aws.String("-1"), UserIdGroupPairs: []*awsec2.UserIdGroupPair{{GroupId: aws.String("sg-task")}}}, true},
+		{&awsec2.IpPermission{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(2049), ToPort: aws.Int64(2049), IpRanges: []*awsec2.IpRange{{CidrIp: aws.String("10.0.0.0/16")}}}, true},
+		{&awsec2.IpPermission{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(2049), ToPort: aws.Int64(2049), UserIdGroupPairs: []*awsec2.UserIdGroupPair{{GroupId: aws.String("sg-other")}}}, false},
+		{&awsec2.IpPermission{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(443), ToPort: aws.Int64(443), UserIdGroupPairs: []*awsec2.UserIdGroupPair{{GroupId: aws.String("sg-task")}}}, false},
+		{&awsec2.IpPermission{IpProtocol: aws.String("udp"), FromPort: aws.Int64(2049), ToPort: aws.Int64(2049), IpRanges: []*awsec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}, false},
+	}
+
+	for _, test := range tests {
+		mockCtrl := gomock.NewController(t)
+		mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+		ec2 := SDKClient{client: mockEC2Client}
+		input := &awsec2.DescribeSecurityGroupsInput{
+			GroupIds: aws.StringSlice([]string{"sg-efs"}),
+		}
+		output := &awsec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*awsec2.SecurityGroup{
+				&awsec2.SecurityGroup{
+					GroupId:       aws.String("sg-efs"),
+					IpPermissions: []*awsec2.IpPermission{test.permission},
+				},
+			},
+		}
+
+		mockEC2Client.EXPECT().DescribeSecurityGroups(input).Return(output, nil)
+
+		allowed, err := ec2.SecurityGroupsAllowIngress([]string{"sg-efs"}, 2049, []string{"sg-task"})
+
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+
+		if allowed != test.allowed {
+			t.Errorf("expected allowed to be %t for %v, got %t", test.allowed, test.permission, allowed)
+		}
+
+		mockCtrl.Finish()
+	}
+}
+
+func TestSubnetsArePrivate(t *testing.T) {
+	natRoutes := []*awsec2.Route{
+		&awsec2.Route{DestinationCidrBlock: aws.String("10.0.0.0/16"), GatewayId: aws.String("local")},
+		&awsec2.Route{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-1234567")},
+	}
+	igwRoutes := []*awsec2.Route{
+		&awsec2.Route{DestinationCidrBlock: aws.String("10.0.0.0/16"), GatewayId: aws.String("local")},
+		&awsec2.Route{DestinationCidrBlock: aws.String("0.0.0.0/0"), GatewayId: aws.String("igw-1234567")},
+	}
+
+	tests := []struct {
+		name       string
+		routes     []*awsec2.Route
+		mainRoutes []*awsec2.Route
+		private    bool
+	}{
+		{"explicit private", natRoutes, nil, true},
+		{"explicit public", igwRoutes, nil, false},
+		{"main private", nil, natRoutes, true},
+		{"main public", nil, igwRoutes, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockEC2Client := sdk.NewMockEC2API(mockCtrl)
+			ec2 := SDKClient{client: mockEC2Client}
+			subnetsOutput := &awsec2.DescribeSubnetsOutput{
+				Subnets: []*awsec2.Subnet{
+					&awsec2.Subnet{SubnetId: aws.String("subnet-1234567"), VpcId: aws.String("vpc-1234567")},
+				},
+			}
+			routeTablesOutput := &awsec2.DescribeRouteTablesOutput{}
+
+			if test.routes != nil {
+				routeTablesOutput.RouteTables = []*awsec2.RouteTable{
+					&awsec2.RouteTable{
+						Associations: []*awsec2.RouteTableAssociation{{SubnetId: aws.String("subnet-1234567")}},
+						Routes:       test.routes,
+					},
+				}
+			}
+
+			mockEC2Client.EXPECT().DescribeSubnets(gomock.Any()).Return(subnetsOutput, nil)
+			mockEC2Client.EXPECT().DescribeRouteTables(gomock.Any()).Return(routeTablesOutput, nil)
+
+			if test.mainRoutes != nil {
+				mainOutput := &awsec2.DescribeRouteTablesOutput{
+					RouteTables: []*awsec2.RouteTable{
+						&awsec2.RouteTable{
+							Associations: []*awsec2.RouteTableAssociation{{Main: aws.Bool(true)}},
+							Routes:       test.mainRoutes,
+						},
+					},
+				}
+
+				mockEC2Client.EXPECT().DescribeRouteTables(gomock.Any()).Return(mainOutput, nil)
+			}
+
+			private, err := ec2.SubnetsArePrivate([]string{"subnet-1234567"})
+
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			if private != test.private {
+				t.Errorf("expected private to be %t, got %t", test.private, private)
+			}
+		})
+	}
+}
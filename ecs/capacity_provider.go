@@ -0,0 +1,137 @@
+package ecs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+)
+
+const (
+	CapacityProviderFargate     = "FARGATE"
+	CapacityProviderFargateSpot = "FARGATE_SPOT"
+
+	maxCapacityProviderBase   = 100000
+	maxCapacityProviderWeight = 1000
+)
+
+// CapacityProviderStrategyItem places a share of tasks on a capacity provider.
+// Base is the minimum number of tasks placed on the provider, and tasks beyond
+// the base are divided between providers in proportion to their weights.
+type CapacityProviderStrategyItem struct {
+	Base             int64
+	CapacityProvider string
+	Weight           int64
+}
+
+// ParseCapacityProviderStrategy parses capacity provider expressions in the
+// form of NAME[,weight=N][,base=N] [e.g. FARGATE_SPOT,weight=4,base=1]. The
+// weight defaults to 1 and the base to 0.
+func ParseCapacityProviderStrategy(expressions []string) ([]CapacityProviderStrategyItem, error) {
+	var strategy []CapacityProviderStrategyItem
+
+	for _, expression := range expressions {
+		fields := strings.Split(expression, ",")
+		item := CapacityProviderStrategyItem{
+			CapacityProvider: strings.TrimSpace(fields[0]),
+			Weight:           1,
+		}
+
+		if item.CapacityProvider == "" {
+			return nil, fmt.Errorf("invalid capacity provider %q: a name is required", expression)
+		}
+
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid capacity provider %q: %q must be in the form of key=value", expression, field)
+			}
+
+			value, err := strconv.ParseInt(kv[1], 10, 64)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid capacity provider %q: %s must be a number", expression, kv[0])
+			}
+
+			switch strings.ToLower(kv[0]) {
+			case "weight":
+				item.Weight = value
+			case "base":
+				item.Base = value
+			default:
+				return nil, fmt.Errorf("invalid capacity provider %q: unknown key %s [must be weight or base]", expression, kv[0])
+			}
+		}
+
+		strategy = append(strategy, item)
+	}
+
+	if err := validateCapacityProviderStrategy(strategy); err != nil {
+		return nil, err
+	}
+
+	return strategy, nil
+}
+
+// validateCapacityProviderStrategy checks a strategy against the limits ECS
+// enforces: weights of 0 to 1000 with at least one above 0, bases of 0 to
+// 100000 with at most one above 0, and each provider listed once.
+func validateCapacityProviderStrategy(strategy []CapacityProviderStrategyItem) error {
+	var weighted, based int
+
+	seen := make(map[string]bool)
+
+	for _, item := range strategy {
+		if seen[item.CapacityProvider] {
+			return fmt.Errorf("capacity provider %s is listed more than once", item.CapacityProvider)
+		}
+
+		seen[item.CapacityProvider] = true
+
+		if item.Weight < 0 || item.Weight > maxCapacityProviderWeight {
+			return fmt.Errorf("invalid weight %d for capacity provider %s: must be between 0 and %d", item.Weight, item.CapacityProvider, maxCapacityProviderWeight)
+		}
+
+		if item.Base < 0 || item.Base > maxCapacityProviderBase {
+			return fmt.Errorf("invalid base %d for capacity provider %s: must be between 0 and %d", item.Base, item.CapacityProvider, maxCapacityProviderBase)
+		}
+
+		if item.Weight > 0 {
+			weighted++
+		}
+
+		if item.Base > 0 {
+			based++
+		}
+	}
+
+	if len(strategy) > 0 && weighted == 0 {
+		return fmt.Errorf("at least one capacity provider must have a weight greater than 0")
+	}
+
+	if based > 1 {
+		return fmt.Errorf("only one capacity provider can have a base")
+	}
+
+	return nil
+}
+
+func capacityProviderStrategy(strategy []CapacityProviderStrategyItem) []*awsecs.CapacityProviderStrategyItem {
+	var items []*awsecs.CapacityProviderStrategyItem
+
+	for _, item := range strategy {
+		items = append(
+			items,
+			&awsecs.CapacityProviderStrategyItem{
+				Base:             aws.Int64(item.Base),
+				CapacityProvider: aws.String(item.CapacityProvider),
+				Weight:           aws.Int64(item.Weight),
+			},
+		)
+	}
+
+	return items
+}
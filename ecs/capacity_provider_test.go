@@ -0,0 +1,59 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCapacityProviderStrategy(t *testing.T) {
+	strategy, err := ParseCapacityProviderStrategy([]string{"FARGATE_SPOT,weight=4", "FARGATE,weight=1,base=2"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []CapacityProviderStrategyItem{
+		CapacityProviderStrategyItem{CapacityProvider: "FARGATE_SPOT", Weight: 4},
+		CapacityProviderStrategyItem{Base: 2, CapacityProvider: "FARGATE", Weight: 1},
+	}
+
+	if !reflect.DeepEqual(strategy, expected) {
+		t.Errorf("expected %+v, got %+v", expected, strategy)
+	}
+}
+
+func TestParseCapacityProviderStrategyDefaults(t *testing.T) {
+	strategy, err := ParseCapacityProviderStrategy([]string{"FARGATE_SPOT"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []CapacityProviderStrategyItem{
+		CapacityProviderStrategyItem{CapacityProvider: "FARGATE_SPOT", Weight: 1},
+	}
+
+	if !reflect.DeepEqual(strategy, expected) {
+		t.Errorf("expected %+v, got %+v", expected, strategy)
+	}
+}
+
+func TestParseCapacityProviderStrategyInvalid(t *testing.T) {
+	var tests = [][]string{
+		[]string{""},
+		[]string{"FARGATE_SPOT,weight"},
+		[]string{"FARGATE_SPOT,weight=high"},
+		[]string{"FARGATE_SPOT,priority=1"},
+		[]string{"FARGATE_SPOT,weight=1001"},
+		[]string{"FARGATE_SPOT,weight=0"},
+		[]string{"FARGATE_SPOT,base=-1"},
+		[]string{"FARGATE_SPOT", "FARGATE_SPOT"},
+		[]string{"FARGATE_SPOT,base=1", "FARGATE,base=1"},
+	}
+
+	for _, test := range tests {
+		if _, err := ParseCapacityProviderStrategy(test); err == nil {
+			t.Errorf("expected error for %q, got none", test)
+		}
+	}
+}
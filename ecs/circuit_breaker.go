@@ -0,0 +1,88 @@
+package ecs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+)
+
+const (
+	CircuitBreakerOff      = "off"
+	CircuitBreakerOn       = "on"
+	CircuitBreakerRollback = "rollback"
+)
+
+// CircuitBreaker configures the deployment circuit breaker, which fails a
+// service deployment whose tasks can't reach a steady state. With Rollback,
+// a failed deployment is rolled back to the last one which completed.
+type CircuitBreaker struct {
+	Enable   bool
+	Rollback bool
+}
+
+// ParseCircuitBreaker parses a circuit breaker mode: off, on [fail deployments
+// which can't reach a steady state], or rollback [fail them and roll back].
+func ParseCircuitBreaker(mode string) (CircuitBreaker, error) {
+	switch strings.ToLower(mode) {
+	case CircuitBreakerOff:
+		return CircuitBreaker{}, nil
+	case CircuitBreakerOn:
+		return CircuitBreaker{Enable: true}, nil
+	case CircuitBreakerRollback:
+		return CircuitBreaker{Enable: true, Rollback: true}, nil
+	}
+
+	return CircuitBreaker{}, fmt.Errorf("invalid circuit breaker mode %s: must be %s, %s, or %s", mode, CircuitBreakerOff, CircuitBreakerOn, CircuitBreakerRollback)
+}
+
+// String describes the circuit breaker's mode [e.g. rollback].
+func (c CircuitBreaker) String() string {
+	switch {
+	case c.Enable && c.Rollback:
+		return CircuitBreakerRollback
+	case c.Enable:
+		return CircuitBreakerOn
+	}
+
+	return CircuitBreakerOff
+}
+
+// UpdateServiceCircuitBreaker changes the service's deployment circuit breaker,
+// taking effect from its next deployment.
+func (ecs *ECS) UpdateServiceCircuitBreaker(serviceName string, circuitBreaker CircuitBreaker) error {
+	_, err := ecs.svc.UpdateService(
+		&awsecs.UpdateServiceInput{
+			Cluster:                 aws.String(ecs.ClusterName),
+			DeploymentConfiguration: circuitBreaker.deploymentConfiguration(),
+			Service:                 aws.String(serviceName),
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not update circuit breaker of service %s: %v", serviceName, err)
+	}
+
+	return nil
+}
+
+func (c CircuitBreaker) deploymentConfiguration() *awsecs.DeploymentConfiguration {
+	return &awsecs.DeploymentConfiguration{
+		DeploymentCircuitBreaker: &awsecs.DeploymentCircuitBreaker{
+			Enable:   aws.Bool(c.Enable),
+			Rollback: aws.Bool(c.Rollback),
+		},
+	}
+}
+
+func circuitBreakerFromDeploymentConfiguration(config *awsecs.DeploymentConfiguration) CircuitBreaker {
+	if config == nil || config.DeploymentCircuitBreaker == nil {
+		return CircuitBreaker{}
+	}
+
+	return CircuitBreaker{
+		Enable:   aws.BoolValue(config.DeploymentCircuitBreaker.Enable),
+		Rollback: aws.BoolValue(config.DeploymentCircuitBreaker.Rollback),
+	}
+}
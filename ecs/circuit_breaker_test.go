@@ -0,0 +1,37 @@
+package ecs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCircuitBreaker(t *testing.T) {
+	var tests = []struct {
+		mode     string
+		expected CircuitBreaker
+	}{
+		{"off", CircuitBreaker{}},
+		{"on", CircuitBreaker{Enable: true}},
+		{"ROLLBACK", CircuitBreaker{Enable: true, Rollback: true}},
+	}
+
+	for _, test := range tests {
+		circuitBreaker, err := ParseCircuitBreaker(test.mode)
+
+		if err != nil {
+			t.Errorf("expected no error for %s, got %v", test.mode, err)
+		}
+
+		if circuitBreaker != test.expected {
+			t.Errorf("expected %+v for %s, got %+v", test.expected, test.mode, circuitBreaker)
+		}
+
+		if mode := circuitBreaker.String(); mode != strings.ToLower(test.mode) {
+			t.Errorf("expected mode %s, got %s", strings.ToLower(test.mode), mode)
+		}
+	}
+
+	if _, err := ParseCircuitBreaker("sometimes"); err == nil {
+		t.Error("expected error for invalid mode, got none")
+	}
+}
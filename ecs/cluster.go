@@ -1,16 +1,198 @@
 package ecs
 
 import (
+	"fmt"
+	"strings"
+	"sync"
+
 	"github.com/aws/aws-sdk-go/aws"
 	awsecs "github.com/aws/aws-sdk-go/service/ecs"
 )
 
+const (
+	clusterStatusActive = "ACTIVE"
+
+	ContainerInsightsDisabled = "disabled"
+	ContainerInsightsEnabled  = "enabled"
+	ContainerInsightsEnhanced = "enhanced"
+)
+
+// clusterExistsCache records the clusters found to exist by region and name,
+// as a cluster of the same name may exist in one region but not another. It's
+// shared by every client, which may be used from several goroutines, so it's
+// only accessed while holding clusterExistsMutex.
+var (
+	clusterExistsCache = make(map[clusterKey]bool)
+	clusterExistsMutex sync.Mutex
+)
+
+type clusterKey struct {
+	region      string
+	clusterName string
+}
+
+// CreateCluster creates the cluster with both the Fargate and Fargate Spot
+// capacity providers available, so tasks can be run on either.
 func (ecs *ECS) CreateCluster() (string, error) {
 	input := &awsecs.CreateClusterInput{
-		ClusterName: aws.String(ecs.ClusterName),
+		CapacityProviders: aws.StringSlice([]string{CapacityProviderFargate, CapacityProviderFargateSpot}),
+		ClusterName:       aws.String(ecs.ClusterName),
 	}
 
 	resp, err := ecs.svc.CreateCluster(input)
 
 	return aws.StringValue(resp.Cluster.ClusterArn), err
 }
+
+// EnsureClusterExists returns an error if the cluster doesn't exist or is
+// inactive. Once a cluster is found it isn't checked again.
+func (ecs *ECS) EnsureClusterExists() error {
+	key := clusterKey{ecs.region, ecs.ClusterName}
+
+	clusterExistsMutex.Lock()
+	exists := clusterExistsCache[key]
+	clusterExistsMutex.Unlock()
+
+	if exists {
+		return nil
+	}
+
+	resp, err := ecs.svc.DescribeClusters(
+		&awsecs.DescribeClustersInput{
+			Clusters: aws.StringSlice([]string{ecs.ClusterName}),
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not describe cluster %q: %v", ecs.ClusterName, err)
+	}
+
+	for _, cluster := range resp.Clusters {
+		if aws.StringValue(cluster.Status) == clusterStatusActive {
+			clusterExistsMutex.Lock()
+			clusterExistsCache[key] = true
+			clusterExistsMutex.Unlock()
+
+			return nil
+		}
+	}
+
+	if ecs.region != "" {
+		return fmt.Errorf("cluster %q not found in region %s", ecs.ClusterName, ecs.region)
+	}
+
+	return fmt.Errorf("cluster %q not found", ecs.ClusterName)
+}
+
+// EnsureCapacityProviders associates the strategy's capacity providers with
+// the cluster if they aren't already, such as Fargate Spot on a cluster
+// created before it was added by CreateCluster. The cluster's other capacity
+// providers and its default strategy are kept.
+func (ecs *ECS) EnsureCapacityProviders(strategy []CapacityProviderStrategyItem) error {
+	if len(strategy) == 0 {
+		return nil
+	}
+
+	resp, err := ecs.svc.DescribeClusters(
+		&awsecs.DescribeClustersInput{
+			Clusters: aws.StringSlice([]string{ecs.ClusterName}),
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not describe cluster %q: %v", ecs.ClusterName, err)
+	}
+
+	if len(resp.Clusters) == 0 {
+		return fmt.Errorf("cluster %q not found", ecs.ClusterName)
+	}
+
+	cluster := resp.Clusters[0]
+	capacityProviders := aws.StringValueSlice(cluster.CapacityProviders)
+	associated := make(map[string]bool)
+	var missing []string
+
+	for _, capacityProvider := range capacityProviders {
+		associated[capacityProvider] = true
+	}
+
+	for _, item := range strategy {
+		if !associated[item.CapacityProvider] {
+			associated[item.CapacityProvider] = true
+			missing = append(missing, item.CapacityProvider)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	ecs.logger().Info("Adding capacity providers %s to cluster %s", strings.Join(missing, ", "), ecs.ClusterName)
+
+	defaultStrategy := cluster.DefaultCapacityProviderStrategy
+
+	if defaultStrategy == nil {
+		defaultStrategy = []*awsecs.CapacityProviderStrategyItem{}
+	}
+
+	_, err = ecs.svc.PutClusterCapacityProviders(
+		&awsecs.PutClusterCapacityProvidersInput{
+			CapacityProviders:               aws.StringSlice(append(capacityProviders, missing...)),
+			Cluster:                         aws.String(ecs.ClusterName),
+			DefaultCapacityProviderStrategy: defaultStrategy,
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not add capacity providers %s to cluster %q: %v", strings.Join(missing, ", "), ecs.ClusterName, err)
+	}
+
+	return nil
+}
+
+// ClusterArn returns the full ARN of the cluster.
+func (ecs *ECS) ClusterArn() (string, error) {
+	resp, err := ecs.svc.DescribeClusters(
+		&awsecs.DescribeClustersInput{
+			Clusters: aws.StringSlice([]string{ecs.ClusterName}),
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("could not describe cluster %q: %v", ecs.ClusterName, err)
+	}
+
+	if len(resp.Clusters) == 0 {
+		return "", fmt.Errorf("cluster %q not found", ecs.ClusterName)
+	}
+
+	return aws.StringValue(resp.Clusters[0].ClusterArn), nil
+}
+
+// ContainerInsights returns the cluster's Container Insights setting: disabled,
+// enabled, or enhanced. Per-task metrics are only published when it's
+// enhanced.
+func (ecs *ECS) ContainerInsights() (string, error) {
+	resp, err := ecs.svc.DescribeClusters(
+		&awsecs.DescribeClustersInput{
+			Clusters: aws.StringSlice([]string{ecs.ClusterName}),
+			Include:  aws.StringSlice([]string{awsecs.ClusterFieldSettings}),
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("could not describe cluster %q: %v", ecs.ClusterName, err)
+	}
+
+	if len(resp.Clusters) == 0 {
+		return "", fmt.Errorf("cluster %q not found", ecs.ClusterName)
+	}
+
+	for _, setting := range resp.Clusters[0].Settings {
+		if aws.StringValue(setting.Name) == awsecs.ClusterSettingNameContainerInsights {
+			return aws.StringValue(setting.Value), nil
+		}
+	}
+
+	return ContainerInsightsDisabled, nil
+}
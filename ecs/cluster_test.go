@@ -0,0 +1,245 @@
+package ecs
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"github.com/jpignata/fargate/ecs/mock/sdk"
+)
+
+func TestEnsureClusterExists(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "exists", svc: mockECSClient}
+	output := &awsecs.DescribeClustersOutput{
+		Clusters: []*awsecs.Cluster{
+			&awsecs.Cluster{ClusterName: aws.String("exists"), Status: aws.String("ACTIVE")},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeClusters(gomock.Any()).Return(output, nil).Times(1)
+
+	for i := 0; i < 2; i++ {
+		if err := ecs.EnsureClusterExists(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+}
+
+func TestEnsureClusterExistsPerRegion(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	active := &awsecs.DescribeClustersOutput{
+		Clusters: []*awsecs.Cluster{
+			&awsecs.Cluster{ClusterName: aws.String("regional"), Status: aws.String("ACTIVE")},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeClusters(gomock.Any()).Return(active, nil)
+	mockECSClient.EXPECT().DescribeClusters(gomock.Any()).Return(&awsecs.DescribeClustersOutput{}, nil)
+
+	east := ECS{ClusterName: "regional", region: "us-east-1", svc: mockECSClient}
+	west := ECS{ClusterName: "regional", region: "us-west-2", svc: mockECSClient}
+
+	if err := east.EnsureClusterExists(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := west.EnsureClusterExists(); err == nil {
+		t.Error("expected error for cluster missing from us-west-2, got none")
+	}
+}
+
+func TestEnsureClusterExistsConcurrently(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var wg sync.WaitGroup
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	output := &awsecs.DescribeClustersOutput{
+		Clusters: []*awsecs.Cluster{
+			&awsecs.Cluster{ClusterName: aws.String("concurrent"), Status: aws.String("ACTIVE")},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeClusters(gomock.Any()).Return(output, nil).AnyTimes()
+
+	for _, region := range []string{"us-east-1", "us-east-2", "us-west-1", "us-west-2"} {
+		wg.Add(1)
+
+		go func(region string) {
+			defer wg.Done()
+
+			ecs := ECS{ClusterName: "concurrent", region: region, svc: mockECSClient}
+
+			if err := ecs.EnsureClusterExists(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		}(region)
+	}
+
+	wg.Wait()
+}
+
+func TestEnsureClusterExistsInactive(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "deleted", region: "eu-west-1", svc: mockECSClient}
+	output := &awsecs.DescribeClustersOutput{
+		Clusters: []*awsecs.Cluster{
+			&awsecs.Cluster{ClusterName: aws.String("deleted"), Status: aws.String("INACTIVE")},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeClusters(gomock.Any()).Return(output, nil)
+
+	err := ecs.EnsureClusterExists()
+
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	if expected := `cluster "deleted" not found in region eu-west-1`; err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err.Error())
+	}
+}
+
+func TestClusterArn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	clusterArn := "arn:aws:ecs:us-east-1:123456789012:cluster/fargate"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	output := &awsecs.DescribeClustersOutput{
+		Clusters: []*awsecs.Cluster{
+			&awsecs.Cluster{ClusterArn: aws.String(clusterArn), ClusterName: aws.String("fargate")},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeClusters(gomock.Any()).Return(output, nil)
+
+	arn, err := ecs.ClusterArn()
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if arn != clusterArn {
+		t.Errorf("expected %s, got %s", clusterArn, arn)
+	}
+}
+
+func TestContainerInsights(t *testing.T) {
+	tests := []struct {
+		settings []*awsecs.ClusterSetting
+		expected string
+	}{
+		{nil, ContainerInsightsDisabled},
+		{[]*awsecs.ClusterSetting{&awsecs.ClusterSetting{Name: aws.String("containerInsights"), Value: aws.String("enhanced")}}, ContainerInsightsEnhanced},
+	}
+
+	for _, test := range tests {
+		mockCtrl := gomock.NewController(t)
+		mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+		ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+		input := &awsecs.DescribeClustersInput{
+			Clusters: aws.StringSlice([]string{"fargate"}),
+			Include:  aws.StringSlice([]string{"SETTINGS"}),
+		}
+		output := &awsecs.DescribeClustersOutput{
+			Clusters: []*awsecs.Cluster{
+				&awsecs.Cluster{ClusterName: aws.String("fargate"), Settings: test.settings},
+			},
+		}
+
+		mockECSClient.EXPECT().DescribeClusters(input).Return(output, nil)
+
+		setting, err := ecs.ContainerInsights()
+
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+
+		if setting != test.expected {
+			t.Errorf("expected %s, got %s", test.expected, setting)
+		}
+
+		mockCtrl.Finish()
+	}
+}
+
+func TestEnsureCapacityProvidersAddsMissing(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Logger: &testLogger{}, svc: mockECSClient}
+	defaultStrategy := []*awsecs.CapacityProviderStrategyItem{
+		&awsecs.CapacityProviderStrategyItem{CapacityProvider: aws.String("FARGATE"), Weight: aws.Int64(1)},
+	}
+	output := &awsecs.DescribeClustersOutput{
+		Clusters: []*awsecs.Cluster{
+			&awsecs.Cluster{
+				CapacityProviders:               aws.StringSlice([]string{"FARGATE"}),
+				ClusterName:                     aws.String("fargate"),
+				DefaultCapacityProviderStrategy: defaultStrategy,
+				Status:                          aws.String("ACTIVE"),
+			},
+		},
+	}
+	input := &awsecs.PutClusterCapacityProvidersInput{
+		CapacityProviders:               aws.StringSlice([]string{"FARGATE", "FARGATE_SPOT"}),
+		Cluster:                         aws.String("fargate"),
+		DefaultCapacityProviderStrategy: defaultStrategy,
+	}
+
+	mockECSClient.EXPECT().DescribeClusters(gomock.Any()).Return(output, nil)
+	mockECSClient.EXPECT().PutClusterCapacityProviders(input).Return(&awsecs.PutClusterCapacityProvidersOutput{}, nil)
+
+	strategy := []CapacityProviderStrategyItem{
+		CapacityProviderStrategyItem{CapacityProvider: "FARGATE_SPOT", Weight: 4},
+		CapacityProviderStrategyItem{CapacityProvider: "FARGATE", Weight: 1},
+	}
+
+	if err := ecs.EnsureCapacityProviders(strategy); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestEnsureCapacityProvidersAlreadyAssociated(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	output := &awsecs.DescribeClustersOutput{
+		Clusters: []*awsecs.Cluster{
+			&awsecs.Cluster{
+				CapacityProviders: aws.StringSlice([]string{"FARGATE", "FARGATE_SPOT"}),
+				ClusterName:       aws.String("fargate"),
+				Status:            aws.String("ACTIVE"),
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeClusters(gomock.Any()).Return(output, nil)
+
+	strategy := []CapacityProviderStrategyItem{
+		CapacityProviderStrategyItem{CapacityProvider: "FARGATE_SPOT", Weight: 1},
+	}
+
+	if err := ecs.EnsureCapacityProviders(strategy); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
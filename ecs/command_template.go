@@ -0,0 +1,68 @@
+package ecs
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var commandTemplateRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// CommandTemplate enables expansion of ${KEY} and $KEY references in a run's
+// command and appended arguments from its environment variables [e.g.
+// process --date ${RUN_DATE}]. Expansion is opt-in so that commands with a
+// literal $ are passed through untouched.
+//
+// References to variables that aren't set are left as-is unless Strict is
+// set, in which case they're an error. If UseProcessEnv is set, variables not
+// given for the run are looked up in fargate's own environment.
+type CommandTemplate struct {
+	Strict        bool
+	UseProcessEnv bool
+}
+
+// Expand returns the arguments with variable references replaced by the
+// values of the given environment variables.
+func (c *CommandTemplate) Expand(args []string, envVars []EnvVar) ([]string, error) {
+	var expanded, unresolved []string
+
+	values := make(map[string]string)
+
+	for _, envVar := range envVars {
+		values[envVar.Key] = envVar.Value
+	}
+
+	lookup := func(key string) (string, bool) {
+		if value, ok := values[key]; ok {
+			return value, true
+		}
+
+		if c.UseProcessEnv {
+			return os.LookupEnv(key)
+		}
+
+		return "", false
+	}
+
+	for _, arg := range args {
+		expanded = append(expanded, commandTemplateRegexp.ReplaceAllStringFunc(arg, func(reference string) string {
+			matches := commandTemplateRegexp.FindStringSubmatch(reference)
+			key := matches[1] + matches[2]
+
+			if value, ok := lookup(key); ok {
+				return value
+			}
+
+			unresolved = append(unresolved, key)
+
+			return reference
+		}))
+	}
+
+	if c.Strict && len(unresolved) > 0 {
+		return nil, fmt.Errorf("could not expand command: %s not set", strings.Join(unresolved, ", "))
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,53 @@
+package ecs
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestCommandTemplateExpand(t *testing.T) {
+	template := &CommandTemplate{}
+	envVars := []EnvVar{
+		EnvVar{Key: "RUN_DATE", Value: "2018-01-01"},
+		EnvVar{Key: "MODE", Value: "full"},
+	}
+	args := []string{"process", "--date", "${RUN_DATE}", "--mode=$MODE", "$UNSET", "${UNSET}", "$1", "cost: $"}
+	expected := []string{"process", "--date", "2018-01-01", "--mode=full", "$UNSET", "${UNSET}", "$1", "cost: $"}
+
+	expanded, err := template.Expand(args, envVars)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(expanded, expected) {
+		t.Errorf("expected %v, got %v", expected, expanded)
+	}
+}
+
+func TestCommandTemplateExpandStrict(t *testing.T) {
+	template := &CommandTemplate{Strict: true}
+
+	if _, err := template.Expand([]string{"process", "${RUN_DATE}"}, nil); err == nil {
+		t.Errorf("expected error, got none")
+	}
+}
+
+func TestCommandTemplateExpandUseProcessEnv(t *testing.T) {
+	os.Setenv("FARGATE_TEST_RUN_DATE", "2018-01-02")
+	defer os.Unsetenv("FARGATE_TEST_RUN_DATE")
+
+	template := &CommandTemplate{Strict: true, UseProcessEnv: true}
+	envVars := []EnvVar{EnvVar{Key: "MODE", Value: "full"}}
+
+	expanded, err := template.Expand([]string{"${FARGATE_TEST_RUN_DATE}", "$MODE"}, envVars)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if expected := []string{"2018-01-02", "full"}; !reflect.DeepEqual(expanded, expected) {
+		t.Errorf("expected %v, got %v", expected, expanded)
+	}
+}
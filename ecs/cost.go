@@ -0,0 +1,81 @@
+package ecs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+)
+
+const (
+	capacityProviderFargateSpot = "FARGATE_SPOT"
+	cpuUnitsInVCpu              = 1024
+)
+
+// FargateRates holds the hourly Fargate prices, in USD, for a region.
+type FargateRates struct {
+	GBHour       float64
+	SpotGBHour   float64
+	SpotVCpuHour float64
+	VCpuHour     float64
+}
+
+// FargateRatesByRegion holds the Linux/x86 Fargate prices used to estimate
+// task costs. Prices change over time; callers may replace or add entries to
+// keep the estimates current.
+var FargateRatesByRegion = map[string]FargateRates{
+	"us-east-1": {VCpuHour: 0.04048, GBHour: 0.004445, SpotVCpuHour: 0.01334058, SpotGBHour: 0.00146489},
+	"us-east-2": {VCpuHour: 0.04048, GBHour: 0.004445, SpotVCpuHour: 0.01334058, SpotGBHour: 0.00146489},
+	"us-west-2": {VCpuHour: 0.04048, GBHour: 0.004445, SpotVCpuHour: 0.01334058, SpotGBHour: 0.00146489},
+	"eu-west-1": {VCpuHour: 0.04048, GBHour: 0.004445, SpotVCpuHour: 0.01334058, SpotGBHour: 0.00146489},
+}
+
+// FargateRatesForRegion returns the Fargate prices for a region and whether
+// the region is known.
+func FargateRatesForRegion(region string) (FargateRates, bool) {
+	rates, ok := FargateRatesByRegion[region]
+
+	return rates, ok
+}
+
+// EstimatedCost estimates what a Fargate task has cost so far from its CPU,
+// memory, and how long it has been (or was) running. Tasks run on the
+// FARGATE_SPOT capacity provider are estimated using the spot rates.
+func (t Task) EstimatedCost(rates FargateRates) (float64, error) {
+	if t.LaunchType == awsecs.LaunchTypeEc2 {
+		return 0, fmt.Errorf("cost of EC2 tasks can't be estimated")
+	}
+
+	cpu, err := strconv.ParseFloat(t.Cpu, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("could not parse task CPU %q: %v", t.Cpu, err)
+	}
+
+	memory, err := strconv.ParseFloat(t.Memory, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("could not parse task memory %q: %v", t.Memory, err)
+	}
+
+	vCpuHour, gbHour := rates.VCpuHour, rates.GBHour
+
+	if t.CapacityProviderName == capacityProviderFargateSpot {
+		vCpuHour, gbHour = rates.SpotVCpuHour, rates.SpotGBHour
+	}
+
+	end := time.Now()
+
+	if !t.StoppedAt.IsZero() {
+		end = t.StoppedAt
+	}
+
+	hours := end.Sub(t.CreatedAt).Hours()
+
+	if hours < 0 {
+		hours = 0
+	}
+
+	return hours * (cpu/cpuUnitsInVCpu*vCpuHour + memory/mebibytesInGibibyte*gbHour), nil
+}
@@ -0,0 +1,71 @@
+package ecs
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var testFargateRates = FargateRates{
+	VCpuHour:     0.04,
+	GBHour:       0.004,
+	SpotVCpuHour: 0.01,
+	SpotGBHour:   0.001,
+}
+
+func TestTaskEstimatedCost(t *testing.T) {
+	createdAt := time.Date(2018, 1, 2, 3, 0, 0, 0, time.UTC)
+	tests := []struct {
+		capacityProviderName string
+		expected             float64
+	}{
+		{"", 2 * (0.5*0.04 + 2*0.004)},
+		{"FARGATE", 2 * (0.5*0.04 + 2*0.004)},
+		{"FARGATE_SPOT", 2 * (0.5*0.01 + 2*0.001)},
+	}
+
+	for _, test := range tests {
+		task := Task{
+			CapacityProviderName: test.capacityProviderName,
+			Cpu:                  "512",
+			CreatedAt:            createdAt,
+			LaunchType:           "FARGATE",
+			Memory:               "2048",
+			StoppedAt:            createdAt.Add(2 * time.Hour),
+		}
+
+		cost, err := task.EstimatedCost(testFargateRates)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if math.Abs(cost-test.expected) > 1e-9 {
+			t.Errorf("expected cost %f for %q, got %f", test.expected, test.capacityProviderName, cost)
+		}
+	}
+}
+
+func TestTaskEstimatedCostInvalid(t *testing.T) {
+	tests := []Task{
+		Task{Cpu: "256", Memory: "512", LaunchType: "EC2"},
+		Task{Cpu: "", Memory: "512", LaunchType: "FARGATE"},
+		Task{Cpu: "256", Memory: "", LaunchType: "FARGATE"},
+	}
+
+	for _, task := range tests {
+		if _, err := task.EstimatedCost(testFargateRates); err == nil {
+			t.Errorf("expected error for %+v, got none", task)
+		}
+	}
+}
+
+func TestFargateRatesForRegion(t *testing.T) {
+	if _, ok := FargateRatesForRegion("us-east-1"); !ok {
+		t.Error("expected rates for us-east-1")
+	}
+
+	if _, ok := FargateRatesForRegion("mars-north-1"); ok {
+		t.Error("expected no rates for mars-north-1")
+	}
+}
@@ -0,0 +1,88 @@
+package ecs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const mebibytesInGibibyte = 1024
+
+// fargateMemoryRanges maps each supported Fargate CPU unit value to the range
+// of memory, in MiB, that it can be paired with. Within a range only whole
+// GiB values are accepted, except for the 512 MiB minimum for 256 CPU units.
+var fargateMemoryRanges = []struct {
+	cpu      int64
+	min, max int64
+}{
+	{256, 1024, 2048},
+	{512, 1024, 4096},
+	{1024, 2048, 8192},
+	{2048, 4096, 16384},
+	{4096, 8192, 30720},
+}
+
+// ValidMemoryValues returns the memory values, in MiB, that Fargate accepts
+// for the given CPU units, or nil if the CPU units aren't supported.
+func ValidMemoryValues(cpu int64) []int64 {
+	var values []int64
+
+	for _, r := range fargateMemoryRanges {
+		if r.cpu != cpu {
+			continue
+		}
+
+		if cpu == 256 {
+			values = append(values, 512)
+		}
+
+		for memory := r.min; memory <= r.max; memory += mebibytesInGibibyte {
+			values = append(values, memory)
+		}
+	}
+
+	return values
+}
+
+// ValidateCpuAndMemory checks a CPU units and memory (MiB) pair against the
+// combinations supported by Fargate, returning an error describing the valid
+// values if the pair isn't supported.
+func ValidateCpuAndMemory(cpu, memory string) error {
+	cpuUnits, err := strconv.ParseInt(cpu, 10, 64)
+
+	if err != nil {
+		return fmt.Errorf("invalid CPU units %s: must be a number", cpu)
+	}
+
+	mebibytes, err := strconv.ParseInt(memory, 10, 64)
+
+	if err != nil {
+		return fmt.Errorf("invalid memory %s: must be a number of MiB", memory)
+	}
+
+	values := ValidMemoryValues(cpuUnits)
+
+	if values == nil {
+		var cpus []string
+
+		for _, r := range fargateMemoryRanges {
+			cpus = append(cpus, strconv.FormatInt(r.cpu, 10))
+		}
+
+		return fmt.Errorf("invalid CPU units %d: must be one of %s", cpuUnits, strings.Join(cpus, ", "))
+	}
+
+	for _, value := range values {
+		if value == mebibytes {
+			return nil
+		}
+	}
+
+	var memories []string
+
+	for _, value := range values {
+		memories = append(memories, strconv.FormatInt(value, 10))
+	}
+
+	return fmt.Errorf("invalid memory %d MiB for %d CPU units: must be one of %s", mebibytes, cpuUnits, strings.Join(memories, ", "))
+}
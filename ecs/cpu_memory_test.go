@@ -0,0 +1,32 @@
+package ecs
+
+import (
+	"testing"
+)
+
+func TestValidateCpuAndMemory(t *testing.T) {
+	tests := []struct {
+		cpu, memory string
+		expected    string
+	}{
+		{"256", "512", ""},
+		{"256", "2048", ""},
+		{"4096", "30720", ""},
+		{"256", "768", "invalid memory 768 MiB for 256 CPU units: must be one of 512, 1024, 2048"},
+		{"512", "512", "invalid memory 512 MiB for 512 CPU units: must be one of 1024, 2048, 3072, 4096"},
+		{"5", "512", "invalid CPU units 5: must be one of 256, 512, 1024, 2048, 4096"},
+		{"abc", "512", "invalid CPU units abc: must be a number"},
+		{"256", "", "invalid memory : must be a number of MiB"},
+	}
+
+	for _, test := range tests {
+		err := ValidateCpuAndMemory(test.cpu, test.memory)
+
+		switch {
+		case test.expected == "" && err != nil:
+			t.Errorf("expected no error for %s/%s, got %v", test.cpu, test.memory, err)
+		case test.expected != "" && (err == nil || err.Error() != test.expected):
+			t.Errorf("expected error %q for %s/%s, got %v", test.expected, test.cpu, test.memory, err)
+		}
+	}
+}
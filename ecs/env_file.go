@@ -0,0 +1,104 @@
+package ecs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var envFileKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func ReadEnvFile(path string) ([]EnvVar, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	envVars, err := ParseEnvFile(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return envVars, nil
+}
+
+func ParseEnvFile(r io.Reader) ([]EnvVar, error) {
+	var envVars []EnvVar
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		parts := strings.SplitN(line, "=", 2)
+
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNumber)
+		}
+
+		key := strings.TrimSpace(parts[0])
+
+		if !envFileKeyRegexp.MatchString(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", lineNumber, key)
+		}
+
+		value, err := parseEnvFileValue(strings.TrimSpace(parts[1]))
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+
+		envVars = append(envVars, EnvVar{Key: key, Value: value})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return envVars, nil
+}
+
+func parseEnvFileValue(value string) (string, error) {
+	switch {
+	case value == "":
+		return "", nil
+	case value[0] == '"':
+		end := strings.LastIndex(value, `"`)
+
+		if end == 0 {
+			return "", fmt.Errorf("unterminated double quoted value")
+		}
+
+		return strconv.Unquote(value[:end+1])
+	case value[0] == '\'':
+		end := strings.LastIndex(value, "'")
+
+		if end == 0 {
+			return "", fmt.Errorf("unterminated single quoted value")
+		}
+
+		return value[1:end], nil
+	default:
+		if i := strings.Index(value, " #"); i != -1 {
+			value = strings.TrimSpace(value[:i])
+		}
+
+		return value, nil
+	}
+}
@@ -0,0 +1,59 @@
+package ecs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	input := `# database settings
+DB_HOST=db.example.com
+DB_PORT=5432 # default port
+
+export REGION=us-east-1
+GREETING="hello \"world\""
+PATTERN='$HOME/*'
+EMPTY=
+`
+	expected := []EnvVar{
+		EnvVar{Key: "DB_HOST", Value: "db.example.com"},
+		EnvVar{Key: "DB_PORT", Value: "5432"},
+		EnvVar{Key: "REGION", Value: "us-east-1"},
+		EnvVar{Key: "GREETING", Value: `hello "world"`},
+		EnvVar{Key: "PATTERN", Value: "$HOME/*"},
+		EnvVar{Key: "EMPTY", Value: ""},
+	}
+
+	envVars, err := ParseEnvFile(strings.NewReader(input))
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(envVars, expected) {
+		t.Errorf("expected %v, got %v", expected, envVars)
+	}
+}
+
+func TestParseEnvFileErrors(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out string
+	}{
+		{"KEY=value\nnot a variable\n", "line 2: expected KEY=value"},
+		{"1KEY=value", "line 1: invalid variable name \"1KEY\""},
+		{"KEY=value\n\nQUOTED=\"unterminated", "line 3: unterminated double quoted value"},
+		{"QUOTED='unterminated", "line 1: unterminated single quoted value"},
+	}
+
+	for _, test := range tests {
+		_, err := ParseEnvFile(strings.NewReader(test.in))
+
+		if err == nil {
+			t.Errorf("expected error %s, got none", test.out)
+		} else if err.Error() != test.out {
+			t.Errorf("expected error %s, got %s", test.out, err)
+		}
+	}
+}
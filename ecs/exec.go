@@ -0,0 +1,99 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+)
+
+const (
+	execTargetFormat  = "ecs:%s_%s_%s"
+	ssmEndpointFormat = "https://ssm.%s.amazonaws.com"
+)
+
+// ExecuteCommandInput configures ExecuteCommand. If no container name is
+// given, the task's first container is used.
+type ExecuteCommandInput struct {
+	Command       string
+	ContainerName string
+	Interactive   bool
+	TaskId        string
+}
+
+// ExecSession is an SSM session opened by ECS Exec within a container. It
+// holds what the Session Manager plugin needs to connect to the session.
+type ExecSession struct {
+	ContainerName string `json:"-"`
+	Endpoint      string `json:"-"`
+	Region        string `json:"-"`
+	SessionId     string `json:"sessionId"`
+	StreamUrl     string `json:"streamUrl"`
+	Target        string `json:"-"`
+	TokenValue    string `json:"tokenValue"`
+}
+
+// ExecuteCommand runs a command within a running task's container via ECS
+// Exec, returning the session through which its input and output flow. The
+// task must have been started with ECS Exec enabled, and its task role must
+// allow the SSM Messages actions which the session relies on.
+func (ecs *ECS) ExecuteCommand(i *ExecuteCommandInput) (*ExecSession, error) {
+	task, err := ecs.DescribeTask(i.TaskId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.ExecuteCommandEnabled {
+		return nil, fmt.Errorf("task %s was not started with ECS Exec enabled", task.TaskId)
+	}
+
+	if task.LastStatus != awsecs.DesiredStatusRunning {
+		return nil, fmt.Errorf("task %s is not running [status: %s]", task.TaskId, task.LastStatus)
+	}
+
+	if len(task.Containers) == 0 {
+		return nil, fmt.Errorf("task %s has no containers", task.TaskId)
+	}
+
+	container := task.Containers[0]
+
+	if i.ContainerName != "" {
+		var found bool
+
+		for _, c := range task.Containers {
+			if c.Name == i.ContainerName {
+				container, found = c, true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("container %s not found in task %s", i.ContainerName, task.TaskId)
+		}
+	}
+
+	resp, err := ecs.svc.ExecuteCommand(
+		&awsecs.ExecuteCommandInput{
+			Cluster:     aws.String(ecs.ClusterName),
+			Command:     aws.String(i.Command),
+			Container:   aws.String(container.Name),
+			Interactive: aws.Bool(i.Interactive),
+			Task:        aws.String(task.TaskArn),
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not execute command in task %s: %v", task.TaskId, err)
+	}
+
+	return &ExecSession{
+		ContainerName: container.Name,
+		Endpoint:      fmt.Sprintf(ssmEndpointFormat, ecs.region),
+		Region:        ecs.region,
+		SessionId:     aws.StringValue(resp.Session.SessionId),
+		StreamUrl:     aws.StringValue(resp.Session.StreamUrl),
+		Target:        fmt.Sprintf(execTargetFormat, ecs.ClusterName, task.TaskId, container.RuntimeId),
+		TokenValue:    aws.StringValue(resp.Session.TokenValue),
+	}, nil
+}
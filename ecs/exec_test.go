@@ -0,0 +1,102 @@
+package ecs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"github.com/jpignata/fargate/ecs/mock/sdk"
+)
+
+func TestExecuteCommand(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", region: "us-east-1", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				Containers: []*awsecs.Container{
+					&awsecs.Container{Name: aws.String("sidecar"), RuntimeId: aws.String("abc-1")},
+					&awsecs.Container{Name: aws.String("app"), RuntimeId: aws.String("abc-2")},
+				},
+				EnableExecuteCommand: aws.Bool(true),
+				LastStatus:           aws.String("RUNNING"),
+				TaskArn:              aws.String(testTaskArn),
+				TaskDefinitionArn:    aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/exec:1"),
+			},
+		},
+	}
+	executeCommandInput := &awsecs.ExecuteCommandInput{
+		Cluster:     aws.String("fargate"),
+		Command:     aws.String("/bin/sh"),
+		Container:   aws.String("app"),
+		Interactive: aws.Bool(true),
+		Task:        aws.String(testTaskArn),
+	}
+	executeCommandOutput := &awsecs.ExecuteCommandOutput{
+		Session: &awsecs.Session{
+			SessionId:  aws.String("session"),
+			StreamUrl:  aws.String("wss://ssmmessages.us-east-1.amazonaws.com/v1/data-channel/session"),
+			TokenValue: aws.String("token"),
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("not found")).AnyTimes()
+	mockECSClient.EXPECT().ExecuteCommand(executeCommandInput).Return(executeCommandOutput, nil)
+
+	session, err := ecs.ExecuteCommand(
+		&ExecuteCommandInput{
+			Command:       "/bin/sh",
+			ContainerName: "app",
+			Interactive:   true,
+			TaskId:        testTaskId,
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if expected := "ecs:fargate_" + testTaskId + "_abc-2"; session.Target != expected {
+		t.Errorf("expected target %s, got %s", expected, session.Target)
+	}
+
+	if expected := "https://ssm.us-east-1.amazonaws.com"; session.Endpoint != expected {
+		t.Errorf("expected endpoint %s, got %s", expected, session.Endpoint)
+	}
+
+	if session.SessionId != "session" || session.TokenValue != "token" {
+		t.Errorf("expected session and token, got %+v", session)
+	}
+}
+
+func TestExecuteCommandNotEnabled(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				LastStatus:        aws.String("RUNNING"),
+				TaskArn:           aws.String(testTaskArn),
+				TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/exec:1"),
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("not found")).AnyTimes()
+
+	_, err := ecs.ExecuteCommand(&ExecuteCommandInput{Command: "/bin/sh", TaskId: testTaskId})
+
+	if expected := "task " + testTaskId + " was not started with ECS Exec enabled"; err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
@@ -0,0 +1,49 @@
+package ecs
+
+import "github.com/jpignata/fargate/console"
+
+// Logger receives the output of an ECS client: progress and diagnostic
+// messages, warnings, and errors. Set ECS.Logger to capture it in an
+// application's own logs or to silence it in tests. By default, output is
+// written to the console.
+//
+// Errors which the client can't recover from are passed to Error before the
+// process exits, as they always have been.
+type Logger interface {
+	Debug(msg string, a ...interface{})
+	Info(msg string, a ...interface{})
+	Warn(msg string, a ...interface{})
+	Error(err error, msg string, a ...interface{})
+}
+
+type consoleLogger struct{}
+
+func (consoleLogger) Debug(msg string, a ...interface{}) {
+	console.Debug(msg, a...)
+}
+
+func (consoleLogger) Info(msg string, a ...interface{}) {
+	console.Info(msg, a...)
+}
+
+func (consoleLogger) Warn(msg string, a ...interface{}) {
+	console.Issue(msg, a...)
+}
+
+func (consoleLogger) Error(err error, msg string, a ...interface{}) {
+	console.Error(err, msg, a...)
+}
+
+func (ecs *ECS) logger() Logger {
+	if ecs.Logger == nil {
+		return consoleLogger{}
+	}
+
+	return ecs.Logger
+}
+
+// errorExit logs an error the client can't recover from and exits.
+func (ecs *ECS) errorExit(err error, msg string, a ...interface{}) {
+	ecs.logger().Error(err, msg, a...)
+	console.Exit(1)
+}
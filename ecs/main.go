@@ -1,18 +1,76 @@
 package ecs
 
+//go:generate mockgen -package sdk -source ../vendor/github.com/aws/aws-sdk-go/service/ecs/ecsiface/interface.go -destination=mock/sdk/ecsiface.go github.com/aws/aws-sdk-go/service/ecs/ecsiface ECSAPI
+
 import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
 )
 
 type ECS struct {
-	svc         *ecs.ECS
+	svc         ecsiface.ECSAPI
+	region      string
 	ClusterName string
+	LaunchType  string
+
+	// Lightweight skips looking up each task's task definition when describing
+	// tasks, leaving Image, TaskRole, PortMappings, Secrets, and environment
+	// variables from the task definition empty. It's much faster for listings
+	// which don't need those details.
+	Lightweight bool
+
+	// Logger receives the client's output. If nil, output is written to the
+	// console. Retried API requests are logged through the Logger the client
+	// has when it's created by New.
+	Logger Logger
+
+	// StartedByPrefix namespaces the task groups created and listed by this
+	// client, allowing multiple tools or teams to share a cluster. It must not
+	// contain a colon, which separates it from the task group name.
+	StartedByPrefix string
+
+	// NetworkInterfaces looks up the security groups and public IPs of tasks'
+	// network interfaces, which ECS doesn't return. Scaling task groups up and
+	// restarting tasks need it to launch tasks into the same security groups.
+	NetworkInterfaces NetworkInterfaceFinder
 }
 
 func New(sess *session.Session, clusterName string) ECS {
-	return ECS{
-		ClusterName: clusterName,
-		svc:         ecs.New(sess),
+	client := ECS{
+		ClusterName:     clusterName,
+		LaunchType:      ecs.LaunchTypeFargate,
+		Logger:          consoleLogger{},
+		region:          aws.StringValue(sess.Config.Region),
+		StartedByPrefix: defaultStartedByPrefix,
+	}
+
+	svc := ecs.New(sess)
+	svc.Handlers.Retry.PushBack(client.logRetry)
+	client.svc = svc
+
+	return client
+}
+
+// logRetry logs an API request which failed and is about to be retried, such
+// as after being throttled.
+func (ecs *ECS) logRetry(r *request.Request) {
+	if r.Error == nil || r.RetryCount >= r.MaxRetries() || !r.ShouldRetry(r) {
+		return
 	}
+
+	ecs.logger().Debug("Retrying %s (attempt %d of %d): %v", r.Operation.Name, r.RetryCount+1, r.MaxRetries(), r.Error)
+}
+
+// WithCluster returns a copy of the client which operates on the named cluster
+// instead, sharing the same underlying connection. This allows a single client
+// to be used across clusters [e.g. to find tasks running an image in every
+// cluster].
+func (ecs *ECS) WithCluster(clusterName string) *ECS {
+	c := *ecs
+	c.ClusterName = clusterName
+
+	return &c
 }
@@ -0,0 +1,115 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"github.com/jpignata/fargate/ecs/mock/sdk"
+)
+
+func TestWithCluster(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := &ECS{ClusterName: "fargate", svc: mockECSClient}
+	input := &awsecs.DescribeTasksInput{
+		Cluster: aws.String("other"),
+		Include: aws.StringSlice([]string{awsecs.TaskFieldTags}),
+		Tasks:   aws.StringSlice([]string{testTaskId}),
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(input).Return(&awsecs.DescribeTasksOutput{}, nil)
+
+	other := ecs.WithCluster("other")
+	other.DescribeTasks([]string{testTaskId})
+
+	if ecs.ClusterName != "fargate" {
+		t.Errorf("expected original cluster to be fargate, got %s", ecs.ClusterName)
+	}
+}
+
+type testLogger struct {
+	debug    []string
+	warnings []string
+}
+
+func (l *testLogger) Debug(msg string, a ...interface{}) {
+	l.debug = append(l.debug, fmt.Sprintf(msg, a...))
+}
+
+func (l *testLogger) Info(msg string, a ...interface{})             {}
+func (l *testLogger) Error(err error, msg string, a ...interface{}) {}
+
+func (l *testLogger) Warn(msg string, a ...interface{}) {
+	l.warnings = append(l.warnings, fmt.Sprintf(msg, a...))
+}
+
+type testEgressChecker bool
+
+func (c testEgressChecker) SecurityGroupsAllowEgress([]string) (bool, error) {
+	return bool(c), nil
+}
+
+func TestLogger(t *testing.T) {
+	logger := &testLogger{}
+	ecs := &ECS{ClusterName: "fargate", Logger: logger}
+
+	ecs.checkEgress(testEgressChecker(false), []string{"sg-abcdef"})
+
+	if expected := []string{"None of the security groups [sg-abcdef] allow outbound HTTPS traffic to 0.0.0.0/0"}; !reflect.DeepEqual(logger.warnings, expected) {
+		t.Errorf("expected warnings %v, got %v", expected, logger.warnings)
+	}
+}
+
+func TestNewLogsRetries(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")}))
+	ecs := New(sess, "fargate")
+
+	if handlers := ecs.svc.(*awsecs.ECS).Handlers.Retry.Len(); handlers != 1 {
+		t.Errorf("expected 1 retry handler, got %d", handlers)
+	}
+}
+
+func TestLogRetry(t *testing.T) {
+	logger := &testLogger{}
+	ecs := &ECS{ClusterName: "fargate", Logger: logger}
+	r := &request.Request{
+		Operation: &request.Operation{Name: "DescribeTasks"},
+		Retryer:   client.DefaultRetryer{NumMaxRetries: 3},
+		Error:     awserr.New("ThrottlingException", "Rate exceeded", nil),
+	}
+
+	ecs.logRetry(r)
+
+	r.RetryCount = 3
+	ecs.logRetry(r)
+
+	if expected := []string{"Retrying DescribeTasks (attempt 1 of 3): ThrottlingException: Rate exceeded"}; !reflect.DeepEqual(logger.debug, expected) {
+		t.Errorf("expected debug messages %v, got %v", expected, logger.debug)
+	}
+}
+
+func TestLogRetrySkipsNonRetryableErrors(t *testing.T) {
+	logger := &testLogger{}
+	ecs := &ECS{ClusterName: "fargate", Logger: logger}
+	r := &request.Request{
+		Operation: &request.Operation{Name: "DescribeTasks"},
+		Retryer:   client.DefaultRetryer{NumMaxRetries: 3},
+		Error:     awserr.New("ClusterNotFoundException", "Cluster not found.", nil),
+	}
+
+	ecs.logRetry(r)
+
+	if len(logger.debug) > 0 {
+		t.Errorf("expected no debug messages, got %v", logger.debug)
+	}
+}
@@ -2,58 +2,98 @@ package ecs
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	awsecs "github.com/aws/aws-sdk-go/service/ecs"
-	"github.com/jpignata/fargate/console"
+)
+
+const (
+	DeploymentControllerCodeDeploy = awsecs.DeploymentControllerTypeCodeDeploy
+	DeploymentControllerEcs        = awsecs.DeploymentControllerTypeEcs
+
+	EventSourceDeployment = "deployment"
+	EventSourceService    = "service"
+	EventSourceTask       = "task"
+
+	maxHealthCheckGracePeriod = 2147483647
+	serviceStablePollInterval = 15 * time.Second
 )
 
 type CreateServiceInput struct {
-	Cluster           string
-	DesiredCount      int64
-	Name              string
-	Port              int64
-	SecurityGroupIds  []string
-	SubnetIds         []string
-	TargetGroupArn    string
-	TaskDefinitionArn string
+	CapacityProviders      []CapacityProviderStrategyItem
+	CircuitBreaker         CircuitBreaker
+	Cluster                string
+	DeploymentController   string
+	DesiredCount           int64
+	EnableExecuteCommand   bool
+	HealthCheckGracePeriod int64
+	Name                   string
+	NoPublicIp             bool
+	PlatformVersion        string
+	Port                   int64
+	SecurityGroupIds       []string
+	ServiceRegistryArn     string
+	SubnetIds              []string
+	TargetGroupArn         string
+	TaskDefinitionArn      string
 }
 
 type Service struct {
-	Cluster           string
-	Cpu               string
-	Deployments       []Deployment
-	DesiredCount      int64
-	EnvVars           []EnvVar
-	Events            []Event
-	Image             string
-	Memory            string
-	Name              string
-	PendingCount      int64
-	RunningCount      int64
-	SecurityGroupIds  []string
-	TargetGroupArn    string
-	TaskDefinitionArn string
-	TaskRole          string
-	SubnetIds         []string
-	Status            string
+	CircuitBreaker         CircuitBreaker
+	Cluster                string
+	Containers             []ContainerDefinition
+	Cpu                    string
+	DeploymentController   string
+	Deployments            []Deployment
+	DesiredCount           int64
+	EnvVars                []EnvVar
+	Events                 []Event
+	HealthCheckGracePeriod int64
+	Image                  string
+	Memory                 string
+	Name                   string
+	PendingCount           int64
+	RunningCount           int64
+	SecurityGroupIds       []string
+	ServiceRegistryArn     string
+	TargetContainerName    string
+	TargetContainerPort    int64
+	TargetGroupArn         string
+	TaskDefinitionArn      string
+	TaskRole               string
+	SubnetIds              []string
+	Status                 string
+}
+
+type ServiceTasks struct {
+	DesiredCount int64
+	PendingCount int64
+	RunningCount int64
+	Tasks        []Task
 }
 
 type Event struct {
 	CreatedAt time.Time
+	Id        string
 	Message   string
+	Source    string
 }
 
 type Deployment struct {
-	CreatedAt    time.Time
-	DesiredCount int64
-	Id           string
-	Image        string
-	PendingCount int64
-	RunningCount int64
-	Status       string
+	CreatedAt          time.Time
+	DesiredCount       int64
+	Id                 string
+	Image              string
+	PendingCount       int64
+	RolloutState       string
+	RolloutStateReason string
+	RunningCount       int64
+	Status             string
+	UpdatedAt          time.Time
 }
 
 func (s *Service) AddEvent(e Event) {
@@ -65,7 +105,7 @@ func (s *Service) AddDeployment(d Deployment) {
 }
 
 func (ecs *ECS) CreateService(input *CreateServiceInput) {
-	console.Debug("Creating ECS service")
+	ecs.logger().Debug("Creating ECS service")
 
 	createServiceInput := &awsecs.CreateServiceInput{
 		Cluster:        aws.String(input.Cluster),
@@ -75,13 +115,46 @@ func (ecs *ECS) CreateService(input *CreateServiceInput) {
 		LaunchType:     aws.String(awsecs.CompatibilityFargate),
 		NetworkConfiguration: &awsecs.NetworkConfiguration{
 			AwsvpcConfiguration: &awsecs.AwsVpcConfiguration{
-				AssignPublicIp: aws.String(awsecs.AssignPublicIpEnabled),
+				AssignPublicIp: aws.String(assignPublicIp(input.NoPublicIp)),
 				Subnets:        aws.StringSlice(input.SubnetIds),
 				SecurityGroups: aws.StringSlice(input.SecurityGroupIds),
 			},
 		},
 	}
 
+	if len(input.CapacityProviders) > 0 {
+		createServiceInput.LaunchType = nil
+		createServiceInput.CapacityProviderStrategy = capacityProviderStrategy(input.CapacityProviders)
+	}
+
+	if input.EnableExecuteCommand {
+		createServiceInput.EnableExecuteCommand = aws.Bool(true)
+	}
+
+	if input.CircuitBreaker.Enable {
+		createServiceInput.DeploymentConfiguration = input.CircuitBreaker.deploymentConfiguration()
+	}
+
+	if input.DeploymentController != "" {
+		createServiceInput.DeploymentController = &awsecs.DeploymentController{
+			Type: aws.String(input.DeploymentController),
+		}
+	}
+
+	if input.PlatformVersion != "" {
+		createServiceInput.PlatformVersion = aws.String(input.PlatformVersion)
+	}
+
+	if input.ServiceRegistryArn != "" {
+		createServiceInput.SetServiceRegistries(
+			[]*awsecs.ServiceRegistry{
+				&awsecs.ServiceRegistry{
+					RegistryArn: aws.String(input.ServiceRegistryArn),
+				},
+			},
+		)
+	}
+
 	if input.TargetGroupArn != "" && input.Port > 0 {
 		createServiceInput.SetLoadBalancers(
 			[]*awsecs.LoadBalancer{
@@ -94,13 +167,17 @@ func (ecs *ECS) CreateService(input *CreateServiceInput) {
 		)
 	}
 
+	if input.HealthCheckGracePeriod > 0 {
+		createServiceInput.HealthCheckGracePeriodSeconds = aws.Int64(input.HealthCheckGracePeriod)
+	}
+
 	_, err := ecs.svc.CreateService(createServiceInput)
 
 	if err != nil {
-		console.ErrorExit(err, "Couldn't create ECS service")
+		ecs.errorExit(err, "Couldn't create ECS service")
 	}
 
-	console.Debug("Created ECS service [%s]", input.Name)
+	ecs.logger().Debug("Created ECS service [%s]", input.Name)
 
 	return
 }
@@ -109,12 +186,40 @@ func (ecs *ECS) DescribeService(serviceName string) Service {
 	services := ecs.DescribeServices([]string{serviceName})
 
 	if len(services) == 0 {
-		console.ErrorExit(fmt.Errorf("Could not find %s", serviceName), "Could not describe ECS service")
+		ecs.errorExit(fmt.Errorf("Could not find %s", serviceName), "Could not describe ECS service")
 	}
 
 	return services[0]
 }
 
+// DescribeServiceTasks returns a service's task counts along with its tasks
+// which match the filter.
+func (ecs *ECS) DescribeServiceTasks(serviceName string, filter TaskFilter) ServiceTasks {
+	resp, err := ecs.svc.DescribeServices(
+		&awsecs.DescribeServicesInput{
+			Cluster:  aws.String(ecs.ClusterName),
+			Services: aws.StringSlice([]string{serviceName}),
+		},
+	)
+
+	if err != nil {
+		ecs.errorExit(err, "Could not describe ECS service")
+	}
+
+	if len(resp.Services) == 0 {
+		ecs.errorExit(fmt.Errorf("Could not find %s", serviceName), "Could not describe ECS service")
+	}
+
+	service := resp.Services[0]
+
+	return ServiceTasks{
+		DesiredCount: aws.Int64Value(service.DesiredCount),
+		PendingCount: aws.Int64Value(service.PendingCount),
+		RunningCount: aws.Int64Value(service.RunningCount),
+		Tasks:        ecs.DescribeTasksForService(serviceName, filter),
+	}
+}
+
 func (ecs *ECS) GetDesiredCount(serviceName string) int64 {
 	service := ecs.DescribeService(serviceName)
 	return service.DesiredCount
@@ -130,7 +235,7 @@ func (ecs *ECS) SetDesiredCount(serviceName string, desiredCount int64) {
 	)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not scale ECS service")
+		ecs.errorExit(err, "Could not scale ECS service")
 	}
 }
 
@@ -143,7 +248,7 @@ func (ecs *ECS) DestroyService(serviceName string) {
 	)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not destroy ECS service")
+		ecs.errorExit(err, "Could not destroy ECS service")
 	}
 }
 
@@ -167,7 +272,7 @@ func (ecs *ECS) ListServices() []Service {
 	)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not list ECS services")
+		ecs.errorExit(err, "Could not list ECS services")
 	}
 
 	if len(serviceArnBatches) > 0 {
@@ -192,7 +297,7 @@ func (ecs *ECS) DescribeServices(serviceArns []string) []Service {
 	)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not describe ECS services")
+		ecs.errorExit(err, "Could not describe ECS services")
 	}
 
 	for _, service := range resp.Services {
@@ -204,14 +309,21 @@ func (ecs *ECS) DescribeServices(serviceArns []string) []Service {
 		}
 
 		s := Service{
-			DesiredCount:      aws.Int64Value(service.DesiredCount),
-			Name:              aws.StringValue(service.ServiceName),
-			PendingCount:      aws.Int64Value(service.PendingCount),
-			RunningCount:      aws.Int64Value(service.RunningCount),
-			SecurityGroupIds:  aws.StringValueSlice(securityGroupIds),
-			Status:            aws.StringValue(service.Status),
-			SubnetIds:         aws.StringValueSlice(subnetIds),
-			TaskDefinitionArn: aws.StringValue(service.TaskDefinition),
+			CircuitBreaker:         circuitBreakerFromDeploymentConfiguration(service.DeploymentConfiguration),
+			DeploymentController:   DeploymentControllerEcs,
+			DesiredCount:           aws.Int64Value(service.DesiredCount),
+			HealthCheckGracePeriod: aws.Int64Value(service.HealthCheckGracePeriodSeconds),
+			Name:                   aws.StringValue(service.ServiceName),
+			PendingCount:           aws.Int64Value(service.PendingCount),
+			RunningCount:           aws.Int64Value(service.RunningCount),
+			SecurityGroupIds:       aws.StringValueSlice(securityGroupIds),
+			Status:                 aws.StringValue(service.Status),
+			SubnetIds:              aws.StringValueSlice(subnetIds),
+			TaskDefinitionArn:      aws.StringValue(service.TaskDefinition),
+		}
+
+		if service.DeploymentController != nil {
+			s.DeploymentController = aws.StringValue(service.DeploymentController.Type)
 		}
 
 		taskDefinition := ecs.DescribeTaskDefinition(aws.StringValue(service.TaskDefinition))
@@ -219,8 +331,15 @@ func (ecs *ECS) DescribeServices(serviceArns []string) []Service {
 		s.Cpu = aws.StringValue(taskDefinition.Cpu)
 		s.Memory = aws.StringValue(taskDefinition.Memory)
 		s.TaskRole = aws.StringValue(taskDefinition.TaskRoleArn)
+		s.Containers = summarizeContainerDefinitions(taskDefinition)
+
+		if len(service.ServiceRegistries) > 0 {
+			s.ServiceRegistryArn = aws.StringValue(service.ServiceRegistries[0].RegistryArn)
+		}
 
 		if len(service.LoadBalancers) > 0 {
+			s.TargetContainerName = aws.StringValue(service.LoadBalancers[0].ContainerName)
+			s.TargetContainerPort = aws.Int64Value(service.LoadBalancers[0].ContainerPort)
 			s.TargetGroupArn = aws.StringValue(service.LoadBalancers[0].TargetGroupArn)
 		}
 
@@ -242,19 +361,24 @@ func (ecs *ECS) DescribeServices(serviceArns []string) []Service {
 			s.AddEvent(
 				Event{
 					CreatedAt: aws.TimeValue(event.CreatedAt),
+					Id:        aws.StringValue(event.Id),
 					Message:   aws.StringValue(event.Message),
+					Source:    EventSourceService,
 				},
 			)
 		}
 
 		for _, d := range service.Deployments {
 			deployment := Deployment{
-				Status:       aws.StringValue(d.Status),
-				DesiredCount: aws.Int64Value(d.DesiredCount),
-				PendingCount: aws.Int64Value(d.PendingCount),
-				RunningCount: aws.Int64Value(d.RunningCount),
-				CreatedAt:    aws.TimeValue(d.CreatedAt),
-				Id:           ecs.getDeploymentId(aws.StringValue(d.TaskDefinition)),
+				Status:             aws.StringValue(d.Status),
+				DesiredCount:       aws.Int64Value(d.DesiredCount),
+				PendingCount:       aws.Int64Value(d.PendingCount),
+				RunningCount:       aws.Int64Value(d.RunningCount),
+				RolloutState:       aws.StringValue(d.RolloutState),
+				RolloutStateReason: aws.StringValue(d.RolloutStateReason),
+				CreatedAt:          aws.TimeValue(d.CreatedAt),
+				UpdatedAt:          aws.TimeValue(d.UpdatedAt),
+				Id:                 ecs.getDeploymentId(aws.StringValue(d.TaskDefinition)),
 			}
 
 			deploymentTaskDefinition := ecs.DescribeTaskDefinition(aws.StringValue(d.TaskDefinition))
@@ -279,8 +403,37 @@ func (ecs *ECS) UpdateServiceTaskDefinition(serviceName, taskDefinitionArn strin
 	)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not update ECS service task definition")
+		ecs.errorExit(err, "Could not update ECS service task definition")
+	}
+}
+
+// UpdateServiceHealthCheckGracePeriod changes how long, in seconds, ECS
+// ignores failing load balancer health checks of the service's newly started
+// tasks. A period of 0 removes it.
+func (ecs *ECS) UpdateServiceHealthCheckGracePeriod(serviceName string, seconds int64) error {
+	_, err := ecs.svc.UpdateService(
+		&awsecs.UpdateServiceInput{
+			Cluster:                       aws.String(ecs.ClusterName),
+			HealthCheckGracePeriodSeconds: aws.Int64(seconds),
+			Service:                       aws.String(serviceName),
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not update health check grace period of service %s: %v", serviceName, err)
+	}
+
+	return nil
+}
+
+// ValidateHealthCheckGracePeriod returns an error unless the health check
+// grace period, in seconds, is within the range ECS supports.
+func ValidateHealthCheckGracePeriod(seconds int64) error {
+	if seconds < 0 || seconds > maxHealthCheckGracePeriod {
+		return fmt.Errorf("invalid health check grace period %d: must be between 0 and %d seconds", seconds, maxHealthCheckGracePeriod)
 	}
+
+	return nil
 }
 
 func (ecs *ECS) RestartService(serviceName string) {
@@ -296,12 +449,234 @@ func (ecs *ECS) RestartService(serviceName string) {
 		if aerr, ok := err.(awserr.Error); ok {
 			switch aerr.Code() {
 			case "ServiceNotFoundException":
-				console.IssueExit("Service %s not found", serviceName)
+				ecs.errorExit(nil, "Service %s not found", serviceName)
 			default:
-				console.ErrorExit(err, "Could not restart service")
+				ecs.errorExit(err, "Could not restart service")
+			}
+		}
+
+		ecs.errorExit(err, "Could not restart service")
+	}
+}
+
+// DescribeServiceEventFeed returns the service's events merged with the stops
+// of its recently stopped tasks and the progress of its deployments, oldest
+// first, so that tasks failing during a deployment can be read alongside the
+// events they cause [e.g. a task stopping with OutOfMemoryError followed by ECS
+// starting its replacement]. Each event has an ID which is stable across calls.
+func (ecs *ECS) DescribeServiceEventFeed(serviceName string) []Event {
+	service := ecs.DescribeService(serviceName)
+	tasks := ecs.DescribeTasksForService(serviceName, TaskFilter{IncludeStopped: true})
+
+	return mergeEventFeed(service.Events, service.Deployments, tasks)
+}
+
+func mergeEventFeed(events []Event, deployments []Deployment, tasks []Task) []Event {
+	feed := append([]Event{}, events...)
+
+	for _, d := range deployments {
+		feed = append(
+			feed,
+			Event{
+				CreatedAt: d.CreatedAt,
+				Id:        fmt.Sprintf("deployment/%s/started", d.Id),
+				Message:   fmt.Sprintf("deployment %s started (%s)", d.Id, d.Image),
+				Source:    EventSourceDeployment,
+			},
+		)
+
+		switch d.RolloutState {
+		case awsecs.DeploymentRolloutStateCompleted:
+			feed = append(
+				feed,
+				Event{
+					CreatedAt: d.UpdatedAt,
+					Id:        fmt.Sprintf("deployment/%s/completed", d.Id),
+					Message:   fmt.Sprintf("deployment %s completed", d.Id),
+					Source:    EventSourceDeployment,
+				},
+			)
+		case awsecs.DeploymentRolloutStateFailed:
+			feed = append(
+				feed,
+				Event{
+					CreatedAt: d.UpdatedAt,
+					Id:        fmt.Sprintf("deployment/%s/failed", d.Id),
+					Message:   fmt.Sprintf("deployment %s failed: %s", d.Id, d.RolloutStateReason),
+					Source:    EventSourceDeployment,
+				},
+			)
+		}
+	}
+
+	for _, task := range tasks {
+		if task.StoppedAt.IsZero() {
+			continue
+		}
+
+		message := fmt.Sprintf("task %s stopped", task.TaskId)
+
+		if summary := task.StopSummary(); summary != "" {
+			message = fmt.Sprintf("%s (%s)", message, summary)
+		}
+
+		feed = append(
+			feed,
+			Event{
+				CreatedAt: task.StoppedAt,
+				Id:        fmt.Sprintf("task/%s/stopped", task.TaskId),
+				Message:   message,
+				Source:    EventSourceTask,
+			},
+		)
+	}
+
+	sort.SliceStable(feed, func(i, j int) bool { return feed[i].CreatedAt.Before(feed[j].CreatedAt) })
+
+	return feed
+}
+
+// WaitForServiceStable polls the service until its deployment has fully rolled
+// out: there's a single, primary deployment whose running count has reached
+// its desired count with no tasks pending. An error describing what's still
+// pending, along with why any of the service's tasks recently stopped, is
+// returned if the service isn't stable within the timeout. A deployment rolled
+// back by the deployment circuit breaker fails immediately.
+func (ecs *ECS) WaitForServiceStable(serviceName string, timeout time.Duration) error {
+	return ecs.WaitForServiceStableWithEvents(serviceName, timeout, nil)
+}
+
+// WaitForServiceStableWithEvents waits for the service to become stable as
+// WaitForServiceStable does, calling fn with each service event posted while
+// waiting, oldest first [e.g. tasks being started or failing health checks].
+// Events posted before the wait began aren't passed to fn.
+func (ecs *ECS) WaitForServiceStableWithEvents(serviceName string, timeout time.Duration, fn func(Event)) error {
+	var seen map[string]bool
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		resp, err := ecs.svc.DescribeServices(
+			&awsecs.DescribeServicesInput{
+				Cluster:  aws.String(ecs.ClusterName),
+				Services: aws.StringSlice([]string{serviceName}),
+			},
+		)
+
+		if err != nil {
+			return fmt.Errorf("could not describe service %s: %v", serviceName, err)
+		}
+
+		if len(resp.Services) == 0 {
+			return fmt.Errorf("service %s not found", serviceName)
+		}
+
+		service := resp.Services[0]
+		pending := servicePending(service)
+
+		if fn != nil {
+			seen = newServiceEvents(service.Events, seen, fn)
+		}
+
+		for _, d := range service.Deployments {
+			if aws.StringValue(d.RolloutState) == awsecs.DeploymentRolloutStateFailed {
+				return fmt.Errorf("deployment of service %s failed: %s", serviceName, aws.StringValue(d.RolloutStateReason))
+			}
+		}
+
+		if pending == "" {
+			return nil
+		}
+
+		if time.Now().Add(serviceStablePollInterval).After(deadline) {
+			return fmt.Errorf("service %s not stable after %s: %s%s", serviceName, timeout, pending, ecs.stoppedTaskSummary(serviceName))
+		}
+
+		time.Sleep(serviceStablePollInterval)
+	}
+}
+
+// newServiceEvents calls fn with each of the events, which ECS lists newest
+// first, not already in seen, oldest first. A nil seen marks every event as
+// seen without calling fn. The updated set of seen event IDs is returned.
+func newServiceEvents(events []*awsecs.ServiceEvent, seen map[string]bool, fn func(Event)) map[string]bool {
+	first := seen == nil
+
+	if first {
+		seen = make(map[string]bool)
+	}
+
+	for i := len(events) - 1; i >= 0; i-- {
+		id := aws.StringValue(events[i].Id)
+
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+
+		if !first {
+			fn(
+				Event{
+					CreatedAt: aws.TimeValue(events[i].CreatedAt),
+					Id:        id,
+					Message:   aws.StringValue(events[i].Message),
+					Source:    EventSourceService,
+				},
+			)
+		}
+	}
+
+	return seen
+}
+
+// servicePending describes what remains before the service is stable, or
+// returns an empty string if it is.
+func servicePending(service *awsecs.Service) string {
+	var pending []string
+
+	if count := len(service.Deployments); count != 1 {
+		pending = append(pending, fmt.Sprintf("%d deployments in progress", count))
+	}
+
+	for _, d := range service.Deployments {
+		if aws.StringValue(d.Status) != "PRIMARY" {
+			continue
+		}
+
+		running := aws.Int64Value(d.RunningCount)
+		desired := aws.Int64Value(d.DesiredCount)
+
+		if running != desired || aws.Int64Value(d.PendingCount) > 0 {
+			pending = append(pending, fmt.Sprintf("%d/%d running, %d pending", running, desired, aws.Int64Value(d.PendingCount)))
+
+			// FailedTasks counts every failure over the life of the
+			// deployment, so it only explains a deployment which has yet to
+			// reach its desired count; a failed rollout is reported by the
+			// rollout state instead.
+			if failed := aws.Int64Value(d.FailedTasks); failed > 0 {
+				pending = append(pending, fmt.Sprintf("%d failed", failed))
 			}
 		}
+	}
+
+	return strings.Join(pending, ", ")
+}
+
+// stoppedTaskSummary summarizes why the service's recently stopped tasks
+// stopped, for explaining why a deployment is stuck.
+func (ecs *ECS) stoppedTaskSummary(serviceName string) string {
+	var reasons []string
 
-		console.ErrorExit(err, "Could not restart service")
+	for _, task := range ecs.DescribeTasksForService(serviceName, TaskFilter{IncludeStopped: true}) {
+		if task.LastStatus == awsecs.DesiredStatusStopped {
+			reasons = append(reasons, fmt.Sprintf("task %s %s", task.TaskId, task.StopSummary()))
+		}
+	}
+
+	if len(reasons) == 0 {
+		return ""
 	}
+
+	return fmt.Sprintf(" (%s)", strings.Join(reasons, "; "))
 }
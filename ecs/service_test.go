@@ -0,0 +1,354 @@
+package ecs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"github.com/jpignata/fargate/ecs/mock/sdk"
+)
+
+func TestWaitForServiceStable(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	input := &awsecs.DescribeServicesInput{
+		Cluster:  aws.String("fargate"),
+		Services: aws.StringSlice([]string{"web"}),
+	}
+	output := &awsecs.DescribeServicesOutput{
+		Services: []*awsecs.Service{
+			&awsecs.Service{
+				Deployments: []*awsecs.Deployment{
+					&awsecs.Deployment{
+						DesiredCount: aws.Int64(2),
+						PendingCount: aws.Int64(0),
+						RunningCount: aws.Int64(2),
+						Status:       aws.String("PRIMARY"),
+					},
+				},
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeServices(input).Return(output, nil)
+
+	if err := ecs.WaitForServiceStable("web", 0); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestWaitForServiceStableTimeout(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	output := &awsecs.DescribeServicesOutput{
+		Services: []*awsecs.Service{
+			&awsecs.Service{
+				Deployments: []*awsecs.Deployment{
+					&awsecs.Deployment{
+						DesiredCount: aws.Int64(2),
+						PendingCount: aws.Int64(1),
+						RunningCount: aws.Int64(1),
+						Status:       aws.String("PRIMARY"),
+					},
+					&awsecs.Deployment{
+						DesiredCount: aws.Int64(0),
+						RunningCount: aws.Int64(1),
+						Status:       aws.String("ACTIVE"),
+					},
+				},
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeServices(gomock.Any()).Return(output, nil)
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	err := ecs.WaitForServiceStable("web", 0)
+
+	if err == nil {
+		t.Fatalf("expected error, got none")
+	}
+
+	if expected := "2 deployments in progress, 1/2 running, 1 pending"; !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected error to contain %q, got %q", expected, err)
+	}
+}
+
+func TestWaitForServiceStableRolloutFailed(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	output := &awsecs.DescribeServicesOutput{
+		Services: []*awsecs.Service{
+			&awsecs.Service{
+				Deployments: []*awsecs.Deployment{
+					&awsecs.Deployment{
+						DesiredCount:       aws.Int64(2),
+						FailedTasks:        aws.Int64(3),
+						RolloutState:       aws.String("FAILED"),
+						RolloutStateReason: aws.String("circuit breaker triggered"),
+						Status:             aws.String("PRIMARY"),
+					},
+				},
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeServices(gomock.Any()).Return(output, nil)
+
+	if err := ecs.WaitForServiceStable("web", 0); err == nil || !strings.Contains(err.Error(), "circuit breaker triggered") {
+		t.Errorf("expected rollout failure, got %v", err)
+	}
+}
+
+func TestWaitForServiceStableAfterRecoveredFailure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	output := &awsecs.DescribeServicesOutput{
+		Services: []*awsecs.Service{
+			&awsecs.Service{
+				Deployments: []*awsecs.Deployment{
+					&awsecs.Deployment{
+						DesiredCount: aws.Int64(2),
+						FailedTasks:  aws.Int64(1),
+						PendingCount: aws.Int64(0),
+						RolloutState: aws.String("COMPLETED"),
+						RunningCount: aws.Int64(2),
+						Status:       aws.String("PRIMARY"),
+					},
+				},
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeServices(gomock.Any()).Return(output, nil)
+
+	if err := ecs.WaitForServiceStable("web", 0); err != nil {
+		t.Errorf("expected service with a recovered task failure to be stable, got %v", err)
+	}
+}
+
+func TestDescribeServiceTasks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var desiredStatuses []string
+
+	stoppedTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/stopped-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+	input := &awsecs.DescribeServicesInput{
+		Cluster:  aws.String("fargate"),
+		Services: aws.StringSlice([]string{"web"}),
+	}
+	output := &awsecs.DescribeServicesOutput{
+		Services: []*awsecs.Service{
+			&awsecs.Service{
+				DesiredCount: aws.Int64(2),
+				PendingCount: aws.Int64(1),
+				RunningCount: aws.Int64(1),
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeServices(input).Return(output, nil)
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			if serviceName := aws.StringValue(input.ServiceName); serviceName != "web" {
+				t.Errorf("expected tasks of service web to be listed, got %s", serviceName)
+			}
+
+			desiredStatus := aws.StringValue(input.DesiredStatus)
+			desiredStatuses = append(desiredStatuses, desiredStatus)
+
+			if desiredStatus == awsecs.DesiredStatusStopped {
+				fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{stoppedTaskArn})}, true)
+			} else {
+				fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{testTaskArn})}, true)
+			}
+		},
+	).Return(nil).Times(2)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+		&awsecs.DescribeTasksOutput{
+			Tasks: []*awsecs.Task{&awsecs.Task{TaskArn: aws.String(testTaskArn), LastStatus: aws.String("RUNNING")}},
+		}, nil,
+	)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+		&awsecs.DescribeTasksOutput{
+			Tasks: []*awsecs.Task{
+				&awsecs.Task{
+					DesiredStatus: aws.String("STOPPED"),
+					LastStatus:    aws.String("STOPPED"),
+					StoppedReason: aws.String("Essential container in task exited"),
+					TaskArn:       aws.String(stoppedTaskArn),
+				},
+			},
+		}, nil,
+	)
+
+	serviceTasks := ecs.DescribeServiceTasks("web", TaskFilter{IncludeStopped: true})
+
+	if serviceTasks.DesiredCount != 2 || serviceTasks.PendingCount != 1 || serviceTasks.RunningCount != 1 {
+		t.Errorf("expected 2 desired, 1 pending, and 1 running, got %d, %d, and %d", serviceTasks.DesiredCount, serviceTasks.PendingCount, serviceTasks.RunningCount)
+	}
+
+	if expected := []string{awsecs.DesiredStatusRunning, awsecs.DesiredStatusStopped}; !reflect.DeepEqual(desiredStatuses, expected) {
+		t.Errorf("expected tasks listed with desired statuses %v, got %v", expected, desiredStatuses)
+	}
+
+	if len(serviceTasks.Tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(serviceTasks.Tasks))
+	}
+
+	if serviceTasks.Tasks[0].Stopped() || !serviceTasks.Tasks[1].Stopped() {
+		t.Errorf("expected a running task followed by a stopped task, got %+v", serviceTasks.Tasks)
+	}
+}
+
+func TestNewServiceEvents(t *testing.T) {
+	var messages []string
+
+	record := func(event Event) { messages = append(messages, event.Message) }
+	events := []*awsecs.ServiceEvent{
+		&awsecs.ServiceEvent{Id: aws.String("2"), Message: aws.String("has started 1 tasks")},
+		&awsecs.ServiceEvent{Id: aws.String("1"), Message: aws.String("has reached a steady state")},
+	}
+
+	seen := newServiceEvents(events, nil, record)
+
+	if len(messages) != 0 {
+		t.Fatalf("expected events from before the wait to be skipped, got %v", messages)
+	}
+
+	events = append(
+		[]*awsecs.ServiceEvent{
+			&awsecs.ServiceEvent{Id: aws.String("4"), Message: aws.String("has reached a steady state")},
+			&awsecs.ServiceEvent{Id: aws.String("3"), Message: aws.String("has stopped 1 running tasks")},
+		},
+		events...,
+	)
+
+	newServiceEvents(events, seen, record)
+
+	if expected := []string{"has stopped 1 running tasks", "has reached a steady state"}; !reflect.DeepEqual(messages, expected) {
+		t.Errorf("expected %v, got %v", expected, messages)
+	}
+}
+
+func TestMergeEventFeed(t *testing.T) {
+	start := time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC)
+	events := []Event{
+		Event{CreatedAt: start.Add(3 * time.Minute), Message: "(service web) was unable to place a task", Source: EventSourceService},
+		Event{CreatedAt: start.Add(time.Minute), Message: "(service web) has started 1 tasks", Source: EventSourceService},
+	}
+	tasks := []Task{
+		Task{TaskId: "running", CreatedAt: start},
+		Task{
+			TaskId:        "oom",
+			StopCategory:  StopCategoryOutOfMemory,
+			StoppedAt:     start.Add(2 * time.Minute),
+			StoppedReason: "Essential container in task exited",
+		},
+	}
+	expected := []string{
+		"(service web) has started 1 tasks",
+		"task oom stopped (out of memory: Essential container in task exited)",
+		"(service web) was unable to place a task",
+	}
+
+	feed := mergeEventFeed(events, nil, tasks)
+
+	if len(feed) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(feed))
+	}
+
+	for i, event := range feed {
+		if event.Message != expected[i] {
+			t.Errorf("expected event %d to be %q, got %q", i, expected[i], event.Message)
+		}
+	}
+
+	if feed[1].Source != EventSourceTask {
+		t.Errorf("expected source %s, got %s", EventSourceTask, feed[1].Source)
+	}
+}
+
+func TestMergeEventFeedDeployments(t *testing.T) {
+	start := time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC)
+	deployments := []Deployment{
+		Deployment{
+			CreatedAt:          start.Add(time.Minute),
+			Id:                 "5",
+			Image:              "web:v2",
+			RolloutState:       awsecs.DeploymentRolloutStateFailed,
+			RolloutStateReason: "ECS deployment circuit breaker: tasks failed to start.",
+			UpdatedAt:          start.Add(10 * time.Minute),
+		},
+		Deployment{
+			CreatedAt:    start,
+			Id:           "4",
+			Image:        "web:v1",
+			RolloutState: awsecs.DeploymentRolloutStateCompleted,
+			UpdatedAt:    start.Add(2 * time.Minute),
+		},
+	}
+	expected := []struct {
+		id      string
+		message string
+	}{
+		{"deployment/4/started", "deployment 4 started (web:v1)"},
+		{"deployment/5/started", "deployment 5 started (web:v2)"},
+		{"deployment/4/completed", "deployment 4 completed"},
+		{"deployment/5/failed", "deployment 5 failed: ECS deployment circuit breaker: tasks failed to start."},
+	}
+
+	feed := mergeEventFeed(nil, deployments, nil)
+
+	if len(feed) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(feed))
+	}
+
+	for i, event := range feed {
+		if event.Id != expected[i].id || event.Message != expected[i].message {
+			t.Errorf("expected event %d to be %s %q, got %s %q", i, expected[i].id, expected[i].message, event.Id, event.Message)
+		}
+
+		if event.Source != EventSourceDeployment {
+			t.Errorf("expected source %s, got %s", EventSourceDeployment, event.Source)
+		}
+	}
+}
+
+func TestValidateHealthCheckGracePeriod(t *testing.T) {
+	var tests = []struct {
+		seconds int64
+		valid   bool
+	}{
+		{0, true},
+		{120, true},
+		{2147483647, true},
+		{-1, false},
+		{2147483648, false},
+	}
+
+	for _, test := range tests {
+		if err := ValidateHealthCheckGracePeriod(test.seconds); (err == nil) != test.valid {
+			t.Errorf("expected valid %t for %d, got %v", test.valid, test.seconds, err)
+		}
+	}
+}
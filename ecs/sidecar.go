@@ -0,0 +1,177 @@
+package ecs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// Sidecar is an additional container run alongside a task's main container
+// [e.g. an nginx proxy or a metrics agent]. Sidecars aren't essential unless
+// marked so; the task keeps running if a non-essential sidecar exits.
+type Sidecar struct {
+	EnvVars   []EnvVar
+	Essential bool
+	Image     string
+	Name      string
+	Port      int64
+}
+
+// ContainerDefinition describes one of a task definition's containers. The
+// first container is the task's main container; any others are sidecars.
+type ContainerDefinition struct {
+	EnvVars      []EnvVar
+	Essential    bool
+	Image        string
+	Name         string
+	PortMappings []PortMapping
+}
+
+// ParseSidecar parses a sidecar expression in the form of
+// NAME,image=IMAGE[,port=N][,env=KEY=VALUE][,essential] [e.g.
+// nginx,image=nginx:1.25,port=80,env=UPSTREAM=localhost:8080]. The env key may
+// be given multiple times.
+func ParseSidecar(expression string) (Sidecar, error) {
+	fields := strings.Split(expression, ",")
+	sidecar := Sidecar{
+		Name: strings.TrimSpace(fields[0]),
+	}
+
+	if sidecar.Name == "" {
+		return Sidecar{}, fmt.Errorf("invalid sidecar %q: a name is required", expression)
+	}
+
+	for _, field := range fields[1:] {
+		field = strings.TrimSpace(field)
+
+		if strings.ToLower(field) == "essential" {
+			sidecar.Essential = true
+			continue
+		}
+
+		kv := strings.SplitN(field, "=", 2)
+
+		if len(kv) != 2 {
+			return Sidecar{}, fmt.Errorf("invalid sidecar %q: %q must be in the form of key=value", expression, field)
+		}
+
+		switch strings.ToLower(kv[0]) {
+		case "image":
+			sidecar.Image = kv[1]
+		case "port":
+			port, err := strconv.ParseInt(kv[1], 10, 64)
+
+			if err != nil || port < 1 || port > 65535 {
+				return Sidecar{}, fmt.Errorf("invalid sidecar %q: port must be a number between 1 and 65535", expression)
+			}
+
+			sidecar.Port = port
+		case "env":
+			env := strings.SplitN(kv[1], "=", 2)
+
+			if len(env) != 2 || env[0] == "" {
+				return Sidecar{}, fmt.Errorf("invalid sidecar %q: env must be in the form of env=KEY=VALUE", expression)
+			}
+
+			sidecar.EnvVars = append(sidecar.EnvVars, EnvVar{Key: env[0], Value: env[1]})
+		default:
+			return Sidecar{}, fmt.Errorf("invalid sidecar %q: unknown key %s [must be image, port, env, or essential]", expression, kv[0])
+		}
+	}
+
+	if sidecar.Image == "" {
+		return Sidecar{}, fmt.Errorf("invalid sidecar %q: an image is required", expression)
+	}
+
+	return sidecar, nil
+}
+
+// validateSidecars returns an error if a sidecar shares its name with the main
+// container or another sidecar, as container names must be unique within a
+// task definition.
+func validateSidecars(mainContainerName string, sidecars []Sidecar) error {
+	names := map[string]bool{mainContainerName: true}
+
+	for _, sidecar := range sidecars {
+		if names[sidecar.Name] {
+			return fmt.Errorf("invalid sidecar %s: container names must be unique within a task", sidecar.Name)
+		}
+
+		names[sidecar.Name] = true
+	}
+
+	return nil
+}
+
+func (s Sidecar) containerDefinition(logConfiguration *awsecs.LogConfiguration) *awsecs.ContainerDefinition {
+	containerDefinition := &awsecs.ContainerDefinition{
+		Essential:        aws.Bool(s.Essential),
+		Image:            aws.String(s.Image),
+		LogConfiguration: logConfiguration,
+		Name:             aws.String(s.Name),
+	}
+
+	for _, envVar := range s.EnvVars {
+		containerDefinition.Environment = append(
+			containerDefinition.Environment,
+			&awsecs.KeyValuePair{
+				Name:  aws.String(envVar.Key),
+				Value: aws.String(envVar.Value),
+			},
+		)
+	}
+
+	if s.Port != 0 {
+		containerDefinition.SetPortMappings(
+			[]*awsecs.PortMapping{
+				&awsecs.PortMapping{
+					ContainerPort: aws.Int64(s.Port),
+				},
+			},
+		)
+	}
+
+	return containerDefinition
+}
+
+// summarizeContainerDefinitions describes each of a task definition's
+// containers, in the order they're defined.
+func summarizeContainerDefinitions(taskDefinition *awsecs.TaskDefinition) []ContainerDefinition {
+	var containers []ContainerDefinition
+
+	for _, c := range taskDefinition.ContainerDefinitions {
+		// Containers are essential unless the task definition says otherwise.
+		container := ContainerDefinition{
+			Essential: c.Essential == nil || aws.BoolValue(c.Essential),
+			Image:     aws.StringValue(c.Image),
+			Name:      aws.StringValue(c.Name),
+		}
+
+		for _, env := range c.Environment {
+			container.EnvVars = append(
+				container.EnvVars,
+				EnvVar{
+					Key:   aws.StringValue(env.Name),
+					Value: aws.StringValue(env.Value),
+				},
+			)
+		}
+
+		for _, portMapping := range c.PortMappings {
+			container.PortMappings = append(
+				container.PortMappings,
+				PortMapping{
+					ContainerPort: aws.Int64Value(portMapping.ContainerPort),
+					Protocol:      aws.StringValue(portMapping.Protocol),
+				},
+			)
+		}
+
+		containers = append(containers, container)
+	}
+
+	return containers
+}
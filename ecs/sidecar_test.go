@@ -0,0 +1,171 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"github.com/jpignata/fargate/ecs/mock/sdk"
+)
+
+func TestParseSidecar(t *testing.T) {
+	sidecar, err := ParseSidecar("nginx,image=nginx:1.25,port=80,env=UPSTREAM=localhost:8080,env=MODE=proxy,essential")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := Sidecar{
+		EnvVars: []EnvVar{
+			EnvVar{Key: "UPSTREAM", Value: "localhost:8080"},
+			EnvVar{Key: "MODE", Value: "proxy"},
+		},
+		Essential: true,
+		Image:     "nginx:1.25",
+		Name:      "nginx",
+		Port:      80,
+	}
+
+	if !reflect.DeepEqual(sidecar, expected) {
+		t.Errorf("expected %+v, got %+v", expected, sidecar)
+	}
+}
+
+func TestParseSidecarErrors(t *testing.T) {
+	var tests = []struct {
+		name       string
+		expression string
+	}{
+		{"no name", ",image=nginx"},
+		{"no image", "nginx,port=80"},
+		{"invalid port", "nginx,image=nginx,port=http"},
+		{"port out of range", "nginx,image=nginx,port=70000"},
+		{"invalid env", "nginx,image=nginx,env=UPSTREAM"},
+		{"unknown key", "nginx,image=nginx,cpu=256"},
+		{"not key=value", "nginx,image=nginx,proxy"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseSidecar(test.expression); err == nil {
+				t.Errorf("expected error for %q, got none", test.expression)
+			}
+		})
+	}
+}
+
+func TestValidateSidecars(t *testing.T) {
+	nginx := Sidecar{Name: "nginx", Image: "nginx"}
+	agent := Sidecar{Name: "agent", Image: "datadog/agent"}
+
+	if err := validateSidecars("web", []Sidecar{nginx, agent}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if err := validateSidecars("web", []Sidecar{nginx, nginx}); err == nil {
+		t.Error("expected error for duplicate sidecar, got none")
+	}
+
+	if err := validateSidecars("nginx", []Sidecar{nginx}); err == nil {
+		t.Error("expected error for sidecar named for the main container, got none")
+	}
+}
+
+func TestCreateTaskDefinitionSidecars(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	output := &awsecs.RegisterTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/service_web:1"),
+		},
+	}
+
+	mockECSClient.EXPECT().RegisterTaskDefinition(gomock.Any()).Do(
+		func(input *awsecs.RegisterTaskDefinitionInput) {
+			if len(input.ContainerDefinitions) != 2 {
+				t.Fatalf("expected 2 container definitions, got %d", len(input.ContainerDefinitions))
+			}
+
+			main, sidecar := input.ContainerDefinitions[0], input.ContainerDefinitions[1]
+
+			if aws.StringValue(main.Name) != "web" || !aws.BoolValue(main.Essential) {
+				t.Errorf("expected essential main container web first, got %s", aws.StringValue(main.Name))
+			}
+
+			if aws.StringValue(sidecar.Name) != "nginx" || aws.StringValue(sidecar.Image) != "nginx:1.25" {
+				t.Errorf("expected sidecar nginx:1.25, got %s %s", aws.StringValue(sidecar.Name), aws.StringValue(sidecar.Image))
+			}
+
+			if aws.BoolValue(sidecar.Essential) {
+				t.Error("expected sidecar to be non-essential")
+			}
+
+			if len(sidecar.PortMappings) != 1 || aws.Int64Value(sidecar.PortMappings[0].ContainerPort) != 80 {
+				t.Errorf("expected sidecar port 80, got %v", sidecar.PortMappings)
+			}
+
+			if sidecar.LogConfiguration == nil {
+				t.Error("expected sidecar to have a log configuration")
+			}
+		},
+	).Return(output, nil)
+
+	ecs.CreateTaskDefinition(
+		&CreateTaskDefinitionInput{
+			Cpu:    "256",
+			Image:  "web:latest",
+			Memory: "512",
+			Name:   "web",
+			Port:   8080,
+			Sidecars: []Sidecar{
+				Sidecar{Image: "nginx:1.25", Name: "nginx", Port: 80},
+			},
+			Type: "service",
+		},
+	)
+}
+
+func TestSummarizeContainerDefinitions(t *testing.T) {
+	taskDefinition := &awsecs.TaskDefinition{
+		ContainerDefinitions: []*awsecs.ContainerDefinition{
+			&awsecs.ContainerDefinition{
+				Image: aws.String("web:latest"),
+				Name:  aws.String("web"),
+				PortMappings: []*awsecs.PortMapping{
+					&awsecs.PortMapping{ContainerPort: aws.Int64(8080), Protocol: aws.String("tcp")},
+				},
+			},
+			&awsecs.ContainerDefinition{
+				Environment: []*awsecs.KeyValuePair{
+					&awsecs.KeyValuePair{Name: aws.String("UPSTREAM"), Value: aws.String("localhost:8080")},
+				},
+				Essential: aws.Bool(false),
+				Image:     aws.String("nginx:1.25"),
+				Name:      aws.String("nginx"),
+			},
+		},
+	}
+
+	expected := []ContainerDefinition{
+		ContainerDefinition{
+			Essential:    true,
+			Image:        "web:latest",
+			Name:         "web",
+			PortMappings: []PortMapping{PortMapping{ContainerPort: 8080, Protocol: "tcp"}},
+		},
+		ContainerDefinition{
+			EnvVars: []EnvVar{EnvVar{Key: "UPSTREAM", Value: "localhost:8080"}},
+			Image:   "nginx:1.25",
+			Name:    "nginx",
+		},
+	}
+
+	if containers := summarizeContainerDefinitions(taskDefinition); !reflect.DeepEqual(containers, expected) {
+		t.Errorf("expected %+v, got %+v", expected, containers)
+	}
+}
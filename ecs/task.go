@@ -1,267 +1,2395 @@
 package ecs
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	awsecs "github.com/aws/aws-sdk-go/service/ecs"
-	"github.com/jpignata/fargate/console"
 )
 
 const (
-	detailNetworkInterfaceId  = "networkInterfaceId"
-	detailSubnetId            = "subnetId"
-	startedByFormat           = "fargate:%s"
-	taskGroupStartedByPattern = "fargate:(.*)"
+	attachmentTypeEni        = "ElasticNetworkInterface"
+	detailNetworkInterfaceId = "networkInterfaceId"
+	detailPrivateIpAddress   = "privateIPv4Address"
+	detailSubnetId           = "subnetId"
+	eniStatusAvailable       = "available"
+	healthCheckPollInterval  = 5 * time.Second
+	idempotencyKeyTag        = "fargate:idempotency-key"
+	idempotencyWindow        = 15 * time.Minute
+	maxEphemeralStorageGiB   = 200
+	minEphemeralStorageGiB   = 21
+	defaultStartedByPrefix   = "fargate"
+	describeTasksBatchSize   = 100
+	redactedSecretValue      = "<secret>"
+	serviceStartedByPrefix   = "ecs-svc/"
+	startedByFormat          = "%s:%s"
+	stopTasksConcurrency     = 10
+	taskRunningPollInterval  = 5 * time.Second
 )
 
+var (
+	platformVersionRegexp = regexp.MustCompile(`^(LATEST|\d+\.\d+\.\d+)$`)
+
+	// taskGroupStartedByRegexp matches the startedBy of a task in a task group,
+	// capturing the prefix and the task group name.
+	taskGroupStartedByRegexp = regexp.MustCompile(`^([^:]+):(.+)$`)
+)
+
+const (
+	StopCategoryEssentialContainerExited = "essential container exited"
+	StopCategoryImagePullFailure         = "image pull failed"
+	StopCategoryOther                    = "other"
+	StopCategoryOutOfMemory              = "out of memory"
+	StopCategorySucceeded                = "succeeded"
+	StopCategoryUserInitiated            = "user initiated"
+)
+
+type Container struct {
+	Essential    bool   `json:"essential"`
+	ExitCode     *int64 `json:"exit_code"`
+	HealthStatus string `json:"health_status"`
+	Image        string `json:"image"`
+	LastStatus   string `json:"last_status"`
+	Name         string `json:"name"`
+	Reason       string `json:"reason"`
+	RuntimeId    string `json:"runtime_id"`
+}
+
+type PortMapping struct {
+	ContainerPort int64  `json:"container_port"`
+	Protocol      string `json:"protocol"`
+}
+
 type Task struct {
-	Cpu              string
-	CreatedAt        time.Time
-	DeploymentId     string
-	DesiredStatus    string
-	EniId            string
-	EnvVars          []EnvVar
-	Image            string
-	LastStatus       string
-	Memory           string
-	SecurityGroupIds []string
-	StartedBy        string
-	SubnetId         string
-	Command          []string
-	TaskId           string
-	TaskRole         string
+	AttachmentStatus       string            `json:"attachment_status"`
+	CapacityProviderName   string            `json:"capacity_provider_name"`
+	Connectivity           string            `json:"connectivity"`
+	ConnectivityAt         *time.Time        `json:"connectivity_at,omitempty"`
+	Containers             []Container       `json:"containers"`
+	Cpu                    string            `json:"cpu"`
+	CreatedAt              time.Time         `json:"created_at"`
+	DeploymentId           string            `json:"deployment_id"`
+	DesiredStatus          string            `json:"desired_status"`
+	EniId                  string            `json:"eni_id"`
+	EnvVars                []EnvVar          `json:"env_vars"`
+	EphemeralStorageGiB    int64             `json:"ephemeral_storage_gib"`
+	ExecuteCommandEnabled  bool              `json:"execute_command_enabled"`
+	Group                  string            `json:"group"`
+	HealthStatus           string            `json:"health_status"`
+	Image                  string            `json:"image"`
+	LastStatus             string            `json:"last_status"`
+	LaunchType             string            `json:"launch_type"`
+	Memory                 string            `json:"memory"`
+	NetworkValid           bool              `json:"network_valid"`
+	NoPublicIp             bool              `json:"no_public_ip"`
+	PlatformVersion        string            `json:"platform_version"`
+	PortMappings           []PortMapping     `json:"port_mappings"`
+	PrivateIpAddress       string            `json:"private_ip_address"`
+	PublicDnsName          string            `json:"public_dns_name"`
+	PublicIpAddress        string            `json:"public_ip_address"`
+	Secrets                []string          `json:"secrets"`
+	StopCategory           string            `json:"stop_category"`
+	StopCode               string            `json:"stop_code"`
+	StoppedAt              time.Time         `json:"stopped_at"`
+	StoppedReason          string            `json:"stopped_reason"`
+	SecurityGroupIds       []string          `json:"security_group_ids"`
+	StartedBy              string            `json:"started_by"`
+	SubnetId               string            `json:"subnet_id"`
+	Tags                   map[string]string `json:"tags"`
+	Command                []string          `json:"command"`
+	TaskArn                string            `json:"task_arn"`
+	TaskDefinitionArn      string            `json:"task_definition_arn"`
+	TaskDefinitionFamily   string            `json:"task_definition_family"`
+	TaskDefinitionRevision int64             `json:"task_definition_revision"`
+	TaskId                 string            `json:"task_id"`
+	TaskRole               string            `json:"task_role"`
+
+	// envVarOverrides are the environment variables the task was run with as
+	// container overrides, as opposed to those of its task definition which
+	// EnvVars also includes.
+	envVarOverrides []EnvVar
+}
+
+// MarshalTasks serializes tasks as an indented JSON array. An empty or nil
+// slice is serialized as an empty array rather than null.
+func MarshalTasks(tasks []Task) ([]byte, error) {
+	if tasks == nil {
+		tasks = []Task{}
+	}
+
+	return json.MarshalIndent(tasks, "", "  ")
+}
+
+// Stopped returns whether the task has stopped.
+func (t Task) Stopped() bool {
+	return t.LastStatus == awsecs.DesiredStatusStopped
+}
+
+// ExitCodes describes the exit codes of a stopped task's containers [e.g. 1, or
+// web=1 nginx=0 for a task with sidecars]. Containers which exited without a
+// code, such as those which never started, are shown with a dash.
+func (t Task) ExitCodes() string {
+	var codes []string
+
+	for _, container := range t.Containers {
+		code := "-"
+
+		if container.ExitCode != nil {
+			code = strconv.FormatInt(*container.ExitCode, 10)
+		}
+
+		if len(t.Containers) == 1 {
+			return code
+		}
+
+		codes = append(codes, fmt.Sprintf("%s=%s", container.Name, code))
+	}
+
+	return strings.Join(codes, " ")
+}
+
+// StopSummary describes why a stopped task stopped, leading with its stop
+// category [e.g. "image pull failed: CannotPullContainerError: ..."].
+func (t Task) StopSummary() string {
+	if t.StopCategory == "" {
+		return ""
+	}
+
+	reason := t.StoppedReason
+
+	for _, container := range t.Containers {
+		if container.Reason != "" {
+			reason = container.Reason
+			break
+		}
+	}
+
+	if reason == "" {
+		return t.StopCategory
+	}
+
+	return fmt.Sprintf("%s: %s", t.StopCategory, reason)
+}
+
+// usesImage returns whether any of the task's containers run the image, or
+// an image containing it if exact isn't set.
+func (t *Task) usesImage(image string, exact bool) bool {
+	images := []string{t.Image}
+
+	for _, container := range t.Containers {
+		images = append(images, container.Image)
+	}
+
+	for _, candidate := range images {
+		if candidate == "" {
+			continue
+		}
+
+		if candidate == image || (!exact && strings.Contains(candidate, image)) {
+			return true
+		}
+	}
+
+	return false
 }
 
+// RunningFor returns how long a task has been running or, for a stopped
+// task, how long it ran before stopping.
 func (t *Task) RunningFor() time.Duration {
-	return time.Now().Sub(t.CreatedAt).Truncate(time.Second)
+	end := time.Now()
+
+	if !t.StoppedAt.IsZero() {
+		end = t.StoppedAt
+	}
+
+	return end.Sub(t.CreatedAt).Truncate(time.Second)
+}
+
+// RunningContainers returns how many of the task's containers are running. A
+// task with sidecars keeps running when a non-essential container stops, so
+// this can be fewer than its number of containers.
+func (t Task) RunningContainers() int {
+	var running int
+
+	for _, container := range t.Containers {
+		if container.LastStatus == awsecs.DesiredStatusRunning {
+			running++
+		}
+	}
+
+	return running
+}
+
+func (t Task) EffectiveEnv() map[string]string {
+	env := make(map[string]string)
+
+	for _, envVar := range t.EnvVars {
+		if _, ok := env[envVar.Key]; !ok {
+			env[envVar.Key] = envVar.Value
+		}
+	}
+
+	for _, secret := range t.Secrets {
+		env[secret] = redactedSecretValue
+	}
+
+	return env
+}
+
+// TaskFilter narrows the tasks returned when listing tasks. DesiredStatus is
+// passed through to ECS (RUNNING, PENDING, or STOPPED) and LastStatuses is
+// applied to the results. The zero value matches everything ECS lists by
+// default, which excludes stopped tasks; set IncludeStopped to list recently
+// stopped tasks as well.
+type TaskFilter struct {
+	DesiredStatus  string
+	IncludeStopped bool
+	LastStatuses   []string
+}
+
+func (f TaskFilter) Matches(task Task) bool {
+	if f.DesiredStatus != "" && task.DesiredStatus != f.DesiredStatus {
+		return false
+	}
+
+	if len(f.LastStatuses) == 0 {
+		return true
+	}
+
+	for _, lastStatus := range f.LastStatuses {
+		if task.LastStatus == lastStatus {
+			return true
+		}
+	}
+
+	return false
+}
+
+type NetworkResourceFinder interface {
+	FindSubnetIDs([]string) ([]string, error)
+	FindSecurityGroupIDs([]string) ([]string, error)
+}
+
+// EgressChecker reports whether any of the given security groups allow the
+// outbound traffic a task needs to pull its image.
+type EgressChecker interface {
+	SecurityGroupsAllowEgress([]string) (bool, error)
+}
+
+// NetworkInterfaceLister returns the status of each network interface created
+// by ECS for a task, keyed by network interface ID.
+type NetworkInterfaceLister interface {
+	ListTaskNetworkInterfaces() (map[string]string, error)
+}
+
+// NetworkInterfaceFinder returns the security group IDs and public IP of each
+// of the given network interfaces, keyed by network interface ID. Interfaces
+// without a public IP have an empty one.
+type NetworkInterfaceFinder interface {
+	FindNetworkInterfaces([]string) (map[string][]string, map[string]string, error)
+}
+
+// OrphanedEni is a network interface left behind by a task which is no longer
+// running. TaskId is empty if the task it belonged to is no longer known.
+type OrphanedEni struct {
+	EniId  string
+	Status string
+	TaskId string
+}
+
+// TargetFinder looks up the state of a target within a load balancer target
+// group, returning an empty state if the target isn't registered.
+type TargetFinder interface {
+	FindTargetState(targetGroupArn, targetId string, port int64) (string, error)
+}
+
+// TargetRegistration is a task's registration in a target group on one of its
+// container ports. State is the target's health state [e.g. healthy,
+// draining].
+type TargetRegistration struct {
+	Port           int64
+	State          string
+	TargetGroupArn string
+}
+
+type Tag struct {
+	Key   string
+	Value string
+}
+
+type TaskRestart struct {
+	Err       error
+	NewTaskId string
+	TaskId    string
 }
 
 type TaskGroup struct {
 	TaskGroupName string
 	Instances     int64
+	Stopped       int64
+	Families      []string
+}
+
+func (t *TaskGroup) AddFamily(family string) {
+	if family == "" {
+		return
+	}
+
+	for _, f := range t.Families {
+		if f == family {
+			return
+		}
+	}
+
+	t.Families = append(t.Families, family)
+}
+
+// ListTaskGroupsInput configures ListTaskGroups. Prefix, if set, limits the
+// results to task groups whose names start with it.
+type ListTaskGroupsInput struct {
+	Prefix string
+}
+
+// StopCategoryCount is the number of a task group's stopped tasks which fall
+// into a stop category, along with the ID of one of them to investigate.
+type StopCategoryCount struct {
+	Category     string
+	Count        int
+	SampleTaskId string
+}
+
+// StaleTask is a running task which isn't on the latest active revision of
+// its task definition family.
+type StaleTask struct {
+	Image    string
+	Revision int64
+	TaskId   string
+}
+
+// TaskDefinitionDrift compares the running tasks of one task definition
+// family within a task group against the family's latest active revision.
+type TaskDefinitionDrift struct {
+	Family         string
+	LatestImage    string
+	LatestRevision int64
+	StaleTasks     []StaleTask
+	TaskCount      int
+}
+
+// StopAllTasksInput configures StopAllTasks. Confirm must be set for any
+// tasks to be stopped. Tasks managed by a service are left alone unless
+// IncludeServiceTasks is set, as ECS will replace them anyway.
+type StopAllTasksInput struct {
+	Confirm             bool
+	IncludeServiceTasks bool
+	Reason              string
+}
+
+// StopAllTasksResult accounts for the tasks considered by StopAllTasks,
+// separating tasks started directly (e.g. by this tool) from tasks started by
+// a service. Errors holds an error for each task which couldn't be stopped.
+type StopAllTasksResult struct {
+	Errors                []error
+	ServiceTaskIds        []string
+	SkippedServiceTaskIds []string
+	TaskIds               []string
+}
+
+// Stopped returns the number of tasks which were successfully stopped.
+func (r StopAllTasksResult) Stopped() int {
+	return len(r.TaskIds) + len(r.ServiceTaskIds) - len(r.Errors)
 }
 
 type RunTaskInput struct {
-	ClusterName       string
-	Count             int64
-	Command           []string
-	EnvVars           []EnvVar
-	SecurityGroupIds  []string
-	SubnetIds         []string
-	TaskDefinitionArn string
-	TaskName          string
+	AppendArgs           []string
+	CapacityProviders    []CapacityProviderStrategyItem
+	ClusterName          string
+	Count                int64
+	Command              []string
+	CommandTemplate      *CommandTemplate
+	ContainerName        string
+	Cpu                  string
+	DnsSearchDomains     []string
+	DnsServers           []string
+	EgressChecker        EgressChecker
+	EntryPoint           []string
+	EnvVars              []EnvVar
+	EnableExecuteCommand bool
+	EphemeralStorageGiB  int64
+	IdempotencyKey       string
+	LaunchType           string
+	Memory               string
+	MemoryReservation    int64
+	NoPublicIp           bool
+	PlacementConstraints []string
+	PlatformVersion      string
+	Secrets              []Secret
+	SecurityGroupIds     []string
+	SpreadAcrossSubnets  bool
+	SubnetIds            []string
+	SubnetPlacements     []SubnetPlacement
+	TaskDefinitionArn    string
+	TaskName             string
 }
 
-func (ecs *ECS) RunTask(i *RunTaskInput) {
-	runTaskInput := &awsecs.RunTaskInput{
-		Cluster:        aws.String(i.ClusterName),
-		Count:          aws.Int64(i.Count),
-		TaskDefinition: aws.String(i.TaskDefinitionArn),
-		LaunchType:     aws.String(awsecs.CompatibilityFargate),
-		StartedBy:      aws.String(fmt.Sprintf(startedByFormat, i.TaskName)),
-		NetworkConfiguration: &awsecs.NetworkConfiguration{
-			AwsvpcConfiguration: &awsecs.AwsVpcConfiguration{
-				AssignPublicIp: aws.String(awsecs.AssignPublicIpEnabled),
-				Subnets:        aws.StringSlice(i.SubnetIds),
-				SecurityGroups: aws.StringSlice(i.SecurityGroupIds),
-			},
-		},
-		Overrides: &awsecs.TaskOverride{
-			ContainerOverrides: []*awsecs.ContainerOverride{},
-		},
+// SubnetPlacement pins Count of a run's tasks to the subnet SubnetId. When a
+// RunTaskInput has subnet placements, its SubnetIds are ignored and a separate
+// RunTask call is made per placement.
+type SubnetPlacement struct {
+	Count    int64
+	SubnetId string
+}
+
+// Validate checks the input for the most common misconfigurations so they
+// can be reported before any request is sent to ECS. Tasks on Fargate must be
+// placed in at least one subnet; EC2 tasks may not use awsvpc networking and so
+// are allowed to omit subnets.
+func (i *RunTaskInput) Validate() error {
+	if i.TaskDefinitionArn == "" {
+		return fmt.Errorf("task definition is required")
+	}
+
+	if i.Count < 1 {
+		return fmt.Errorf("invalid count %d: must be > 0", i.Count)
+	}
+
+	if len(i.SubnetIds) == 0 && len(i.SubnetPlacements) == 0 && (i.LaunchType == "" || i.LaunchType == awsecs.LaunchTypeFargate) {
+		return fmt.Errorf("at least one subnet is required")
+	}
+
+	if len(i.SubnetPlacements) > 0 {
+		if err := i.validateSubnetPlacements(); err != nil {
+			return err
+		}
+	}
+
+	if len(i.Command) > 0 && len(i.AppendArgs) > 0 {
+		return fmt.Errorf("a command and arguments to append to the container's command cannot both be given")
+	}
+
+	if (len(i.DnsServers) > 0 || len(i.DnsSearchDomains) > 0) && (i.LaunchType == "" || i.LaunchType == awsecs.LaunchTypeFargate) {
+		return fmt.Errorf("DNS servers and search domains are not supported with the %s launch type; configure them with the VPC's DHCP options or Route 53 Resolver rules instead", awsecs.LaunchTypeFargate)
+	}
+
+	if i.PlatformVersion != "" {
+		if i.LaunchType == awsecs.LaunchTypeEc2 {
+			return fmt.Errorf("a platform version is not supported with the %s launch type", awsecs.LaunchTypeEc2)
+		}
+
+		if err := ValidatePlatformVersion(i.PlatformVersion); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateEphemeralStorage returns an error unless the amount of ephemeral
+// storage, in GiB, is within the range Fargate supports.
+func ValidateEphemeralStorage(sizeGiB int64) error {
+	if sizeGiB < minEphemeralStorageGiB || sizeGiB > maxEphemeralStorageGiB {
+		return fmt.Errorf("invalid ephemeral storage size %d GiB: must be between %d and %d GiB", sizeGiB, minEphemeralStorageGiB, maxEphemeralStorageGiB)
+	}
+
+	return nil
+}
+
+// ValidatePlatformVersion returns an error unless the Fargate platform version
+// is LATEST or a version number [e.g. 1.4.0].
+func ValidatePlatformVersion(platformVersion string) error {
+	if !platformVersionRegexp.MatchString(platformVersion) {
+		return fmt.Errorf("invalid platform version %s: must be LATEST or a version number [e.g. 1.4.0]", platformVersion)
+	}
+
+	return nil
+}
+
+func (i *RunTaskInput) validateSubnetPlacements() error {
+	var total int64
+
+	if i.SpreadAcrossSubnets {
+		return fmt.Errorf("subnet placements and spreading tasks across subnets cannot both be used")
+	}
+
+	seen := make(map[string]bool)
+
+	for _, placement := range i.SubnetPlacements {
+		if placement.SubnetId == "" {
+			return fmt.Errorf("subnet placement is missing a subnet")
+		}
+
+		if placement.Count < 1 {
+			return fmt.Errorf("invalid count %d for subnet %s: must be > 0", placement.Count, placement.SubnetId)
+		}
+
+		if seen[placement.SubnetId] {
+			return fmt.Errorf("subnet %s is placed more than once", placement.SubnetId)
+		}
+
+		seen[placement.SubnetId] = true
+		total += placement.Count
+	}
+
+	if total != i.Count {
+		return fmt.Errorf("subnet placements add up to %d tasks, but %d are to be run", total, i.Count)
+	}
+
+	return nil
+}
+
+// resolveContainerName sets the name of the container that command,
+// environment, secrets, and entry point overrides apply to, returning an error
+// if the task definition has no such container. If no container name is
+// given, the container named after the task is used for backward
+// compatibility, or else the task definition's first container.
+func (i *RunTaskInput) resolveContainerName(taskDefinition *awsecs.TaskDefinition) error {
+	if i.ContainerName == "" && i.TaskName != "" {
+		for _, containerDefinition := range taskDefinition.ContainerDefinitions {
+			if aws.StringValue(containerDefinition.Name) == i.TaskName {
+				i.ContainerName = i.TaskName
+				return nil
+			}
+		}
+	}
+
+	index, err := containerDefinitionIndex(taskDefinition, i.ContainerName)
+
+	if err != nil {
+		return err
+	}
+
+	i.ContainerName = aws.StringValue(taskDefinition.ContainerDefinitions[index].Name)
+
+	return nil
+}
+
+func (ecs *ECS) RunTask(i *RunTaskInput) []string {
+	taskIds, err := ecs.runTask(i)
+
+	if err != nil {
+		ecs.errorExit(err, "Could not run ECS task")
+	}
+
+	return taskIds
+}
+
+// RunTaskBalanced runs tasks in whichever of the given clusters has the fewest
+// tasks, returning its name along with the IDs of the tasks started. Clusters
+// whose tasks can't be counted are skipped.
+func RunTaskBalanced(clusters []*ECS, i *RunTaskInput) (string, []string, error) {
+	var chosen *ECS
+	var errs []string
+
+	if len(clusters) == 0 {
+		return "", nil, errors.New("no clusters given to run tasks in")
+	}
+
+	minTaskCount := -1
+
+	for _, cluster := range clusters {
+		taskCount, err := cluster.countTasks()
+
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", cluster.ClusterName, err))
+			continue
+		}
+
+		if minTaskCount == -1 || taskCount < minTaskCount {
+			chosen = cluster
+			minTaskCount = taskCount
+		}
+	}
+
+	if chosen == nil {
+		return "", nil, fmt.Errorf("no available clusters: %s", strings.Join(errs, ", "))
+	}
+
+	input := *i
+	input.ClusterName = chosen.ClusterName
+
+	taskIds, err := chosen.runTask(&input)
+
+	return chosen.ClusterName, taskIds, err
+}
+
+func (ecs *ECS) runTask(i *RunTaskInput) ([]string, error) {
+	// Check everything which could fail before registering any new revisions
+	// of the task definition, so a failed run doesn't leave revisions behind.
+	taskDefinition, err := ecs.prepareRunTask(i, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if i.EgressChecker != nil {
+		ecs.checkEgress(i.EgressChecker, i.SecurityGroupIds)
+	}
+
+	if i.IdempotencyKey != "" {
+		taskIds, err := ecs.findTaskIdsByIdempotencyKey(i.ClusterName, i.TaskName, i.IdempotencyKey)
+
+		if err != nil {
+			return taskIds, fmt.Errorf("could not check for existing tasks: %v", err)
+		}
+
+		if len(taskIds) > 0 {
+			ecs.logger().Debug("Found existing tasks for idempotency key %s [%s]", i.IdempotencyKey, strings.Join(taskIds, ", "))
+			return taskIds, nil
+		}
+	}
+
+	if len(i.Secrets) > 0 {
+		taskDefinitionArn, err := ecs.AddSecretsToTaskDefinition(i.TaskDefinitionArn, i.ContainerName, i.Secrets)
+
+		if err != nil {
+			return nil, err
+		}
+
+		i.TaskDefinitionArn = taskDefinitionArn
+	}
+
+	if len(i.EntryPoint) > 0 {
+		taskDefinitionArn, err := ecs.SetTaskDefinitionEntryPoint(i.TaskDefinitionArn, i.ContainerName, i.EntryPoint)
+
+		if err != nil {
+			return nil, err
+		}
+
+		i.TaskDefinitionArn = taskDefinitionArn
+	}
+
+	if len(i.DnsServers) > 0 || len(i.DnsSearchDomains) > 0 {
+		taskDefinitionArn, err := ecs.SetTaskDefinitionDns(i.TaskDefinitionArn, i.ContainerName, i.DnsServers, i.DnsSearchDomains)
+
+		if err != nil {
+			return nil, err
+		}
+
+		i.TaskDefinitionArn = taskDefinitionArn
+	}
+
+	// The revisions registered above only change the container's secrets,
+	// entry point, and DNS settings, so the described task definition still
+	// serves to build the requests.
+	if inputs := splitRunTaskInput(i); len(inputs) > 0 {
+		return ecs.startTasksAcrossSubnets(i, inputs, taskDefinition)
+	}
+
+	return ecs.startTasks(i, taskDefinition)
+}
+
+// prepareRunTask validates the input, resolves its task definition and the
+// container overrides apply to, and checks that the task definition supports
+// the run's settings, without making any changes. taskDefinition, if given,
+// stands in for the input's task definition; otherwise the task definition is
+// resolved and described.
+func (ecs *ECS) prepareRunTask(i *RunTaskInput, taskDefinition *awsecs.TaskDefinition) (*awsecs.TaskDefinition, error) {
+	if err := i.Validate(); err != nil {
+		return nil, err
+	}
+
+	if taskDefinition == nil {
+		taskDefinitionArn, err := ecs.ResolveTaskDefinitionArn(i.TaskDefinitionArn)
+
+		if err != nil {
+			return nil, err
+		}
+
+		i.TaskDefinitionArn = taskDefinitionArn
+
+		if taskDefinition, err = ecs.describeTaskDefinition(taskDefinitionArn); err != nil {
+			return nil, fmt.Errorf("could not describe task definition %s: %v", taskDefinitionArn, err)
+		}
+	}
+
+	if err := i.resolveContainerName(taskDefinition); err != nil {
+		return nil, err
+	}
+
+	if len(i.DnsServers) > 0 || len(i.DnsSearchDomains) > 0 {
+		if err := validateTaskDefinitionDns(taskDefinition); err != nil {
+			return nil, err
+		}
+	}
+
+	return taskDefinition, nil
+}
+
+// RunTaskToCompletion runs a single task, blocks until it stops, and returns
+// the exit code of its primary container: the container overrides apply to,
+// or the task's first container. Count must be 1; to run several tasks to
+// completion, call this once per task.
+//
+// If follow is given, it's called with the ID of the started task, for
+// example to stream its logs, and should return once the task has stopped.
+// If ctx's deadline passes before the task stops, the task is stopped. If ctx
+// is canceled instead, such as when the user interrupts the CLI, the task is
+// left running. Either way, the returned error names the task.
+func (ecs *ECS) RunTaskToCompletion(ctx context.Context, i *RunTaskInput, follow func(taskId string) error) (int, error) {
+	if i.Count != 1 {
+		return 0, fmt.Errorf("invalid count %d: only a single task can be run to completion", i.Count)
+	}
+
+	taskIds, err := ecs.runTask(i)
+
+	if err != nil {
+		return 0, err
+	}
+
+	taskId := taskIds[0]
+
+	if follow != nil {
+		if err := follow(taskId); err != nil {
+			ecs.logger().Debug("Could not follow task %s: %v", taskId, err)
+		}
+	}
+
+	// With no maximum number of attempts, the waiter polls until the task
+	// stops or the context is done.
+	err = ecs.svc.WaitUntilTasksStoppedWithContext(
+		ctx,
+		&awsecs.DescribeTasksInput{
+			Cluster: aws.String(ecs.ClusterName),
+			Tasks:   aws.StringSlice(taskIds),
+		},
+		request.WithWaiterMaxAttempts(0),
+	)
+
+	if err != nil {
+		switch ctx.Err() {
+		case context.DeadlineExceeded:
+			if stopErr := ecs.stopTask(taskId, "Timed out waiting for the task to stop"); stopErr != nil {
+				return 0, fmt.Errorf("task %s did not stop in time and could not be stopped: %v", taskId, stopErr)
+			}
+
+			return 0, fmt.Errorf("task %s did not stop in time and was stopped", taskId)
+		case context.Canceled:
+			return 0, fmt.Errorf("stopped waiting for task %s, which is still running", taskId)
+		}
+
+		return 0, fmt.Errorf("could not wait for task %s to stop: %v", taskId, err)
+	}
+
+	task, err := ecs.DescribeTask(taskId)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(task.Containers) == 0 {
+		return 0, fmt.Errorf("task %s has no containers", taskId)
+	}
+
+	container := task.Containers[0]
+
+	for _, c := range task.Containers {
+		if c.Name == i.ContainerName {
+			container = c
+			break
+		}
+	}
+
+	if container.ExitCode == nil {
+		return 0, fmt.Errorf("task %s stopped without an exit code for container %s: %s", taskId, container.Name, task.StopSummary())
+	}
+
+	return int(*container.ExitCode), nil
+}
+
+// WaitUntilContainerHealthy polls until the named container within a task
+// reports a HEALTHY status. This is useful where the task's aggregate health
+// doesn't reflect a particular container, such as when a sidecar is slower to
+// start or has no health check of its own.
+//
+// An error is returned straight away if the container has no health check, as
+// it would never become healthy, and if the container becomes UNHEALTHY or the
+// task stops while waiting.
+func (ecs *ECS) WaitUntilContainerHealthy(taskId, containerName string, timeout time.Duration) error {
+	var checked bool
+
+	if containerName == "" {
+		return fmt.Errorf("container name must not be empty")
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		task, err := ecs.DescribeTask(taskId)
+
+		if err != nil {
+			return err
+		}
+
+		if !checked {
+			if err := ecs.checkContainerHealthCheck(task.TaskDefinitionArn, containerName); err != nil {
+				return err
+			}
+
+			checked = true
+		}
+
+		healthStatus := ""
+
+		for _, container := range task.Containers {
+			if container.Name == containerName {
+				healthStatus = container.HealthStatus
+				break
+			}
+		}
+
+		switch {
+		case healthStatus == awsecs.HealthStatusHealthy:
+			return nil
+		case healthStatus == awsecs.HealthStatusUnhealthy:
+			return fmt.Errorf("container %s in task %s is unhealthy", containerName, task.TaskId)
+		case task.LastStatus == awsecs.DesiredStatusStopped:
+			return fmt.Errorf("task %s stopped before container %s became healthy: %s", task.TaskId, containerName, task.StopSummary())
+		}
+
+		if time.Now().Add(healthCheckPollInterval).After(deadline) {
+			return fmt.Errorf("container %s in task %s not healthy after %s", containerName, task.TaskId, timeout)
+		}
+
+		time.Sleep(healthCheckPollInterval)
+	}
+}
+
+// WaitForTasksRunning polls the tasks until all of them are RUNNING. An error
+// is returned straight away if any of the tasks stops, with why it stopped, or
+// once the timeout elapses, naming the tasks which are still pending. Tasks
+// which can't be found yet, as newly started tasks may not be visible
+// straight away, are treated as pending.
+func (ecs *ECS) WaitForTasksRunning(taskIds []string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		var pending []string
+
+		found := make(map[string]bool)
+
+		for _, task := range ecs.DescribeTasks(taskIds) {
+			found[task.TaskId] = true
+
+			switch task.LastStatus {
+			case awsecs.DesiredStatusRunning:
+			case awsecs.DesiredStatusStopped:
+				return fmt.Errorf("task %s stopped before it was running: %s", task.TaskId, task.StopSummary())
+			default:
+				pending = append(pending, fmt.Sprintf("%s (%s)", task.TaskId, strings.ToLower(task.LastStatus)))
+			}
+		}
+
+		for _, taskId := range taskIds {
+			if !found[taskId] {
+				pending = append(pending, fmt.Sprintf("%s (not found)", taskId))
+			}
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		if time.Now().Add(taskRunningPollInterval).After(deadline) {
+			return fmt.Errorf("tasks not running after %s: %s", timeout, strings.Join(pending, ", "))
+		}
+
+		time.Sleep(taskRunningPollInterval)
+	}
+}
+
+// checkContainerHealthCheck returns an error unless the named container has a
+// health check defined in the task definition.
+func (ecs *ECS) checkContainerHealthCheck(taskDefinitionArn, containerName string) error {
+	taskDefinition, err := ecs.describeTaskDefinition(taskDefinitionArn)
+
+	if err != nil {
+		return fmt.Errorf("could not describe task definition %s: %v", taskDefinitionArn, err)
+	}
+
+	index, err := containerDefinitionIndex(taskDefinition, containerName)
+
+	if err != nil {
+		return err
+	}
+
+	if taskDefinition.ContainerDefinitions[index].HealthCheck == nil {
+		return fmt.Errorf("container %s has no health check defined in task definition %s", containerName, taskDefinitionArn)
+	}
+
+	return nil
+}
+
+// checkEgress warns if none of the security groups allow outbound traffic
+// sufficient to pull an image, as the task would otherwise start and then fail
+// with a CannotPullContainerError. The run isn't blocked as the image may be
+// reachable some other way, such as through a VPC endpoint.
+func (ecs *ECS) checkEgress(checker EgressChecker, securityGroupIds []string) {
+	if len(securityGroupIds) == 0 {
+		return
+	}
+
+	allowed, err := checker.SecurityGroupsAllowEgress(securityGroupIds)
+
+	if err != nil {
+		ecs.logger().Debug("Could not check security group egress: %v", err)
+		return
+	}
+
+	if !allowed {
+		ecs.logger().Warn("None of the security groups [%s] allow outbound HTTPS traffic to 0.0.0.0/0", strings.Join(securityGroupIds, ", "))
+		ecs.logger().Info("Tasks may fail to pull their image unless it's reachable through a VPC endpoint")
+	}
+}
+
+// startTasksAcrossSubnets starts each subnet's share of the task count with a
+// separate RunTask call. Failures are collected so that tasks started in other
+// subnets are still returned.
+func (ecs *ECS) startTasksAcrossSubnets(i *RunTaskInput, inputs []*RunTaskInput, taskDefinition *awsecs.TaskDefinition) ([]string, error) {
+	var taskIds, errs []string
+
+	for _, input := range inputs {
+		subnetTaskIds, err := ecs.startTasks(input, taskDefinition)
+		taskIds = append(taskIds, subnetTaskIds...)
+
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", input.SubnetIds[0], err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return taskIds, fmt.Errorf("%d of %d tasks failed to start: %s", i.Count-int64(len(taskIds)), i.Count, strings.Join(errs, ", "))
+	}
+
+	return taskIds, nil
+}
+
+// BuildRunTaskInputs assembles the RunTask requests that RunTask would send
+// for the given input without starting any tasks or registering any task
+// definitions, for use as a dry run. A request is returned per subnet when
+// spreading tasks across subnets or when placing them in specific subnets.
+// Secrets, entry point, and DNS overrides, which require registering a new
+// task definition revision, aren't reflected in the requests.
+//
+// taskDefinition, if given, stands in for the input's task definition, such
+// as one the caller has yet to register. Otherwise the task definition is
+// resolved and described as RunTask would.
+func (ecs *ECS) BuildRunTaskInputs(i *RunTaskInput, taskDefinition *awsecs.TaskDefinition) ([]*awsecs.RunTaskInput, error) {
+	var runTaskInputs []*awsecs.RunTaskInput
+
+	// The input is resolved on a copy, leaving the caller's input as it was
+	// given.
+	resolved := *i
+
+	taskDefinition, err := ecs.prepareRunTask(&resolved, taskDefinition)
+
+	if err != nil {
+		return runTaskInputs, err
+	}
+
+	inputs := splitRunTaskInput(&resolved)
+
+	if len(inputs) == 0 {
+		inputs = []*RunTaskInput{&resolved}
+	}
+
+	for _, input := range inputs {
+		runTaskInput, err := ecs.buildRunTaskInput(input, taskDefinition)
+
+		if err != nil {
+			return runTaskInputs, err
+		}
+
+		runTaskInputs = append(runTaskInputs, runTaskInput)
+	}
+
+	return runTaskInputs, nil
+}
+
+func (ecs *ECS) startTasks(i *RunTaskInput, taskDefinition *awsecs.TaskDefinition) ([]string, error) {
+	var taskIds []string
+
+	runTaskInput, err := ecs.buildRunTaskInput(i, taskDefinition)
+
+	if err != nil {
+		return taskIds, err
+	}
+
+	resp, err := ecs.svc.RunTask(runTaskInput)
+
+	if err != nil {
+		return taskIds, err
+	}
+
+	for _, t := range resp.Tasks {
+		taskIds = append(taskIds, taskIdFromArn(aws.StringValue(t.TaskArn)))
+	}
+
+	if len(resp.Failures) > 0 {
+		return taskIds, fmt.Errorf("%d of %d tasks failed to start: %s", len(resp.Failures), i.Count, aws.StringValue(resp.Failures[0].Reason))
+	}
+
+	return taskIds, nil
+}
+
+// buildRunTaskInput assembles a RunTask request from an input whose container
+// name has been resolved against the given task definition.
+func (ecs *ECS) buildRunTaskInput(i *RunTaskInput, taskDefinition *awsecs.TaskDefinition) (*awsecs.RunTaskInput, error) {
+	runTaskInput := &awsecs.RunTaskInput{
+		Cluster:        aws.String(i.ClusterName),
+		Count:          aws.Int64(i.Count),
+		TaskDefinition: aws.String(i.TaskDefinitionArn),
+		LaunchType:     aws.String(awsecs.LaunchTypeFargate),
+		StartedBy:      aws.String(ecs.startedBy(i.TaskName)),
+		Overrides: &awsecs.TaskOverride{
+			ContainerOverrides: []*awsecs.ContainerOverride{},
+		},
+	}
+
+	// Task-level CPU and memory overrides resize a single run without
+	// registering a new revision of the task definition.
+	if i.Cpu != "" {
+		runTaskInput.Overrides.Cpu = aws.String(i.Cpu)
+	}
+
+	if i.Memory != "" {
+		runTaskInput.Overrides.Memory = aws.String(i.Memory)
+	}
+
+	switch i.LaunchType {
+	case "", awsecs.LaunchTypeFargate:
+		if len(i.PlacementConstraints) > 0 {
+			return nil, fmt.Errorf("placement constraints are not supported with the %s launch type", awsecs.LaunchTypeFargate)
+		}
+
+		if i.MemoryReservation != 0 {
+			return nil, fmt.Errorf("memory reservation is not supported with the %s launch type, which reserves the task's full memory", awsecs.LaunchTypeFargate)
+		}
+
+		if i.Cpu != "" && i.Memory != "" {
+			if err := ValidateCpuAndMemory(i.Cpu, i.Memory); err != nil {
+				return nil, err
+			}
+		}
+
+		if i.EphemeralStorageGiB != 0 {
+			if err := ValidateEphemeralStorage(i.EphemeralStorageGiB); err != nil {
+				return nil, err
+			}
+
+			runTaskInput.Overrides.EphemeralStorage = &awsecs.EphemeralStorage{
+				SizeInGiB: aws.Int64(i.EphemeralStorageGiB),
+			}
+		}
+
+		// A launch type and capacity provider strategy are mutually exclusive;
+		// Fargate capacity providers run tasks on Fargate regardless.
+		if len(i.CapacityProviders) > 0 {
+			runTaskInput.LaunchType = nil
+			runTaskInput.CapacityProviderStrategy = capacityProviderStrategy(i.CapacityProviders)
+		}
+
+		if i.PlatformVersion != "" {
+			runTaskInput.PlatformVersion = aws.String(i.PlatformVersion)
+		}
+
+		runTaskInput.NetworkConfiguration = &awsecs.NetworkConfiguration{
+			AwsvpcConfiguration: &awsecs.AwsVpcConfiguration{
+				AssignPublicIp: aws.String(assignPublicIp(i.NoPublicIp)),
+				Subnets:        aws.StringSlice(i.SubnetIds),
+				SecurityGroups: aws.StringSlice(i.SecurityGroupIds),
+			},
+		}
+	case awsecs.LaunchTypeEc2:
+		if i.EphemeralStorageGiB != 0 {
+			return nil, fmt.Errorf("ephemeral storage is not supported with the %s launch type", awsecs.LaunchTypeEc2)
+		}
+
+		if len(i.CapacityProviders) > 0 {
+			return nil, fmt.Errorf("capacity providers are not supported with the %s launch type", awsecs.LaunchTypeEc2)
+		}
+
+		runTaskInput.LaunchType = aws.String(awsecs.LaunchTypeEc2)
+
+		// Container instances don't support public IP assignment, and only
+		// tasks using the awsvpc network mode accept a network configuration.
+		if aws.StringValue(taskDefinition.NetworkMode) == awsecs.NetworkModeAwsvpc {
+			runTaskInput.NetworkConfiguration = &awsecs.NetworkConfiguration{
+				AwsvpcConfiguration: &awsecs.AwsVpcConfiguration{
+					Subnets:        aws.StringSlice(i.SubnetIds),
+					SecurityGroups: aws.StringSlice(i.SecurityGroupIds),
+				},
+			}
+		}
+
+		if i.MemoryReservation < 0 {
+			return nil, fmt.Errorf("invalid memory reservation %d MiB: must be > 0", i.MemoryReservation)
+		}
+
+		if i.MemoryReservation > 0 {
+			index, err := containerDefinitionIndex(taskDefinition, i.ContainerName)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if memory := aws.Int64Value(taskDefinition.ContainerDefinitions[index].Memory); memory > 0 && i.MemoryReservation > memory {
+				return nil, fmt.Errorf("invalid memory reservation %d MiB: must not exceed container %s's %d MiB memory limit", i.MemoryReservation, i.ContainerName, memory)
+			}
+		}
+
+		for _, expression := range i.PlacementConstraints {
+			runTaskInput.PlacementConstraints = append(
+				runTaskInput.PlacementConstraints,
+				&awsecs.PlacementConstraint{
+					Expression: aws.String(expression),
+					Type:       aws.String(awsecs.PlacementConstraintTypeMemberOf),
+				},
+			)
+		}
+	default:
+		return nil, fmt.Errorf("invalid launch type %s: must be %s or %s", i.LaunchType, awsecs.LaunchTypeFargate, awsecs.LaunchTypeEc2)
+	}
+
+	var environment []*awsecs.KeyValuePair
+	for _, envVar := range i.EnvVars {
+		environment = append(environment,
+			&awsecs.KeyValuePair{
+				Name:  aws.String(envVar.Key),
+				Value: aws.String(envVar.Value),
+			},
+		)
+	}
+
+	command, appendArgs := i.Command, i.AppendArgs
+
+	if i.CommandTemplate != nil {
+		var err error
+
+		if command, err = i.CommandTemplate.Expand(command, i.EnvVars); err != nil {
+			return nil, err
+		}
+
+		if appendArgs, err = i.CommandTemplate.Expand(appendArgs, i.EnvVars); err != nil {
+			return nil, err
+		}
+	}
+
+	// Arguments are appended to the command in the task definition, passing
+	// them to the image's entry point when the container has no command.
+	if len(appendArgs) > 0 {
+		index, err := containerDefinitionIndex(taskDefinition, i.ContainerName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		command = append(aws.StringValueSlice(taskDefinition.ContainerDefinitions[index].Command), appendArgs...)
+	}
+
+	if (len(command) > 0) || (len(i.EnvVars) > 0) || i.MemoryReservation > 0 {
+		containerOverride := &awsecs.ContainerOverride{
+			Command:     aws.StringSlice(command),
+			Environment: environment,
+			Name:        aws.String(i.ContainerName),
+		}
+
+		if i.MemoryReservation > 0 {
+			containerOverride.MemoryReservation = aws.Int64(i.MemoryReservation)
+		}
+
+		runTaskInput.Overrides.ContainerOverrides = append(runTaskInput.Overrides.ContainerOverrides, containerOverride)
+	}
+
+	if i.IdempotencyKey != "" {
+		runTaskInput.Tags = []*awsecs.Tag{
+			&awsecs.Tag{
+				Key:   aws.String(idempotencyKeyTag),
+				Value: aws.String(i.IdempotencyKey),
+			},
+		}
+	}
+
+	if i.EnableExecuteCommand {
+		runTaskInput.EnableExecuteCommand = aws.Bool(true)
+	}
+
+	return runTaskInput, nil
+}
+
+// findTaskIdsByIdempotencyKey returns the IDs of tasks in a task group, either
+// running or stopped, which were tagged with the given idempotency key and
+// created within the idempotency window.
+func (ecs *ECS) findTaskIdsByIdempotencyKey(clusterName, taskGroupName, key string) ([]string, error) {
+	var taskIds []string
+	var describeErr error
+
+	since := time.Now().Add(-idempotencyWindow)
+
+	for _, desiredStatus := range []string{awsecs.DesiredStatusRunning, awsecs.DesiredStatusStopped} {
+		err := ecs.svc.ListTasksPages(
+			&awsecs.ListTasksInput{
+				Cluster:       aws.String(clusterName),
+				DesiredStatus: aws.String(desiredStatus),
+				StartedBy:     aws.String(ecs.startedBy(taskGroupName)),
+			},
+			func(resp *awsecs.ListTasksOutput, lastPage bool) bool {
+				if len(resp.TaskArns) == 0 {
+					return true
+				}
+
+				out, err := ecs.svc.DescribeTasks(
+					&awsecs.DescribeTasksInput{
+						Cluster: aws.String(clusterName),
+						Include: aws.StringSlice([]string{awsecs.TaskFieldTags}),
+						Tasks:   resp.TaskArns,
+					},
+				)
+
+				if err != nil {
+					describeErr = err
+					return false
+				}
+
+				for _, t := range out.Tasks {
+					if aws.TimeValue(t.CreatedAt).Before(since) {
+						continue
+					}
+
+					for _, tag := range t.Tags {
+						if aws.StringValue(tag.Key) == idempotencyKeyTag && aws.StringValue(tag.Value) == key {
+							taskIds = append(taskIds, taskIdFromArn(aws.StringValue(t.TaskArn)))
+						}
+					}
+				}
+
+				return true
+			},
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if describeErr != nil {
+			return nil, describeErr
+		}
+	}
+
+	return taskIds, nil
+}
+
+// DescribeTasksForService returns a service's tasks which match the filter.
+// The zero filter returns its running and pending tasks; set IncludeStopped to
+// also return recently stopped tasks, such as those that failed during a
+// deployment.
+func (ecs *ECS) DescribeTasksForService(serviceName string, filter TaskFilter) []Task {
+	return ecs.listTasks(
+		&awsecs.ListTasksInput{
+			Cluster:     aws.String(ecs.ClusterName),
+			LaunchType:  aws.String(ecs.launchType()),
+			ServiceName: aws.String(serviceName),
+		},
+		filter,
+	)
+}
+
+func (ecs *ECS) DescribeTasksForDeployment(serviceName, deploymentId string) []Task {
+	tasks := []Task{}
+
+	for _, task := range ecs.DescribeTasksForService(serviceName, TaskFilter{}) {
+		if task.DeploymentId == deploymentId {
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks
+}
+
+func (ecs *ECS) ListTasksByTag(key, value string) []Task {
+	tasks := []Task{}
+	input := &awsecs.ListTasksInput{
+		Cluster: aws.String(ecs.ClusterName),
+	}
+
+	for _, task := range ecs.listTasks(input, TaskFilter{}) {
+		if tagValue, ok := task.Tags[key]; ok && tagValue == value {
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks
+}
+
+// ListTasksByImage returns the tasks in the cluster with a container running
+// the given image, such as to find the tasks exposed to a vulnerable base
+// image. If exact is set, the image must match in full [e.g.
+// nginx:1.25.3]; otherwise, tasks whose images contain it are returned [e.g.
+// nginx: for all nginx tags]. Every container's image is checked, not only
+// the one the task was run with.
+func (ecs *ECS) ListTasksByImage(image string, exact bool) []Task {
+	tasks := []Task{}
+	input := &awsecs.ListTasksInput{
+		Cluster: aws.String(ecs.ClusterName),
+	}
+
+	for _, task := range ecs.listTasks(input, TaskFilter{}) {
+		if task.usesImage(image, exact) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks
+}
+
+// SummarizeTaskGroupStops counts a task group's recently stopped tasks by why
+// they stopped [e.g. 30 succeeded, 12 out of memory, 8 image pull failed],
+// most common first. ECS retains stopped tasks for about an hour.
+func (ecs *ECS) SummarizeTaskGroupStops(taskGroupName string) []StopCategoryCount {
+	tasks := ecs.listTasks(
+		&awsecs.ListTasksInput{
+			Cluster:       aws.String(ecs.ClusterName),
+			DesiredStatus: aws.String(awsecs.DesiredStatusStopped),
+			LaunchType:    aws.String(ecs.launchType()),
+			StartedBy:     aws.String(ecs.startedBy(taskGroupName)),
+		},
+		TaskFilter{},
+	)
+
+	return summarizeStops(tasks)
+}
+
+// DescribeTasksForTaskGroup returns a task group's tasks which match the
+// filter. The zero filter returns its running and pending tasks.
+func (ecs *ECS) DescribeTasksForTaskGroup(taskGroupName string, filter TaskFilter) []Task {
+	return ecs.listTasks(
+		&awsecs.ListTasksInput{
+			StartedBy:  aws.String(ecs.startedBy(taskGroupName)),
+			Cluster:    aws.String(ecs.ClusterName),
+			LaunchType: aws.String(ecs.launchType()),
+		},
+		filter,
+	)
+}
+
+// DescribeTaskGroupDrift compares a task group's running tasks against the
+// latest active revision of their task definition family, to show which tasks
+// would need restarting to pick up a newly registered revision. A task group
+// whose tasks span several families has each family compared separately, so
+// the result holds one entry per family, ordered by family name.
+func (ecs *ECS) DescribeTaskGroupDrift(taskGroupName string) ([]TaskDefinitionDrift, error) {
+	latest := make(map[string]*awsecs.TaskDefinition)
+	tasks := ecs.DescribeTasksForTaskGroup(taskGroupName, TaskFilter{})
+
+	for _, task := range tasks {
+		if _, ok := latest[task.TaskDefinitionFamily]; ok {
+			continue
+		}
+
+		taskDefinitionArn, err := ecs.ResolveTaskDefinitionArn(task.TaskDefinitionFamily)
+
+		if err != nil {
+			return nil, err
+		}
+
+		taskDefinition, err := ecs.describeTaskDefinition(taskDefinitionArn)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not describe task definition %s: %v", taskDefinitionArn, err)
+		}
+
+		latest[task.TaskDefinitionFamily] = taskDefinition
+	}
+
+	return taskDefinitionDrift(tasks, latest), nil
+}
+
+// FindOrphanedEnis returns the network interfaces created by ECS for tasks
+// which are no longer running, as candidates for cleanup; nothing is deleted.
+// A network interface is orphaned if it belonged to one of the cluster's
+// recently stopped tasks, or if it's available (detached) and no running task
+// in the cluster uses it. In-use network interfaces which can't be tied to a
+// task in the cluster aren't reported, as they may belong to another cluster.
+func (ecs *ECS) FindOrphanedEnis(lister NetworkInterfaceLister) ([]OrphanedEni, error) {
+	var orphaned []OrphanedEni
+
+	statuses, err := lister.ListTaskNetworkInterfaces()
+
+	if err != nil {
+		return orphaned, err
+	}
+
+	running := make(map[string]bool)
+	stoppedTaskIds := make(map[string]string)
+
+	for _, task := range ecs.listTasks(&awsecs.ListTasksInput{Cluster: aws.String(ecs.ClusterName)}, TaskFilter{}) {
+		running[task.EniId] = true
+	}
+
+	stoppedInput := &awsecs.ListTasksInput{
+		Cluster:       aws.String(ecs.ClusterName),
+		DesiredStatus: aws.String(awsecs.DesiredStatusStopped),
+	}
+
+	for _, task := range ecs.listTasks(stoppedInput, TaskFilter{}) {
+		if task.EniId != "" {
+			stoppedTaskIds[task.EniId] = task.TaskId
+		}
+	}
+
+	for eniId, status := range statuses {
+		if running[eniId] {
+			continue
+		}
+
+		taskId, stopped := stoppedTaskIds[eniId]
+
+		if stopped || status == eniStatusAvailable {
+			orphaned = append(orphaned, OrphanedEni{EniId: eniId, Status: status, TaskId: taskId})
+		}
+	}
+
+	sort.Slice(orphaned, func(i, j int) bool { return orphaned[i].EniId < orphaned[j].EniId })
+
+	return orphaned, nil
+}
+
+// ValidateTaskNetworks sets NetworkValid on each task to whether its subnet
+// and security groups still exist. ECS doesn't return tasks' security groups,
+// so callers must fill in SecurityGroupIds, such as from the tasks' network
+// interfaces; tasks without them only have their subnet checked.
+func ValidateTaskNetworks(tasks []Task, finder NetworkResourceFinder) error {
+	var subnetIds, securityGroupIds []string
+
+	for _, task := range tasks {
+		if task.SubnetId != "" {
+			subnetIds = append(subnetIds, task.SubnetId)
+		}
+
+		securityGroupIds = append(securityGroupIds, task.SecurityGroupIds...)
+	}
+
+	foundSubnetIds, err := finder.FindSubnetIDs(subnetIds)
+
+	if err != nil {
+		return err
+	}
+
+	foundSecurityGroupIds, err := finder.FindSecurityGroupIDs(securityGroupIds)
+
+	if err != nil {
+		return err
+	}
+
+	found := make(map[string]bool)
+
+	for _, id := range append(foundSubnetIds, foundSecurityGroupIds...) {
+		found[id] = true
+	}
+
+	for i := range tasks {
+		tasks[i].NetworkValid = tasks[i].SubnetId == "" || found[tasks[i].SubnetId]
+
+		for _, securityGroupId := range tasks[i].SecurityGroupIds {
+			if !found[securityGroupId] {
+				tasks[i].NetworkValid = false
+			}
+		}
+	}
+
+	return nil
+}
+
+// FindTargetRegistrations returns the target groups in which a task is
+// currently registered, including those it's draining from. Tasks are
+// correlated to IP targets by their private IP address and container ports,
+// so tasks without a network interface or port mappings are never found.
+func FindTargetRegistrations(task Task, targetGroupArns []string, finder TargetFinder) ([]TargetRegistration, error) {
+	var registrations []TargetRegistration
+
+	if task.PrivateIpAddress == "" {
+		return registrations, nil
+	}
+
+	for _, targetGroupArn := range targetGroupArns {
+		for _, portMapping := range task.PortMappings {
+			state, err := finder.FindTargetState(targetGroupArn, task.PrivateIpAddress, portMapping.ContainerPort)
+
+			if err != nil {
+				return registrations, err
+			}
+
+			if state != "" {
+				registrations = append(
+					registrations,
+					TargetRegistration{
+						Port:           portMapping.ContainerPort,
+						State:          state,
+						TargetGroupArn: targetGroupArn,
+					},
+				)
+			}
+		}
+	}
+
+	return registrations, nil
+}
+
+// ListTaskGroups returns the task groups in the cluster with the number of
+// their tasks which match the filter. When the filter includes stopped tasks,
+// recently stopped tasks (which ECS retains for about an hour) are counted
+// separately from running instances so groups don't vanish when their tasks
+// exit.
+func (ecs *ECS) ListTaskGroups(i *ListTaskGroupsInput, filter TaskFilter) []*TaskGroup {
+	var taskGroups []*TaskGroup
+
+	taskGroupFor := func(taskGroupName string) *TaskGroup {
+		for _, taskGroup := range taskGroups {
+			if taskGroup.TaskGroupName == taskGroupName {
+				return taskGroup
+			}
+		}
+
+		taskGroup := &TaskGroup{TaskGroupName: taskGroupName}
+		taskGroups = append(taskGroups, taskGroup)
+
+		return taskGroup
+	}
+
+	taskGroupNameFor := func(task Task) (string, bool) {
+		taskGroupName, ok := ecs.taskGroupNameFromStartedBy(task.StartedBy)
+
+		return taskGroupName, ok && strings.HasPrefix(taskGroupName, i.Prefix)
+	}
+
+	input := &awsecs.ListTasksInput{
+		Cluster:    aws.String(ecs.ClusterName),
+		LaunchType: aws.String(ecs.launchType()),
+	}
+
+	for _, task := range ecs.listTasks(input, filter) {
+		if taskGroupName, ok := taskGroupNameFor(task); ok {
+			taskGroup := taskGroupFor(taskGroupName)
+
+			if task.DesiredStatus == awsecs.DesiredStatusStopped {
+				taskGroup.Stopped++
+			} else {
+				taskGroup.Instances++
+			}
+
+			taskGroup.AddFamily(task.TaskDefinitionFamily)
+		}
+	}
+
+	return taskGroups
+}
+
+// ScaleTaskGroup runs or stops tasks until the task group has desired tasks.
+// New tasks copy the network configuration of an existing task in the group,
+// which requires NetworkInterfaces to look up its security groups.
+func (ecs *ECS) ScaleTaskGroup(taskGroupName string, desired int64) error {
+	if desired < 0 {
+		return fmt.Errorf("desired count %d must be >= 0", desired)
+	}
+
+	tasks := ecs.DescribeTasksForTaskGroup(taskGroupName, TaskFilter{})
+
+	if len(tasks) == 0 {
+		return fmt.Errorf("task group %s not found; run it with fargate task run first", taskGroupName)
+	}
+
+	current := int64(len(tasks))
+
+	switch {
+	case desired > current:
+		securityGroupIds, noPublicIp, err := ecs.taskNetworkConfiguration(tasks[0])
+
+		if err != nil {
+			return err
+		}
+
+		input := runTaskInputFromTaskGroup(taskGroupName, tasks)
+		input.ClusterName = ecs.ClusterName
+		input.Count = desired - current
+		input.NoPublicIp = noPublicIp
+		input.SecurityGroupIds = securityGroupIds
+
+		_, err = ecs.runTask(input)
+
+		return err
+	case desired < current:
+		var taskIds []string
+
+		sort.Slice(tasks, func(i, j int) bool { return tasks[i].CreatedAt.After(tasks[j].CreatedAt) })
+
+		for _, task := range tasks[:current-desired] {
+			taskIds = append(taskIds, task.TaskId)
+		}
+
+		if errs := ecs.StopTasks(taskIds, fmt.Sprintf("Scaled task group %s down to %d", taskGroupName, desired)); len(errs) > 0 {
+			return fmt.Errorf("could not stop %d of %d tasks: %v", len(errs), len(taskIds), errs[0])
+		}
+	}
+
+	return nil
+}
+
+// RestartTasks replaces each task with a new one run the same way, stopping it
+// once its replacement is running. Tasks described without their security
+// groups have them looked up through NetworkInterfaces.
+func (ecs *ECS) RestartTasks(tasks []Task) []TaskRestart {
+	var restarts []TaskRestart
+
+	for _, task := range tasks {
+		restart := TaskRestart{TaskId: task.TaskId}
+
+		if _, ok := ecs.taskGroupNameFromStartedBy(task.StartedBy); !ok {
+			restart.Err = fmt.Errorf("task %s was not started by fargate task run", task.TaskId)
+			restarts = append(restarts, restart)
+			continue
+		}
+
+		securityGroupIds, noPublicIp, err := ecs.taskNetworkConfiguration(task)
+
+		if err != nil {
+			restart.Err = err
+			restarts = append(restarts, restart)
+			continue
+		}
+
+		input := ecs.runTaskInputFromTask(task)
+		input.ClusterName = ecs.ClusterName
+		input.Count = 1
+		input.NoPublicIp = noPublicIp
+		input.SecurityGroupIds = securityGroupIds
+
+		newTaskIds, err := ecs.runTask(input)
+
+		if err != nil {
+			restart.Err = fmt.Errorf("could not run replacement for task %s: %v", task.TaskId, err)
+			restarts = append(restarts, restart)
+			continue
+		}
+
+		restart.NewTaskId = newTaskIds[0]
+
+		err = ecs.svc.WaitUntilTasksRunning(
+			&awsecs.DescribeTasksInput{
+				Cluster: aws.String(ecs.ClusterName),
+				Tasks:   aws.StringSlice(newTaskIds),
+			},
+		)
+
+		if err != nil {
+			restart.Err = fmt.Errorf("replacement task %s did not reach RUNNING, left task %s running: %v", restart.NewTaskId, task.TaskId, err)
+		} else {
+			restart.Err = ecs.stopTask(task.TaskId, fmt.Sprintf("Replaced by task %s", restart.NewTaskId))
+		}
+
+		restarts = append(restarts, restart)
+	}
+
+	return restarts
+}
+
+func (ecs *ECS) runTaskInputFromTask(task Task) *RunTaskInput {
+	taskGroupName, _ := ecs.taskGroupNameFromStartedBy(task.StartedBy)
+	input := &RunTaskInput{
+		Command:           task.Command,
+		Cpu:               task.Cpu,
+		EnvVars:           task.envVarOverrides,
+		LaunchType:        task.LaunchType,
+		Memory:            task.Memory,
+		TaskDefinitionArn: task.TaskDefinitionArn,
+		TaskName:          taskGroupName,
+	}
+
+	if task.SubnetId != "" {
+		input.SubnetIds = []string{task.SubnetId}
+	}
+
+	if task.EphemeralStorageGiB >= minEphemeralStorageGiB {
+		input.EphemeralStorageGiB = task.EphemeralStorageGiB
+	}
+
+	if task.CapacityProviderName == CapacityProviderFargateSpot {
+		input.CapacityProviders = []CapacityProviderStrategyItem{
+			CapacityProviderStrategyItem{CapacityProvider: CapacityProviderFargateSpot, Weight: 1},
+		}
+	}
+
+	input.EnableExecuteCommand = task.ExecuteCommandEnabled
+
+	return input
+}
+
+// taskNetworkConfiguration returns the security groups of a task and whether
+// it has no public IP, so that tasks run in its place are reachable the same
+// way. ECS doesn't return these, so unless they've already been filled in
+// they're looked up from the task's network interface.
+func (ecs *ECS) taskNetworkConfiguration(task Task) ([]string, bool, error) {
+	if len(task.SecurityGroupIds) > 0 {
+		return task.SecurityGroupIds, task.NoPublicIp, nil
+	}
+
+	if task.EniId == "" {
+		if task.SubnetId != "" {
+			return nil, false, fmt.Errorf("could not determine the security groups of task %s: it has no network interface", task.TaskId)
+		}
+
+		return nil, false, nil
+	}
+
+	if ecs.NetworkInterfaces == nil {
+		return nil, false, fmt.Errorf("could not determine the security groups of task %s: no network interface finder configured", task.TaskId)
+	}
+
+	securityGroupIds, publicIps, err := ecs.NetworkInterfaces.FindNetworkInterfaces([]string{task.EniId})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if _, ok := securityGroupIds[task.EniId]; !ok {
+		return nil, false, fmt.Errorf("could not find network interface %s of task %s", task.EniId, task.TaskId)
+	}
+
+	return securityGroupIds[task.EniId], publicIps[task.EniId] == "", nil
+}
+
+func runTaskInputFromTaskGroup(taskGroupName string, tasks []Task) *RunTaskInput {
+	var subnetIds []string
+
+	template := tasks[0]
+	seenSubnetIds := make(map[string]bool)
+
+	for _, task := range tasks {
+		if task.SubnetId != "" && !seenSubnetIds[task.SubnetId] {
+			seenSubnetIds[task.SubnetId] = true
+			subnetIds = append(subnetIds, task.SubnetId)
+		}
+	}
+
+	input := &RunTaskInput{
+		Command:           template.Command,
+		EnvVars:           template.envVarOverrides,
+		LaunchType:        template.LaunchType,
+		SubnetIds:         subnetIds,
+		TaskDefinitionArn: template.TaskDefinitionArn,
+		TaskName:          taskGroupName,
+	}
+
+	if template.EphemeralStorageGiB >= minEphemeralStorageGiB {
+		input.EphemeralStorageGiB = template.EphemeralStorageGiB
+	}
+
+	if template.CapacityProviderName == CapacityProviderFargateSpot {
+		input.CapacityProviders = []CapacityProviderStrategyItem{
+			CapacityProviderStrategyItem{CapacityProvider: CapacityProviderFargateSpot, Weight: 1},
+		}
+	}
+
+	return input
+}
+
+// StopTasks stops the given tasks, recording reason as their stopped reason
+// if it isn't empty, and returns an error for each task which couldn't be
+// stopped.
+func (ecs *ECS) StopTasks(taskIds []string, reason string) []error {
+	_, errs := ecs.stopTasks(taskIds, reason)
+
+	return errs
+}
+
+// stopTasks stops tasks concurrently, up to stopTasksConcurrency at a time,
+// returning the IDs of the tasks stopped in the order they were given.
+func (ecs *ECS) stopTasks(taskIds []string, reason string) ([]string, []error) {
+	var stoppedTaskIds []string
+	var errs []error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	stopped := make([]bool, len(taskIds))
+	indexes := make(chan int)
+
+	for i := 0; i < stopTasksConcurrency && i < len(taskIds); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := range indexes {
+				if err := ecs.stopTask(taskIds[index], reason); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				} else {
+					stopped[index] = true
+				}
+			}
+		}()
+	}
+
+	for index := range taskIds {
+		indexes <- index
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	for index, taskId := range taskIds {
+		if stopped[index] {
+			stoppedTaskIds = append(stoppedTaskIds, taskIdFromArn(taskId))
+		}
+	}
+
+	return stoppedTaskIds, errs
+}
+
+// StopAllTasks stops every running task in the cluster. It refuses to do
+// anything unless i.Confirm is set.
+func (ecs *ECS) StopAllTasks(i *StopAllTasksInput) (StopAllTasksResult, error) {
+	var result StopAllTasksResult
+	var taskIds []string
+
+	if !i.Confirm {
+		return result, fmt.Errorf("refusing to stop all tasks in cluster %s without confirmation", ecs.ClusterName)
+	}
+
+	input := &awsecs.ListTasksInput{
+		Cluster: aws.String(ecs.ClusterName),
+	}
+
+	for _, task := range ecs.listTasks(input, TaskFilter{}) {
+		switch {
+		case !strings.HasPrefix(task.StartedBy, serviceStartedByPrefix):
+			result.TaskIds = append(result.TaskIds, task.TaskId)
+		case i.IncludeServiceTasks:
+			result.ServiceTaskIds = append(result.ServiceTaskIds, task.TaskId)
+		default:
+			result.SkippedServiceTaskIds = append(result.SkippedServiceTaskIds, task.TaskId)
+		}
+	}
+
+	taskIds = append(taskIds, result.TaskIds...)
+	taskIds = append(taskIds, result.ServiceTaskIds...)
+	result.Errors = ecs.StopTasks(taskIds, i.Reason)
+
+	return result, nil
+}
+
+// StopTasksOlderThan stops the tasks in a task group which have been running
+// for longer than age, returning the IDs of the tasks stopped and an error for
+// each task which couldn't be stopped.
+func (ecs *ECS) StopTasksOlderThan(taskGroupName string, age time.Duration, reason string) ([]string, []error) {
+	return ecs.stopTasksOlderThan(ecs.DescribeTasksForTaskGroup(taskGroupName, TaskFilter{}), age, reason)
+}
+
+// StopAllTasksOlderThan stops the tasks in the cluster which have been
+// running for longer than age. Tasks managed by a service are left alone
+// unless includeServiceTasks is set.
+func (ecs *ECS) StopAllTasksOlderThan(age time.Duration, includeServiceTasks bool, reason string) ([]string, []error) {
+	var tasks []Task
+
+	input := &awsecs.ListTasksInput{
+		Cluster: aws.String(ecs.ClusterName),
+	}
+
+	for _, task := range ecs.listTasks(input, TaskFilter{}) {
+		if includeServiceTasks || !strings.HasPrefix(task.StartedBy, serviceStartedByPrefix) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	return ecs.stopTasksOlderThan(tasks, age, reason)
+}
+
+func (ecs *ECS) stopTasksOlderThan(tasks []Task, age time.Duration, reason string) ([]string, []error) {
+	var taskIds []string
+
+	for _, task := range tasks {
+		if task.RunningFor() > age {
+			taskIds = append(taskIds, task.TaskId)
+		}
+	}
+
+	return ecs.stopTasks(taskIds, reason)
+}
+
+// TagTasks adds tags to each of the given tasks, which may be given by ID or
+// ARN, returning an error for each task which couldn't be tagged.
+func (ecs *ECS) TagTasks(taskIds []string, tags []Tag) []error {
+	var awsTags []*awsecs.Tag
+
+	for _, tag := range tags {
+		awsTags = append(
+			awsTags,
+			&awsecs.Tag{
+				Key:   aws.String(tag.Key),
+				Value: aws.String(tag.Value),
+			},
+		)
+	}
+
+	taskArns, errs := ecs.taskArns(taskIds)
+
+	for _, taskArn := range taskArns {
+		_, err := ecs.svc.TagResource(
+			&awsecs.TagResourceInput{
+				ResourceArn: aws.String(taskArn),
+				Tags:        awsTags,
+			},
+		)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not tag task %s: %v", taskIdFromArn(taskArn), err))
+		}
+	}
+
+	return errs
+}
+
+// UntagTasks removes the tags with the given keys from each of the given
+// tasks, returning an error for each task which couldn't be untagged.
+func (ecs *ECS) UntagTasks(taskIds []string, keys []string) []error {
+	taskArns, errs := ecs.taskArns(taskIds)
+
+	for _, taskArn := range taskArns {
+		_, err := ecs.svc.UntagResource(
+			&awsecs.UntagResourceInput{
+				ResourceArn: aws.String(taskArn),
+				TagKeys:     aws.StringSlice(keys),
+			},
+		)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not untag task %s: %v", taskIdFromArn(taskArn), err))
+		}
+	}
+
+	return errs
+}
+
+// taskArns resolves task IDs to full task ARNs, which the tagging APIs
+// require. ARNs are passed through and IDs are looked up in the cluster.
+func (ecs *ECS) taskArns(taskIds []string) ([]string, []error) {
+	var taskArns, lookupTaskIds []string
+	var errs []error
+
+	for _, taskId := range taskIds {
+		if strings.HasPrefix(taskId, "arn:") {
+			taskArns = append(taskArns, taskId)
+		} else {
+			lookupTaskIds = append(lookupTaskIds, taskId)
+		}
+	}
+
+	for start := 0; start < len(lookupTaskIds); start += describeTasksBatchSize {
+		end := start + describeTasksBatchSize
+
+		if end > len(lookupTaskIds) {
+			end = len(lookupTaskIds)
+		}
+
+		resp, err := ecs.svc.DescribeTasks(
+			&awsecs.DescribeTasksInput{
+				Cluster: aws.String(ecs.ClusterName),
+				Tasks:   aws.StringSlice(lookupTaskIds[start:end]),
+			},
+		)
+
+		if err != nil {
+			for _, taskId := range lookupTaskIds[start:end] {
+				errs = append(errs, fmt.Errorf("could not find task %s: %v", taskId, err))
+			}
+
+			continue
+		}
+
+		for _, t := range resp.Tasks {
+			taskArns = append(taskArns, aws.StringValue(t.TaskArn))
+		}
+
+		for _, failure := range resp.Failures {
+			errs = append(errs, fmt.Errorf("could not find task %s: %s", taskIdFromArn(aws.StringValue(failure.Arn)), strings.ToLower(aws.StringValue(failure.Reason))))
+		}
+	}
+
+	return taskArns, errs
+}
+
+func (ecs *ECS) IsTaskStopped(taskId string) bool {
+	tasks := ecs.DescribeTasks([]string{taskId})
+
+	return len(tasks) == 0 || tasks[0].LastStatus == awsecs.DesiredStatusStopped
+}
+
+func (ecs *ECS) StopTask(taskId, reason string) {
+	if err := ecs.stopTask(taskId, reason); err != nil {
+		ecs.errorExit(err, "Could not stop ECS task")
 	}
+}
 
-	var environment []*awsecs.KeyValuePair
-	for _, envVar := range i.EnvVars {
-		environment = append(environment,
-			&awsecs.KeyValuePair{
-				Name:  aws.String(envVar.Key),
-				Value: aws.String(envVar.Value),
-			},
-		)
+func (ecs *ECS) stopTask(taskId, reason string) error {
+	taskId = taskIdFromArn(taskId)
+	input := &awsecs.StopTaskInput{
+		Cluster: aws.String(ecs.ClusterName),
+		Task:    aws.String(taskId),
 	}
 
-	if (len(i.Command) > 0) || (len(i.EnvVars) > 0) {
-		runTaskInput.Overrides.ContainerOverrides = append(
-			runTaskInput.Overrides.ContainerOverrides,
-			&awsecs.ContainerOverride{
-				Command: aws.StringSlice(i.Command),
-				Environment: environment,
-				Name:    aws.String(i.TaskName),
-			},
-		)
+	if reason != "" {
+		input.Reason = aws.String(reason)
 	}
 
-	_, err := ecs.svc.RunTask(runTaskInput)
+	_, err := ecs.svc.StopTask(input)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not run ECS task")
+		return fmt.Errorf("could not stop task %s: %v", taskId, err)
 	}
+
+	return nil
 }
 
-func (ecs *ECS) DescribeTasksForService(serviceName string) []Task {
-	return ecs.listTasks(
+func (ecs *ECS) countTasks() (int, error) {
+	var taskCount int
+
+	err := ecs.svc.ListTasksPages(
 		&awsecs.ListTasksInput{
-			Cluster:     aws.String(ecs.ClusterName),
-			LaunchType:  aws.String(awsecs.CompatibilityFargate),
-			ServiceName: aws.String(serviceName),
+			Cluster: aws.String(ecs.ClusterName),
+		},
+		func(resp *awsecs.ListTasksOutput, lastPage bool) bool {
+			taskCount += len(resp.TaskArns)
+
+			return true
 		},
 	)
+
+	return taskCount, err
 }
 
-func (ecs *ECS) DescribeTasksForTaskGroup(taskGroupName string) []Task {
-	return ecs.listTasks(
-		&awsecs.ListTasksInput{
-			StartedBy: aws.String(fmt.Sprintf(startedByFormat, taskGroupName)),
-			Cluster:   aws.String(ecs.ClusterName),
+// listTasks lists and describes the tasks matching the input and filter. The
+// input isn't modified. See streamTasks.
+func (ecs *ECS) listTasks(input *awsecs.ListTasksInput, filter TaskFilter) []Task {
+	var tasks []Task
+
+	err := ecs.streamTasks(
+		context.Background(),
+		input,
+		filter,
+		func(task Task) bool {
+			tasks = append(tasks, task)
+			return true
 		},
 	)
-}
 
-func (ecs *ECS) ListTaskGroups() []*TaskGroup {
-	var taskGroups []*TaskGroup
+	if err != nil {
+		ecs.errorExit(err, "Could not list ECS tasks")
+	}
 
-	taskGroupStartedByRegexp := regexp.MustCompile(taskGroupStartedByPattern)
+	return tasks
+}
 
+// StreamTasks calls fn with each of the cluster's tasks as soon as it's
+// described, a page at a time, rather than listing every task before
+// returning. This keeps memory use flat on very large clusters and allows
+// results to be shown as they arrive. If a task group name is given, only its
+// tasks are streamed. Only tasks matching the filter are passed to fn.
+//
+// Return false from fn to stop early. Canceling ctx also stops the stream, in
+// which case the context's error is returned.
+func (ecs *ECS) StreamTasks(ctx context.Context, taskGroupName string, filter TaskFilter, fn func(Task) bool) error {
 	input := &awsecs.ListTasksInput{
-		Cluster: aws.String(ecs.ClusterName),
+		Cluster:    aws.String(ecs.ClusterName),
+		LaunchType: aws.String(ecs.launchType()),
+	}
+
+	if taskGroupName != "" {
+		input.StartedBy = aws.String(ecs.startedBy(taskGroupName))
+	}
+
+	return ecs.streamTasks(ctx, input, filter, fn)
+}
+
+// streamTasks lists the tasks matching the input a page at a time, describing
+// each page and calling fn with each task matching the filter. Tasks starting
+// or stopping while pages are fetched can be listed on more than one page, so
+// each task is only passed to fn once. The input isn't modified.
+func (ecs *ECS) streamTasks(ctx context.Context, input *awsecs.ListTasksInput, filter TaskFilter, fn func(Task) bool) error {
+	var stopped bool
+
+	listInput := *input
+
+	if filter.DesiredStatus != "" && listInput.DesiredStatus == nil {
+		listInput.DesiredStatus = aws.String(filter.DesiredStatus)
+	}
+
+	listInputs := []awsecs.ListTasksInput{listInput}
+
+	// ECS only lists tasks with one desired status at a time, so stopped tasks
+	// are listed separately and follow the others.
+	if filter.IncludeStopped && listInput.DesiredStatus == nil {
+		runningInput, stoppedInput := listInput, listInput
+		runningInput.DesiredStatus = aws.String(awsecs.DesiredStatusRunning)
+		stoppedInput.DesiredStatus = aws.String(awsecs.DesiredStatusStopped)
+		listInputs = []awsecs.ListTasksInput{runningInput, stoppedInput}
 	}
 
-OUTER:
-	for _, task := range ecs.listTasks(input) {
-		matches := taskGroupStartedByRegexp.FindStringSubmatch(task.StartedBy)
+	seen := make(map[string]bool)
 
-		if len(matches) == 2 {
-			taskGroupName := matches[1]
+	for i := range listInputs {
+		err := ecs.svc.ListTasksPagesWithContext(
+			ctx,
+			&listInputs[i],
+			func(resp *awsecs.ListTasksOutput, lastPage bool) bool {
+				var taskArns []string
 
-			for _, taskGroup := range taskGroups {
-				if taskGroup.TaskGroupName == taskGroupName {
-					taskGroup.Instances++
-					continue OUTER
+				for _, taskArn := range aws.StringValueSlice(resp.TaskArns) {
+					if !seen[taskArn] {
+						seen[taskArn] = true
+						taskArns = append(taskArns, taskArn)
+					}
 				}
-			}
 
-			taskGroups = append(
-				taskGroups,
-				&TaskGroup{
-					TaskGroupName: taskGroupName,
-					Instances:     1,
-				},
-			)
+				ecs.logger().Debug("Listed %d tasks in cluster %s", len(seen), ecs.ClusterName)
+
+				for _, task := range ecs.DescribeTasks(taskArns) {
+					if ctx.Err() != nil {
+						return false
+					}
+
+					if filter.Matches(task) && !fn(task) {
+						stopped = true
+						return false
+					}
+				}
+
+				return ctx.Err() == nil
+			},
+		)
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not list tasks in cluster %s: %v", ecs.ClusterName, err)
+		}
+
+		if stopped {
+			break
 		}
 	}
 
-	return taskGroups
+	return nil
 }
 
-func (ecs *ECS) StopTasks(taskIds []string) {
-	for _, taskId := range taskIds {
-		ecs.StopTask(taskId)
+// DescribeTask returns a single task by its ID or full ARN, or an error if no
+// such task exists in the cluster.
+func (ecs *ECS) DescribeTask(taskId string) (*Task, error) {
+	taskId = taskIdFromArn(taskId)
+
+	if taskId == "" {
+		return nil, fmt.Errorf("task ID must not be empty")
 	}
-}
 
-func (ecs *ECS) StopTask(taskId string) {
-	_, err := ecs.svc.StopTask(
-		&awsecs.StopTaskInput{
-			Cluster: aws.String(ecs.ClusterName),
-			Task:    aws.String(taskId),
-		},
-	)
+	tasks := ecs.DescribeTasks([]string{taskId})
 
-	if err != nil {
-		console.ErrorExit(err, "Could not stop ECS task")
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("task %s not found in cluster %s", taskId, ecs.ClusterName)
 	}
+
+	return &tasks[0], nil
 }
 
-func (ecs *ECS) listTasks(input *awsecs.ListTasksInput) []Task {
+// DescribeTasks describes the given tasks, which may be given by ID or ARN.
+// Whitespace around IDs is ignored, as are blank and duplicate IDs, and the
+// tasks are described in batches of up to 100.
+func (ecs *ECS) DescribeTasks(taskIds []string) []Task {
 	var tasks []Task
-	var taskArnBatches [][]string
-
-	err := ecs.svc.ListTasksPages(
-		input,
-		func(resp *awsecs.ListTasksOutput, lastPage bool) bool {
-			if len(resp.TaskArns) > 0 {
-				taskArnBatches = append(taskArnBatches, aws.StringValueSlice(resp.TaskArns))
-			}
 
-			return true
-		},
-	)
+	taskIds = sanitizeTaskIds(taskIds)
 
-	if err != nil {
-		console.ErrorExit(err, "Could not list ECS tasks")
-	}
+	for start := 0; start < len(taskIds); start += describeTasksBatchSize {
+		end := start + describeTasksBatchSize
 
-	if len(taskArnBatches) > 0 {
-		for _, taskArnBatch := range taskArnBatches {
-			for _, task := range ecs.DescribeTasks(taskArnBatch) {
-				tasks = append(tasks, task)
-			}
+		if end > len(taskIds) {
+			end = len(taskIds)
 		}
+
+		tasks = append(tasks, ecs.describeTasks(taskIds[start:end])...)
 	}
 
 	return tasks
 }
 
-func (ecs *ECS) DescribeTasks(taskIds []string) []Task {
+func (ecs *ECS) describeTasks(taskIds []string) []Task {
 	var tasks []Task
 
-	if len(taskIds) == 0 {
-		return tasks
-	}
-
 	resp, err := ecs.svc.DescribeTasks(
 		&awsecs.DescribeTasksInput{
 			Cluster: aws.String(ecs.ClusterName),
+			Include: aws.StringSlice([]string{awsecs.TaskFieldTags}),
 			Tasks:   aws.StringSlice(taskIds),
 		},
 	)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not describe ECS tasks")
+		ecs.errorExit(err, "Could not describe ECS tasks")
 	}
 
 	for _, t := range resp.Tasks {
-		taskArn := aws.StringValue(t.TaskArn)
-		contents := strings.Split(taskArn, "/")
-		taskId := contents[len(contents)-1]
+		taskId := taskIdFromArn(aws.StringValue(t.TaskArn))
 
 		task := Task{
-			Cpu:           aws.StringValue(t.Cpu),
-			CreatedAt:     aws.TimeValue(t.CreatedAt),
-			DeploymentId:  ecs.getDeploymentId(aws.StringValue(t.TaskDefinitionArn)),
-			DesiredStatus: aws.StringValue(t.DesiredStatus),
-			LastStatus:    aws.StringValue(t.LastStatus),
-			Memory:        aws.StringValue(t.Memory),
-			TaskId:        taskId,
-			StartedBy:     aws.StringValue(t.StartedBy),
-		}
-
-		taskDefinition := ecs.DescribeTaskDefinition(aws.StringValue(t.TaskDefinitionArn))
-		task.Image = aws.StringValue(taskDefinition.ContainerDefinitions[0].Image)
-		task.TaskRole = aws.StringValue(taskDefinition.TaskRoleArn)
-
-		var keys []string
-		if len(t.Overrides.ContainerOverrides[0].Environment) > 0 {
-			for _, envOverride := range t.Overrides.ContainerOverrides[0].Environment {
-				keys = append(keys, aws.StringValue(envOverride.Name))
-				task.EnvVars = append(
-					task.EnvVars,
-					EnvVar{
-						Key:   aws.StringValue(envOverride.Name),
-						Value: aws.StringValue(envOverride.Value),
+			CapacityProviderName:   aws.StringValue(t.CapacityProviderName),
+			Cpu:                    aws.StringValue(t.Cpu),
+			CreatedAt:              aws.TimeValue(t.CreatedAt),
+			DeploymentId:           ecs.getDeploymentId(aws.StringValue(t.TaskDefinitionArn)),
+			DesiredStatus:          aws.StringValue(t.DesiredStatus),
+			ExecuteCommandEnabled:  aws.BoolValue(t.EnableExecuteCommand),
+			Group:                  aws.StringValue(t.Group),
+			HealthStatus:           aws.StringValue(t.HealthStatus),
+			LastStatus:             aws.StringValue(t.LastStatus),
+			LaunchType:             aws.StringValue(t.LaunchType),
+			Memory:                 aws.StringValue(t.Memory),
+			PlatformVersion:        aws.StringValue(t.PlatformVersion),
+			TaskArn:                aws.StringValue(t.TaskArn),
+			TaskId:                 taskId,
+			StartedBy:              aws.StringValue(t.StartedBy),
+			StopCode:               aws.StringValue(t.StopCode),
+			StoppedAt:              aws.TimeValue(t.StoppedAt),
+			StoppedReason:          aws.StringValue(t.StoppedReason),
+			TaskDefinitionArn:      aws.StringValue(t.TaskDefinitionArn),
+			TaskDefinitionFamily:   ecs.getTaskDefinitionFamily(aws.StringValue(t.TaskDefinitionArn)),
+			TaskDefinitionRevision: ecs.getTaskDefinitionRevision(aws.StringValue(t.TaskDefinitionArn)),
+		}
+
+		if len(t.Tags) > 0 {
+			task.Tags = make(map[string]string)
+
+			for _, tag := range t.Tags {
+				task.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+		}
+
+		// Tasks which never reached the networking phase have no connectivity
+		// and are left with an empty status.
+		if t.Connectivity != nil {
+			task.Connectivity = aws.StringValue(t.Connectivity)
+			task.ConnectivityAt = t.ConnectivityAt
+		}
+
+		if t.EphemeralStorage != nil {
+			task.EphemeralStorageGiB = aws.Int64Value(t.EphemeralStorage.SizeInGiB)
+		}
+
+		// Containers are essential unless their task definition, described
+		// below, says otherwise.
+		for _, container := range t.Containers {
+			task.Containers = append(
+				task.Containers,
+				Container{
+					Essential:    true,
+					ExitCode:     container.ExitCode,
+					HealthStatus: aws.StringValue(container.HealthStatus),
+					Image:        aws.StringValue(container.Image),
+					LastStatus:   aws.StringValue(container.LastStatus),
+					Name:         aws.StringValue(container.Name),
+					Reason:       aws.StringValue(container.Reason),
+					RuntimeId:    aws.StringValue(container.RuntimeId),
+				},
+			)
+		}
+
+		if task.LastStatus == awsecs.DesiredStatusStopped {
+			task.StopCategory = classifyStop(task.StopCode, task.StoppedReason, task.Containers)
+		}
+
+		// The task definition may have since been deleted; if so, the task is
+		// still returned without the details it would have provided.
+		var containerDefinition *awsecs.ContainerDefinition
+
+		if !ecs.Lightweight {
+			taskDefinition, err := ecs.describeTaskDefinition(aws.StringValue(t.TaskDefinitionArn))
+
+			if err != nil {
+				ecs.logger().Debug("Could not describe task definition for task %s: %v", taskId, err)
+			} else {
+				task.TaskRole = aws.StringValue(taskDefinition.TaskRoleArn)
+
+				if len(taskDefinition.ContainerDefinitions) > 0 {
+					containerDefinition = taskDefinition.ContainerDefinitions[0]
+				}
+
+				nonEssential := make(map[string]bool)
+
+				for _, c := range summarizeContainerDefinitions(taskDefinition) {
+					nonEssential[c.Name] = !c.Essential
+				}
+
+				for i := range task.Containers {
+					task.Containers[i].Essential = !nonEssential[task.Containers[i].Name]
+				}
+			}
+		}
+
+		task.PortMappings = []PortMapping{}
+
+		if containerDefinition != nil {
+			task.Image = aws.StringValue(containerDefinition.Image)
+
+			for _, portMapping := range containerDefinition.PortMappings {
+				task.PortMappings = append(
+					task.PortMappings,
+					PortMapping{
+						ContainerPort: aws.Int64Value(portMapping.ContainerPort),
+						Protocol:      aws.StringValue(portMapping.Protocol),
 					},
 				)
 			}
+
+			for _, secret := range containerDefinition.Secrets {
+				task.Secrets = append(task.Secrets, aws.StringValue(secret.Name))
+			}
+		}
+
+		// Tasks started outside of fargate, such as from the console, may have
+		// no container overrides at all.
+		var containerOverride *awsecs.ContainerOverride
+
+		if t.Overrides != nil && len(t.Overrides.ContainerOverrides) > 0 {
+			containerOverride = t.Overrides.ContainerOverrides[0]
+		}
+
+		// Override values take precedence, so they're listed first and the
+		// task definition's values for the same keys are left out.
+		overridden := make(map[string]bool)
+
+		if containerOverride != nil {
+			for _, envOverride := range containerOverride.Environment {
+				envVar := EnvVar{
+					Key:   aws.StringValue(envOverride.Name),
+					Value: aws.StringValue(envOverride.Value),
+				}
+
+				overridden[envVar.Key] = true
+				task.EnvVars = append(task.EnvVars, envVar)
+				task.envVarOverrides = append(task.envVarOverrides, envVar)
+			}
 		}
 
-		for _, environment := range taskDefinition.ContainerDefinitions[0].Environment {
-			for _, key := range keys {
-				if aws.StringValue(environment.Name) == key {
+		if containerDefinition != nil {
+			for _, environment := range containerDefinition.Environment {
+				if overridden[aws.StringValue(environment.Name)] {
 					continue
 				}
 
@@ -275,19 +2403,29 @@ func (ecs *ECS) DescribeTasks(taskIds []string) []Task {
 			}
 		}
 
-		if len(t.Overrides.ContainerOverrides[0].Command) > 0 {
-			task.Command = aws.StringValueSlice(t.Overrides.ContainerOverrides[0].Command)
+		if containerOverride != nil && len(containerOverride.Command) > 0 {
+			task.Command = aws.StringValueSlice(containerOverride.Command)
 		}
 
-		if len(t.Attachments) == 1 {
-			for _, detail := range t.Attachments[0].Details {
+		for _, attachment := range t.Attachments {
+			if aws.StringValue(attachment.Type) != attachmentTypeEni {
+				continue
+			}
+
+			task.AttachmentStatus = aws.StringValue(attachment.Status)
+
+			for _, detail := range attachment.Details {
 				switch aws.StringValue(detail.Name) {
 				case detailNetworkInterfaceId:
 					task.EniId = aws.StringValue(detail.Value)
+				case detailPrivateIpAddress:
+					task.PrivateIpAddress = aws.StringValue(detail.Value)
 				case detailSubnetId:
 					task.SubnetId = aws.StringValue(detail.Value)
 				}
 			}
+
+			break
 		}
 
 		tasks = append(tasks, task)
@@ -295,3 +2433,291 @@ func (ecs *ECS) DescribeTasks(taskIds []string) []Task {
 
 	return tasks
 }
+
+// assignPublicIp returns whether Fargate tasks are assigned a public IP,
+// which they need to reach the internet from a public subnet.
+func assignPublicIp(noPublicIp bool) string {
+	if noPublicIp {
+		return awsecs.AssignPublicIpDisabled
+	}
+
+	return awsecs.AssignPublicIpEnabled
+}
+
+func (ecs *ECS) startedBy(taskGroupName string) string {
+	return fmt.Sprintf(startedByFormat, ecs.startedByPrefix(), taskGroupName)
+}
+
+func (ecs *ECS) taskGroupNameFromStartedBy(startedBy string) (string, bool) {
+	matches := taskGroupStartedByRegexp.FindStringSubmatch(startedBy)
+
+	if len(matches) != 3 || matches[1] != ecs.startedByPrefix() {
+		return "", false
+	}
+
+	return matches[2], true
+}
+
+func (ecs *ECS) startedByPrefix() string {
+	if ecs.StartedByPrefix == "" {
+		return defaultStartedByPrefix
+	}
+
+	return ecs.StartedByPrefix
+}
+
+func (ecs *ECS) launchType() string {
+	if ecs.LaunchType == "" {
+		return awsecs.LaunchTypeFargate
+	}
+
+	return ecs.LaunchType
+}
+
+// splitRunTaskInput returns an input per RunTask call needed to place the
+// input's tasks in their subnets, or nil if a single call will do.
+func splitRunTaskInput(i *RunTaskInput) []*RunTaskInput {
+	switch {
+	case len(i.SubnetPlacements) > 0:
+		return splitRunTaskInputByPlacement(i)
+	case i.SpreadAcrossSubnets && len(i.SubnetIds) > 1 && i.Count > 1:
+		return splitRunTaskInputBySubnet(i)
+	}
+
+	return nil
+}
+
+// splitRunTaskInputByPlacement returns an input per subnet placement, each
+// running the placement's count of tasks in only its subnet.
+func splitRunTaskInputByPlacement(i *RunTaskInput) []*RunTaskInput {
+	var inputs []*RunTaskInput
+
+	for _, placement := range i.SubnetPlacements {
+		input := *i
+		input.Count = placement.Count
+		input.SubnetIds = []string{placement.SubnetId}
+		input.SubnetPlacements = nil
+
+		inputs = append(inputs, &input)
+	}
+
+	return inputs
+}
+
+// splitRunTaskInputBySubnet partitions the input's task count across its
+// subnets, returning an input per subnet with a non-zero share.
+func splitRunTaskInputBySubnet(i *RunTaskInput) []*RunTaskInput {
+	var inputs []*RunTaskInput
+
+	for index, count := range partitionCount(i.Count, len(i.SubnetIds)) {
+		if count == 0 {
+			continue
+		}
+
+		input := *i
+		input.Count = count
+		input.SubnetIds = []string{i.SubnetIds[index]}
+
+		inputs = append(inputs, &input)
+	}
+
+	return inputs
+}
+
+// partitionCount splits count into n shares that differ by at most one, with
+// the remainder going to the earliest shares (e.g. 7 across 3 is 3, 2, 2).
+func partitionCount(count int64, n int) []int64 {
+	shares := make([]int64, n)
+
+	for index := range shares {
+		shares[index] = count / int64(n)
+
+		if int64(index) < count%int64(n) {
+			shares[index]++
+		}
+	}
+
+	return shares
+}
+
+// classifyStop categorizes why a task stopped from its stop code, stopped
+// reason, and container reasons. Image pull failures and containers killed for
+// exceeding their memory are detected first as they're reported under the more
+// generic stop codes.
+func classifyStop(stopCode, stoppedReason string, containers []Container) string {
+	reasons := []string{stoppedReason}
+
+	for _, container := range containers {
+		reasons = append(reasons, container.Reason)
+	}
+
+	for _, reason := range reasons {
+		switch {
+		case strings.Contains(reason, "CannotPullContainerError"), strings.Contains(reason, "pull image manifest"):
+			return StopCategoryImagePullFailure
+		case strings.Contains(reason, "OutOfMemoryError"):
+			return StopCategoryOutOfMemory
+		}
+	}
+
+	switch stopCode {
+	case awsecs.TaskStopCodeUserInitiated:
+		return StopCategoryUserInitiated
+	case awsecs.TaskStopCodeEssentialContainerExited:
+		if exitedCleanly(containers) {
+			return StopCategorySucceeded
+		}
+
+		return StopCategoryEssentialContainerExited
+	}
+
+	return StopCategoryOther
+}
+
+// exitedCleanly returns whether every container which exited did so with a
+// zero exit code, and at least one did.
+func exitedCleanly(containers []Container) bool {
+	exited := false
+
+	for _, container := range containers {
+		if container.ExitCode == nil {
+			continue
+		}
+
+		if *container.ExitCode != 0 {
+			return false
+		}
+
+		exited = true
+	}
+
+	return exited
+}
+
+// summarizeStops counts the tasks in each stop category, most common first,
+// using the most recently stopped task in each as its sample.
+func summarizeStops(tasks []Task) []StopCategoryCount {
+	var counts []StopCategoryCount
+
+	stoppedAt := make(map[string]time.Time)
+
+	for _, task := range tasks {
+		if task.StopCategory == "" {
+			continue
+		}
+
+		index := -1
+
+		for i := range counts {
+			if counts[i].Category == task.StopCategory {
+				index = i
+				break
+			}
+		}
+
+		if index < 0 {
+			counts = append(counts, StopCategoryCount{Category: task.StopCategory})
+			index = len(counts) - 1
+		}
+
+		counts[index].Count++
+
+		if counts[index].SampleTaskId == "" || task.StoppedAt.After(stoppedAt[task.StopCategory]) {
+			counts[index].SampleTaskId = task.TaskId
+			stoppedAt[task.StopCategory] = task.StoppedAt
+		}
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+
+		return counts[i].Category < counts[j].Category
+	})
+
+	return counts
+}
+
+// taskDefinitionDrift groups tasks by family and lists those whose revision
+// differs from the family's latest active revision, given by family name.
+func taskDefinitionDrift(tasks []Task, latest map[string]*awsecs.TaskDefinition) []TaskDefinitionDrift {
+	var drifts []TaskDefinitionDrift
+
+	index := make(map[string]int)
+
+	for _, task := range tasks {
+		taskDefinition, ok := latest[task.TaskDefinitionFamily]
+
+		if !ok {
+			continue
+		}
+
+		i, ok := index[task.TaskDefinitionFamily]
+
+		if !ok {
+			drift := TaskDefinitionDrift{
+				Family:         task.TaskDefinitionFamily,
+				LatestRevision: aws.Int64Value(taskDefinition.Revision),
+			}
+
+			if len(taskDefinition.ContainerDefinitions) > 0 {
+				drift.LatestImage = aws.StringValue(taskDefinition.ContainerDefinitions[0].Image)
+			}
+
+			i = len(drifts)
+			index[task.TaskDefinitionFamily] = i
+			drifts = append(drifts, drift)
+		}
+
+		drifts[i].TaskCount++
+
+		if task.TaskDefinitionRevision != drifts[i].LatestRevision {
+			drifts[i].StaleTasks = append(
+				drifts[i].StaleTasks,
+				StaleTask{
+					Image:    task.Image,
+					Revision: task.TaskDefinitionRevision,
+					TaskId:   task.TaskId,
+				},
+			)
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].Family < drifts[j].Family
+	})
+
+	return drifts
+}
+
+// sanitizeTaskIds converts a mix of task ARNs and task IDs into task IDs,
+// trimming whitespace and dropping blank and duplicate IDs while preserving
+// their order.
+func sanitizeTaskIds(taskIds []string) []string {
+	var sanitized []string
+
+	seen := make(map[string]bool)
+
+	for _, taskId := range taskIds {
+		taskId = strings.TrimSpace(taskId)
+
+		if taskId == "" {
+			continue
+		}
+
+		taskId = taskIdFromArn(taskId)
+
+		if !seen[taskId] {
+			seen[taskId] = true
+			sanitized = append(sanitized, taskId)
+		}
+	}
+
+	return sanitized
+}
+
+func taskIdFromArn(taskArn string) string {
+	contents := strings.Split(taskArn, "/")
+	return contents[len(contents)-1]
+}
@@ -2,45 +2,152 @@ package ecs
 
 import (
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	awsecs "github.com/aws/aws-sdk-go/service/ecs"
-	"github.com/jpignata/fargate/console"
 )
 
-const logStreamPrefix = "fargate"
+const (
+	logStreamPrefix = "fargate"
+
+	logOptionGroup        = "awslogs-group"
+	logOptionRegion       = "awslogs-region"
+	logOptionStreamPrefix = "awslogs-stream-prefix"
+
+	secretsManagerService = "secretsmanager"
+	ssmService            = "ssm"
+)
 
 var taskDefinitionCache = make(map[string]*awsecs.TaskDefinition)
 
 type CreateTaskDefinitionInput struct {
-	Cpu              string
-	EnvVars          []EnvVar
-	ExecutionRoleArn string
-	Image            string
-	Memory           string
-	Name             string
-	Port             int64
-	LogGroupName     string
-	LogRegion        string
-	TaskRole         string
-	Type             string
+	Cpu                 string
+	CpuArchitecture     string
+	EnvVars             []EnvVar
+	EphemeralStorageGiB int64
+	ExecutionRoleArn    string
+	Image               string
+	LaunchType          string
+	Memory              string
+	Name                string
+	Port                int64
+	LogGroupName        string
+	LogRegion           string
+	Secrets             []Secret
+	Sidecars            []Sidecar
+	TaskRole            string
+	Type                string
+	Volumes             []Volume
 }
 
 type EnvVar struct {
-	Key   string
-	Value string
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Secret is an environment variable whose value ECS injects into a container
+// from Secrets Manager or SSM Parameter Store when the task starts, so the
+// value itself never appears in the task definition.
+type Secret struct {
+	Name      string
+	ValueFrom string
+}
+
+// ParseSecret parses a secret in the form of NAME=SOURCE, where the source is
+// the ARN of a Secrets Manager secret [e.g.
+// arn:aws:secretsmanager:us-east-1:123456789012:secret:db-password-AbCdEf],
+// the ARN of an SSM parameter, or the name of an SSM parameter in the task's
+// region [e.g. /prod/db/password].
+func ParseSecret(expression string) (Secret, error) {
+	kv := strings.SplitN(expression, "=", 2)
+
+	if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+		return Secret{}, fmt.Errorf("invalid secret %q: must be in the form of NAME=SOURCE", expression)
+	}
+
+	if strings.HasPrefix(kv[1], "arn:") {
+		fields := strings.Split(kv[1], ":")
+
+		if len(fields) < 6 || (fields[2] != secretsManagerService && fields[2] != ssmService) {
+			return Secret{}, fmt.Errorf("invalid secret %q: source must be a Secrets Manager secret or SSM parameter", expression)
+		}
+	}
+
+	return Secret{
+		Name:      strings.ToUpper(kv[0]),
+		ValueFrom: kv[1],
+	}, nil
+}
+
+type LogConfiguration struct {
+	ContainerName   string
+	LogGroupName    string
+	LogRegion       string
+	LogStreamPrefix string
+}
+
+func (c LogConfiguration) LogStreamName(taskId string) string {
+	return fmt.Sprintf("%s/%s/%s", c.LogStreamPrefix, c.ContainerName, taskId)
 }
 
 func (ecs *ECS) CreateTaskDefinition(input *CreateTaskDefinitionInput) string {
-	console.Debug("Creating ECS task definition")
+	ecs.logger().Debug("Creating ECS task definition")
+
+	registerTaskDefinitionInput, err := input.registerTaskDefinitionInput()
+
+	if err != nil {
+		ecs.errorExit(err, "Couldn't register ECS task definition")
+	}
+
+	resp, err := ecs.svc.RegisterTaskDefinition(registerTaskDefinitionInput)
+
+	if err != nil {
+		ecs.errorExit(err, "Couldn't register ECS task definition")
+	}
+
+	td := resp.TaskDefinition
+
+	ecs.logger().Debug("Created ECS task definition [%s:%d]", aws.StringValue(td.Family), aws.Int64Value(td.Revision))
 
+	return aws.StringValue(td.TaskDefinitionArn)
+}
+
+// TaskDefinition returns the task definition CreateTaskDefinition would
+// register for the input, without registering it, such as for a dry run. As it
+// has no revision, its ARN is given as its family.
+func (input *CreateTaskDefinitionInput) TaskDefinition() (*awsecs.TaskDefinition, error) {
+	registerTaskDefinitionInput, err := input.registerTaskDefinitionInput()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &awsecs.TaskDefinition{
+		ContainerDefinitions:    registerTaskDefinitionInput.ContainerDefinitions,
+		Cpu:                     registerTaskDefinitionInput.Cpu,
+		EphemeralStorage:        registerTaskDefinitionInput.EphemeralStorage,
+		ExecutionRoleArn:        registerTaskDefinitionInput.ExecutionRoleArn,
+		Family:                  registerTaskDefinitionInput.Family,
+		Memory:                  registerTaskDefinitionInput.Memory,
+		NetworkMode:             registerTaskDefinitionInput.NetworkMode,
+		RequiresCompatibilities: registerTaskDefinitionInput.RequiresCompatibilities,
+		RuntimePlatform:         registerTaskDefinitionInput.RuntimePlatform,
+		TaskDefinitionArn:       registerTaskDefinitionInput.Family,
+		TaskRoleArn:             registerTaskDefinitionInput.TaskRoleArn,
+		Volumes:                 registerTaskDefinitionInput.Volumes,
+	}, nil
+}
+
+func (input *CreateTaskDefinitionInput) registerTaskDefinitionInput() (*awsecs.RegisterTaskDefinitionInput, error) {
 	logConfiguration := &awsecs.LogConfiguration{
 		LogDriver: aws.String(awsecs.LogDriverAwslogs),
 		Options: map[string]*string{
-			"awslogs-region":        aws.String(input.LogRegion),
-			"awslogs-group":         aws.String(input.LogGroupName),
-			"awslogs-stream-prefix": aws.String(logStreamPrefix),
+			logOptionRegion:       aws.String(input.LogRegion),
+			logOptionGroup:        aws.String(input.LogGroupName),
+			logOptionStreamPrefix: aws.String(logStreamPrefix),
 		},
 	}
 
@@ -52,6 +159,16 @@ func (ecs *ECS) CreateTaskDefinition(input *CreateTaskDefinitionInput) string {
 		Name:             aws.String(input.Name),
 	}
 
+	for _, secret := range input.Secrets {
+		containerDefinition.Secrets = append(
+			containerDefinition.Secrets,
+			&awsecs.Secret{
+				Name:      aws.String(secret.Name),
+				ValueFrom: aws.String(secret.ValueFrom),
+			},
+		)
+	}
+
 	if input.Port != 0 {
 		containerDefinition.SetPortMappings(
 			[]*awsecs.PortMapping{
@@ -62,28 +179,77 @@ func (ecs *ECS) CreateTaskDefinition(input *CreateTaskDefinitionInput) string {
 		)
 	}
 
-	resp, err := ecs.svc.RegisterTaskDefinition(
-		&awsecs.RegisterTaskDefinitionInput{
-			ContainerDefinitions:    []*awsecs.ContainerDefinition{containerDefinition},
-			Cpu:                     aws.String(input.Cpu),
-			ExecutionRoleArn:        aws.String(input.ExecutionRoleArn),
-			Family:                  aws.String(fmt.Sprintf("%s_%s", input.Type, input.Name)),
-			Memory:                  aws.String(input.Memory),
-			NetworkMode:             aws.String(awsecs.NetworkModeAwsvpc),
-			RequiresCompatibilities: aws.StringSlice([]string{awsecs.CompatibilityFargate}),
-			TaskRoleArn:             aws.String(input.TaskRole),
-		},
-	)
+	if err := validateSidecars(input.Name, input.Sidecars); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
-		console.ErrorExit(err, "Couldn't register ECS task definition")
+	volumes, mountPoints := efsVolumes(input.Volumes)
+	containerDefinition.MountPoints = mountPoints
+
+	containerDefinitions := []*awsecs.ContainerDefinition{containerDefinition}
+
+	// The main container is always defined first, as the image, environment,
+	// and overrides fargate manages all apply to the first container.
+	for _, sidecar := range input.Sidecars {
+		containerDefinitions = append(containerDefinitions, sidecar.containerDefinition(logConfiguration))
 	}
 
-	td := resp.TaskDefinition
+	compatibilities := []string{awsecs.CompatibilityFargate}
+
+	if input.LaunchType == awsecs.LaunchTypeEc2 {
+		compatibilities = append(compatibilities, awsecs.CompatibilityEc2)
+	}
 
-	console.Debug("Created ECS task definition [%s:%d]", aws.StringValue(td.Family), aws.Int64Value(td.Revision))
+	registerTaskDefinitionInput := &awsecs.RegisterTaskDefinitionInput{
+		ContainerDefinitions:    containerDefinitions,
+		Cpu:                     aws.String(input.Cpu),
+		ExecutionRoleArn:        aws.String(input.ExecutionRoleArn),
+		Family:                  aws.String(fmt.Sprintf("%s_%s", input.Type, input.Name)),
+		Memory:                  aws.String(input.Memory),
+		NetworkMode:             aws.String(awsecs.NetworkModeAwsvpc),
+		RequiresCompatibilities: aws.StringSlice(compatibilities),
+		TaskRoleArn:             aws.String(input.TaskRole),
+		Volumes:                 volumes,
+	}
 
-	return aws.StringValue(td.TaskDefinitionArn)
+	if input.EphemeralStorageGiB != 0 {
+		registerTaskDefinitionInput.EphemeralStorage = &awsecs.EphemeralStorage{
+			SizeInGiB: aws.Int64(input.EphemeralStorageGiB),
+		}
+	}
+
+	if input.CpuArchitecture != "" {
+		registerTaskDefinitionInput.RuntimePlatform = &awsecs.RuntimePlatform{
+			CpuArchitecture:       aws.String(input.CpuArchitecture),
+			OperatingSystemFamily: aws.String(awsecs.OSFamilyLinux),
+		}
+	}
+
+	return registerTaskDefinitionInput, nil
+}
+
+// ValidateCpuArchitecture returns an error unless the CPU architecture is one
+// that Fargate supports [X86_64 or ARM64].
+func ValidateCpuArchitecture(cpuArchitecture string) error {
+	switch cpuArchitecture {
+	case awsecs.CPUArchitectureX8664, awsecs.CPUArchitectureArm64:
+		return nil
+	default:
+		return fmt.Errorf("invalid CPU architecture %s: must be %s or %s", cpuArchitecture, awsecs.CPUArchitectureX8664, awsecs.CPUArchitectureArm64)
+	}
+}
+
+// GetCpuArchitectureFromTaskDefinition returns the task definition's CPU
+// architecture, or an empty string if it doesn't specify one, in which case
+// tasks run on X86_64.
+func (ecs *ECS) GetCpuArchitectureFromTaskDefinition(taskDefinitionArn string) string {
+	taskDefinition := ecs.DescribeTaskDefinition(taskDefinitionArn)
+
+	if taskDefinition.RuntimePlatform == nil {
+		return ""
+	}
+
+	return aws.StringValue(taskDefinition.RuntimePlatform.CpuArchitecture)
 }
 
 func (input *CreateTaskDefinitionInput) Environment() []*awsecs.KeyValuePair {
@@ -102,8 +268,18 @@ func (input *CreateTaskDefinitionInput) Environment() []*awsecs.KeyValuePair {
 }
 
 func (ecs *ECS) DescribeTaskDefinition(taskDefinitionArn string) *awsecs.TaskDefinition {
+	taskDefinition, err := ecs.describeTaskDefinition(taskDefinitionArn)
+
+	if err != nil {
+		ecs.errorExit(err, "Could not describe ECS task definition")
+	}
+
+	return taskDefinition
+}
+
+func (ecs *ECS) describeTaskDefinition(taskDefinitionArn string) (*awsecs.TaskDefinition, error) {
 	if taskDefinitionCache[taskDefinitionArn] != nil {
-		return taskDefinitionCache[taskDefinitionArn]
+		return taskDefinitionCache[taskDefinitionArn], nil
 	}
 
 	resp, err := ecs.svc.DescribeTaskDefinition(
@@ -113,109 +289,324 @@ func (ecs *ECS) DescribeTaskDefinition(taskDefinitionArn string) *awsecs.TaskDef
 	)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not describe ECS task definition")
+		return nil, err
+	}
+
+	taskDefinitionCache[taskDefinitionArn] = resp.TaskDefinition
+
+	return taskDefinitionCache[taskDefinitionArn], nil
+}
+
+// ResolveTaskDefinitionArn returns the full ARN of the latest active revision
+// of a task definition given by family alone [e.g. my-batch-job]. Task
+// definitions given by ARN or by family and revision [e.g. my-batch-job:7] are
+// returned as-is, as ECS accepts them directly.
+func (ecs *ECS) ResolveTaskDefinitionArn(taskDefinition string) (string, error) {
+	if strings.HasPrefix(taskDefinition, "arn:") || strings.Contains(taskDefinition, ":") {
+		return taskDefinition, nil
+	}
+
+	// Describing a family returns its latest active revision, which may have
+	// changed since the family was last described, so the cache is bypassed.
+	resp, err := ecs.svc.DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(taskDefinition),
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("could not find an active revision of task definition family %s: %v", taskDefinition, err)
 	}
 
+	taskDefinitionArn := aws.StringValue(resp.TaskDefinition.TaskDefinitionArn)
 	taskDefinitionCache[taskDefinitionArn] = resp.TaskDefinition
 
-	return taskDefinitionCache[taskDefinitionArn]
+	return taskDefinitionArn, nil
+}
+
+// RollbackTaskDefinitionArn returns the ARN of the revision of a task
+// definition's family to roll back to: the given revision, or if revision is 0,
+// the latest active revision preceding the task definition. Only active
+// revisions can be rolled back to, as ECS won't start tasks from inactive ones.
+func (ecs *ECS) RollbackTaskDefinitionArn(taskDefinitionArn string, revision int64) (string, error) {
+	family := ecs.getTaskDefinitionFamily(taskDefinitionArn)
+	current := ecs.getTaskDefinitionRevision(taskDefinitionArn)
+
+	if revision != 0 {
+		if revision == current {
+			return "", fmt.Errorf("task definition %s:%d is already the current revision", family, revision)
+		}
+
+		taskDefinition, err := ecs.describeTaskDefinition(fmt.Sprintf("%s:%d", family, revision))
+
+		if err != nil {
+			return "", fmt.Errorf("could not find revision %d of task definition family %s: %v", revision, family, err)
+		}
+
+		if status := aws.StringValue(taskDefinition.Status); status != awsecs.TaskDefinitionStatusActive {
+			return "", fmt.Errorf("task definition %s:%d is %s and can't be rolled back to", family, revision, strings.ToLower(status))
+		}
+
+		return aws.StringValue(taskDefinition.TaskDefinitionArn), nil
+	}
+
+	var previousArn string
+
+	// Family prefixes also match other families [e.g. web matches web-worker],
+	// so each revision's family is checked.
+	err := ecs.svc.ListTaskDefinitionsPages(
+		&awsecs.ListTaskDefinitionsInput{
+			FamilyPrefix: aws.String(family),
+			Sort:         aws.String(awsecs.SortOrderDesc),
+			Status:       aws.String(awsecs.TaskDefinitionStatusActive),
+		},
+		func(resp *awsecs.ListTaskDefinitionsOutput, lastPage bool) bool {
+			for _, arn := range aws.StringValueSlice(resp.TaskDefinitionArns) {
+				if ecs.getTaskDefinitionFamily(arn) == family && ecs.getTaskDefinitionRevision(arn) < current {
+					previousArn = arn
+					return false
+				}
+			}
+
+			return true
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("could not list revisions of task definition family %s: %v", family, err)
+	}
+
+	if previousArn == "" {
+		return "", fmt.Errorf("no active revision of task definition family %s precedes revision %d", family, current)
+	}
+
+	return previousArn, nil
+}
+
+// containerDefinitionIndex returns the index of the named container within a
+// task definition, or of the first container if no name is given.
+func containerDefinitionIndex(taskDefinition *awsecs.TaskDefinition, containerName string) (int, error) {
+	var names []string
+
+	if len(taskDefinition.ContainerDefinitions) == 0 {
+		return 0, fmt.Errorf("task definition %s has no containers", aws.StringValue(taskDefinition.TaskDefinitionArn))
+	}
+
+	if containerName == "" {
+		return 0, nil
+	}
+
+	for index, containerDefinition := range taskDefinition.ContainerDefinitions {
+		if aws.StringValue(containerDefinition.Name) == containerName {
+			return index, nil
+		}
+
+		names = append(names, aws.StringValue(containerDefinition.Name))
+	}
+
+	return 0, fmt.Errorf("container %s not found in task definition %s [containers: %s]", containerName, aws.StringValue(taskDefinition.TaskDefinitionArn), strings.Join(names, ", "))
+}
+
+// GetLogConfiguration returns the awslogs settings of the named container
+// within a task definition, or of the first container if no name is given.
+func (ecs *ECS) GetLogConfiguration(taskDefinitionArn, containerName string) (LogConfiguration, error) {
+	taskDefinition, err := ecs.describeTaskDefinition(taskDefinitionArn)
+
+	if err != nil {
+		return LogConfiguration{}, fmt.Errorf("could not describe task definition %s: %v", taskDefinitionArn, err)
+	}
+
+	index, err := containerDefinitionIndex(taskDefinition, containerName)
+
+	if err != nil {
+		return LogConfiguration{}, err
+	}
+
+	containerDefinition := taskDefinition.ContainerDefinitions[index]
+	logConfiguration := containerDefinition.LogConfiguration
+
+	if logConfiguration == nil || aws.StringValue(logConfiguration.LogDriver) != awsecs.LogDriverAwslogs {
+		return LogConfiguration{}, fmt.Errorf("container %s does not use the awslogs log driver", aws.StringValue(containerDefinition.Name))
+	}
+
+	return LogConfiguration{
+		ContainerName:   aws.StringValue(containerDefinition.Name),
+		LogGroupName:    aws.StringValue(logConfiguration.Options[logOptionGroup]),
+		LogRegion:       aws.StringValue(logConfiguration.Options[logOptionRegion]),
+		LogStreamPrefix: aws.StringValue(logConfiguration.Options[logOptionStreamPrefix]),
+	}, nil
 }
 
 func (ecs *ECS) UpdateTaskDefinitionImage(taskDefinitionArn, image string) string {
-	taskDefinition := ecs.DescribeTaskDefinition(taskDefinitionArn)
-	taskDefinition.ContainerDefinitions[0].Image = aws.String(image)
-
-	resp, err := ecs.svc.RegisterTaskDefinition(
-		&awsecs.RegisterTaskDefinitionInput{
-			ContainerDefinitions:    taskDefinition.ContainerDefinitions,
-			Cpu:                     taskDefinition.Cpu,
-			ExecutionRoleArn:        taskDefinition.ExecutionRoleArn,
-			Family:                  taskDefinition.Family,
-			Memory:                  taskDefinition.Memory,
-			NetworkMode:             taskDefinition.NetworkMode,
-			RequiresCompatibilities: taskDefinition.RequiresCompatibilities,
-			TaskRoleArn:             taskDefinition.TaskRoleArn,
+	taskDefinitionArn, err := ecs.registerTaskDefinitionRevision(
+		ecs.DescribeTaskDefinition(taskDefinitionArn),
+		"",
+		func(containerDefinition *awsecs.ContainerDefinition) {
+			containerDefinition.Image = aws.String(image)
 		},
 	)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not register ECS task definition")
+		ecs.errorExit(err, "Could not register ECS task definition")
 	}
 
-	return aws.StringValue(resp.TaskDefinition.TaskDefinitionArn)
+	return taskDefinitionArn
 }
 
 func (ecs *ECS) AddEnvVarsToTaskDefinition(taskDefinitionArn string, envVars []EnvVar) string {
-	taskDefinition := ecs.DescribeTaskDefinition(taskDefinitionArn)
+	taskDefinitionArn, err := ecs.registerTaskDefinitionRevision(
+		ecs.DescribeTaskDefinition(taskDefinitionArn),
+		"",
+		func(containerDefinition *awsecs.ContainerDefinition) {
+			environment := append([]*awsecs.KeyValuePair{}, containerDefinition.Environment...)
+
+			for _, envVar := range envVars {
+				environment = append(environment,
+					&awsecs.KeyValuePair{
+						Name:  aws.String(envVar.Key),
+						Value: aws.String(envVar.Value),
+					},
+				)
+			}
 
-	for _, envVar := range envVars {
-		keyValuePair := &awsecs.KeyValuePair{
-			Name:  aws.String(envVar.Key),
-			Value: aws.String(envVar.Value),
-		}
+			containerDefinition.Environment = environment
+		},
+	)
 
-		taskDefinition.ContainerDefinitions[0].Environment = append(
-			taskDefinition.ContainerDefinitions[0].Environment,
-			keyValuePair,
-		)
+	if err != nil {
+		ecs.errorExit(err, "Could not register ECS task definition")
+	}
+
+	return taskDefinitionArn
+}
+
+// AddSecretsToTaskDefinition registers a new revision of a task definition
+// with the given secrets added to the named container, or the first container
+// if no name is given, and returns its ARN.
+func (ecs *ECS) AddSecretsToTaskDefinition(taskDefinitionArn, containerName string, secrets []Secret) (string, error) {
+	taskDefinition, err := ecs.describeTaskDefinition(taskDefinitionArn)
+
+	if err != nil {
+		return "", fmt.Errorf("could not describe task definition %s: %v", taskDefinitionArn, err)
 	}
 
-	resp, err := ecs.svc.RegisterTaskDefinition(
-		&awsecs.RegisterTaskDefinitionInput{
-			ContainerDefinitions:    taskDefinition.ContainerDefinitions,
-			Cpu:                     taskDefinition.Cpu,
-			ExecutionRoleArn:        taskDefinition.ExecutionRoleArn,
-			Family:                  taskDefinition.Family,
-			Memory:                  taskDefinition.Memory,
-			NetworkMode:             taskDefinition.NetworkMode,
-			RequiresCompatibilities: taskDefinition.RequiresCompatibilities,
-			TaskRoleArn:             taskDefinition.TaskRoleArn,
+	return ecs.registerTaskDefinitionRevision(
+		taskDefinition,
+		containerName,
+		func(containerDefinition *awsecs.ContainerDefinition) {
+			containerDefinition.Secrets = append([]*awsecs.Secret{}, containerDefinition.Secrets...)
+
+			for _, secret := range secrets {
+				awsSecret := &awsecs.Secret{
+					Name:      aws.String(secret.Name),
+					ValueFrom: aws.String(secret.ValueFrom),
+				}
+				replaced := false
+
+				// A secret of the same name replaces the existing one in place,
+				// rather than being added alongside it.
+				for i, existing := range containerDefinition.Secrets {
+					if aws.StringValue(existing.Name) == secret.Name {
+						containerDefinition.Secrets[i] = awsSecret
+						replaced = true
+					}
+				}
+
+				if !replaced {
+					containerDefinition.Secrets = append(containerDefinition.Secrets, awsSecret)
+				}
+			}
 		},
 	)
+}
+
+// SetTaskDefinitionEntryPoint registers a new revision of a task definition
+// with the entry point of the named container, or the first container if no
+// name is given, replaced. Container overrides on RunTask can't change the
+// entry point, so this is used in their place.
+func (ecs *ECS) SetTaskDefinitionEntryPoint(taskDefinitionArn, containerName string, entryPoint []string) (string, error) {
+	taskDefinition, err := ecs.describeTaskDefinition(taskDefinitionArn)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not register ECS task definition")
+		return "", fmt.Errorf("could not describe task definition %s: %v", taskDefinitionArn, err)
 	}
 
-	return aws.StringValue(resp.TaskDefinition.TaskDefinitionArn)
+	return ecs.registerTaskDefinitionRevision(
+		taskDefinition,
+		containerName,
+		func(containerDefinition *awsecs.ContainerDefinition) {
+			containerDefinition.EntryPoint = aws.StringSlice(entryPoint)
+		},
+	)
 }
 
-func (ecs *ECS) RemoveEnvVarsFromTaskDefinition(taskDefinitionArn string, keys []string) string {
-	var newEnvironment []*awsecs.KeyValuePair
+// SetTaskDefinitionDns registers a new revision of a task definition with the
+// DNS servers and search domains of the named container, or the first
+// container if no name is given, replaced. Container overrides can't set DNS
+// options, and ECS only honors them for tasks that don't use the awsvpc
+// network mode, so an error is returned for awsvpc task definitions.
+func (ecs *ECS) SetTaskDefinitionDns(taskDefinitionArn, containerName string, dnsServers, dnsSearchDomains []string) (string, error) {
+	taskDefinition, err := ecs.describeTaskDefinition(taskDefinitionArn)
 
-	taskDefinition := ecs.DescribeTaskDefinition(taskDefinitionArn)
-	environment := taskDefinition.ContainerDefinitions[0].Environment
+	if err != nil {
+		return "", fmt.Errorf("could not describe task definition %s: %v", taskDefinitionArn, err)
+	}
 
-	for _, keyValuePair := range environment {
-		for _, key := range keys {
-			if aws.StringValue(keyValuePair.Name) == key {
-				continue
+	if err := validateTaskDefinitionDns(taskDefinition); err != nil {
+		return "", err
+	}
+
+	return ecs.registerTaskDefinitionRevision(
+		taskDefinition,
+		containerName,
+		func(containerDefinition *awsecs.ContainerDefinition) {
+			if len(dnsServers) > 0 {
+				containerDefinition.DnsServers = aws.StringSlice(dnsServers)
 			}
 
-			newEnvironment = append(newEnvironment, keyValuePair)
-		}
+			if len(dnsSearchDomains) > 0 {
+				containerDefinition.DnsSearchDomains = aws.StringSlice(dnsSearchDomains)
+			}
+		},
+	)
+}
+
+// validateTaskDefinitionDns returns an error if the task definition uses the
+// awsvpc network mode, for which ECS ignores container DNS settings.
+func validateTaskDefinitionDns(taskDefinition *awsecs.TaskDefinition) error {
+	if aws.StringValue(taskDefinition.NetworkMode) == awsecs.NetworkModeAwsvpc {
+		return fmt.Errorf("DNS servers and search domains are not supported for tasks using the %s network mode", awsecs.NetworkModeAwsvpc)
 	}
 
-	taskDefinition.ContainerDefinitions[0].Environment = newEnvironment
+	return nil
+}
+
+func (ecs *ECS) RemoveEnvVarsFromTaskDefinition(taskDefinitionArn string, keys []string) string {
+	taskDefinitionArn, err := ecs.registerTaskDefinitionRevision(
+		ecs.DescribeTaskDefinition(taskDefinitionArn),
+		"",
+		func(containerDefinition *awsecs.ContainerDefinition) {
+			var newEnvironment []*awsecs.KeyValuePair
+
+			for _, keyValuePair := range containerDefinition.Environment {
+				for _, key := range keys {
+					if aws.StringValue(keyValuePair.Name) == key {
+						continue
+					}
+
+					newEnvironment = append(newEnvironment, keyValuePair)
+				}
+			}
 
-	resp, err := ecs.svc.RegisterTaskDefinition(
-		&awsecs.RegisterTaskDefinitionInput{
-			ContainerDefinitions:    taskDefinition.ContainerDefinitions,
-			Cpu:                     taskDefinition.Cpu,
-			ExecutionRoleArn:        taskDefinition.ExecutionRoleArn,
-			Family:                  taskDefinition.Family,
-			Memory:                  taskDefinition.Memory,
-			NetworkMode:             taskDefinition.NetworkMode,
-			RequiresCompatibilities: taskDefinition.RequiresCompatibilities,
-			TaskRoleArn:             taskDefinition.TaskRoleArn,
+			containerDefinition.Environment = newEnvironment
 		},
 	)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not register ECS task definition")
+		ecs.errorExit(err, "Could not register ECS task definition")
 	}
 
-	return aws.StringValue(resp.TaskDefinition.TaskDefinitionArn)
+	return taskDefinitionArn
 }
 
 func (ecs *ECS) GetEnvVarsFromTaskDefinition(taskDefinitionArn string) []EnvVar {
@@ -236,7 +627,7 @@ func (ecs *ECS) GetEnvVarsFromTaskDefinition(taskDefinitionArn string) []EnvVar
 }
 
 func (ecs *ECS) UpdateTaskDefinitionCpuAndMemory(taskDefinitionArn, cpu, memory string) string {
-	taskDefinition := ecs.DescribeTaskDefinition(taskDefinitionArn)
+	taskDefinition := *ecs.DescribeTaskDefinition(taskDefinitionArn)
 
 	if cpu != "" {
 		taskDefinition.Cpu = aws.String(cpu)
@@ -246,24 +637,136 @@ func (ecs *ECS) UpdateTaskDefinitionCpuAndMemory(taskDefinitionArn, cpu, memory
 		taskDefinition.Memory = aws.String(memory)
 	}
 
-	resp, err := ecs.svc.RegisterTaskDefinition(
-		&awsecs.RegisterTaskDefinitionInput{
-			ContainerDefinitions:    taskDefinition.ContainerDefinitions,
-			Cpu:                     taskDefinition.Cpu,
-			ExecutionRoleArn:        taskDefinition.ExecutionRoleArn,
-			Family:                  taskDefinition.Family,
-			Memory:                  taskDefinition.Memory,
-			NetworkMode:             taskDefinition.NetworkMode,
-			RequiresCompatibilities: taskDefinition.RequiresCompatibilities,
-			TaskRoleArn:             taskDefinition.TaskRoleArn,
+	taskDefinitionArn, err := ecs.registerTaskDefinitionRevision(&taskDefinition, "", nil)
+
+	if err != nil {
+		ecs.errorExit(err, "Could not register ECS task definition")
+	}
+
+	return taskDefinitionArn
+}
+
+// UpdateTaskDefinitionEphemeralStorage registers a new revision of a task
+// definition with the given amount of ephemeral storage, in GiB, and returns
+// its ARN.
+func (ecs *ECS) UpdateTaskDefinitionEphemeralStorage(taskDefinitionArn string, sizeGiB int64) string {
+	taskDefinition := *ecs.DescribeTaskDefinition(taskDefinitionArn)
+	taskDefinition.EphemeralStorage = &awsecs.EphemeralStorage{
+		SizeInGiB: aws.Int64(sizeGiB),
+	}
+
+	taskDefinitionArn, err := ecs.registerTaskDefinitionRevision(&taskDefinition, "", nil)
+
+	if err != nil {
+		ecs.errorExit(err, "Could not register ECS task definition")
+	}
+
+	return taskDefinitionArn
+}
+
+// registerTaskDefinitionRevision registers a new revision of a task definition
+// and returns its ARN. mutate, if given, is called with a copy of the named
+// container's definition, or the first container's if no name is given, to
+// make changes to it; the task definition itself is left unchanged, as it may
+// be cached. Every other setting of the task definition is carried over to the
+// new revision.
+//
+// If the changes leave the task definition as it was, or match the latest
+// revision of its family, that revision is returned instead so that running
+// the same task repeatedly doesn't register a new revision each time.
+func (ecs *ECS) registerTaskDefinitionRevision(taskDefinition *awsecs.TaskDefinition, containerName string, mutate func(*awsecs.ContainerDefinition)) (string, error) {
+	index, err := containerDefinitionIndex(taskDefinition, containerName)
+
+	if err != nil {
+		return "", err
+	}
+
+	containerDefinitions := make([]*awsecs.ContainerDefinition, len(taskDefinition.ContainerDefinitions))
+	copy(containerDefinitions, taskDefinition.ContainerDefinitions)
+
+	if mutate != nil {
+		containerDefinition := *containerDefinitions[index]
+		mutate(&containerDefinition)
+		containerDefinitions[index] = &containerDefinition
+	}
+
+	input := registerTaskDefinitionInput(taskDefinition, containerDefinitions)
+
+	// Callers may change task-level settings on a copy of the task definition
+	// before calling, so the revision is compared as described rather than as
+	// passed in.
+	if taskDefinitionArn := aws.StringValue(taskDefinition.TaskDefinitionArn); taskDefinitionArn != "" {
+		source, err := ecs.describeTaskDefinition(taskDefinitionArn)
+
+		if err == nil && reflect.DeepEqual(input, registerTaskDefinitionInput(source, source.ContainerDefinitions)) {
+			return taskDefinitionArn, nil
+		}
+	}
+
+	if latestArn, ok := ecs.matchLatestTaskDefinition(input); ok {
+		ecs.logger().Debug("Reusing task definition %s", latestArn)
+		return latestArn, nil
+	}
+
+	resp, err := ecs.svc.RegisterTaskDefinition(input)
+
+	if err != nil {
+		return "", fmt.Errorf("could not register task definition %s: %v", aws.StringValue(taskDefinition.Family), err)
+	}
+
+	return aws.StringValue(resp.TaskDefinition.TaskDefinitionArn), nil
+}
+
+// matchLatestTaskDefinition returns the ARN of the latest active revision of
+// the input's family if registering the input would produce an identical
+// revision.
+func (ecs *ECS) matchLatestTaskDefinition(input *awsecs.RegisterTaskDefinitionInput) (string, bool) {
+	// The latest revision changes as revisions are registered, so the cache is
+	// bypassed.
+	resp, err := ecs.svc.DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{
+			TaskDefinition: input.Family,
 		},
 	)
 
 	if err != nil {
-		console.ErrorExit(err, "Could not register ECS task definition")
+		ecs.logger().Debug("Could not describe task definition family %s: %v", aws.StringValue(input.Family), err)
+		return "", false
 	}
 
-	return aws.StringValue(resp.TaskDefinition.TaskDefinitionArn)
+	latest := resp.TaskDefinition
+
+	if !reflect.DeepEqual(input, registerTaskDefinitionInput(latest, latest.ContainerDefinitions)) {
+		return "", false
+	}
+
+	taskDefinitionArn := aws.StringValue(latest.TaskDefinitionArn)
+	taskDefinitionCache[taskDefinitionArn] = latest
+
+	return taskDefinitionArn, true
+}
+
+// registerTaskDefinitionInput builds the input to register a revision of a
+// task definition with the given container definitions.
+func registerTaskDefinitionInput(taskDefinition *awsecs.TaskDefinition, containerDefinitions []*awsecs.ContainerDefinition) *awsecs.RegisterTaskDefinitionInput {
+	return &awsecs.RegisterTaskDefinitionInput{
+		ContainerDefinitions:    containerDefinitions,
+		Cpu:                     taskDefinition.Cpu,
+		EphemeralStorage:        taskDefinition.EphemeralStorage,
+		ExecutionRoleArn:        taskDefinition.ExecutionRoleArn,
+		Family:                  taskDefinition.Family,
+		InferenceAccelerators:   taskDefinition.InferenceAccelerators,
+		IpcMode:                 taskDefinition.IpcMode,
+		Memory:                  taskDefinition.Memory,
+		NetworkMode:             taskDefinition.NetworkMode,
+		PidMode:                 taskDefinition.PidMode,
+		PlacementConstraints:    taskDefinition.PlacementConstraints,
+		ProxyConfiguration:      taskDefinition.ProxyConfiguration,
+		RequiresCompatibilities: taskDefinition.RequiresCompatibilities,
+		RuntimePlatform:         taskDefinition.RuntimePlatform,
+		TaskRoleArn:             taskDefinition.TaskRoleArn,
+		Volumes:                 taskDefinition.Volumes,
+	}
 }
 
 func (ecs *ECS) getDeploymentId(taskDefinitionArn string) string {
@@ -271,6 +774,31 @@ func (ecs *ECS) getDeploymentId(taskDefinitionArn string) string {
 	return contents[len(contents)-1]
 }
 
+func (ecs *ECS) getTaskDefinitionFamily(taskDefinitionArn string) string {
+	contents := strings.Split(taskDefinitionArn, "/")
+	familyAndRevision := strings.Split(contents[len(contents)-1], ":")
+	return familyAndRevision[0]
+}
+
+// getTaskDefinitionRevision parses the revision out of a task definition ARN
+// [e.g. ".../family:12"], returning 0 if it has none.
+func (ecs *ECS) getTaskDefinitionRevision(taskDefinitionArn string) int64 {
+	contents := strings.Split(taskDefinitionArn, "/")
+	familyAndRevision := strings.Split(contents[len(contents)-1], ":")
+
+	if len(familyAndRevision) < 2 {
+		return 0
+	}
+
+	revision, err := strconv.ParseInt(familyAndRevision[1], 10, 64)
+
+	if err != nil {
+		return 0
+	}
+
+	return revision
+}
+
 func (ecs *ECS) GetCpuAndMemoryFromTaskDefinition(taskDefinitionArn string) (string, string) {
 	taskDefinition := ecs.DescribeTaskDefinition(taskDefinitionArn)
 
@@ -0,0 +1,422 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"github.com/jpignata/fargate/ecs/mock/sdk"
+)
+
+const testTaskDefinitionArnPrefix = "arn:aws:ecs:us-east-1:123456789012:task-definition/"
+
+func TestRollbackTaskDefinitionArnPrevious(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	output := &awsecs.ListTaskDefinitionsOutput{
+		TaskDefinitionArns: aws.StringSlice(
+			[]string{
+				testTaskDefinitionArnPrefix + "service_web-worker:9",
+				testTaskDefinitionArnPrefix + "service_web:12",
+				testTaskDefinitionArnPrefix + "service_web:10",
+				testTaskDefinitionArnPrefix + "service_web:8",
+			},
+		),
+	}
+
+	mockECSClient.EXPECT().ListTaskDefinitionsPages(gomock.Any(), gomock.Any()).Do(
+		func(input *awsecs.ListTaskDefinitionsInput, fn func(*awsecs.ListTaskDefinitionsOutput, bool) bool) {
+			if aws.StringValue(input.Status) != awsecs.TaskDefinitionStatusActive {
+				t.Errorf("expected only active revisions to be listed, got %s", aws.StringValue(input.Status))
+			}
+
+			fn(output, true)
+		},
+	).Return(nil)
+
+	arn, err := ecs.RollbackTaskDefinitionArn(testTaskDefinitionArnPrefix+"service_web:12", 0)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if expected := testTaskDefinitionArnPrefix + "service_web:10"; arn != expected {
+		t.Errorf("expected %s, got %s", expected, arn)
+	}
+}
+
+func TestRollbackTaskDefinitionArnNoPrevious(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	output := &awsecs.ListTaskDefinitionsOutput{
+		TaskDefinitionArns: aws.StringSlice([]string{testTaskDefinitionArnPrefix + "service_api:1"}),
+	}
+
+	mockECSClient.EXPECT().ListTaskDefinitionsPages(gomock.Any(), gomock.Any()).Do(
+		func(input *awsecs.ListTaskDefinitionsInput, fn func(*awsecs.ListTaskDefinitionsOutput, bool) bool) {
+			fn(output, true)
+		},
+	).Return(nil)
+
+	if _, err := ecs.RollbackTaskDefinitionArn(testTaskDefinitionArnPrefix+"service_api:1", 0); err == nil {
+		t.Error("expected error, got none")
+	}
+}
+
+func TestRollbackTaskDefinitionArnRevision(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("service_rollback:3")},
+	).Return(
+		&awsecs.DescribeTaskDefinitionOutput{
+			TaskDefinition: &awsecs.TaskDefinition{
+				Status:            aws.String(awsecs.TaskDefinitionStatusInactive),
+				TaskDefinitionArn: aws.String(testTaskDefinitionArnPrefix + "service_rollback:3"),
+			},
+		}, nil,
+	)
+
+	if _, err := ecs.RollbackTaskDefinitionArn(testTaskDefinitionArnPrefix+"service_rollback:5", 5); err == nil {
+		t.Error("expected error rolling back to the current revision, got none")
+	}
+
+	if _, err := ecs.RollbackTaskDefinitionArn(testTaskDefinitionArnPrefix+"service_rollback:5", 3); err == nil {
+		t.Error("expected error rolling back to an inactive revision, got none")
+	}
+}
+
+func TestRegisterTaskDefinitionRevision(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	taskDefinition := &awsecs.TaskDefinition{
+		ContainerDefinitions: []*awsecs.ContainerDefinition{
+			&awsecs.ContainerDefinition{Name: aws.String("web"), Image: aws.String("web:1")},
+			&awsecs.ContainerDefinition{Name: aws.String("proxy"), Image: aws.String("envoy:1")},
+		},
+		Family:          aws.String("service_web"),
+		RuntimePlatform: &awsecs.RuntimePlatform{CpuArchitecture: aws.String("ARM64")},
+		TaskRoleArn:     aws.String("arn:aws:iam::123456789012:role/web"),
+		Volumes:         []*awsecs.Volume{&awsecs.Volume{Name: aws.String("data")}},
+	}
+	output := &awsecs.RegisterTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			TaskDefinitionArn: aws.String(testTaskDefinitionArnPrefix + "service_web:2"),
+		},
+	}
+
+	mockECSClient.EXPECT().RegisterTaskDefinition(gomock.Any()).Do(
+		func(input *awsecs.RegisterTaskDefinitionInput) {
+			if image := aws.StringValue(input.ContainerDefinitions[1].Image); image != "envoy:2" {
+				t.Errorf("expected proxy image envoy:2, got %s", image)
+			}
+
+			if image := aws.StringValue(input.ContainerDefinitions[0].Image); image != "web:1" {
+				t.Errorf("expected web image web:1, got %s", image)
+			}
+
+			if aws.StringValue(input.TaskRoleArn) != aws.StringValue(taskDefinition.TaskRoleArn) {
+				t.Errorf("expected task role to be carried over, got %s", aws.StringValue(input.TaskRoleArn))
+			}
+
+			if len(input.Volumes) != 1 || input.RuntimePlatform == nil {
+				t.Errorf("expected volumes and runtime platform to be carried over, got %v and %v", input.Volumes, input.RuntimePlatform)
+			}
+		},
+	).Return(output, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("service_web")},
+	).Return(&awsecs.DescribeTaskDefinitionOutput{TaskDefinition: taskDefinition}, nil)
+
+	arn, err := ecs.registerTaskDefinitionRevision(taskDefinition, "proxy", func(containerDefinition *awsecs.ContainerDefinition) {
+		containerDefinition.Image = aws.String("envoy:2")
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if expected := testTaskDefinitionArnPrefix + "service_web:2"; arn != expected {
+		t.Errorf("expected %s, got %s", expected, arn)
+	}
+
+	if image := aws.StringValue(taskDefinition.ContainerDefinitions[1].Image); image != "envoy:1" {
+		t.Errorf("expected the described task definition to be left unchanged, got image %s", image)
+	}
+}
+
+func TestAddSecretsToTaskDefinition(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	taskDefinitionArn := testTaskDefinitionArnPrefix + "secrets_job:1"
+	taskDefinition := &awsecs.TaskDefinition{
+		ContainerDefinitions: []*awsecs.ContainerDefinition{
+			&awsecs.ContainerDefinition{
+				Name:        aws.String("job"),
+				Environment: []*awsecs.KeyValuePair{&awsecs.KeyValuePair{Name: aws.String("STAGE"), Value: aws.String("prod")}},
+				Secrets:     []*awsecs.Secret{&awsecs.Secret{Name: aws.String("DB_PASSWORD"), ValueFrom: aws.String("/old/db-password")}},
+			},
+		},
+		Family:            aws.String("secrets_job"),
+		TaskDefinitionArn: aws.String(taskDefinitionArn),
+	}
+	output := &awsecs.RegisterTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			TaskDefinitionArn: aws.String(testTaskDefinitionArnPrefix + "secrets_job:2"),
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(taskDefinitionArn)},
+	).Return(&awsecs.DescribeTaskDefinitionOutput{TaskDefinition: taskDefinition}, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("secrets_job")},
+	).Return(&awsecs.DescribeTaskDefinitionOutput{TaskDefinition: taskDefinition}, nil)
+	mockECSClient.EXPECT().RegisterTaskDefinition(gomock.Any()).Do(
+		func(input *awsecs.RegisterTaskDefinitionInput) {
+			containerDefinition := input.ContainerDefinitions[0]
+
+			if len(containerDefinition.Secrets) != 2 {
+				t.Fatalf("expected 2 secrets, got %d", len(containerDefinition.Secrets))
+			}
+
+			if valueFrom := aws.StringValue(containerDefinition.Secrets[0].ValueFrom); valueFrom != "/new/db-password" {
+				t.Errorf("expected DB_PASSWORD to be replaced with /new/db-password, got %s", valueFrom)
+			}
+
+			if name := aws.StringValue(containerDefinition.Secrets[1].Name); name != "API_KEY" {
+				t.Errorf("expected API_KEY to be added, got %s", name)
+			}
+
+			if len(containerDefinition.Environment) != 1 {
+				t.Errorf("expected environment to be carried over, got %v", containerDefinition.Environment)
+			}
+		},
+	).Return(output, nil)
+
+	arn, err := ecs.AddSecretsToTaskDefinition(
+		taskDefinitionArn,
+		"job",
+		[]Secret{
+			Secret{Name: "DB_PASSWORD", ValueFrom: "/new/db-password"},
+			Secret{Name: "API_KEY", ValueFrom: "/prod/api-key"},
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if expected := testTaskDefinitionArnPrefix + "secrets_job:2"; arn != expected {
+		t.Errorf("expected %s, got %s", expected, arn)
+	}
+
+	if len(taskDefinition.ContainerDefinitions[0].Secrets) != 1 {
+		t.Errorf("expected the described task definition to be left unchanged, got %v", taskDefinition.ContainerDefinitions[0].Secrets)
+	}
+}
+
+func TestAddSecretsToTaskDefinitionUnchanged(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	taskDefinitionArn := testTaskDefinitionArnPrefix + "secrets_unchanged_job:3"
+	taskDefinition := &awsecs.TaskDefinition{
+		ContainerDefinitions: []*awsecs.ContainerDefinition{
+			&awsecs.ContainerDefinition{
+				Name:    aws.String("job"),
+				Secrets: []*awsecs.Secret{&awsecs.Secret{Name: aws.String("API_KEY"), ValueFrom: aws.String("/prod/api-key")}},
+			},
+		},
+		Family:            aws.String("secrets_unchanged_job"),
+		TaskDefinitionArn: aws.String(taskDefinitionArn),
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(taskDefinitionArn)},
+	).Return(&awsecs.DescribeTaskDefinitionOutput{TaskDefinition: taskDefinition}, nil)
+	mockECSClient.EXPECT().RegisterTaskDefinition(gomock.Any()).Times(0)
+
+	arn, err := ecs.AddSecretsToTaskDefinition(
+		taskDefinitionArn,
+		"job",
+		[]Secret{Secret{Name: "API_KEY", ValueFrom: "/prod/api-key"}},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if arn != taskDefinitionArn {
+		t.Errorf("expected %s, got %s", taskDefinitionArn, arn)
+	}
+}
+
+func TestAddSecretsToTaskDefinitionReusesLatestRevision(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	taskDefinitionArn := testTaskDefinitionArnPrefix + "secrets_reused_job:1"
+	latestArn := testTaskDefinitionArnPrefix + "secrets_reused_job:2"
+	taskDefinition := &awsecs.TaskDefinition{
+		ContainerDefinitions: []*awsecs.ContainerDefinition{
+			&awsecs.ContainerDefinition{Name: aws.String("job"), Image: aws.String("job:1")},
+		},
+		Family:            aws.String("secrets_reused_job"),
+		TaskDefinitionArn: aws.String(taskDefinitionArn),
+	}
+	latest := &awsecs.TaskDefinition{
+		ContainerDefinitions: []*awsecs.ContainerDefinition{
+			&awsecs.ContainerDefinition{
+				Name:    aws.String("job"),
+				Image:   aws.String("job:1"),
+				Secrets: []*awsecs.Secret{&awsecs.Secret{Name: aws.String("API_KEY"), ValueFrom: aws.String("/prod/api-key")}},
+			},
+		},
+		Family:            aws.String("secrets_reused_job"),
+		TaskDefinitionArn: aws.String(latestArn),
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(taskDefinitionArn)},
+	).Return(&awsecs.DescribeTaskDefinitionOutput{TaskDefinition: taskDefinition}, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("secrets_reused_job")},
+	).Return(&awsecs.DescribeTaskDefinitionOutput{TaskDefinition: latest}, nil)
+	mockECSClient.EXPECT().RegisterTaskDefinition(gomock.Any()).Times(0)
+
+	arn, err := ecs.AddSecretsToTaskDefinition(
+		taskDefinitionArn,
+		"job",
+		[]Secret{Secret{Name: "API_KEY", ValueFrom: "/prod/api-key"}},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if arn != latestArn {
+		t.Errorf("expected %s, got %s", latestArn, arn)
+	}
+}
+
+func TestUpdateTaskDefinitionCpuAndMemory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	taskDefinitionArn := testTaskDefinitionArnPrefix + "resized_job:1"
+	taskDefinition := &awsecs.TaskDefinition{
+		ContainerDefinitions: []*awsecs.ContainerDefinition{
+			&awsecs.ContainerDefinition{Name: aws.String("job")},
+		},
+		Cpu:               aws.String("256"),
+		Family:            aws.String("resized_job"),
+		Memory:            aws.String("512"),
+		TaskDefinitionArn: aws.String(taskDefinitionArn),
+	}
+	output := &awsecs.RegisterTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			TaskDefinitionArn: aws.String(testTaskDefinitionArnPrefix + "resized_job:2"),
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(taskDefinitionArn)},
+	).Return(&awsecs.DescribeTaskDefinitionOutput{TaskDefinition: taskDefinition}, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("resized_job")},
+	).Return(&awsecs.DescribeTaskDefinitionOutput{TaskDefinition: taskDefinition}, nil)
+	mockECSClient.EXPECT().RegisterTaskDefinition(gomock.Any()).Do(
+		func(input *awsecs.RegisterTaskDefinitionInput) {
+			if aws.StringValue(input.Cpu) != "1024" || aws.StringValue(input.Memory) != "2048" {
+				t.Errorf("expected 1024 CPU units / 2048 MiB, got %s / %s", aws.StringValue(input.Cpu), aws.StringValue(input.Memory))
+			}
+		},
+	).Return(output, nil)
+
+	arn := ecs.UpdateTaskDefinitionCpuAndMemory(taskDefinitionArn, "1024", "2048")
+
+	if expected := testTaskDefinitionArnPrefix + "resized_job:2"; arn != expected {
+		t.Errorf("expected %s, got %s", expected, arn)
+	}
+}
+
+func TestGetLogConfiguration(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	taskDefinitionArn := testTaskDefinitionArnPrefix + "log_configuration_job:1"
+	taskDefinition := &awsecs.TaskDefinition{
+		ContainerDefinitions: []*awsecs.ContainerDefinition{
+			&awsecs.ContainerDefinition{Name: aws.String("sidecar")},
+			&awsecs.ContainerDefinition{
+				LogConfiguration: &awsecs.LogConfiguration{
+					LogDriver: aws.String(awsecs.LogDriverAwslogs),
+					Options: aws.StringMap(
+						map[string]string{
+							"awslogs-group":         "/batch/job",
+							"awslogs-region":        "us-west-2",
+							"awslogs-stream-prefix": "batch",
+						},
+					),
+				},
+				Name: aws.String("job"),
+			},
+		},
+		TaskDefinitionArn: aws.String(taskDefinitionArn),
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(taskDefinitionArn)},
+	).Return(&awsecs.DescribeTaskDefinitionOutput{TaskDefinition: taskDefinition}, nil)
+
+	logConfiguration, err := ecs.GetLogConfiguration(taskDefinitionArn, "job")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := LogConfiguration{
+		ContainerName:   "job",
+		LogGroupName:    "/batch/job",
+		LogRegion:       "us-west-2",
+		LogStreamPrefix: "batch",
+	}
+
+	if logConfiguration != expected {
+		t.Errorf("expected %+v, got %+v", expected, logConfiguration)
+	}
+
+	if streamName := logConfiguration.LogStreamName("abc123"); streamName != "batch/job/abc123" {
+		t.Errorf("expected log stream batch/job/abc123, got %s", streamName)
+	}
+
+	if _, err := ecs.GetLogConfiguration(taskDefinitionArn, "sidecar"); err == nil {
+		t.Error("expected error for a container without awslogs, got none")
+	}
+}
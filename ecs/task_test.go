@@ -0,0 +1,3471 @@
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"github.com/jpignata/fargate/ecs/mock/sdk"
+)
+
+const (
+	testTaskId  = "0d3c1e8d-1a2b-4c5d-8e9f-0a1b2c3d4e5f"
+	testTaskArn = "arn:aws:ecs:us-east-1:123456789012:task/fargate/" + testTaskId
+)
+
+func TestStopTaskAcceptsIdOrArn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	input := &awsecs.StopTaskInput{
+		Cluster: aws.String("fargate"),
+		Task:    aws.String(testTaskId),
+	}
+
+	mockECSClient.EXPECT().StopTask(input).Return(&awsecs.StopTaskOutput{}, nil).Times(2)
+
+	for _, taskId := range []string{testTaskId, testTaskArn} {
+		if err := ecs.stopTask(taskId, ""); err != nil {
+			t.Errorf("expected no error for %s, got %v", taskId, err)
+		}
+	}
+}
+
+func TestStopTasksAcceptsIdOrArn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	input := &awsecs.StopTaskInput{
+		Cluster: aws.String("fargate"),
+		Task:    aws.String(testTaskId),
+	}
+
+	mockECSClient.EXPECT().StopTask(input).Return(&awsecs.StopTaskOutput{}, nil).Times(2)
+
+	if errs := ecs.StopTasks([]string{testTaskId, testTaskArn}, ""); len(errs) > 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
+
+func TestDescribeTasksAcceptsIdOrArn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	input := &awsecs.DescribeTasksInput{
+		Cluster: aws.String("fargate"),
+		Include: aws.StringSlice([]string{awsecs.TaskFieldTags}),
+		Tasks:   aws.StringSlice([]string{testTaskId}),
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(input).Return(&awsecs.DescribeTasksOutput{}, nil)
+
+	if tasks := ecs.DescribeTasks([]string{testTaskId, testTaskArn}); len(tasks) > 0 {
+		t.Errorf("expected no tasks, got %v", tasks)
+	}
+}
+
+func TestDescribeTasksWithDeregisteredTaskDefinition(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/deregistered:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				LastStatus:        aws.String("RUNNING"),
+				TaskArn:           aws.String(testTaskArn),
+				TaskDefinitionArn: aws.String(taskDefinitionArn),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{
+						&awsecs.ContainerOverride{
+							Environment: []*awsecs.KeyValuePair{
+								&awsecs.KeyValuePair{Name: aws.String("FOO"), Value: aws.String("bar")},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("ClientException: Unable to describe task definition."))
+
+	tasks := ecs.DescribeTasks([]string{testTaskId})
+
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+
+	if tasks[0].TaskId != testTaskId {
+		t.Errorf("expected task ID %s, got %s", testTaskId, tasks[0].TaskId)
+	}
+
+	if tasks[0].TaskArn != testTaskArn {
+		t.Errorf("expected task ARN %s, got %s", testTaskArn, tasks[0].TaskArn)
+	}
+
+	if tasks[0].Image != "" || tasks[0].TaskRole != "" {
+		t.Errorf("expected blank image and task role, got %q and %q", tasks[0].Image, tasks[0].TaskRole)
+	}
+
+	if env := tasks[0].EffectiveEnv(); env["FOO"] != "bar" {
+		t.Errorf("expected override environment to be kept, got %v", env)
+	}
+}
+
+func TestDescribeTasksWithoutContainerOverrides(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	otherTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/other-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				LastStatus: aws.String("RUNNING"),
+				TaskArn:    aws.String(testTaskArn),
+				Overrides:  &awsecs.TaskOverride{},
+			},
+			&awsecs.Task{
+				LastStatus: aws.String("RUNNING"),
+				TaskArn:    aws.String(otherTaskArn),
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("not found")).AnyTimes()
+
+	tasks := ecs.DescribeTasks([]string{testTaskId, "other-task"})
+
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(tasks))
+	}
+
+	for _, task := range tasks {
+		if len(task.EnvVars) > 0 || len(task.Command) > 0 {
+			t.Errorf("expected no environment or command, got %v and %v", task.EnvVars, task.Command)
+		}
+	}
+}
+
+func TestDescribeTasksWithOnlyBlankIds(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ecs := ECS{ClusterName: "fargate", svc: sdk.NewMockECSAPI(mockCtrl)}
+
+	if tasks := ecs.DescribeTasks([]string{"", "  "}); len(tasks) > 0 {
+		t.Errorf("expected no tasks, got %v", tasks)
+	}
+}
+
+func TestSanitizeTaskIds(t *testing.T) {
+	taskIds := []string{" abc ", "", testTaskArn, "abc", "  ", testTaskId, "def"}
+	expected := []string{"abc", testTaskId, "def"}
+
+	if sanitized := sanitizeTaskIds(taskIds); !reflect.DeepEqual(sanitized, expected) {
+		t.Errorf("expected %v, got %v", expected, sanitized)
+	}
+}
+
+func TestDescribeTasksMergesEnvironment(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/merge-env:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				TaskArn:           aws.String(testTaskArn),
+				TaskDefinitionArn: aws.String(taskDefinitionArn),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{
+						&awsecs.ContainerOverride{
+							Environment: []*awsecs.KeyValuePair{
+								&awsecs.KeyValuePair{Name: aws.String("MODE"), Value: aws.String("override")},
+								&awsecs.KeyValuePair{Name: aws.String("EXTRA"), Value: aws.String("override")},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	describeTaskDefinitionOutput := &awsecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			ContainerDefinitions: []*awsecs.ContainerDefinition{
+				&awsecs.ContainerDefinition{
+					Environment: []*awsecs.KeyValuePair{
+						&awsecs.KeyValuePair{Name: aws.String("MODE"), Value: aws.String("definition")},
+						&awsecs.KeyValuePair{Name: aws.String("REGION"), Value: aws.String("definition")},
+						&awsecs.KeyValuePair{Name: aws.String("LEVEL"), Value: aws.String("definition")},
+					},
+				},
+			},
+			TaskDefinitionArn: aws.String(taskDefinitionArn),
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(describeTaskDefinitionOutput, nil)
+
+	tasks := ecs.DescribeTasks([]string{testTaskId})
+
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+
+	expected := []EnvVar{
+		EnvVar{Key: "MODE", Value: "override"},
+		EnvVar{Key: "EXTRA", Value: "override"},
+		EnvVar{Key: "REGION", Value: "definition"},
+		EnvVar{Key: "LEVEL", Value: "definition"},
+	}
+
+	if !reflect.DeepEqual(tasks[0].EnvVars, expected) {
+		t.Errorf("expected %v, got %v", expected, tasks[0].EnvVars)
+	}
+}
+
+func TestDescribeTasksEnvironmentWithoutOverrides(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/merge-env-no-overrides:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				TaskArn:           aws.String(testTaskArn),
+				TaskDefinitionArn: aws.String(taskDefinitionArn),
+			},
+		},
+	}
+	describeTaskDefinitionOutput := &awsecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			ContainerDefinitions: []*awsecs.ContainerDefinition{
+				&awsecs.ContainerDefinition{
+					Environment: []*awsecs.KeyValuePair{
+						&awsecs.KeyValuePair{Name: aws.String("MODE"), Value: aws.String("definition")},
+					},
+				},
+			},
+			TaskDefinitionArn: aws.String(taskDefinitionArn),
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(describeTaskDefinitionOutput, nil)
+
+	tasks := ecs.DescribeTasks([]string{testTaskId})
+
+	if expected := []EnvVar{EnvVar{Key: "MODE", Value: "definition"}}; len(tasks) != 1 || !reflect.DeepEqual(tasks[0].EnvVars, expected) {
+		t.Errorf("expected %v, got %+v", expected, tasks)
+	}
+}
+
+func TestTaskEffectiveEnv(t *testing.T) {
+	task := Task{
+		EnvVars: []EnvVar{
+			EnvVar{Key: "PORT", Value: "8080"},
+			EnvVar{Key: "DEBUG", Value: "true"},
+			EnvVar{Key: "PORT", Value: "80"},
+			EnvVar{Key: "REGION", Value: "us-east-1"},
+		},
+		Secrets: []string{"DB_PASSWORD"},
+	}
+	expected := map[string]string{
+		"PORT":        "8080",
+		"DEBUG":       "true",
+		"REGION":      "us-east-1",
+		"DB_PASSWORD": "<secret>",
+	}
+
+	if env := task.EffectiveEnv(); !reflect.DeepEqual(env, expected) {
+		t.Errorf("expected %v, got %v", expected, env)
+	}
+}
+
+func TestTaskUsesImage(t *testing.T) {
+	task := Task{
+		Image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/web:abc123",
+		Containers: []Container{
+			Container{Image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/web:abc123"},
+			Container{Image: "nginx:1.25.3"},
+		},
+	}
+	tests := []struct {
+		image    string
+		exact    bool
+		expected bool
+	}{
+		{"nginx:1.25.3", true, true},
+		{"nginx:", false, true},
+		{"nginx:", true, false},
+		{"/web:", false, true},
+		{"redis", false, false},
+	}
+
+	for _, test := range tests {
+		if uses := task.usesImage(test.image, test.exact); uses != test.expected {
+			t.Errorf("expected %t for %s (exact: %t), got %t", test.expected, test.image, test.exact, uses)
+		}
+	}
+}
+
+func TestTaskFilterMatches(t *testing.T) {
+	task := Task{DesiredStatus: "RUNNING", LastStatus: "PROVISIONING"}
+	tests := []struct {
+		filter   TaskFilter
+		expected bool
+	}{
+		{TaskFilter{}, true},
+		{TaskFilter{DesiredStatus: "RUNNING"}, true},
+		{TaskFilter{DesiredStatus: "STOPPED"}, false},
+		{TaskFilter{LastStatuses: []string{"PENDING", "PROVISIONING"}}, true},
+		{TaskFilter{LastStatuses: []string{"RUNNING"}}, false},
+		{TaskFilter{DesiredStatus: "RUNNING", LastStatuses: []string{"RUNNING"}}, false},
+	}
+
+	for _, test := range tests {
+		if matches := test.filter.Matches(task); matches != test.expected {
+			t.Errorf("expected %t for %+v, got %t", test.expected, test.filter, matches)
+		}
+	}
+}
+
+func TestMarshalTasks(t *testing.T) {
+	tasks := []Task{
+		Task{
+			CreatedAt:  time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC),
+			EnvVars:    []EnvVar{EnvVar{Key: "PORT", Value: "80"}},
+			LastStatus: "RUNNING",
+			TaskId:     "abcdef",
+		},
+	}
+
+	out, err := MarshalTasks(tasks)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var decoded []map[string]interface{}
+
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(decoded))
+	}
+
+	for key, expected := range map[string]interface{}{
+		"task_id":     "abcdef",
+		"last_status": "RUNNING",
+		"created_at":  "2018-01-02T03:04:05Z",
+	} {
+		if decoded[0][key] != expected {
+			t.Errorf("expected %s to be %v, got %v", key, expected, decoded[0][key])
+		}
+	}
+
+	if envVars, ok := decoded[0]["env_vars"].([]interface{}); !ok || len(envVars) != 1 {
+		t.Errorf("expected 1 env var, got %v", decoded[0]["env_vars"])
+	}
+}
+
+func TestMarshalTasksEmpty(t *testing.T) {
+	out, err := MarshalTasks(nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if string(out) != "[]" {
+		t.Errorf("expected [], got %s", out)
+	}
+}
+
+func TestPartitionCount(t *testing.T) {
+	tests := []struct {
+		count    int64
+		n        int
+		expected []int64
+	}{
+		{6, 3, []int64{2, 2, 2}},
+		{7, 3, []int64{3, 2, 2}},
+		{8, 3, []int64{3, 3, 2}},
+		{2, 3, []int64{1, 1, 0}},
+		{1, 1, []int64{1}},
+	}
+
+	for _, test := range tests {
+		if shares := partitionCount(test.count, test.n); !reflect.DeepEqual(shares, test.expected) {
+			t.Errorf("expected %v for %d across %d, got %v", test.expected, test.count, test.n, shares)
+		}
+	}
+}
+
+func TestTaskGroupNameFromStartedBy(t *testing.T) {
+	tests := []struct {
+		startedBy string
+		name      string
+		ok        bool
+	}{
+		{"fargate:web", "web", true},
+		{"fargate:data:nightly", "data:nightly", true},
+		{"ecs-svc/1234567890123456789", "", false},
+		{"", "", false},
+		{"fargate:", "", false},
+		{"notfargate:web", "", false},
+		{"fargate", "", false},
+	}
+
+	ecs := ECS{}
+
+	for _, test := range tests {
+		name, ok := ecs.taskGroupNameFromStartedBy(test.startedBy)
+
+		if name != test.name || ok != test.ok {
+			t.Errorf("expected (%q, %t) for %q, got (%q, %t)", test.name, test.ok, test.startedBy, name, ok)
+		}
+	}
+}
+
+func TestTaskGroupNameFromStartedByWithPrefix(t *testing.T) {
+	ecs := ECS{StartedByPrefix: "team.a"}
+
+	if name, ok := ecs.taskGroupNameFromStartedBy("team.a:web"); !ok || name != "web" {
+		t.Errorf("expected (web, true), got (%q, %t)", name, ok)
+	}
+
+	if name, ok := ecs.taskGroupNameFromStartedBy("teamXa:web"); ok {
+		t.Errorf("expected no match, got %q", name)
+	}
+
+	if name, ok := ecs.taskGroupNameFromStartedBy("fargate:web"); ok {
+		t.Errorf("expected no match, got %q", name)
+	}
+}
+
+// expectTaskDefinition expects the task definition to be described any number
+// of times, describing it as having a container of each of the given names.
+func expectTaskDefinition(mockECSClient *sdk.MockECSAPI, taskDefinitionArn string, containerNames ...string) {
+	var containerDefinitions []*awsecs.ContainerDefinition
+
+	for _, containerName := range containerNames {
+		containerDefinitions = append(containerDefinitions, &awsecs.ContainerDefinition{Name: aws.String(containerName)})
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(taskDefinitionArn)},
+	).Return(
+		&awsecs.DescribeTaskDefinitionOutput{
+			TaskDefinition: &awsecs.TaskDefinition{
+				ContainerDefinitions: containerDefinitions,
+				TaskDefinitionArn:    aws.String(taskDefinitionArn),
+			},
+		},
+		nil,
+	).AnyTimes()
+}
+
+func expectListTasks(mockECSClient *sdk.MockECSAPI, taskArns ...string) {
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice(taskArns)}, true)
+		},
+	).Return(nil)
+}
+
+func testTaskGroupTask(taskArn, taskDefinitionArn, subnetId string, createdAt time.Time) *awsecs.Task {
+	return &awsecs.Task{
+		Attachments: []*awsecs.Attachment{
+			&awsecs.Attachment{
+				Type: aws.String("ElasticNetworkInterface"),
+				Details: []*awsecs.KeyValuePair{
+					&awsecs.KeyValuePair{Name: aws.String("networkInterfaceId"), Value: aws.String("eni-" + taskIdFromArn(taskArn))},
+					&awsecs.KeyValuePair{Name: aws.String("subnetId"), Value: aws.String(subnetId)},
+				},
+			},
+		},
+		CreatedAt:         aws.Time(createdAt),
+		DesiredStatus:     aws.String(awsecs.DesiredStatusRunning),
+		LastStatus:        aws.String(awsecs.DesiredStatusRunning),
+		StartedBy:         aws.String("fargate:web"),
+		TaskArn:           aws.String(taskArn),
+		TaskDefinitionArn: aws.String(taskDefinitionArn),
+		Overrides: &awsecs.TaskOverride{
+			ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{Name: aws.String("web")}},
+		},
+	}
+}
+
+func TestBuildRunTaskInputsSpread(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	expectTaskDefinition(mockECSClient, "task_web:1", "web")
+
+	input := &RunTaskInput{
+		ClusterName:         "fargate",
+		Count:               3,
+		SecurityGroupIds:    []string{"sg-abcdef"},
+		SpreadAcrossSubnets: true,
+		SubnetIds:           []string{"subnet-a", "subnet-b"},
+		TaskDefinitionArn:   "task_web:1",
+		TaskName:            "web",
+	}
+
+	runTaskInputs, err := ecs.BuildRunTaskInputs(input, nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(runTaskInputs) != 2 {
+		t.Fatalf("expected 2 inputs, got %d", len(runTaskInputs))
+	}
+
+	for index, expected := range []struct {
+		count  int64
+		subnet string
+	}{{2, "subnet-a"}, {1, "subnet-b"}} {
+		runTaskInput := runTaskInputs[index]
+		subnets := aws.StringValueSlice(runTaskInput.NetworkConfiguration.AwsvpcConfiguration.Subnets)
+
+		if aws.Int64Value(runTaskInput.Count) != expected.count || !reflect.DeepEqual(subnets, []string{expected.subnet}) {
+			t.Errorf("expected %d tasks in %s, got %d in %v", expected.count, expected.subnet, aws.Int64Value(runTaskInput.Count), subnets)
+		}
+
+		if startedBy := aws.StringValue(runTaskInput.StartedBy); startedBy != "fargate:web" {
+			t.Errorf("expected startedBy fargate:web, got %s", startedBy)
+		}
+	}
+}
+
+func TestBuildRunTaskInputsWithTaskDefinition(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	createTaskDefinitionInput := &CreateTaskDefinitionInput{
+		Cpu:        "256",
+		Image:      "migrate:1",
+		LaunchType: awsecs.LaunchTypeEc2,
+		Memory:     "512",
+		Name:       "migrate",
+		Type:       "task",
+	}
+	taskDefinition, err := createTaskDefinitionInput.TaskDefinition()
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	input := &RunTaskInput{
+		AppendArgs:        []string{"--trace"},
+		Count:             1,
+		LaunchType:        awsecs.LaunchTypeEc2,
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: "task_migrate",
+		TaskName:          "migrate",
+	}
+
+	// Neither the unregistered task definition nor its family is described,
+	// and nothing is registered or run.
+	runTaskInputs, err := ecs.BuildRunTaskInputs(input, taskDefinition)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	runTaskInput := runTaskInputs[0]
+
+	if runTaskInput.NetworkConfiguration == nil {
+		t.Error("expected a network configuration for the awsvpc network mode, got none")
+	}
+
+	containerOverride := runTaskInput.Overrides.ContainerOverrides[0]
+
+	if name := aws.StringValue(containerOverride.Name); name != "migrate" {
+		t.Errorf("expected container override for migrate, got %s", name)
+	}
+
+	if command := aws.StringValueSlice(containerOverride.Command); !reflect.DeepEqual(command, []string{"--trace"}) {
+		t.Errorf("expected command [--trace], got %v", command)
+	}
+
+	if input.ContainerName != "" {
+		t.Errorf("expected the input to be left unchanged, got container %s", input.ContainerName)
+	}
+}
+
+func TestBuildRunTaskInputsResolvesTaskDefinitionFamily(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := testTaskDefinitionArnPrefix + "dry_run_job:4"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			ContainerDefinitions: []*awsecs.ContainerDefinition{
+				&awsecs.ContainerDefinition{Name: aws.String("job")},
+			},
+			TaskDefinitionArn: aws.String(taskDefinitionArn),
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("dry_run_job")},
+	).Return(describeOutput, nil)
+	mockECSClient.EXPECT().RegisterTaskDefinition(gomock.Any()).Times(0)
+
+	input := &RunTaskInput{
+		Count:             1,
+		EntryPoint:        []string{"/bin/sh"},
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: "dry_run_job",
+		TaskName:          "job",
+	}
+
+	runTaskInputs, err := ecs.BuildRunTaskInputs(input, nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if arn := aws.StringValue(runTaskInputs[0].TaskDefinition); arn != taskDefinitionArn {
+		t.Errorf("expected task definition %s, got %s", taskDefinitionArn, arn)
+	}
+}
+
+func TestBuildRunTaskInputsCapacityProviderStrategy(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	expectTaskDefinition(mockECSClient, "task_web:1", "web")
+
+	input := &RunTaskInput{
+		CapacityProviders: []CapacityProviderStrategyItem{
+			CapacityProviderStrategyItem{CapacityProvider: "FARGATE_SPOT", Weight: 4},
+			CapacityProviderStrategyItem{Base: 1, CapacityProvider: "FARGATE", Weight: 1},
+		},
+		ClusterName:       "fargate",
+		Count:             1,
+		SecurityGroupIds:  []string{"sg-abcdef"},
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: "task_web:1",
+		TaskName:          "web",
+	}
+
+	runTaskInputs, err := ecs.BuildRunTaskInputs(input, nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	runTaskInput := runTaskInputs[0]
+
+	if runTaskInput.LaunchType != nil {
+		t.Errorf("expected no launch type, got %s", aws.StringValue(runTaskInput.LaunchType))
+	}
+
+	expected := []*awsecs.CapacityProviderStrategyItem{
+		&awsecs.CapacityProviderStrategyItem{Base: aws.Int64(0), CapacityProvider: aws.String("FARGATE_SPOT"), Weight: aws.Int64(4)},
+		&awsecs.CapacityProviderStrategyItem{Base: aws.Int64(1), CapacityProvider: aws.String("FARGATE"), Weight: aws.Int64(1)},
+	}
+
+	if !reflect.DeepEqual(runTaskInput.CapacityProviderStrategy, expected) {
+		t.Errorf("expected %v, got %v", expected, runTaskInput.CapacityProviderStrategy)
+	}
+}
+
+func TestBuildRunTaskInputsNoPublicIp(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	expectTaskDefinition(mockECSClient, "task_web:1", "web")
+
+	for _, noPublicIp := range []bool{false, true} {
+		input := &RunTaskInput{
+			ClusterName:       "fargate",
+			Count:             1,
+			NoPublicIp:        noPublicIp,
+			SecurityGroupIds:  []string{"sg-abcdef"},
+			SubnetIds:         []string{"subnet-a"},
+			TaskDefinitionArn: "task_web:1",
+			TaskName:          "web",
+		}
+
+		runTaskInputs, err := ecs.BuildRunTaskInputs(input, nil)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		expected := awsecs.AssignPublicIpEnabled
+
+		if noPublicIp {
+			expected = awsecs.AssignPublicIpDisabled
+		}
+
+		if assignPublicIp := aws.StringValue(runTaskInputs[0].NetworkConfiguration.AwsvpcConfiguration.AssignPublicIp); assignPublicIp != expected {
+			t.Errorf("expected public IP assignment %s with NoPublicIp %t, got %s", expected, noPublicIp, assignPublicIp)
+		}
+	}
+}
+
+func TestBuildRunTaskInputsCpuAndMemoryOverrides(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	expectTaskDefinition(mockECSClient, "task_backfill:1", "backfill")
+
+	input := &RunTaskInput{
+		ClusterName:       "fargate",
+		Count:             1,
+		Cpu:               "2048",
+		Memory:            "8192",
+		SecurityGroupIds:  []string{"sg-abcdef"},
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: "task_backfill:1",
+		TaskName:          "backfill",
+	}
+
+	runTaskInputs, err := ecs.BuildRunTaskInputs(input, nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	overrides := runTaskInputs[0].Overrides
+
+	if aws.StringValue(overrides.Cpu) != "2048" || aws.StringValue(overrides.Memory) != "8192" {
+		t.Errorf("expected overrides of 2048 CPU units / 8192 MiB, got %s / %s", aws.StringValue(overrides.Cpu), aws.StringValue(overrides.Memory))
+	}
+
+	input.Memory = "512"
+
+	if _, err := ecs.BuildRunTaskInputs(input, nil); err == nil {
+		t.Error("expected error for invalid CPU and memory combination, got none")
+	}
+}
+
+func TestBuildRunTaskInputsSubnetPlacements(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	expectTaskDefinition(mockECSClient, "task_web:1", "web")
+
+	input := &RunTaskInput{
+		ClusterName:       "fargate",
+		Count:             3,
+		SecurityGroupIds:  []string{"sg-abcdef"},
+		SubnetIds:         []string{"subnet-c"},
+		SubnetPlacements:  []SubnetPlacement{{1, "subnet-a"}, {2, "subnet-b"}},
+		TaskDefinitionArn: "task_web:1",
+		TaskName:          "web",
+	}
+
+	runTaskInputs, err := ecs.BuildRunTaskInputs(input, nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(runTaskInputs) != 2 {
+		t.Fatalf("expected 2 inputs, got %d", len(runTaskInputs))
+	}
+
+	for index, expected := range input.SubnetPlacements {
+		runTaskInput := runTaskInputs[index]
+		subnets := aws.StringValueSlice(runTaskInput.NetworkConfiguration.AwsvpcConfiguration.Subnets)
+
+		if aws.Int64Value(runTaskInput.Count) != expected.Count || !reflect.DeepEqual(subnets, []string{expected.SubnetId}) {
+			t.Errorf("expected %d tasks in %s, got %d in %v", expected.Count, expected.SubnetId, aws.Int64Value(runTaskInput.Count), subnets)
+		}
+	}
+}
+
+func TestBuildRunTaskInputsInvalidLaunchType(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	expectTaskDefinition(mockECSClient, "task_web:1", "web")
+
+	input := &RunTaskInput{
+		Count:             1,
+		LaunchType:        "LAMBDA",
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: "task_web:1",
+		TaskName:          "web",
+	}
+
+	if _, err := ecs.BuildRunTaskInputs(input, nil); err == nil {
+		t.Errorf("expected error, got none")
+	}
+}
+
+func TestTagTasks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeInput := &awsecs.DescribeTasksInput{
+		Cluster: aws.String("fargate"),
+		Tasks:   aws.StringSlice([]string{testTaskId, "missing"}),
+	}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{TaskArn: aws.String(testTaskArn)},
+		},
+		Failures: []*awsecs.Failure{
+			&awsecs.Failure{Arn: aws.String("missing"), Reason: aws.String("MISSING")},
+		},
+	}
+	tagInput := &awsecs.TagResourceInput{
+		ResourceArn: aws.String(testTaskArn),
+		Tags: []*awsecs.Tag{
+			&awsecs.Tag{Key: aws.String("team"), Value: aws.String("data")},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(describeInput).Return(describeOutput, nil)
+	mockECSClient.EXPECT().TagResource(tagInput).Return(&awsecs.TagResourceOutput{}, nil)
+
+	errs := ecs.TagTasks([]string{testTaskId, "missing"}, []Tag{Tag{Key: "team", Value: "data"}})
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+
+	if expected := "could not find task missing: missing"; errs[0].Error() != expected {
+		t.Errorf("expected error %s, got %v", expected, errs[0])
+	}
+}
+
+func TestUntagTasksWithArn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	untagInput := &awsecs.UntagResourceInput{
+		ResourceArn: aws.String(testTaskArn),
+		TagKeys:     aws.StringSlice([]string{"team"}),
+	}
+
+	mockECSClient.EXPECT().UntagResource(untagInput).Return(&awsecs.UntagResourceOutput{}, nil)
+
+	if errs := ecs.UntagTasks([]string{testTaskArn}, []string{"team"}); len(errs) > 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
+
+func TestClassifyStop(t *testing.T) {
+	tests := []struct {
+		stopCode      string
+		stoppedReason string
+		containers    []Container
+		expected      string
+	}{
+		{"TaskFailedToStart", "CannotPullContainerError: pull image manifest has been retried 5 time(s): not found", nil, StopCategoryImagePullFailure},
+		{"EssentialContainerExited", "Essential container in task exited", []Container{Container{Reason: "OutOfMemoryError: Container killed due to memory usage"}}, StopCategoryOutOfMemory},
+		{"EssentialContainerExited", "Essential container in task exited", []Container{Container{}}, StopCategoryEssentialContainerExited},
+		{"EssentialContainerExited", "Essential container in task exited", []Container{Container{ExitCode: aws.Int64(1)}}, StopCategoryEssentialContainerExited},
+		{"EssentialContainerExited", "Essential container in task exited", []Container{Container{ExitCode: aws.Int64(0)}, Container{}}, StopCategorySucceeded},
+		{"UserInitiated", "Task stopped by user", nil, StopCategoryUserInitiated},
+		{"ServiceSchedulerInitiated", "Scaling activity initiated by deployment", nil, StopCategoryOther},
+	}
+
+	for _, test := range tests {
+		if category := classifyStop(test.stopCode, test.stoppedReason, test.containers); category != test.expected {
+			t.Errorf("expected %q for %s (%s), got %q", test.expected, test.stopCode, test.stoppedReason, category)
+		}
+	}
+}
+
+func TestSummarizeStops(t *testing.T) {
+	start := time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC)
+	tasks := []Task{
+		Task{TaskId: "a", StopCategory: StopCategorySucceeded, StoppedAt: start},
+		Task{TaskId: "b", StopCategory: StopCategoryOutOfMemory, StoppedAt: start},
+		Task{TaskId: "c", StopCategory: StopCategorySucceeded, StoppedAt: start.Add(time.Minute)},
+		Task{TaskId: "d"},
+		Task{TaskId: "e", StopCategory: StopCategoryImagePullFailure, StoppedAt: start},
+		Task{TaskId: "f", StopCategory: StopCategorySucceeded, StoppedAt: start.Add(-time.Minute)},
+	}
+	expected := []StopCategoryCount{
+		StopCategoryCount{Category: StopCategorySucceeded, Count: 3, SampleTaskId: "c"},
+		StopCategoryCount{Category: StopCategoryImagePullFailure, Count: 1, SampleTaskId: "e"},
+		StopCategoryCount{Category: StopCategoryOutOfMemory, Count: 1, SampleTaskId: "b"},
+	}
+
+	if counts := summarizeStops(tasks); !reflect.DeepEqual(counts, expected) {
+		t.Errorf("expected %+v, got %+v", expected, counts)
+	}
+}
+
+func TestTaskDefinitionDrift(t *testing.T) {
+	tasks := []Task{
+		Task{TaskId: "1", TaskDefinitionFamily: "web", TaskDefinitionRevision: 5, Image: "web:5"},
+		Task{TaskId: "2", TaskDefinitionFamily: "web", TaskDefinitionRevision: 7, Image: "web:7"},
+		Task{TaskId: "3", TaskDefinitionFamily: "web", TaskDefinitionRevision: 5, Image: "web:5"},
+		Task{TaskId: "4", TaskDefinitionFamily: "cron", TaskDefinitionRevision: 2, Image: "cron:2"},
+	}
+	latest := map[string]*awsecs.TaskDefinition{
+		"web": &awsecs.TaskDefinition{
+			ContainerDefinitions: []*awsecs.ContainerDefinition{
+				&awsecs.ContainerDefinition{Image: aws.String("web:7")},
+			},
+			Revision: aws.Int64(7),
+		},
+		"cron": &awsecs.TaskDefinition{
+			ContainerDefinitions: []*awsecs.ContainerDefinition{
+				&awsecs.ContainerDefinition{Image: aws.String("cron:2")},
+			},
+			Revision: aws.Int64(2),
+		},
+	}
+	expected := []TaskDefinitionDrift{
+		TaskDefinitionDrift{Family: "cron", LatestImage: "cron:2", LatestRevision: 2, TaskCount: 1},
+		TaskDefinitionDrift{
+			Family:         "web",
+			LatestImage:    "web:7",
+			LatestRevision: 7,
+			StaleTasks: []StaleTask{
+				StaleTask{Image: "web:5", Revision: 5, TaskId: "1"},
+				StaleTask{Image: "web:5", Revision: 5, TaskId: "3"},
+			},
+			TaskCount: 3,
+		},
+	}
+
+	if drifts := taskDefinitionDrift(tasks, latest); !reflect.DeepEqual(drifts, expected) {
+		t.Errorf("expected %+v, got %+v", expected, drifts)
+	}
+}
+
+func TestTaskStopSummary(t *testing.T) {
+	task := Task{
+		Containers:    []Container{Container{Name: "web"}},
+		StopCategory:  StopCategoryImagePullFailure,
+		StoppedReason: "CannotPullContainerError: tag not found",
+	}
+
+	if expected, summary := "image pull failed: CannotPullContainerError: tag not found", task.StopSummary(); summary != expected {
+		t.Errorf("expected %q, got %q", expected, summary)
+	}
+
+	if summary := (Task{}).StopSummary(); summary != "" {
+		t.Errorf("expected no summary for a running task, got %q", summary)
+	}
+}
+
+func TestTaskExitCodes(t *testing.T) {
+	task := Task{
+		Containers: []Container{Container{Name: "web", ExitCode: aws.Int64(1)}},
+	}
+
+	if codes := task.ExitCodes(); codes != "1" {
+		t.Errorf("expected 1, got %q", codes)
+	}
+
+	task.Containers = append(task.Containers, Container{Name: "nginx"})
+
+	if expected, codes := "web=1 nginx=-", task.ExitCodes(); codes != expected {
+		t.Errorf("expected %q, got %q", expected, codes)
+	}
+}
+
+func TestListTasksIncludeStopped(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var desiredStatuses []string
+
+	stoppedTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/stopped-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			desiredStatus := aws.StringValue(input.DesiredStatus)
+			desiredStatuses = append(desiredStatuses, desiredStatus)
+
+			if desiredStatus == awsecs.DesiredStatusStopped {
+				fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{stoppedTaskArn})}, true)
+			} else {
+				fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{testTaskArn})}, true)
+			}
+		},
+	).Return(nil).Times(2)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+		&awsecs.DescribeTasksOutput{
+			Tasks: []*awsecs.Task{&awsecs.Task{TaskArn: aws.String(testTaskArn), LastStatus: aws.String("RUNNING")}},
+		}, nil,
+	)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+		&awsecs.DescribeTasksOutput{
+			Tasks: []*awsecs.Task{&awsecs.Task{TaskArn: aws.String(stoppedTaskArn), LastStatus: aws.String("STOPPED")}},
+		}, nil,
+	)
+
+	tasks := ecs.listTasks(&awsecs.ListTasksInput{Cluster: aws.String("fargate")}, TaskFilter{IncludeStopped: true})
+
+	if expected := []string{awsecs.DesiredStatusRunning, awsecs.DesiredStatusStopped}; !reflect.DeepEqual(desiredStatuses, expected) {
+		t.Errorf("expected tasks listed with desired statuses %v, got %v", expected, desiredStatuses)
+	}
+
+	if len(tasks) != 2 || tasks[0].Stopped() || !tasks[1].Stopped() {
+		t.Errorf("expected a running task followed by a stopped task, got %+v", tasks)
+	}
+}
+
+func TestListTasksFilterDoesNotModifyInput(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+	input := &awsecs.ListTasksInput{Cluster: aws.String("fargate")}
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, listInput *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			if desiredStatus := aws.StringValue(listInput.DesiredStatus); desiredStatus != awsecs.DesiredStatusStopped {
+				t.Errorf("expected tasks listed with desired status %s, got %q", awsecs.DesiredStatusStopped, desiredStatus)
+			}
+
+			fn(&awsecs.ListTasksOutput{}, true)
+		},
+	).Return(nil)
+
+	ecs.listTasks(input, TaskFilter{DesiredStatus: awsecs.DesiredStatusStopped})
+
+	if input.DesiredStatus != nil {
+		t.Errorf("expected input to be left unchanged, got desired status %s", aws.StringValue(input.DesiredStatus))
+	}
+}
+
+func TestBuildRunTaskInputsMemoryReservationOnFargate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	expectTaskDefinition(mockECSClient, "task_web:1", "web")
+
+	input := &RunTaskInput{
+		Count:             1,
+		MemoryReservation: 256,
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: "task_web:1",
+		TaskName:          "web",
+	}
+
+	if _, err := ecs.BuildRunTaskInputs(input, nil); err == nil {
+		t.Errorf("expected error, got none")
+	}
+}
+
+func TestBuildRunTaskInputsMemoryReservationOnEc2(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/reserved:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			TaskDefinitionArn: aws.String(taskDefinitionArn),
+			ContainerDefinitions: []*awsecs.ContainerDefinition{
+				&awsecs.ContainerDefinition{Name: aws.String("proxy"), Memory: aws.Int64(128)},
+				&awsecs.ContainerDefinition{Name: aws.String("app"), Memory: aws.Int64(1024)},
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(describeOutput, nil)
+
+	input := &RunTaskInput{
+		ContainerName:     "app",
+		Count:             1,
+		LaunchType:        awsecs.LaunchTypeEc2,
+		MemoryReservation: 512,
+		TaskDefinitionArn: taskDefinitionArn,
+		TaskName:          "reserved",
+	}
+
+	runTaskInputs, err := ecs.BuildRunTaskInputs(input, nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	containerOverride := runTaskInputs[0].Overrides.ContainerOverrides[0]
+
+	if name := aws.StringValue(containerOverride.Name); name != "app" {
+		t.Errorf("expected container override for app, got %s", name)
+	}
+
+	if memoryReservation := aws.Int64Value(containerOverride.MemoryReservation); memoryReservation != 512 {
+		t.Errorf("expected memory reservation 512, got %d", memoryReservation)
+	}
+
+	input.MemoryReservation = 2048
+
+	if _, err := ecs.BuildRunTaskInputs(input, nil); err == nil {
+		t.Error("expected error for memory reservation above app's memory limit, got none")
+	}
+}
+func TestStopTasksWithReason(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var taskIds []string
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	for i := 0; i < 2*stopTasksConcurrency+1; i++ {
+		taskIds = append(taskIds, fmt.Sprintf("task-%d", i))
+	}
+
+	mockECSClient.EXPECT().StopTask(gomock.Any()).Do(
+		func(input *awsecs.StopTaskInput) {
+			if reason := aws.StringValue(input.Reason); reason != "Deploying v2" {
+				t.Errorf("expected reason Deploying v2, got %q", reason)
+			}
+		},
+	).Return(&awsecs.StopTaskOutput{}, nil).Times(len(taskIds))
+
+	stoppedTaskIds, errs := ecs.stopTasks(taskIds, "Deploying v2")
+
+	if len(errs) > 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+
+	if !reflect.DeepEqual(stoppedTaskIds, taskIds) {
+		t.Errorf("expected %v, got %v", taskIds, stoppedTaskIds)
+	}
+}
+
+func TestStopAllTasksRequiresConfirmation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ecs := ECS{ClusterName: "fargate", svc: sdk.NewMockECSAPI(mockCtrl)}
+
+	if _, err := ecs.StopAllTasks(&StopAllTasksInput{}); err == nil {
+		t.Error("expected error without confirmation, got none")
+	}
+}
+
+func TestStopAllTasksSkipsServiceTasks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	serviceTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/service-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	listOutput := &awsecs.ListTasksOutput{
+		TaskArns: aws.StringSlice([]string{testTaskArn, serviceTaskArn}),
+	}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				StartedBy: aws.String("fargate:web"),
+				TaskArn:   aws.String(testTaskArn),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+				},
+			},
+			&awsecs.Task{
+				StartedBy: aws.String("ecs-svc/1234567890"),
+				TaskArn:   aws.String(serviceTaskArn),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+				},
+			},
+		},
+	}
+	stopInput := &awsecs.StopTaskInput{
+		Cluster: aws.String("fargate"),
+		Task:    aws.String(testTaskId),
+	}
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			fn(listOutput, true)
+		},
+	).Return(nil)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("not found")).AnyTimes()
+	mockECSClient.EXPECT().StopTask(stopInput).Return(&awsecs.StopTaskOutput{}, nil)
+
+	result, err := ecs.StopAllTasks(&StopAllTasksInput{Confirm: true})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(result.TaskIds, []string{testTaskId}) {
+		t.Errorf("expected task IDs %v, got %v", []string{testTaskId}, result.TaskIds)
+	}
+
+	if !reflect.DeepEqual(result.SkippedServiceTaskIds, []string{"service-task"}) {
+		t.Errorf("expected skipped service task IDs %v, got %v", []string{"service-task"}, result.SkippedServiceTaskIds)
+	}
+
+	if result.Stopped() != 1 {
+		t.Errorf("expected 1 stopped task, got %d", result.Stopped())
+	}
+}
+
+func TestBuildRunTaskInputsContainerName(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/nightly-batch:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			TaskDefinitionArn: aws.String(taskDefinitionArn),
+			ContainerDefinitions: []*awsecs.ContainerDefinition{
+				&awsecs.ContainerDefinition{Name: aws.String("app")},
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(describeOutput, nil)
+
+	input := &RunTaskInput{
+		Command:           []string{"rake", "nightly"},
+		ContainerName:     "app",
+		Count:             1,
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: taskDefinitionArn,
+		TaskName:          "nightly-batch",
+	}
+
+	runTaskInputs, err := ecs.BuildRunTaskInputs(input, nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if name := aws.StringValue(runTaskInputs[0].Overrides.ContainerOverrides[0].Name); name != "app" {
+		t.Errorf("expected container override for app, got %s", name)
+	}
+
+	input.ContainerName = "worker"
+
+	if _, err := ecs.BuildRunTaskInputs(input, nil); err == nil {
+		t.Error("expected error for unknown container, got none")
+	}
+}
+
+func TestRunTaskInputResolveContainerName(t *testing.T) {
+	taskDefinition := &awsecs.TaskDefinition{
+		ContainerDefinitions: []*awsecs.ContainerDefinition{
+			&awsecs.ContainerDefinition{Name: aws.String("app")},
+			&awsecs.ContainerDefinition{Name: aws.String("nightly-batch")},
+		},
+	}
+	tests := []struct {
+		input    RunTaskInput
+		expected string
+	}{
+		{RunTaskInput{ContainerName: "app", TaskName: "nightly-batch"}, "app"},
+		{RunTaskInput{TaskName: "nightly-batch"}, "nightly-batch"},
+		{RunTaskInput{TaskName: "weekly-batch"}, "app"},
+		{RunTaskInput{}, "app"},
+	}
+
+	for _, test := range tests {
+		input := test.input
+
+		if err := input.resolveContainerName(taskDefinition); err != nil {
+			t.Errorf("expected no error for %+v, got %v", test.input, err)
+		}
+
+		if input.ContainerName != test.expected {
+			t.Errorf("expected container %s for %+v, got %s", test.expected, test.input, input.ContainerName)
+		}
+	}
+
+	input := RunTaskInput{ContainerName: "worker", TaskName: "nightly-batch"}
+
+	if err := input.resolveContainerName(taskDefinition); err == nil {
+		t.Error("expected error for unknown container, got none")
+	}
+}
+
+func TestGetTaskDefinitionRevision(t *testing.T) {
+	ecs := ECS{}
+	tests := []struct {
+		taskDefinitionArn string
+		expected          int64
+	}{
+		{"arn:aws:ecs:us-east-1:123456789012:task-definition/web:12", 12},
+		{"web:3", 3},
+		{"web", 0},
+		{"arn:aws:ecs:us-east-1:123456789012:task-definition/web:latest", 0},
+	}
+
+	for _, test := range tests {
+		if revision := ecs.getTaskDefinitionRevision(test.taskDefinitionArn); revision != test.expected {
+			t.Errorf("expected revision %d for %s, got %d", test.expected, test.taskDefinitionArn, revision)
+		}
+	}
+}
+
+type mockTargetFinder map[string]string
+
+func (f mockTargetFinder) FindTargetState(targetGroupArn, targetId string, port int64) (string, error) {
+	return f[fmt.Sprintf("%s/%s:%d", targetGroupArn, targetId, port)], nil
+}
+
+func TestFindTargetRegistrations(t *testing.T) {
+	finder := mockTargetFinder{
+		"tg-1/10.0.0.1:80":   "healthy",
+		"tg-2/10.0.0.1:8080": "draining",
+	}
+	task := Task{
+		PortMappings:     []PortMapping{PortMapping{ContainerPort: 80}, PortMapping{ContainerPort: 8080}},
+		PrivateIpAddress: "10.0.0.1",
+	}
+	expected := []TargetRegistration{
+		TargetRegistration{Port: 80, State: "healthy", TargetGroupArn: "tg-1"},
+		TargetRegistration{Port: 8080, State: "draining", TargetGroupArn: "tg-2"},
+	}
+
+	registrations, err := FindTargetRegistrations(task, []string{"tg-1", "tg-2", "tg-3"}, finder)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(registrations, expected) {
+		t.Errorf("expected %v, got %v", expected, registrations)
+	}
+
+	task.PrivateIpAddress = ""
+
+	if registrations, _ := FindTargetRegistrations(task, []string{"tg-1"}, finder); len(registrations) > 0 {
+		t.Errorf("expected no registrations without a private IP, got %v", registrations)
+	}
+}
+
+func TestRunTaskInputValidate(t *testing.T) {
+	tests := []struct {
+		input *RunTaskInput
+		valid bool
+	}{
+		{&RunTaskInput{Count: 1, SubnetIds: []string{"subnet-a"}, TaskDefinitionArn: "task_web:1"}, true},
+		{&RunTaskInput{Count: 1, LaunchType: "EC2", TaskDefinitionArn: "task_web:1"}, true},
+		{&RunTaskInput{Count: 1, SubnetIds: []string{"subnet-a"}}, false},
+		{&RunTaskInput{Count: 0, SubnetIds: []string{"subnet-a"}, TaskDefinitionArn: "task_web:1"}, false},
+		{&RunTaskInput{Count: -1, SubnetIds: []string{"subnet-a"}, TaskDefinitionArn: "task_web:1"}, false},
+		{&RunTaskInput{Count: 1, TaskDefinitionArn: "task_web:1"}, false},
+		{&RunTaskInput{Count: 1, LaunchType: "FARGATE", TaskDefinitionArn: "task_web:1"}, false},
+		{&RunTaskInput{Count: 1, DnsServers: []string{"10.0.0.2"}, SubnetIds: []string{"subnet-a"}, TaskDefinitionArn: "task_web:1"}, false},
+		{&RunTaskInput{Count: 1, DnsServers: []string{"10.0.0.2"}, LaunchType: "EC2", TaskDefinitionArn: "task_web:1"}, true},
+		{&RunTaskInput{Count: 3, SubnetPlacements: []SubnetPlacement{{1, "subnet-a"}, {2, "subnet-b"}}, TaskDefinitionArn: "task_web:1"}, true},
+		{&RunTaskInput{Count: 2, SubnetPlacements: []SubnetPlacement{{1, "subnet-a"}, {2, "subnet-b"}}, TaskDefinitionArn: "task_web:1"}, false},
+		{&RunTaskInput{Count: 2, SubnetPlacements: []SubnetPlacement{{1, "subnet-a"}, {1, "subnet-a"}}, TaskDefinitionArn: "task_web:1"}, false},
+		{&RunTaskInput{Count: 2, SubnetPlacements: []SubnetPlacement{{2, "subnet-a"}, {0, "subnet-b"}}, TaskDefinitionArn: "task_web:1"}, false},
+		{&RunTaskInput{Count: 2, SpreadAcrossSubnets: true, SubnetPlacements: []SubnetPlacement{{2, "subnet-a"}}, TaskDefinitionArn: "task_web:1"}, false},
+		{&RunTaskInput{Count: 1, PlatformVersion: "1.4.0", SubnetIds: []string{"subnet-a"}, TaskDefinitionArn: "task_web:1"}, true},
+		{&RunTaskInput{Count: 1, PlatformVersion: "LATEST", SubnetIds: []string{"subnet-a"}, TaskDefinitionArn: "task_web:1"}, true},
+		{&RunTaskInput{Count: 1, PlatformVersion: "1.4", SubnetIds: []string{"subnet-a"}, TaskDefinitionArn: "task_web:1"}, false},
+		{&RunTaskInput{Count: 1, LaunchType: "EC2", PlatformVersion: "1.4.0", TaskDefinitionArn: "task_web:1"}, false},
+	}
+
+	for _, test := range tests {
+		if err := test.input.Validate(); (err == nil) != test.valid {
+			t.Errorf("expected valid to be %t for %+v, got error %v", test.valid, test.input, err)
+		}
+	}
+}
+
+func TestDescribeTasksForServiceIncludeStopped(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", LaunchType: "FARGATE", svc: mockECSClient}
+	runningInput := &awsecs.ListTasksInput{
+		Cluster:       aws.String("fargate"),
+		DesiredStatus: aws.String("RUNNING"),
+		LaunchType:    aws.String("FARGATE"),
+		ServiceName:   aws.String("web"),
+	}
+	stoppedInput := &awsecs.ListTasksInput{
+		Cluster:       aws.String("fargate"),
+		DesiredStatus: aws.String("STOPPED"),
+		LaunchType:    aws.String("FARGATE"),
+		ServiceName:   aws.String("web"),
+	}
+
+	gomock.InOrder(
+		mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), runningInput, gomock.Any()).Return(nil),
+		mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), stoppedInput, gomock.Any()).Return(nil),
+	)
+
+	if tasks := ecs.DescribeTasksForService("web", TaskFilter{IncludeStopped: true}); len(tasks) > 0 {
+		t.Errorf("expected no tasks, got %v", tasks)
+	}
+}
+
+func TestListTasksDeduplicatesAcrossPages(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	otherTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/other-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeInput := &awsecs.DescribeTasksInput{
+		Cluster: aws.String("fargate"),
+		Include: aws.StringSlice([]string{awsecs.TaskFieldTags}),
+		Tasks:   aws.StringSlice([]string{testTaskId}),
+	}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				TaskArn: aws.String(testTaskArn),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+				},
+			},
+		},
+	}
+	otherDescribeInput := &awsecs.DescribeTasksInput{
+		Cluster: aws.String("fargate"),
+		Include: aws.StringSlice([]string{awsecs.TaskFieldTags}),
+		Tasks:   aws.StringSlice([]string{"other-task"}),
+	}
+	otherDescribeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				TaskArn: aws.String(otherTaskArn),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+				},
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{testTaskArn})}, false)
+			fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{testTaskArn, otherTaskArn})}, true)
+		},
+	).Return(nil)
+	mockECSClient.EXPECT().DescribeTasks(describeInput).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTasks(otherDescribeInput).Return(otherDescribeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("not found")).AnyTimes()
+
+	tasks := ecs.listTasks(&awsecs.ListTasksInput{Cluster: aws.String("fargate")}, TaskFilter{})
+
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(tasks))
+	}
+
+	if tasks[0].TaskId != testTaskId || tasks[1].TaskId != "other-task" {
+		t.Errorf("expected tasks %s and other-task, got %s and %s", testTaskId, tasks[0].TaskId, tasks[1].TaskId)
+	}
+}
+
+func TestDescribeTasksConnectivity(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	connectivityAt := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	overrides := &awsecs.TaskOverride{
+		ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+	}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				Connectivity:   aws.String("CONNECTED"),
+				ConnectivityAt: aws.Time(connectivityAt),
+				Overrides:      overrides,
+				TaskArn:        aws.String(testTaskArn),
+			},
+			&awsecs.Task{
+				Overrides: overrides,
+				TaskArn:   aws.String("arn:aws:ecs:us-east-1:123456789012:task/fargate/provisioning"),
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("not found")).AnyTimes()
+
+	tasks := ecs.DescribeTasks([]string{testTaskId, "provisioning"})
+
+	if tasks[0].Connectivity != "CONNECTED" || tasks[0].ConnectivityAt == nil || !tasks[0].ConnectivityAt.Equal(connectivityAt) {
+		t.Errorf("expected CONNECTED at %s, got %q at %v", connectivityAt, tasks[0].Connectivity, tasks[0].ConnectivityAt)
+	}
+
+	if tasks[1].Connectivity != "" || tasks[1].ConnectivityAt != nil {
+		t.Errorf("expected no connectivity, got %q at %v", tasks[1].Connectivity, tasks[1].ConnectivityAt)
+	}
+
+	if output, err := json.Marshal(tasks[1]); err != nil || strings.Contains(string(output), "connectivity_at") {
+		t.Errorf("expected no connectivity time in JSON output, got %s (%v)", output, err)
+	}
+}
+
+func TestRunTaskDnsWithAwsvpcRegistersNoRevisions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/dns-awsvpc:1"
+	describeOutput := &awsecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			TaskDefinitionArn: aws.String(taskDefinitionArn),
+			NetworkMode:       aws.String(awsecs.NetworkModeAwsvpc),
+			ContainerDefinitions: []*awsecs.ContainerDefinition{
+				&awsecs.ContainerDefinition{Name: aws.String("web")},
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(describeOutput, nil).AnyTimes()
+	mockECSClient.EXPECT().RegisterTaskDefinition(gomock.Any()).Times(0)
+	mockECSClient.EXPECT().RunTask(gomock.Any()).Times(0)
+
+	_, err := ecs.runTask(
+		&RunTaskInput{
+			Count:             1,
+			DnsServers:        []string{"10.0.0.2"},
+			EntryPoint:        []string{"/bin/sh"},
+			LaunchType:        awsecs.LaunchTypeEc2,
+			Secrets:           []Secret{Secret{Name: "TOKEN", ValueFrom: "arn:aws:ssm:us-east-1:123456789012:parameter/token"}},
+			SubnetIds:         []string{"subnet-a"},
+			TaskDefinitionArn: taskDefinitionArn,
+			TaskName:          "web",
+		},
+	)
+
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	if !strings.Contains(err.Error(), awsecs.NetworkModeAwsvpc) {
+		t.Errorf("expected network mode error, got %v", err)
+	}
+}
+
+func TestRunTaskToCompletion(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var followed string
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	runTaskOutput := &awsecs.RunTaskOutput{
+		Tasks: []*awsecs.Task{&awsecs.Task{TaskArn: aws.String(testTaskArn)}},
+	}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				Containers: []*awsecs.Container{
+					&awsecs.Container{Name: aws.String("sidecar"), ExitCode: aws.Int64(0)},
+					&awsecs.Container{Name: aws.String("job"), ExitCode: aws.Int64(3)},
+				},
+				LastStatus: aws.String("STOPPED"),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+				},
+				TaskArn: aws.String(testTaskArn),
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().RunTask(gomock.Any()).Return(runTaskOutput, nil)
+	mockECSClient.EXPECT().WaitUntilTasksStoppedWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	expectTaskDefinition(mockECSClient, "task_job:1", "sidecar", "job")
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("not found")).AnyTimes()
+
+	input := &RunTaskInput{
+		Count:             1,
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: "task_job:1",
+		TaskName:          "job",
+	}
+
+	exitCode, err := ecs.RunTaskToCompletion(
+		context.Background(),
+		input,
+		func(taskId string) error {
+			followed = taskId
+			return nil
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", exitCode)
+	}
+
+	if followed != testTaskId {
+		t.Errorf("expected to follow task %s, got %s", testTaskId, followed)
+	}
+}
+
+func TestRunTaskToCompletionMultipleTasks(t *testing.T) {
+	ecs := ECS{ClusterName: "fargate"}
+	input := &RunTaskInput{Count: 2, SubnetIds: []string{"subnet-a"}, TaskDefinitionArn: "task_job:1"}
+
+	if _, err := ecs.RunTaskToCompletion(context.Background(), input, nil); err == nil {
+		t.Error("expected error, got none")
+	}
+}
+
+func TestDescribeTasksLightweight(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				CapacityProviderName: aws.String("FARGATE_SPOT"),
+				LastStatus:           aws.String("RUNNING"),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+				},
+				TaskArn:           aws.String(testTaskArn),
+				TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/web:4"),
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+
+	tasks := ecs.DescribeTasks([]string{testTaskId})
+
+	if len(tasks) != 1 || tasks[0].LastStatus != "RUNNING" || tasks[0].TaskDefinitionFamily != "web" {
+		t.Errorf("expected running task from family web, got %+v", tasks)
+	}
+
+	if tasks[0].CapacityProviderName != "FARGATE_SPOT" {
+		t.Errorf("expected capacity provider FARGATE_SPOT, got %q", tasks[0].CapacityProviderName)
+	}
+}
+
+func TestRunTaskToCompletionCanceled(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	runTaskOutput := &awsecs.RunTaskOutput{
+		Tasks: []*awsecs.Task{&awsecs.Task{TaskArn: aws.String(testTaskArn)}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancel()
+
+	mockECSClient.EXPECT().RunTask(gomock.Any()).Return(runTaskOutput, nil)
+	mockECSClient.EXPECT().WaitUntilTasksStoppedWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("waiter context canceled"))
+	expectTaskDefinition(mockECSClient, "task_job:1", "job")
+
+	input := &RunTaskInput{
+		Count:             1,
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: "task_job:1",
+		TaskName:          "job",
+	}
+
+	_, err := ecs.RunTaskToCompletion(ctx, input, nil)
+
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	if !strings.Contains(err.Error(), testTaskId) {
+		t.Errorf("expected error to name task %s, got %v", testTaskId, err)
+	}
+}
+
+func TestWaitForTasksRunning(t *testing.T) {
+	var tests = []struct {
+		lastStatus string
+		expectErr  bool
+	}{
+		{"RUNNING", false},
+		{"PENDING", true},
+		{"STOPPED", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.lastStatus, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+			ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+
+			mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+				&awsecs.DescribeTasksOutput{
+					Tasks: []*awsecs.Task{
+						&awsecs.Task{TaskArn: aws.String(testTaskArn), LastStatus: aws.String(test.lastStatus)},
+					},
+				}, nil,
+			)
+
+			err := ecs.WaitForTasksRunning([]string{testTaskId}, 0)
+
+			if test.expectErr && err == nil {
+				t.Errorf("expected error for %s task, got none", test.lastStatus)
+			}
+
+			if !test.expectErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWaitForTasksRunningNotFound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(&awsecs.DescribeTasksOutput{}, nil)
+
+	err := ecs.WaitForTasksRunning([]string{testTaskId}, 0)
+
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	if expected := "tasks not running after 0s: " + testTaskId + " (not found)"; err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err)
+	}
+}
+
+func TestWaitUntilContainerHealthy(t *testing.T) {
+	var tests = []struct {
+		name         string
+		healthCheck  *awsecs.HealthCheck
+		healthStatus string
+		lastStatus   string
+		err          string
+	}{
+		{"healthy", &awsecs.HealthCheck{}, "HEALTHY", "RUNNING", ""},
+		{"no health check", nil, "UNKNOWN", "RUNNING", "container app has no health check defined"},
+		{"unhealthy", &awsecs.HealthCheck{}, "UNHEALTHY", "RUNNING", "container app in task " + testTaskId + " is unhealthy"},
+		{"stopped", &awsecs.HealthCheck{}, "UNKNOWN", "STOPPED", "task " + testTaskId + " stopped before container app became healthy"},
+		{"timeout", &awsecs.HealthCheck{}, "UNKNOWN", "RUNNING", "container app in task " + testTaskId + " not healthy after 0s"},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			taskDefinitionArn := fmt.Sprintf("arn:aws:ecs:us-east-1:123456789012:task-definition/container-health:%d", i+1)
+			mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+			ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+			describeOutput := &awsecs.DescribeTasksOutput{
+				Tasks: []*awsecs.Task{
+					&awsecs.Task{
+						Containers: []*awsecs.Container{
+							&awsecs.Container{Name: aws.String("sidecar"), HealthStatus: aws.String("HEALTHY")},
+							&awsecs.Container{Name: aws.String("app"), HealthStatus: aws.String(test.healthStatus)},
+						},
+						LastStatus:        aws.String(test.lastStatus),
+						TaskArn:           aws.String(testTaskArn),
+						TaskDefinitionArn: aws.String(taskDefinitionArn),
+					},
+				},
+			}
+			describeTaskDefinitionOutput := &awsecs.DescribeTaskDefinitionOutput{
+				TaskDefinition: &awsecs.TaskDefinition{
+					ContainerDefinitions: []*awsecs.ContainerDefinition{
+						&awsecs.ContainerDefinition{Name: aws.String("sidecar"), HealthCheck: &awsecs.HealthCheck{}},
+						&awsecs.ContainerDefinition{Name: aws.String("app"), HealthCheck: test.healthCheck},
+					},
+					TaskDefinitionArn: aws.String(taskDefinitionArn),
+				},
+			}
+
+			mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+			mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(describeTaskDefinitionOutput, nil)
+
+			err := ecs.WaitUntilContainerHealthy(testTaskId, "app", 0)
+
+			switch {
+			case test.err == "" && err != nil:
+				t.Errorf("expected no error, got %v", err)
+			case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestBuildRunTaskInputsAppendArgs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/migrate:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			TaskDefinitionArn: aws.String(taskDefinitionArn),
+			ContainerDefinitions: []*awsecs.ContainerDefinition{
+				&awsecs.ContainerDefinition{
+					Command: aws.StringSlice([]string{"rake", "db:migrate"}),
+					Name:    aws.String("migrate"),
+				},
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(describeOutput, nil)
+
+	input := &RunTaskInput{
+		AppendArgs:        []string{"--trace"},
+		Count:             1,
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: taskDefinitionArn,
+		TaskName:          "migrate",
+	}
+
+	runTaskInputs, err := ecs.BuildRunTaskInputs(input, nil)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{"rake", "db:migrate", "--trace"}
+
+	if command := aws.StringValueSlice(runTaskInputs[0].Overrides.ContainerOverrides[0].Command); !reflect.DeepEqual(command, expected) {
+		t.Errorf("expected command %v, got %v", expected, command)
+	}
+
+	input.Command = []string{"rake", "db:seed"}
+
+	if _, err := ecs.BuildRunTaskInputs(input, nil); err == nil {
+		t.Error("expected error with both command and append args, got none")
+	}
+}
+
+func TestStopTasksOlderThan(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	tasks := []Task{
+		Task{TaskId: "old", CreatedAt: time.Now().Add(-48 * time.Hour)},
+		Task{TaskId: "new", CreatedAt: time.Now().Add(-time.Hour)},
+	}
+	stopInput := &awsecs.StopTaskInput{
+		Cluster: aws.String("fargate"),
+		Reason:  aws.String("Cleaning up"),
+		Task:    aws.String("old"),
+	}
+
+	mockECSClient.EXPECT().StopTask(stopInput).Return(&awsecs.StopTaskOutput{}, nil)
+
+	taskIds, errs := ecs.stopTasksOlderThan(tasks, 24*time.Hour, "Cleaning up")
+
+	if len(errs) > 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+
+	if !reflect.DeepEqual(taskIds, []string{"old"}) {
+		t.Errorf("expected [old], got %v", taskIds)
+	}
+}
+
+func TestStopAllTasksOlderThanSkipsServiceTasks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	newTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/new-task"
+	serviceTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/service-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	listOutput := &awsecs.ListTasksOutput{
+		TaskArns: aws.StringSlice([]string{testTaskArn, newTaskArn, serviceTaskArn}),
+	}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				CreatedAt: aws.Time(time.Now().Add(-48 * time.Hour)),
+				StartedBy: aws.String("fargate:web"),
+				TaskArn:   aws.String(testTaskArn),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+				},
+			},
+			&awsecs.Task{
+				CreatedAt: aws.Time(time.Now().Add(-time.Hour)),
+				StartedBy: aws.String("fargate:web"),
+				TaskArn:   aws.String(newTaskArn),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+				},
+			},
+			&awsecs.Task{
+				CreatedAt: aws.Time(time.Now().Add(-48 * time.Hour)),
+				StartedBy: aws.String("ecs-svc/1234567890"),
+				TaskArn:   aws.String(serviceTaskArn),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+				},
+			},
+		},
+	}
+	stopInput := &awsecs.StopTaskInput{
+		Cluster: aws.String("fargate"),
+		Reason:  aws.String("Cleaning up"),
+		Task:    aws.String(testTaskId),
+	}
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			fn(listOutput, true)
+		},
+	).Return(nil)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("not found")).AnyTimes()
+	mockECSClient.EXPECT().StopTask(stopInput).Return(&awsecs.StopTaskOutput{}, nil)
+
+	taskIds, errs := ecs.StopAllTasksOlderThan(24*time.Hour, false, "Cleaning up")
+
+	if len(errs) > 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+
+	if !reflect.DeepEqual(taskIds, []string{testTaskId}) {
+		t.Errorf("expected %v, got %v", []string{testTaskId}, taskIds)
+	}
+}
+
+func TestListTaskGroupsWithPrefix(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	otherTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/other-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	listOutput := &awsecs.ListTasksOutput{
+		TaskArns: aws.StringSlice([]string{testTaskArn, otherTaskArn}),
+	}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				StartedBy: aws.String("fargate:data-import"),
+				TaskArn:   aws.String(testTaskArn),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+				},
+			},
+			&awsecs.Task{
+				StartedBy: aws.String("fargate:web-worker"),
+				TaskArn:   aws.String(otherTaskArn),
+				Overrides: &awsecs.TaskOverride{
+					ContainerOverrides: []*awsecs.ContainerOverride{&awsecs.ContainerOverride{}},
+				},
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			fn(listOutput, true)
+		},
+	).Return(nil)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("not found")).AnyTimes()
+
+	taskGroups := ecs.ListTaskGroups(&ListTaskGroupsInput{Prefix: "data-"}, TaskFilter{})
+
+	if len(taskGroups) != 1 {
+		t.Fatalf("expected 1 task group, got %d", len(taskGroups))
+	}
+
+	if taskGroups[0].TaskGroupName != "data-import" {
+		t.Errorf("expected task group data-import, got %s", taskGroups[0].TaskGroupName)
+	}
+}
+
+func TestListTaskGroupsIncludeStopped(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskArn := func(taskId string) string {
+		return "arn:aws:ecs:us-east-1:123456789012:task/fargate/" + taskId
+	}
+	task := func(taskId, taskGroupName, desiredStatus string) *awsecs.Task {
+		return &awsecs.Task{
+			DesiredStatus:     aws.String(desiredStatus),
+			StartedBy:         aws.String("fargate:" + taskGroupName),
+			TaskArn:           aws.String(taskArn(taskId)),
+			TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/" + taskGroupName + ":1"),
+		}
+	}
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			if aws.StringValue(input.DesiredStatus) == awsecs.DesiredStatusStopped {
+				fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{taskArn("web-3"), taskArn("batch-1")})}, true)
+			} else {
+				fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{taskArn("web-1"), taskArn("web-2"), taskArn("worker-1")})}, true)
+			}
+		},
+	).Return(nil).Times(2)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+		&awsecs.DescribeTasksOutput{
+			Tasks: []*awsecs.Task{
+				task("web-1", "web", awsecs.DesiredStatusRunning),
+				task("web-2", "web", awsecs.DesiredStatusRunning),
+				task("worker-1", "worker", awsecs.DesiredStatusRunning),
+			},
+		}, nil,
+	)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+		&awsecs.DescribeTasksOutput{
+			Tasks: []*awsecs.Task{
+				task("web-3", "web", awsecs.DesiredStatusStopped),
+				task("batch-1", "batch", awsecs.DesiredStatusStopped),
+			},
+		}, nil,
+	)
+
+	taskGroups := ecs.ListTaskGroups(&ListTaskGroupsInput{}, TaskFilter{IncludeStopped: true})
+	expected := []*TaskGroup{
+		&TaskGroup{TaskGroupName: "web", Instances: 2, Stopped: 1, Families: []string{"web"}},
+		&TaskGroup{TaskGroupName: "worker", Instances: 1, Families: []string{"worker"}},
+		&TaskGroup{TaskGroupName: "batch", Stopped: 1, Families: []string{"batch"}},
+	}
+
+	if len(taskGroups) != len(expected) {
+		t.Fatalf("expected %d task groups, got %d", len(expected), len(taskGroups))
+	}
+
+	for i := range expected {
+		if !reflect.DeepEqual(taskGroups[i], expected[i]) {
+			t.Errorf("expected task group %+v, got %+v", *expected[i], *taskGroups[i])
+		}
+	}
+}
+
+func TestListTaskGroupsWithLaunchType(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", LaunchType: awsecs.LaunchTypeEc2, svc: mockECSClient}
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			if launchType := aws.StringValue(input.LaunchType); launchType != awsecs.LaunchTypeEc2 {
+				t.Errorf("expected launch type %s, got %s", awsecs.LaunchTypeEc2, launchType)
+			}
+
+			fn(&awsecs.ListTasksOutput{}, true)
+		},
+	).Return(nil)
+
+	if taskGroups := ecs.ListTaskGroups(&ListTaskGroupsInput{}, TaskFilter{}); len(taskGroups) != 0 {
+		t.Errorf("expected no task groups, got %d", len(taskGroups))
+	}
+}
+
+// testNetworkInterfaceFinder finds network interfaces with the given security
+// groups. None of them have a public IP.
+type testNetworkInterfaceFinder map[string][]string
+
+func (f testNetworkInterfaceFinder) FindNetworkInterfaces(eniIds []string) (map[string][]string, map[string]string, error) {
+	securityGroupIds := make(map[string][]string)
+	publicIps := make(map[string]string)
+
+	for _, eniId := range eniIds {
+		if groupIds, ok := f[eniId]; ok {
+			securityGroupIds[eniId] = groupIds
+			publicIps[eniId] = ""
+		}
+	}
+
+	return securityGroupIds, publicIps, nil
+}
+
+func TestScaleTaskGroupUp(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/scale-up:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{
+		ClusterName:       "fargate",
+		NetworkInterfaces: testNetworkInterfaceFinder{"eni-" + taskIdFromArn(testTaskArn): []string{"sg-a"}},
+		svc:               mockECSClient,
+	}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{testTaskGroupTask(testTaskArn, taskDefinitionArn, "subnet-a", time.Now())},
+	}
+	runTaskOutput := &awsecs.RunTaskOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{TaskArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task/fargate/new-1")},
+			&awsecs.Task{TaskArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task/fargate/new-2")},
+		},
+	}
+
+	expectListTasks(mockECSClient, testTaskArn)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	expectTaskDefinition(mockECSClient, taskDefinitionArn, "web")
+	mockECSClient.EXPECT().RunTask(gomock.Any()).Do(
+		func(input *awsecs.RunTaskInput) {
+			if count := aws.Int64Value(input.Count); count != 2 {
+				t.Errorf("expected 2 tasks to be run, got %d", count)
+			}
+
+			if startedBy := aws.StringValue(input.StartedBy); startedBy != "fargate:web" {
+				t.Errorf("expected started by fargate:web, got %s", startedBy)
+			}
+
+			vpcConfiguration := input.NetworkConfiguration.AwsvpcConfiguration
+
+			if subnets := aws.StringValueSlice(vpcConfiguration.Subnets); !reflect.DeepEqual(subnets, []string{"subnet-a"}) {
+				t.Errorf("expected subnets [subnet-a], got %v", subnets)
+			}
+
+			if securityGroups := aws.StringValueSlice(vpcConfiguration.SecurityGroups); !reflect.DeepEqual(securityGroups, []string{"sg-a"}) {
+				t.Errorf("expected security groups [sg-a], got %v", securityGroups)
+			}
+
+			if assignPublicIp := aws.StringValue(vpcConfiguration.AssignPublicIp); assignPublicIp != awsecs.AssignPublicIpDisabled {
+				t.Errorf("expected public IP assignment %s, got %s", awsecs.AssignPublicIpDisabled, assignPublicIp)
+			}
+		},
+	).Return(runTaskOutput, nil)
+
+	if err := ecs.ScaleTaskGroup("web", 3); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestScaleTaskGroupUpWithoutNetworkInterface(t *testing.T) {
+	tests := []struct {
+		name              string
+		networkInterfaces NetworkInterfaceFinder
+	}{
+		{name: "no finder"},
+		{name: "interface not found", networkInterfaces: testNetworkInterfaceFinder{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/scale-up-no-eni:1"
+			mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+			ecs := ECS{ClusterName: "fargate", NetworkInterfaces: test.networkInterfaces, svc: mockECSClient}
+			describeOutput := &awsecs.DescribeTasksOutput{
+				Tasks: []*awsecs.Task{testTaskGroupTask(testTaskArn, taskDefinitionArn, "subnet-a", time.Now())},
+			}
+
+			expectListTasks(mockECSClient, testTaskArn)
+			mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+			expectTaskDefinition(mockECSClient, taskDefinitionArn, "web")
+			mockECSClient.EXPECT().RunTask(gomock.Any()).Times(0)
+
+			if err := ecs.ScaleTaskGroup("web", 2); err == nil {
+				t.Error("expected error, got none")
+			}
+		})
+	}
+}
+
+func TestScaleTaskGroupDown(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/scale-down:1"
+	newerTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/newer-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			testTaskGroupTask(testTaskArn, taskDefinitionArn, "subnet-a", time.Now().Add(-time.Hour)),
+			testTaskGroupTask(newerTaskArn, taskDefinitionArn, "subnet-a", time.Now()),
+		},
+	}
+	stopInput := &awsecs.StopTaskInput{
+		Cluster: aws.String("fargate"),
+		Reason:  aws.String("Scaled task group web down to 1"),
+		Task:    aws.String("newer-task"),
+	}
+
+	expectListTasks(mockECSClient, testTaskArn, newerTaskArn)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	expectTaskDefinition(mockECSClient, taskDefinitionArn, "web")
+	mockECSClient.EXPECT().StopTask(stopInput).Return(&awsecs.StopTaskOutput{}, nil)
+
+	if err := ecs.ScaleTaskGroup("web", 1); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestScaleTaskGroupUnchanged(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/scale-unchanged:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{testTaskGroupTask(testTaskArn, taskDefinitionArn, "subnet-a", time.Now())},
+	}
+
+	expectListTasks(mockECSClient, testTaskArn)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	expectTaskDefinition(mockECSClient, taskDefinitionArn, "web")
+	mockECSClient.EXPECT().RunTask(gomock.Any()).Times(0)
+	mockECSClient.EXPECT().StopTask(gomock.Any()).Times(0)
+
+	if err := ecs.ScaleTaskGroup("web", 1); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestScaleTaskGroupNotFound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	expectListTasks(mockECSClient)
+
+	err := ecs.ScaleTaskGroup("web", 2)
+
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	if !strings.Contains(err.Error(), "task group web not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestScaleTaskGroupNegative(t *testing.T) {
+	ecs := ECS{ClusterName: "fargate"}
+
+	if err := ecs.ScaleTaskGroup("web", -1); err == nil {
+		t.Error("expected error, got none")
+	}
+}
+
+func TestRestartTasks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/restart:1"
+	newTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/new-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	task := Task{
+		NoPublicIp:        true,
+		SecurityGroupIds:  []string{"sg-a"},
+		StartedBy:         "fargate:web",
+		SubnetId:          "subnet-a",
+		TaskDefinitionArn: taskDefinitionArn,
+		TaskId:            testTaskId,
+	}
+	runTaskOutput := &awsecs.RunTaskOutput{
+		Tasks: []*awsecs.Task{&awsecs.Task{TaskArn: aws.String(newTaskArn)}},
+	}
+	stopInput := &awsecs.StopTaskInput{
+		Cluster: aws.String("fargate"),
+		Reason:  aws.String("Replaced by task new-task"),
+		Task:    aws.String(testTaskId),
+	}
+
+	expectTaskDefinition(mockECSClient, taskDefinitionArn, "web")
+
+	gomock.InOrder(
+		mockECSClient.EXPECT().RunTask(gomock.Any()).Do(
+			func(input *awsecs.RunTaskInput) {
+				if count := aws.Int64Value(input.Count); count != 1 {
+					t.Errorf("expected 1 task to be run, got %d", count)
+				}
+
+				if securityGroups := aws.StringValueSlice(input.NetworkConfiguration.AwsvpcConfiguration.SecurityGroups); !reflect.DeepEqual(securityGroups, []string{"sg-a"}) {
+					t.Errorf("expected security groups [sg-a], got %v", securityGroups)
+				}
+			},
+		).Return(runTaskOutput, nil),
+		mockECSClient.EXPECT().WaitUntilTasksRunning(
+			&awsecs.DescribeTasksInput{
+				Cluster: aws.String("fargate"),
+				Tasks:   aws.StringSlice([]string{"new-task"}),
+			},
+		).Return(nil),
+		mockECSClient.EXPECT().StopTask(stopInput).Return(&awsecs.StopTaskOutput{}, nil),
+	)
+
+	restarts := ecs.RestartTasks([]Task{task})
+	expected := []TaskRestart{TaskRestart{TaskId: testTaskId, NewTaskId: "new-task"}}
+
+	if !reflect.DeepEqual(restarts, expected) {
+		t.Errorf("expected %v, got %v", expected, restarts)
+	}
+}
+
+func TestRestartTasksReplacementNotRunning(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/restart-not-running:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	task := Task{
+		SecurityGroupIds:  []string{"sg-a"},
+		StartedBy:         "fargate:web",
+		SubnetId:          "subnet-a",
+		TaskDefinitionArn: taskDefinitionArn,
+		TaskId:            testTaskId,
+	}
+	runTaskOutput := &awsecs.RunTaskOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{TaskArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task/fargate/new-task")},
+		},
+	}
+
+	expectTaskDefinition(mockECSClient, taskDefinitionArn, "web")
+	mockECSClient.EXPECT().RunTask(gomock.Any()).Return(runTaskOutput, nil)
+	mockECSClient.EXPECT().WaitUntilTasksRunning(gomock.Any()).Return(errors.New("exceeded max wait time"))
+	mockECSClient.EXPECT().StopTask(gomock.Any()).Times(0)
+
+	restarts := ecs.RestartTasks([]Task{task})
+
+	if len(restarts) != 1 {
+		t.Fatalf("expected 1 restart, got %d", len(restarts))
+	}
+
+	if restarts[0].NewTaskId != "new-task" {
+		t.Errorf("expected new task new-task, got %s", restarts[0].NewTaskId)
+	}
+
+	if restarts[0].Err == nil || !strings.Contains(restarts[0].Err.Error(), "left task "+testTaskId+" running") {
+		t.Errorf("expected error leaving task %s running, got %v", testTaskId, restarts[0].Err)
+	}
+}
+
+func TestRestartTasksLooksUpNetworkInterface(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/restart-eni:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{
+		ClusterName:       "fargate",
+		NetworkInterfaces: testNetworkInterfaceFinder{"eni-1": []string{"sg-b"}},
+		svc:               mockECSClient,
+	}
+	task := Task{
+		EniId:             "eni-1",
+		EnvVars:           []EnvVar{EnvVar{Key: "RUN", Value: "1"}, EnvVar{Key: "DEFINED", Value: "1"}},
+		StartedBy:         "fargate:web",
+		SubnetId:          "subnet-a",
+		TaskDefinitionArn: taskDefinitionArn,
+		TaskId:            testTaskId,
+		envVarOverrides:   []EnvVar{EnvVar{Key: "RUN", Value: "1"}},
+	}
+	runTaskOutput := &awsecs.RunTaskOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{TaskArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task/fargate/new-task")},
+		},
+	}
+
+	expectTaskDefinition(mockECSClient, taskDefinitionArn, "web")
+	mockECSClient.EXPECT().RunTask(gomock.Any()).Do(
+		func(input *awsecs.RunTaskInput) {
+			vpcConfiguration := input.NetworkConfiguration.AwsvpcConfiguration
+
+			if securityGroups := aws.StringValueSlice(vpcConfiguration.SecurityGroups); !reflect.DeepEqual(securityGroups, []string{"sg-b"}) {
+				t.Errorf("expected security groups [sg-b], got %v", securityGroups)
+			}
+
+			if assignPublicIp := aws.StringValue(vpcConfiguration.AssignPublicIp); assignPublicIp != awsecs.AssignPublicIpDisabled {
+				t.Errorf("expected public IP assignment %s, got %s", awsecs.AssignPublicIpDisabled, assignPublicIp)
+			}
+
+			environment := input.Overrides.ContainerOverrides[0].Environment
+
+			if len(environment) != 1 || aws.StringValue(environment[0].Name) != "RUN" {
+				t.Errorf("expected only the RUN environment variable override, got %v", environment)
+			}
+		},
+	).Return(runTaskOutput, nil)
+	mockECSClient.EXPECT().WaitUntilTasksRunning(gomock.Any()).Return(nil)
+	mockECSClient.EXPECT().StopTask(gomock.Any()).Return(&awsecs.StopTaskOutput{}, nil)
+
+	restarts := ecs.RestartTasks([]Task{task})
+
+	if len(restarts) != 1 || restarts[0].Err != nil {
+		t.Errorf("expected task %s to be restarted, got %v", testTaskId, restarts)
+	}
+}
+
+func TestRestartTasksWithoutSecurityGroups(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	task := Task{
+		EniId:             "eni-1",
+		StartedBy:         "fargate:web",
+		SubnetId:          "subnet-a",
+		TaskDefinitionArn: "arn:aws:ecs:us-east-1:123456789012:task-definition/restart-no-sg:1",
+		TaskId:            testTaskId,
+	}
+
+	mockECSClient.EXPECT().RunTask(gomock.Any()).Times(0)
+
+	restarts := ecs.RestartTasks([]Task{task})
+
+	if len(restarts) != 1 || restarts[0].Err == nil {
+		t.Fatalf("expected an error restarting task %s, got %v", testTaskId, restarts)
+	}
+
+	if !strings.Contains(restarts[0].Err.Error(), "security groups") {
+		t.Errorf("expected security groups error, got %v", restarts[0].Err)
+	}
+}
+
+func TestRestartTasksNotStartedByFargate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	task := Task{StartedBy: "ecs-svc/1234567890", TaskId: testTaskId}
+
+	mockECSClient.EXPECT().RunTask(gomock.Any()).Times(0)
+
+	restarts := ecs.RestartTasks([]Task{task})
+
+	if len(restarts) != 1 || restarts[0].Err == nil {
+		t.Fatalf("expected an error restarting task %s, got %v", testTaskId, restarts)
+	}
+
+	if restarts[0].NewTaskId != "" {
+		t.Errorf("expected no new task, got %s", restarts[0].NewTaskId)
+	}
+}
+
+func expectCountTasks(mockECSClient *sdk.MockECSAPI, clusterName string, taskCount int, err error) {
+	mockECSClient.EXPECT().ListTasksPages(
+		&awsecs.ListTasksInput{Cluster: aws.String(clusterName)},
+		gomock.Any(),
+	).Do(
+		func(input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			if err == nil {
+				fn(&awsecs.ListTasksOutput{TaskArns: make([]*string, taskCount)}, true)
+			}
+		},
+	).Return(err)
+}
+
+func TestRunTaskBalanced(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/balanced:1"
+	busyClient := sdk.NewMockECSAPI(mockCtrl)
+	idleClient := sdk.NewMockECSAPI(mockCtrl)
+	failingClient := sdk.NewMockECSAPI(mockCtrl)
+	clusters := []*ECS{
+		&ECS{ClusterName: "busy", svc: busyClient},
+		&ECS{ClusterName: "failing", Logger: &testLogger{}, svc: failingClient},
+		&ECS{ClusterName: "idle", svc: idleClient},
+	}
+	input := &RunTaskInput{
+		ClusterName:       "fargate",
+		Count:             1,
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: taskDefinitionArn,
+		TaskName:          "web",
+	}
+	runTaskOutput := &awsecs.RunTaskOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{TaskArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task/idle/new-task")},
+		},
+	}
+
+	expectCountTasks(busyClient, "busy", 3, nil)
+	expectCountTasks(failingClient, "failing", 0, errors.New("AccessDeniedException"))
+	expectCountTasks(idleClient, "idle", 1, nil)
+	expectTaskDefinition(idleClient, taskDefinitionArn, "web")
+	busyClient.EXPECT().RunTask(gomock.Any()).Times(0)
+	failingClient.EXPECT().RunTask(gomock.Any()).Times(0)
+	idleClient.EXPECT().RunTask(gomock.Any()).Do(
+		func(input *awsecs.RunTaskInput) {
+			if cluster := aws.StringValue(input.Cluster); cluster != "idle" {
+				t.Errorf("expected cluster idle, got %s", cluster)
+			}
+		},
+	).Return(runTaskOutput, nil)
+
+	clusterName, taskIds, err := RunTaskBalanced(clusters, input)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if clusterName != "idle" {
+		t.Errorf("expected cluster idle, got %s", clusterName)
+	}
+
+	if !reflect.DeepEqual(taskIds, []string{"new-task"}) {
+		t.Errorf("expected task IDs [new-task], got %v", taskIds)
+	}
+
+	if input.ClusterName != "fargate" {
+		t.Errorf("expected the input to be left unchanged, got cluster %s", input.ClusterName)
+	}
+}
+
+func TestRunTaskBalancedAllClustersFailing(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	firstClient := sdk.NewMockECSAPI(mockCtrl)
+	secondClient := sdk.NewMockECSAPI(mockCtrl)
+	clusters := []*ECS{
+		&ECS{ClusterName: "first", svc: firstClient},
+		&ECS{ClusterName: "second", svc: secondClient},
+	}
+
+	expectCountTasks(firstClient, "first", 0, errors.New("ClusterNotFoundException"))
+	expectCountTasks(secondClient, "second", 0, errors.New("AccessDeniedException"))
+	firstClient.EXPECT().RunTask(gomock.Any()).Times(0)
+	secondClient.EXPECT().RunTask(gomock.Any()).Times(0)
+
+	_, _, err := RunTaskBalanced(clusters, &RunTaskInput{Count: 1})
+
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+
+	for _, expected := range []string{"first: ClusterNotFoundException", "second: AccessDeniedException"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q, got %v", expected, err)
+		}
+	}
+}
+
+func TestRunTaskBalancedWithoutClusters(t *testing.T) {
+	_, _, err := RunTaskBalanced(nil, &RunTaskInput{Count: 1})
+
+	if err == nil || !strings.Contains(err.Error(), "no clusters") {
+		t.Errorf("expected no clusters error, got %v", err)
+	}
+}
+
+func TestRunTaskEntryPointRegistrationError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/entry_point_error:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Logger: &testLogger{}, svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			ContainerDefinitions: []*awsecs.ContainerDefinition{
+				&awsecs.ContainerDefinition{Name: aws.String("job")},
+			},
+			Family:            aws.String("entry_point_error"),
+			TaskDefinitionArn: aws.String(taskDefinitionArn),
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(taskDefinitionArn)},
+	).Return(describeOutput, nil)
+	mockECSClient.EXPECT().DescribeTaskDefinition(
+		&awsecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("entry_point_error")},
+	).Return(nil, errors.New("ClientException"))
+	mockECSClient.EXPECT().RegisterTaskDefinition(gomock.Any()).Return(nil, errors.New("ClientException: Too many revisions"))
+	mockECSClient.EXPECT().RunTask(gomock.Any()).Times(0)
+
+	input := &RunTaskInput{
+		ClusterName:       "fargate",
+		Count:             1,
+		EntryPoint:        []string{"/bin/sh"},
+		SubnetIds:         []string{"subnet-a"},
+		TaskDefinitionArn: taskDefinitionArn,
+		TaskName:          "job",
+	}
+
+	if _, err := ecs.runTask(input); err == nil || !strings.Contains(err.Error(), "Too many revisions") {
+		t.Errorf("expected registration error, got %v", err)
+	}
+}
+
+func expectListTasksByDesiredStatus(mockECSClient *sdk.MockECSAPI, desiredStatus string, taskArns ...string) {
+	mockECSClient.EXPECT().ListTasksPages(
+		&awsecs.ListTasksInput{
+			Cluster:       aws.String("fargate"),
+			DesiredStatus: aws.String(desiredStatus),
+			StartedBy:     aws.String("fargate:web"),
+		},
+		gomock.Any(),
+	).Do(
+		func(input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice(taskArns)}, true)
+		},
+	).Return(nil)
+}
+
+func TestFindTaskIdsByIdempotencyKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	otherTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/other-task"
+	expiredTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/expired-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	taggedTask := func(taskArn, key string, createdAt time.Time) *awsecs.Task {
+		return &awsecs.Task{
+			CreatedAt: aws.Time(createdAt),
+			TaskArn:   aws.String(taskArn),
+			Tags: []*awsecs.Tag{
+				&awsecs.Tag{Key: aws.String("fargate:idempotency-key"), Value: aws.String(key)},
+			},
+		}
+	}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			taggedTask(testTaskArn, "deploy-42", time.Now().Add(-time.Minute)),
+			taggedTask(otherTaskArn, "deploy-41", time.Now().Add(-time.Minute)),
+			taggedTask(expiredTaskArn, "deploy-42", time.Now().Add(-time.Hour)),
+		},
+	}
+
+	expectListTasksByDesiredStatus(mockECSClient, awsecs.DesiredStatusRunning, testTaskArn, otherTaskArn, expiredTaskArn)
+	expectListTasksByDesiredStatus(mockECSClient, awsecs.DesiredStatusStopped)
+	mockECSClient.EXPECT().DescribeTasks(
+		&awsecs.DescribeTasksInput{
+			Cluster: aws.String("fargate"),
+			Include: aws.StringSlice([]string{awsecs.TaskFieldTags}),
+			Tasks:   aws.StringSlice([]string{testTaskArn, otherTaskArn, expiredTaskArn}),
+		},
+	).Return(describeOutput, nil)
+
+	taskIds, err := ecs.findTaskIdsByIdempotencyKey("fargate", "web", "deploy-42")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(taskIds, []string{testTaskId}) {
+		t.Errorf("expected %v, got %v", []string{testTaskId}, taskIds)
+	}
+}
+
+func TestFindTaskIdsByIdempotencyKeyMiss(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	expectListTasksByDesiredStatus(mockECSClient, awsecs.DesiredStatusRunning)
+	expectListTasksByDesiredStatus(mockECSClient, awsecs.DesiredStatusStopped)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Times(0)
+
+	taskIds, err := ecs.findTaskIdsByIdempotencyKey("fargate", "web", "deploy-42")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(taskIds) > 0 {
+		t.Errorf("expected no tasks, got %v", taskIds)
+	}
+}
+
+func TestFindTaskIdsByIdempotencyKeyDescribeError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	expectListTasksByDesiredStatus(mockECSClient, awsecs.DesiredStatusRunning, testTaskArn)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(nil, errors.New("throttled"))
+
+	if _, err := ecs.findTaskIdsByIdempotencyKey("fargate", "web", "deploy-42"); err == nil {
+		t.Error("expected error, got none")
+	}
+}
+
+func TestRunTaskWithIdempotencyKeyReturnsExistingTasks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/idempotent:1"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				CreatedAt: aws.Time(time.Now()),
+				TaskArn:   aws.String(testTaskArn),
+				Tags: []*awsecs.Tag{
+					&awsecs.Tag{Key: aws.String("fargate:idempotency-key"), Value: aws.String("deploy-42")},
+				},
+			},
+		},
+	}
+
+	expectTaskDefinition(mockECSClient, taskDefinitionArn, "web")
+	expectListTasksByDesiredStatus(mockECSClient, awsecs.DesiredStatusRunning, testTaskArn)
+	expectListTasksByDesiredStatus(mockECSClient, awsecs.DesiredStatusStopped)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+	mockECSClient.EXPECT().RunTask(gomock.Any()).Times(0)
+
+	taskIds, err := ecs.runTask(
+		&RunTaskInput{
+			ClusterName:       "fargate",
+			Count:             1,
+			IdempotencyKey:    "deploy-42",
+			SubnetIds:         []string{"subnet-a"},
+			TaskDefinitionArn: taskDefinitionArn,
+			TaskName:          "web",
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(taskIds, []string{testTaskId}) {
+		t.Errorf("expected %v, got %v", []string{testTaskId}, taskIds)
+	}
+}
+
+func TestDescribeTasksForDeployment(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	oldTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/old-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+	listInput := &awsecs.ListTasksInput{
+		Cluster:     aws.String("fargate"),
+		LaunchType:  aws.String(awsecs.LaunchTypeFargate),
+		ServiceName: aws.String("web"),
+	}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				TaskArn:           aws.String(testTaskArn),
+				TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/web:4"),
+			},
+			&awsecs.Task{
+				TaskArn:           aws.String(oldTaskArn),
+				TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/web:3"),
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			if !reflect.DeepEqual(input, listInput) {
+				t.Errorf("expected list tasks input %v, got %v", listInput, input)
+			}
+
+			fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{testTaskArn, oldTaskArn})}, true)
+		},
+	).Return(nil)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+
+	tasks := ecs.DescribeTasksForDeployment("web", "4")
+
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+
+	if tasks[0].TaskId != testTaskId {
+		t.Errorf("expected task %s, got %s", testTaskId, tasks[0].TaskId)
+	}
+}
+
+func TestDescribeTasksForDeploymentWithoutTasks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+
+	expectListTasks(mockECSClient)
+
+	if tasks := ecs.DescribeTasksForDeployment("web", "4"); tasks == nil || len(tasks) != 0 {
+		t.Errorf("expected an empty list of tasks, got %v", tasks)
+	}
+}
+
+func TestDescribeTask(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+	input := &awsecs.DescribeTasksInput{
+		Cluster: aws.String("fargate"),
+		Include: aws.StringSlice([]string{awsecs.TaskFieldTags}),
+		Tasks:   aws.StringSlice([]string{testTaskId}),
+	}
+	output := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{TaskArn: aws.String(testTaskArn), LastStatus: aws.String("RUNNING")},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(input).Return(output, nil)
+
+	task, err := ecs.DescribeTask(testTaskArn)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if task.TaskId != testTaskId {
+		t.Errorf("expected task %s, got %s", testTaskId, task.TaskId)
+	}
+
+	if task.LastStatus != "RUNNING" {
+		t.Errorf("expected last status RUNNING, got %s", task.LastStatus)
+	}
+}
+
+func TestDescribeTaskNotFound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+	output := &awsecs.DescribeTasksOutput{
+		Failures: []*awsecs.Failure{
+			&awsecs.Failure{Arn: aws.String(testTaskArn), Reason: aws.String("MISSING")},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(output, nil)
+
+	task, err := ecs.DescribeTask(testTaskId)
+
+	if task != nil {
+		t.Errorf("expected no task, got %+v", task)
+	}
+
+	if expected := "task " + testTaskId + " not found in cluster fargate"; err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestDescribeTaskEmptyId(t *testing.T) {
+	ecs := ECS{ClusterName: "fargate"}
+
+	if _, err := ecs.DescribeTask(" "); err == nil {
+		t.Error("expected error, got none")
+	}
+}
+
+func TestListTasksByTag(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	stagingTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/staging-task"
+	untaggedTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/untagged-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				TaskArn: aws.String(testTaskArn),
+				Tags:    []*awsecs.Tag{&awsecs.Tag{Key: aws.String("env"), Value: aws.String("production")}},
+			},
+			&awsecs.Task{
+				TaskArn: aws.String(stagingTaskArn),
+				Tags:    []*awsecs.Tag{&awsecs.Tag{Key: aws.String("env"), Value: aws.String("staging")}},
+			},
+			&awsecs.Task{TaskArn: aws.String(untaggedTaskArn)},
+		},
+	}
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			if input.LaunchType != nil || input.StartedBy != nil || input.ServiceName != nil {
+				t.Errorf("expected all of the cluster's tasks to be listed, got %v", input)
+			}
+
+			fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{testTaskArn, stagingTaskArn, untaggedTaskArn})}, true)
+		},
+	).Return(nil)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+
+	tasks := ecs.ListTasksByTag("env", "production")
+
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+
+	if tasks[0].TaskId != testTaskId {
+		t.Errorf("expected task %s, got %s", testTaskId, tasks[0].TaskId)
+	}
+}
+
+func TestListTasksByTagWithoutMatches(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+	describeOutput := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				TaskArn: aws.String(testTaskArn),
+				Tags:    []*awsecs.Tag{&awsecs.Tag{Key: aws.String("team"), Value: aws.String("production")}},
+			},
+		},
+	}
+
+	expectListTasks(mockECSClient, testTaskArn)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(describeOutput, nil)
+
+	if tasks := ecs.ListTasksByTag("env", "production"); len(tasks) != 0 {
+		t.Errorf("expected no tasks, got %v", tasks)
+	}
+}
+
+func TestCreateTaskDefinitionCpuArchitecture(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	output := &awsecs.RegisterTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/task_web:1"),
+		},
+	}
+
+	mockECSClient.EXPECT().RegisterTaskDefinition(gomock.Any()).Do(
+		func(input *awsecs.RegisterTaskDefinitionInput) {
+			expected := &awsecs.RuntimePlatform{
+				CpuArchitecture:       aws.String("ARM64"),
+				OperatingSystemFamily: aws.String("LINUX"),
+			}
+
+			if !reflect.DeepEqual(input.RuntimePlatform, expected) {
+				t.Errorf("expected runtime platform %v, got %v", expected, input.RuntimePlatform)
+			}
+		},
+	).Return(output, nil)
+
+	ecs.CreateTaskDefinition(
+		&CreateTaskDefinitionInput{
+			Cpu:             "256",
+			CpuArchitecture: "ARM64",
+			Image:           "web:latest",
+			Memory:          "512",
+			Name:            "web",
+			Type:            "task",
+		},
+	)
+}
+
+func TestCreateTaskDefinitionEphemeralStorage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	output := &awsecs.RegisterTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/task_etl:1"),
+		},
+	}
+
+	mockECSClient.EXPECT().RegisterTaskDefinition(gomock.Any()).Do(
+		func(input *awsecs.RegisterTaskDefinitionInput) {
+			if input.EphemeralStorage == nil {
+				t.Fatal("expected ephemeral storage, got none")
+			}
+
+			if size := aws.Int64Value(input.EphemeralStorage.SizeInGiB); size != 50 {
+				t.Errorf("expected ephemeral storage of 50 GiB, got %d", size)
+			}
+		},
+	).Return(output, nil)
+
+	ecs.CreateTaskDefinition(
+		&CreateTaskDefinitionInput{
+			Cpu:                 "256",
+			EphemeralStorageGiB: 50,
+			Image:               "etl:latest",
+			Memory:              "512",
+			Name:                "etl",
+			Type:                "task",
+		},
+	)
+}
+
+func TestValidateEphemeralStorage(t *testing.T) {
+	for _, sizeGiB := range []int64{21, 50, 200} {
+		if err := ValidateEphemeralStorage(sizeGiB); err != nil {
+			t.Errorf("expected no error for %d GiB, got %v", sizeGiB, err)
+		}
+	}
+
+	for _, sizeGiB := range []int64{20, 201} {
+		if err := ValidateEphemeralStorage(sizeGiB); err == nil {
+			t.Errorf("expected error for %d GiB, got none", sizeGiB)
+		}
+	}
+}
+
+func TestParseSecret(t *testing.T) {
+	var tests = []struct {
+		expression string
+		expected   Secret
+	}{
+		{
+			"db_password=arn:aws:secretsmanager:us-east-1:123456789012:secret:db-AbCdEf",
+			Secret{Name: "DB_PASSWORD", ValueFrom: "arn:aws:secretsmanager:us-east-1:123456789012:secret:db-AbCdEf"},
+		},
+		{
+			"API_KEY=arn:aws:ssm:us-east-1:123456789012:parameter/prod/api-key",
+			Secret{Name: "API_KEY", ValueFrom: "arn:aws:ssm:us-east-1:123456789012:parameter/prod/api-key"},
+		},
+		{
+			"API_KEY=/prod/api-key",
+			Secret{Name: "API_KEY", ValueFrom: "/prod/api-key"},
+		},
+	}
+
+	for _, test := range tests {
+		secret, err := ParseSecret(test.expression)
+
+		if err != nil {
+			t.Errorf("expected no error for %s, got %v", test.expression, err)
+		}
+
+		if secret != test.expected {
+			t.Errorf("expected %+v, got %+v", test.expected, secret)
+		}
+	}
+
+	for _, expression := range []string{"API_KEY", "=/prod/api-key", "API_KEY=", "API_KEY=arn:aws:s3:::bucket/key"} {
+		if _, err := ParseSecret(expression); err == nil {
+			t.Errorf("expected error for %s, got none", expression)
+		}
+	}
+}
+
+func TestValidateCpuArchitecture(t *testing.T) {
+	for _, cpuArchitecture := range []string{"X86_64", "ARM64"} {
+		if err := ValidateCpuArchitecture(cpuArchitecture); err != nil {
+			t.Errorf("expected no error for %s, got %v", cpuArchitecture, err)
+		}
+	}
+
+	if err := ValidateCpuArchitecture("arm64"); err == nil {
+		t.Error("expected error for arm64, got none")
+	}
+}
+
+func TestResolveTaskDefinitionArn(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	taskDefinitionArn := "arn:aws:ecs:us-east-1:123456789012:task-definition/resolve-batch:7"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	input := &awsecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String("resolve-batch"),
+	}
+	output := &awsecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &awsecs.TaskDefinition{
+			TaskDefinitionArn: aws.String(taskDefinitionArn),
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(input).Return(output, nil)
+
+	tests := []struct {
+		taskDefinition string
+		expected       string
+	}{
+		{"resolve-batch", taskDefinitionArn},
+		{"resolve-batch:6", "resolve-batch:6"},
+		{taskDefinitionArn, taskDefinitionArn},
+	}
+
+	for _, test := range tests {
+		resolved, err := ecs.ResolveTaskDefinitionArn(test.taskDefinition)
+
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+
+		if resolved != test.expected {
+			t.Errorf("expected %s for %s, got %s", test.expected, test.taskDefinition, resolved)
+		}
+	}
+}
+
+func TestResolveTaskDefinitionArnWithoutActiveRevision(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("ClientException: Unable to describe task definition."))
+
+	if _, err := ecs.ResolveTaskDefinitionArn("retired-batch"); err == nil {
+		t.Errorf("expected error, got none")
+	}
+}
+
+type testNetworkInterfaceLister map[string]string
+
+func (l testNetworkInterfaceLister) ListTaskNetworkInterfaces() (map[string]string, error) {
+	return l, nil
+}
+
+func TestFindOrphanedEnis(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	stoppedTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/stopped-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	taskWithEni := func(taskArn, eniId string) *awsecs.Task {
+		return &awsecs.Task{
+			Attachments: []*awsecs.Attachment{
+				&awsecs.Attachment{
+					Type: aws.String("ElasticNetworkInterface"),
+					Details: []*awsecs.KeyValuePair{
+						&awsecs.KeyValuePair{Name: aws.String("networkInterfaceId"), Value: aws.String(eniId)},
+					},
+				},
+			},
+			TaskArn: aws.String(taskArn),
+		}
+	}
+	lister := testNetworkInterfaceLister{
+		"eni-available": "available",
+		"eni-live":      "in-use",
+		"eni-other":     "in-use",
+		"eni-stopped":   "in-use",
+	}
+
+	gomock.InOrder(
+		mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+			func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+				fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{testTaskArn})}, true)
+			},
+		).Return(nil),
+		mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+			&awsecs.DescribeTasksOutput{Tasks: []*awsecs.Task{taskWithEni(testTaskArn, "eni-live")}}, nil,
+		),
+		mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+			func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+				fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{stoppedTaskArn})}, true)
+			},
+		).Return(nil),
+		mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+			&awsecs.DescribeTasksOutput{Tasks: []*awsecs.Task{taskWithEni(stoppedTaskArn, "eni-stopped")}}, nil,
+		),
+	)
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("not found")).AnyTimes()
+
+	orphaned, err := ecs.FindOrphanedEnis(lister)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []OrphanedEni{
+		OrphanedEni{EniId: "eni-available", Status: "available"},
+		OrphanedEni{EniId: "eni-stopped", Status: "in-use", TaskId: "stopped-task"},
+	}
+
+	if !reflect.DeepEqual(orphaned, expected) {
+		t.Errorf("expected %+v, got %+v", expected, orphaned)
+	}
+}
+
+// testNetworkResourceFinder finds the subnets and security groups it lists,
+// recording the security group IDs it was asked for.
+type testNetworkResourceFinder struct {
+	ids                       map[string]bool
+	requestedSecurityGroupIds []string
+}
+
+func (f *testNetworkResourceFinder) FindSubnetIDs(ids []string) ([]string, error) {
+	return f.find(ids), nil
+}
+
+func (f *testNetworkResourceFinder) FindSecurityGroupIDs(ids []string) ([]string, error) {
+	f.requestedSecurityGroupIds = append(f.requestedSecurityGroupIds, ids...)
+
+	return f.find(ids), nil
+}
+
+func (f *testNetworkResourceFinder) find(ids []string) []string {
+	var found []string
+
+	for _, id := range ids {
+		if f.ids[id] {
+			found = append(found, id)
+		}
+	}
+
+	return found
+}
+
+func TestValidateTaskNetworks(t *testing.T) {
+	finder := &testNetworkResourceFinder{ids: map[string]bool{"subnet-a": true, "sg-a": true}}
+	tasks := []Task{
+		Task{SubnetId: "subnet-a", SecurityGroupIds: []string{"sg-a"}},
+		Task{SubnetId: "subnet-b", SecurityGroupIds: []string{"sg-a"}},
+		Task{SubnetId: "subnet-a", SecurityGroupIds: []string{"sg-a", "sg-b"}},
+	}
+
+	if err := ValidateTaskNetworks(tasks, finder); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for i, expected := range []bool{true, false, false} {
+		if tasks[i].NetworkValid != expected {
+			t.Errorf("expected task %d to have valid network %t, got %t", i, expected, tasks[i].NetworkValid)
+		}
+	}
+}
+
+func TestValidateTaskNetworksWithoutSecurityGroups(t *testing.T) {
+	finder := &testNetworkResourceFinder{ids: map[string]bool{"subnet-a": true}}
+	tasks := []Task{
+		Task{SubnetId: "subnet-a"},
+		Task{SubnetId: "subnet-b"},
+	}
+
+	if err := ValidateTaskNetworks(tasks, finder); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(finder.requestedSecurityGroupIds) != 0 {
+		t.Errorf("expected no security groups to be checked, got %v", finder.requestedSecurityGroupIds)
+	}
+
+	if !tasks[0].NetworkValid || tasks[1].NetworkValid {
+		t.Errorf("expected only the task in an existing subnet to be valid, got %+v", tasks)
+	}
+}
+
+func TestStreamTasks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var streamed []string
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	pages := [][]string{
+		[]string{"arn:aws:ecs:us-east-1:123456789012:task/fargate/1", "arn:aws:ecs:us-east-1:123456789012:task/fargate/2"},
+		[]string{"arn:aws:ecs:us-east-1:123456789012:task/fargate/3"},
+	}
+
+	mockECSClient.EXPECT().DescribeTaskDefinition(gomock.Any()).Return(nil, errors.New("not found")).AnyTimes()
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			if startedBy := aws.StringValue(input.StartedBy); startedBy != "fargate:web" {
+				t.Errorf("expected started by fargate:web, got %s", startedBy)
+			}
+
+			for i, page := range pages {
+				if !fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice(page)}, i == len(pages)-1) {
+					return
+				}
+			}
+		},
+	).Return(nil)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+		&awsecs.DescribeTasksOutput{
+			Tasks: []*awsecs.Task{
+				&awsecs.Task{TaskArn: aws.String(pages[0][0])},
+				&awsecs.Task{TaskArn: aws.String(pages[0][1])},
+			},
+		},
+		nil,
+	)
+
+	err := ecs.StreamTasks(context.Background(), "web", TaskFilter{}, func(task Task) bool {
+		streamed = append(streamed, task.TaskId)
+		return len(streamed) < 2
+	})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if expected := []string{"1", "2"}; !reflect.DeepEqual(streamed, expected) {
+		t.Errorf("expected %v, got %v", expected, streamed)
+	}
+}
+
+func TestStreamTasksCanceled(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancel()
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("request canceled"))
+
+	err := ecs.StreamTasks(ctx, "", TaskFilter{}, func(task Task) bool {
+		t.Errorf("expected no tasks, got %s", task.TaskId)
+		return true
+	})
+
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestStreamTasksIncludeStopped(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var desiredStatuses, streamed []string
+
+	stoppedTaskArn := "arn:aws:ecs:us-east-1:123456789012:task/fargate/stopped-task"
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", Lightweight: true, svc: mockECSClient}
+
+	mockECSClient.EXPECT().ListTasksPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(ctx interface{}, input *awsecs.ListTasksInput, fn func(*awsecs.ListTasksOutput, bool) bool) {
+			desiredStatus := aws.StringValue(input.DesiredStatus)
+			desiredStatuses = append(desiredStatuses, desiredStatus)
+
+			if desiredStatus == awsecs.DesiredStatusStopped {
+				fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{stoppedTaskArn})}, true)
+			} else {
+				fn(&awsecs.ListTasksOutput{TaskArns: aws.StringSlice([]string{testTaskArn})}, true)
+			}
+		},
+	).Return(nil).Times(2)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+		&awsecs.DescribeTasksOutput{
+			Tasks: []*awsecs.Task{&awsecs.Task{TaskArn: aws.String(testTaskArn), LastStatus: aws.String("RUNNING")}},
+		}, nil,
+	)
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(
+		&awsecs.DescribeTasksOutput{
+			Tasks: []*awsecs.Task{&awsecs.Task{TaskArn: aws.String(stoppedTaskArn), LastStatus: aws.String("STOPPED")}},
+		}, nil,
+	)
+
+	err := ecs.StreamTasks(context.Background(), "web", TaskFilter{IncludeStopped: true}, func(task Task) bool {
+		streamed = append(streamed, task.TaskId)
+		return true
+	})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if expected := []string{awsecs.DesiredStatusRunning, awsecs.DesiredStatusStopped}; !reflect.DeepEqual(desiredStatuses, expected) {
+		t.Errorf("expected tasks listed with desired statuses %v, got %v", expected, desiredStatuses)
+	}
+
+	if expected := []string{testTaskId, "stopped-task"}; !reflect.DeepEqual(streamed, expected) {
+		t.Errorf("expected %v, got %v", expected, streamed)
+	}
+}
@@ -0,0 +1,129 @@
+package ecs
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// TaskTransition is a single point in a task's lifecycle, such as the task
+// being provisioned or its container images finishing pulling.
+type TaskTransition struct {
+	At          time.Time `json:"at"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+}
+
+// ContainerState is the last reported state of a container within a task.
+// Reason is only set by ECS when a container failed to start or exited.
+type ContainerState struct {
+	ExitCode   *int64 `json:"exit_code,omitempty"`
+	LastStatus string `json:"last_status"`
+	Name       string `json:"name"`
+	Reason     string `json:"reason"`
+}
+
+// TaskTimeline is a structured view of how a task moved through its
+// lifecycle, intended to explain why a task is stuck or stopped.
+type TaskTimeline struct {
+	Connectivity   string           `json:"connectivity"`
+	ConnectivityAt *time.Time       `json:"connectivity_at,omitempty"`
+	Containers     []ContainerState `json:"containers"`
+	DesiredStatus  string           `json:"desired_status"`
+	LastStatus     string           `json:"last_status"`
+	StopCode       string           `json:"stop_code"`
+	StoppedReason  string           `json:"stopped_reason"`
+	TaskId         string           `json:"task_id"`
+	Transitions    []TaskTransition `json:"transitions"`
+}
+
+// DescribeTaskTimeline returns the state transitions recorded by ECS for a
+// single task by its ID or full ARN, ordered from oldest to newest.
+func (ecs *ECS) DescribeTaskTimeline(taskId string) (*TaskTimeline, error) {
+	taskId = taskIdFromArn(taskId)
+
+	if taskId == "" {
+		return nil, fmt.Errorf("task ID must not be empty")
+	}
+
+	resp, err := ecs.svc.DescribeTasks(
+		&awsecs.DescribeTasksInput{
+			Cluster: aws.String(ecs.ClusterName),
+			Tasks:   aws.StringSlice([]string{taskId}),
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not describe task %s: %v", taskId, err)
+	}
+
+	if len(resp.Tasks) == 0 {
+		return nil, fmt.Errorf("task %s not found in cluster %s", taskId, ecs.ClusterName)
+	}
+
+	return newTaskTimeline(resp.Tasks[0]), nil
+}
+
+func newTaskTimeline(t *awsecs.Task) *TaskTimeline {
+	timeline := &TaskTimeline{
+		Connectivity:   aws.StringValue(t.Connectivity),
+		ConnectivityAt: t.ConnectivityAt,
+		DesiredStatus:  aws.StringValue(t.DesiredStatus),
+		LastStatus:     aws.StringValue(t.LastStatus),
+		StopCode:       aws.StringValue(t.StopCode),
+		StoppedReason:  aws.StringValue(t.StoppedReason),
+		TaskId:         taskIdFromArn(aws.StringValue(t.TaskArn)),
+	}
+
+	timeline.add(t.CreatedAt, "PROVISIONING", "Task created")
+	timeline.add(t.ConnectivityAt, "PENDING", "Task network connected")
+	timeline.add(t.PullStartedAt, "PENDING", "Started pulling container images")
+	timeline.add(t.PullStoppedAt, "PENDING", "Finished pulling container images")
+	timeline.add(t.StartedAt, "RUNNING", "Task started")
+	timeline.add(t.StoppingAt, "STOPPING", "Task stopping")
+	timeline.add(t.ExecutionStoppedAt, "STOPPING", "Containers stopped")
+
+	stopped := "Task stopped"
+
+	if timeline.StoppedReason != "" {
+		stopped = timeline.StoppedReason
+	}
+
+	timeline.add(t.StoppedAt, "STOPPED", stopped)
+
+	sort.SliceStable(timeline.Transitions, func(i, j int) bool {
+		return timeline.Transitions[i].At.Before(timeline.Transitions[j].At)
+	})
+
+	for _, c := range t.Containers {
+		timeline.Containers = append(
+			timeline.Containers,
+			ContainerState{
+				ExitCode:   c.ExitCode,
+				LastStatus: aws.StringValue(c.LastStatus),
+				Name:       aws.StringValue(c.Name),
+				Reason:     aws.StringValue(c.Reason),
+			},
+		)
+	}
+
+	return timeline
+}
+
+func (t *TaskTimeline) add(at *time.Time, status, description string) {
+	if at == nil || at.IsZero() {
+		return
+	}
+
+	t.Transitions = append(
+		t.Transitions,
+		TaskTransition{
+			At:          *at,
+			Description: description,
+			Status:      status,
+		},
+	)
+}
@@ -0,0 +1,95 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/golang/mock/gomock"
+	"github.com/jpignata/fargate/ecs/mock/sdk"
+)
+
+func TestDescribeTaskTimeline(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+	createdAt := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	input := &awsecs.DescribeTasksInput{
+		Cluster: aws.String("fargate"),
+		Tasks:   aws.StringSlice([]string{testTaskId}),
+	}
+	output := &awsecs.DescribeTasksOutput{
+		Tasks: []*awsecs.Task{
+			&awsecs.Task{
+				Connectivity:   aws.String("CONNECTED"),
+				ConnectivityAt: aws.Time(createdAt.Add(10 * time.Second)),
+				Containers: []*awsecs.Container{
+					&awsecs.Container{
+						LastStatus: aws.String("STOPPED"),
+						Name:       aws.String("web"),
+						Reason:     aws.String("CannotPullContainerError: pull image manifest has been retried 5 time(s)"),
+					},
+				},
+				CreatedAt:     aws.Time(createdAt),
+				DesiredStatus: aws.String("STOPPED"),
+				LastStatus:    aws.String("STOPPED"),
+				PullStartedAt: aws.Time(createdAt.Add(15 * time.Second)),
+				StopCode:      aws.String("TaskFailedToStart"),
+				StoppedAt:     aws.Time(createdAt.Add(90 * time.Second)),
+				StoppedReason: aws.String("Task failed to start"),
+				StoppingAt:    aws.Time(createdAt.Add(80 * time.Second)),
+				TaskArn:       aws.String(testTaskArn),
+			},
+		},
+	}
+
+	mockECSClient.EXPECT().DescribeTasks(input).Return(output, nil)
+
+	timeline, err := ecs.DescribeTaskTimeline(testTaskArn)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expectedTransitions := []TaskTransition{
+		TaskTransition{At: createdAt, Status: "PROVISIONING", Description: "Task created"},
+		TaskTransition{At: createdAt.Add(10 * time.Second), Status: "PENDING", Description: "Task network connected"},
+		TaskTransition{At: createdAt.Add(15 * time.Second), Status: "PENDING", Description: "Started pulling container images"},
+		TaskTransition{At: createdAt.Add(80 * time.Second), Status: "STOPPING", Description: "Task stopping"},
+		TaskTransition{At: createdAt.Add(90 * time.Second), Status: "STOPPED", Description: "Task failed to start"},
+	}
+
+	if !reflect.DeepEqual(timeline.Transitions, expectedTransitions) {
+		t.Errorf("expected transitions %+v, got %+v", expectedTransitions, timeline.Transitions)
+	}
+
+	if timeline.TaskId != testTaskId {
+		t.Errorf("expected task ID %s, got %s", testTaskId, timeline.TaskId)
+	}
+
+	if timeline.Connectivity != "CONNECTED" || timeline.StopCode != "TaskFailedToStart" {
+		t.Errorf("expected CONNECTED/TaskFailedToStart, got %s/%s", timeline.Connectivity, timeline.StopCode)
+	}
+
+	if len(timeline.Containers) != 1 || timeline.Containers[0].Reason != aws.StringValue(output.Tasks[0].Containers[0].Reason) {
+		t.Errorf("expected container reason to be captured, got %+v", timeline.Containers)
+	}
+}
+
+func TestDescribeTaskTimelineNotFound(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockECSClient := sdk.NewMockECSAPI(mockCtrl)
+	ecs := ECS{ClusterName: "fargate", svc: mockECSClient}
+
+	mockECSClient.EXPECT().DescribeTasks(gomock.Any()).Return(&awsecs.DescribeTasksOutput{}, nil)
+
+	if _, err := ecs.DescribeTaskTimeline(testTaskId); err == nil {
+		t.Error("expected error for missing task, got nil")
+	}
+}
@@ -0,0 +1,96 @@
+package ecs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsecs "github.com/aws/aws-sdk-go/service/ecs"
+)
+
+const (
+	efsVolumeNameFormat = "efs-%s"
+	fileSystemIdPrefix  = "fs-"
+)
+
+// Volume mounts an EFS file system into a task's main container. Traffic to
+// the file system is encrypted in transit.
+type Volume struct {
+	ContainerPath string
+	FileSystemId  string
+	ReadOnly      bool
+}
+
+// ParseVolume parses a volume in the form of FILE_SYSTEM_ID:CONTAINER_PATH[:ro]
+// [e.g. fs-12345678:/mnt/data].
+func ParseVolume(expression string) (Volume, error) {
+	fields := strings.Split(expression, ":")
+
+	if len(fields) < 2 || len(fields) > 3 {
+		return Volume{}, fmt.Errorf("invalid volume %q: must be in the form of FILE_SYSTEM_ID:CONTAINER_PATH[:ro]", expression)
+	}
+
+	volume := Volume{
+		ContainerPath: fields[1],
+		FileSystemId:  fields[0],
+	}
+
+	if !strings.HasPrefix(volume.FileSystemId, fileSystemIdPrefix) {
+		return Volume{}, fmt.Errorf("invalid volume %q: %s is not an EFS file system ID [e.g. fs-12345678]", expression, volume.FileSystemId)
+	}
+
+	if !path.IsAbs(volume.ContainerPath) {
+		return Volume{}, fmt.Errorf("invalid volume %q: container path %s must be absolute", expression, volume.ContainerPath)
+	}
+
+	if len(fields) == 3 {
+		switch fields[2] {
+		case "ro":
+			volume.ReadOnly = true
+		case "rw":
+		default:
+			return Volume{}, fmt.Errorf("invalid volume %q: mode must be ro or rw", expression)
+		}
+	}
+
+	return volume, nil
+}
+
+// efsVolumes returns the task definition volumes for a set of EFS mounts, one
+// per file system, and the container mount points which reference them.
+func efsVolumes(volumes []Volume) ([]*awsecs.Volume, []*awsecs.MountPoint) {
+	var (
+		taskVolumes []*awsecs.Volume
+		mountPoints []*awsecs.MountPoint
+	)
+
+	defined := make(map[string]bool)
+
+	for _, volume := range volumes {
+		name := fmt.Sprintf(efsVolumeNameFormat, volume.FileSystemId)
+
+		if !defined[name] {
+			defined[name] = true
+			taskVolumes = append(taskVolumes,
+				&awsecs.Volume{
+					EfsVolumeConfiguration: &awsecs.EFSVolumeConfiguration{
+						FileSystemId:      aws.String(volume.FileSystemId),
+						TransitEncryption: aws.String(awsecs.EFSTransitEncryptionEnabled),
+					},
+					Name: aws.String(name),
+				},
+			)
+		}
+
+		mountPoints = append(mountPoints,
+			&awsecs.MountPoint{
+				ContainerPath: aws.String(volume.ContainerPath),
+				ReadOnly:      aws.Bool(volume.ReadOnly),
+				SourceVolume:  aws.String(name),
+			},
+		)
+	}
+
+	return taskVolumes, mountPoints
+}
@@ -0,0 +1,66 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestParseVolume(t *testing.T) {
+	var tests = []struct {
+		expression string
+		expected   Volume
+	}{
+		{"fs-12345678:/mnt/data", Volume{ContainerPath: "/mnt/data", FileSystemId: "fs-12345678"}},
+		{"fs-12345678:/mnt/data:ro", Volume{ContainerPath: "/mnt/data", FileSystemId: "fs-12345678", ReadOnly: true}},
+		{"fs-12345678:/mnt/data:rw", Volume{ContainerPath: "/mnt/data", FileSystemId: "fs-12345678"}},
+	}
+
+	for _, test := range tests {
+		volume, err := ParseVolume(test.expression)
+
+		if err != nil {
+			t.Errorf("expected no error for %s, got %v", test.expression, err)
+		}
+
+		if volume != test.expected {
+			t.Errorf("expected %+v, got %+v", test.expected, volume)
+		}
+	}
+
+	for _, expression := range []string{"fs-12345678", "vol-12345678:/mnt/data", "fs-12345678:mnt/data", "fs-12345678:/mnt/data:rx", "fs-1:/a:ro:x"} {
+		if _, err := ParseVolume(expression); err == nil {
+			t.Errorf("expected error for %s, got none", expression)
+		}
+	}
+}
+
+func TestEfsVolumes(t *testing.T) {
+	volumes, mountPoints := efsVolumes(
+		[]Volume{
+			Volume{ContainerPath: "/mnt/data", FileSystemId: "fs-12345678"},
+			Volume{ContainerPath: "/mnt/config", FileSystemId: "fs-12345678", ReadOnly: true},
+			Volume{ContainerPath: "/mnt/cache", FileSystemId: "fs-87654321"},
+		},
+	)
+
+	if len(volumes) != 2 {
+		t.Fatalf("expected a volume per file system, got %d", len(volumes))
+	}
+
+	if name := aws.StringValue(volumes[0].Name); name != "efs-fs-12345678" {
+		t.Errorf("expected volume efs-fs-12345678, got %s", name)
+	}
+
+	if encryption := aws.StringValue(volumes[0].EfsVolumeConfiguration.TransitEncryption); encryption != "ENABLED" {
+		t.Errorf("expected transit encryption ENABLED, got %s", encryption)
+	}
+
+	if len(mountPoints) != 3 {
+		t.Fatalf("expected 3 mount points, got %d", len(mountPoints))
+	}
+
+	if source := aws.StringValue(mountPoints[1].SourceVolume); source != "efs-fs-12345678" || !aws.BoolValue(mountPoints[1].ReadOnly) {
+		t.Errorf("expected read-only mount of efs-fs-12345678, got %s", mountPoints[1])
+	}
+}
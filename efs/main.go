@@ -0,0 +1,16 @@
+package efs
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/efs"
+)
+
+type EFS struct {
+	svc *efs.EFS
+}
+
+func New(sess *session.Session) EFS {
+	return EFS{
+		svc: efs.New(sess),
+	}
+}
@@ -0,0 +1,58 @@
+package efs
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsefs "github.com/aws/aws-sdk-go/service/efs"
+)
+
+// MountTarget is the network endpoint through which a file system is reached
+// from within an availability zone.
+type MountTarget struct {
+	AvailabilityZone string
+	MountTargetId    string
+	SecurityGroupIds []string
+	SubnetId         string
+	VpcId            string
+}
+
+// DescribeMountTargets returns a file system's mount targets along with the
+// security groups applied to each.
+func (efs *EFS) DescribeMountTargets(fileSystemId string) ([]MountTarget, error) {
+	var mountTargets []MountTarget
+
+	resp, err := efs.svc.DescribeMountTargets(
+		&awsefs.DescribeMountTargetsInput{
+			FileSystemId: aws.String(fileSystemId),
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not describe mount targets for file system %s: %v", fileSystemId, err)
+	}
+
+	for _, mt := range resp.MountTargets {
+		mountTarget := MountTarget{
+			AvailabilityZone: aws.StringValue(mt.AvailabilityZoneName),
+			MountTargetId:    aws.StringValue(mt.MountTargetId),
+			SubnetId:         aws.StringValue(mt.SubnetId),
+			VpcId:            aws.StringValue(mt.VpcId),
+		}
+
+		securityGroupsResp, err := efs.svc.DescribeMountTargetSecurityGroups(
+			&awsefs.DescribeMountTargetSecurityGroupsInput{
+				MountTargetId: mt.MountTargetId,
+			},
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not describe security groups for mount target %s: %v", mountTarget.MountTargetId, err)
+		}
+
+		mountTarget.SecurityGroupIds = aws.StringValueSlice(securityGroupsResp.SecurityGroups)
+		mountTargets = append(mountTargets, mountTarget)
+	}
+
+	return mountTargets, nil
+}
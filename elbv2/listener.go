@@ -322,3 +322,15 @@ func (elbv2 SDKClient) DeleteRule(ruleARN string) {
 		console.ErrorExit(err, "Could not delete ELB rule")
 	}
 }
+
+func (elbv2 SDKClient) DeleteListener(listenerARN string) {
+	_, err := elbv2.client.DeleteListener(
+		&awselbv2.DeleteListenerInput{
+			ListenerArn: aws.String(listenerARN),
+		},
+	)
+
+	if err != nil {
+		console.ErrorExit(err, "Could not delete ELB listener")
+	}
+}
@@ -1,6 +1,8 @@
 package elbv2
 
 import (
+	"fmt"
+
 	"github.com/aws/aws-sdk-go/aws"
 	awselbv2 "github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/jpignata/fargate/console"
@@ -114,6 +116,38 @@ func (elbv2 SDKClient) DescribeTargetGroups(targetGroupARNs []string) []TargetGr
 	return targetGroups
 }
 
+// FindTargetState returns the health state [e.g. healthy, draining] of a
+// target in a target group, or an empty string if it isn't registered.
+func (elbv2 SDKClient) FindTargetState(targetGroupARN, targetID string, port int64) (string, error) {
+	resp, err := elbv2.client.DescribeTargetHealth(
+		&awselbv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(targetGroupARN),
+			Targets: []*awselbv2.TargetDescription{
+				&awselbv2.TargetDescription{
+					Id:   aws.String(targetID),
+					Port: aws.Int64(port),
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("could not describe target health: %v", err)
+	}
+
+	for _, description := range resp.TargetHealthDescriptions {
+		if description.TargetHealth == nil {
+			continue
+		}
+
+		if state := aws.StringValue(description.TargetHealth.State); state != awselbv2.TargetHealthStateEnumUnused {
+			return state, nil
+		}
+	}
+
+	return "", nil
+}
+
 func (elbv2 SDKClient) describeTargetGroupByName(targetGroupName string) *awselbv2.TargetGroup {
 	resp, err := elbv2.client.DescribeTargetGroups(
 		&awselbv2.DescribeTargetGroupsInput{
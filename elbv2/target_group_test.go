@@ -84,3 +84,64 @@ func TestCreateTargetGroupError(t *testing.T) {
 		t.Errorf("expected empty ARN, got %s", arn)
 	}
 }
+
+func TestFindTargetState(t *testing.T) {
+	targetGroupARN := "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/my-targets/73e2d6bc24d8a067"
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockELBV2API := sdk.NewMockELBV2API(mockCtrl)
+	elbv2 := SDKClient{client: mockELBV2API}
+
+	i := &awselbv2.DescribeTargetHealthInput{
+		TargetGroupArn: aws.String(targetGroupARN),
+		Targets: []*awselbv2.TargetDescription{
+			&awselbv2.TargetDescription{
+				Id:   aws.String("10.0.0.1"),
+				Port: aws.Int64(80),
+			},
+		},
+	}
+	o := &awselbv2.DescribeTargetHealthOutput{
+		TargetHealthDescriptions: []*awselbv2.TargetHealthDescription{
+			&awselbv2.TargetHealthDescription{
+				TargetHealth: &awselbv2.TargetHealth{State: aws.String("draining")},
+			},
+		},
+	}
+
+	mockELBV2API.EXPECT().DescribeTargetHealth(i).Return(o, nil)
+
+	state, err := elbv2.FindTargetState(targetGroupARN, "10.0.0.1", 80)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if state != "draining" {
+		t.Errorf("expected state draining, got %s", state)
+	}
+}
+
+func TestFindTargetStateNotRegistered(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockELBV2API := sdk.NewMockELBV2API(mockCtrl)
+	elbv2 := SDKClient{client: mockELBV2API}
+
+	o := &awselbv2.DescribeTargetHealthOutput{
+		TargetHealthDescriptions: []*awselbv2.TargetHealthDescription{
+			&awselbv2.TargetHealthDescription{
+				TargetHealth: &awselbv2.TargetHealth{State: aws.String("unused")},
+			},
+		},
+	}
+
+	mockELBV2API.EXPECT().DescribeTargetHealth(gomock.Any()).Return(o, nil)
+
+	if state, _ := elbv2.FindTargetState("arn", "10.0.0.1", 80); state != "" {
+		t.Errorf("expected no state, got %s", state)
+	}
+}
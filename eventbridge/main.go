@@ -0,0 +1,16 @@
+package eventbridge
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+)
+
+type EventBridge struct {
+	svc *eventbridge.EventBridge
+}
+
+func New(sess *session.Session) EventBridge {
+	return EventBridge{
+		svc: eventbridge.New(sess),
+	}
+}
@@ -0,0 +1,165 @@
+package eventbridge
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awseventbridge "github.com/aws/aws-sdk-go/service/eventbridge"
+)
+
+const (
+	scheduledTaskRuleFormat   = "fargate-task-%s"
+	scheduledTaskTargetId     = "fargate"
+	scheduleExpressionPattern = `^(rate\(\d+ (minute|minutes|hour|hours|day|days)\)|cron\(\S+( \S+){5}\))$`
+)
+
+// ScheduledTask is an EventBridge rule which runs a task definition on a
+// schedule given as a cron or rate expression [e.g. rate(1 hour),
+// cron(0 12 * * ? *)].
+type ScheduledTask struct {
+	ClusterArn         string
+	Count              int64
+	NoPublicIp         bool
+	RoleArn            string
+	ScheduleExpression string
+	SecurityGroupIds   []string
+	SubnetIds          []string
+	TaskDefinitionArn  string
+	TaskName           string
+}
+
+// RuleName returns the name of the EventBridge rule for the scheduled task.
+func (t *ScheduledTask) RuleName() string {
+	return ScheduledTaskRuleName(t.TaskName)
+}
+
+// ScheduledTaskRuleName returns the name of the EventBridge rule which runs the
+// named task on a schedule.
+func ScheduledTaskRuleName(taskName string) string {
+	return fmt.Sprintf(scheduledTaskRuleFormat, taskName)
+}
+
+// ValidateScheduleExpression returns an error unless the expression is a cron
+// expression with six fields or a rate expression.
+func ValidateScheduleExpression(expression string) error {
+	if !regexp.MustCompile(scheduleExpressionPattern).MatchString(expression) {
+		return fmt.Errorf("invalid schedule expression %q: must be rate(value unit) or cron(minutes hours day-of-month month day-of-week year)", expression)
+	}
+
+	return nil
+}
+
+// PutScheduledTask creates the rule for a scheduled task or, if it already
+// exists, updates its schedule and target.
+func (eb *EventBridge) PutScheduledTask(t *ScheduledTask) error {
+	if err := ValidateScheduleExpression(t.ScheduleExpression); err != nil {
+		return err
+	}
+
+	_, err := eb.svc.PutRule(
+		&awseventbridge.PutRuleInput{
+			Description:        aws.String(fmt.Sprintf("Runs task %s", t.TaskName)),
+			Name:               aws.String(t.RuleName()),
+			ScheduleExpression: aws.String(t.ScheduleExpression),
+			State:              aws.String(awseventbridge.RuleStateEnabled),
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not put rule %s: %v", t.RuleName(), err)
+	}
+
+	assignPublicIp := awseventbridge.AssignPublicIpEnabled
+
+	if t.NoPublicIp {
+		assignPublicIp = awseventbridge.AssignPublicIpDisabled
+	}
+
+	resp, err := eb.svc.PutTargets(
+		&awseventbridge.PutTargetsInput{
+			Rule: aws.String(t.RuleName()),
+			Targets: []*awseventbridge.Target{
+				&awseventbridge.Target{
+					Arn: aws.String(t.ClusterArn),
+					EcsParameters: &awseventbridge.EcsParameters{
+						LaunchType: aws.String(awseventbridge.LaunchTypeFargate),
+						NetworkConfiguration: &awseventbridge.NetworkConfiguration{
+							AwsvpcConfiguration: &awseventbridge.AwsVpcConfiguration{
+								AssignPublicIp: aws.String(assignPublicIp),
+								SecurityGroups: aws.StringSlice(t.SecurityGroupIds),
+								Subnets:        aws.StringSlice(t.SubnetIds),
+							},
+						},
+						TaskCount:         aws.Int64(t.Count),
+						TaskDefinitionArn: aws.String(t.TaskDefinitionArn),
+					},
+					Id:      aws.String(scheduledTaskTargetId),
+					RoleArn: aws.String(t.RoleArn),
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not put target for rule %s: %v", t.RuleName(), err)
+	}
+
+	if len(resp.FailedEntries) > 0 {
+		return fmt.Errorf("could not put target for rule %s: %s", t.RuleName(), aws.StringValue(resp.FailedEntries[0].ErrorMessage))
+	}
+
+	return nil
+}
+
+// DeleteScheduledTask removes the rule for the named task's schedule along
+// with its targets. Tasks already started by the rule are left running.
+func (eb *EventBridge) DeleteScheduledTask(taskName string) error {
+	ruleName := ScheduledTaskRuleName(taskName)
+
+	resp, err := eb.svc.ListTargetsByRule(
+		&awseventbridge.ListTargetsByRuleInput{
+			Rule: aws.String(ruleName),
+		},
+	)
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == awseventbridge.ErrCodeResourceNotFoundException {
+			return fmt.Errorf("task %s has no schedule", taskName)
+		}
+
+		return fmt.Errorf("could not list targets for rule %s: %v", ruleName, err)
+	}
+
+	if len(resp.Targets) > 0 {
+		var ids []string
+
+		for _, target := range resp.Targets {
+			ids = append(ids, aws.StringValue(target.Id))
+		}
+
+		_, err := eb.svc.RemoveTargets(
+			&awseventbridge.RemoveTargetsInput{
+				Ids:  aws.StringSlice(ids),
+				Rule: aws.String(ruleName),
+			},
+		)
+
+		if err != nil {
+			return fmt.Errorf("could not remove targets for rule %s: %v", ruleName, err)
+		}
+	}
+
+	_, err = eb.svc.DeleteRule(
+		&awseventbridge.DeleteRuleInput{
+			Name: aws.String(ruleName),
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not delete rule %s: %v", ruleName, err)
+	}
+
+	return nil
+}
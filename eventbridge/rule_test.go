@@ -0,0 +1,36 @@
+package eventbridge
+
+import (
+	"testing"
+)
+
+func TestValidateScheduleExpression(t *testing.T) {
+	var tests = []struct {
+		expression string
+		valid      bool
+	}{
+		{"rate(1 hour)", true},
+		{"rate(5 minutes)", true},
+		{"rate(2 days)", true},
+		{"cron(0 12 * * ? *)", true},
+		{"cron(15 10 ? * MON-FRI *)", true},
+		{"rate(1 week)", false},
+		{"cron(0 12 * * ?)", false},
+		{"0 12 * * ? *", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if err := ValidateScheduleExpression(test.expression); (err == nil) != test.valid {
+			t.Errorf("expected valid %t for %q, got %v", test.valid, test.expression, err)
+		}
+	}
+}
+
+func TestScheduledTaskRuleName(t *testing.T) {
+	task := &ScheduledTask{TaskName: "nightly-report"}
+
+	if name := task.RuleName(); name != "fargate-task-nightly-report" {
+		t.Errorf("expected fargate-task-nightly-report, got %s", name)
+	}
+}
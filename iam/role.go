@@ -1,8 +1,12 @@
 package iam
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	awsiam "github.com/aws/aws-sdk-go/service/iam"
@@ -24,6 +28,45 @@ const ecsTaskExecutionRoleAssumeRolePolicyDocument = `{
   ]
 }`
 
+const ecsTaskExecutionSecretsPolicyName = "fargate-secrets"
+
+const (
+	secretsManagerStatementId = "SecretsManager"
+	ssmStatementId            = "ParameterStore"
+)
+
+const ecsEventsRoleName = "ecsEventsRole"
+const ecsEventsPolicyArn = "arn:aws:iam::aws:policy/service-role/AmazonEC2ContainerServiceEventsRole"
+const ecsEventsRoleAssumeRolePolicyDocument = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "",
+      "Effect": "Allow",
+      "Principal": {
+        "Service": "events.amazonaws.com"
+      },
+      "Action": "sts:AssumeRole"
+    }
+  ]
+}`
+
+const ecsCodeDeployRoleName = "ecsCodeDeployRole"
+const ecsCodeDeployPolicyArn = "arn:aws:iam::aws:policy/AWSCodeDeployRoleForECS"
+const ecsCodeDeployRoleAssumeRolePolicyDocument = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "",
+      "Effect": "Allow",
+      "Principal": {
+        "Service": "codedeploy.amazonaws.com"
+      },
+      "Action": "sts:AssumeRole"
+    }
+  ]
+}`
+
 func (iam *IAM) CreateEcsTaskExecutionRole() string {
 	getRoleResp, err := iam.svc.GetRole(
 		&awsiam.GetRoleInput{
@@ -63,3 +106,222 @@ func (iam *IAM) CreateEcsTaskExecutionRole() string {
 
 	return ecsTaskExecutionRoleArn
 }
+
+// CreateEcsEventsRole returns the ARN of the role which EventBridge assumes to
+// run scheduled tasks, creating it if it doesn't exist.
+func (iam *IAM) CreateEcsEventsRole() string {
+	getRoleResp, err := iam.svc.GetRole(
+		&awsiam.GetRoleInput{
+			RoleName: aws.String(ecsEventsRoleName),
+		},
+	)
+
+	if err == nil {
+		return *getRoleResp.Role.Arn
+	}
+
+	createRoleResp, err := iam.svc.CreateRole(
+		&awsiam.CreateRoleInput{
+			AssumeRolePolicyDocument: aws.String(ecsEventsRoleAssumeRolePolicyDocument),
+			RoleName:                 aws.String(ecsEventsRoleName),
+		},
+	)
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ecsEventsRoleArn := *createRoleResp.Role.Arn
+
+	_, err = iam.svc.AttachRolePolicy(
+		&awsiam.AttachRolePolicyInput{
+			RoleName:  aws.String(ecsEventsRoleName),
+			PolicyArn: aws.String(ecsEventsPolicyArn),
+		},
+	)
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return ecsEventsRoleArn
+}
+
+// CreateEcsCodeDeployRole returns the ARN of the role which CodeDeploy assumes
+// to run blue/green deployments of services, creating it if necessary.
+func (iam *IAM) CreateEcsCodeDeployRole() string {
+	getRoleResp, err := iam.svc.GetRole(
+		&awsiam.GetRoleInput{
+			RoleName: aws.String(ecsCodeDeployRoleName),
+		},
+	)
+
+	if err == nil {
+		return *getRoleResp.Role.Arn
+	}
+
+	createRoleResp, err := iam.svc.CreateRole(
+		&awsiam.CreateRoleInput{
+			AssumeRolePolicyDocument: aws.String(ecsCodeDeployRoleAssumeRolePolicyDocument),
+			RoleName:                 aws.String(ecsCodeDeployRoleName),
+		},
+	)
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ecsCodeDeployRoleArn := *createRoleResp.Role.Arn
+
+	_, err = iam.svc.AttachRolePolicy(
+		&awsiam.AttachRolePolicyInput{
+			RoleName:  aws.String(ecsCodeDeployRoleName),
+			PolicyArn: aws.String(ecsCodeDeployPolicyArn),
+		},
+	)
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return ecsCodeDeployRoleArn
+}
+
+type policyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []policyStatement `json:"Statement"`
+}
+
+type policyStatement struct {
+	Sid      string   `json:"Sid"`
+	Effect   string   `json:"Effect"`
+	Action   []string `json:"Action"`
+	Resource []string `json:"Resource"`
+}
+
+// GrantSecretAccess allows the task execution role to read the given Secrets
+// Manager secrets and SSM parameters, each given as an ARN or, for SSM
+// parameters, a name, so that ECS can inject them into containers. Access is
+// added to the role's fargate-secrets inline policy alongside any previously
+// granted.
+func (iam *IAM) GrantSecretAccess(valueFroms []string) error {
+	resources := map[string][]string{}
+
+	getRolePolicyResp, err := iam.svc.GetRolePolicy(
+		&awsiam.GetRolePolicyInput{
+			PolicyName: aws.String(ecsTaskExecutionSecretsPolicyName),
+			RoleName:   aws.String(ecsTaskExecutionRoleName),
+		},
+	)
+
+	if err == nil {
+		var existing policyDocument
+
+		document, err := url.QueryUnescape(aws.StringValue(getRolePolicyResp.PolicyDocument))
+
+		if err != nil {
+			return fmt.Errorf("could not read policy %s: %v", ecsTaskExecutionSecretsPolicyName, err)
+		}
+
+		if err := json.Unmarshal([]byte(document), &existing); err != nil {
+			return fmt.Errorf("could not read policy %s: %v", ecsTaskExecutionSecretsPolicyName, err)
+		}
+
+		for _, statement := range existing.Statement {
+			resources[statement.Sid] = append(resources[statement.Sid], statement.Resource...)
+		}
+	}
+
+	for _, valueFrom := range valueFroms {
+		sid, resource := secretResource(valueFrom)
+		resources[sid] = append(resources[sid], resource)
+	}
+
+	policy := policyDocument{Version: "2012-10-17"}
+
+	if r := uniqueSorted(resources[secretsManagerStatementId]); len(r) > 0 {
+		policy.Statement = append(policy.Statement,
+			policyStatement{
+				Sid:      secretsManagerStatementId,
+				Effect:   "Allow",
+				Action:   []string{"secretsmanager:GetSecretValue"},
+				Resource: r,
+			},
+		)
+	}
+
+	if r := uniqueSorted(resources[ssmStatementId]); len(r) > 0 {
+		policy.Statement = append(policy.Statement,
+			policyStatement{
+				Sid:      ssmStatementId,
+				Effect:   "Allow",
+				Action:   []string{"ssm:GetParameters"},
+				Resource: r,
+			},
+		)
+	}
+
+	document, err := json.Marshal(policy)
+
+	if err != nil {
+		return fmt.Errorf("could not build policy %s: %v", ecsTaskExecutionSecretsPolicyName, err)
+	}
+
+	_, err = iam.svc.PutRolePolicy(
+		&awsiam.PutRolePolicyInput{
+			PolicyDocument: aws.String(string(document)),
+			PolicyName:     aws.String(ecsTaskExecutionSecretsPolicyName),
+			RoleName:       aws.String(ecsTaskExecutionRoleName),
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("could not grant role %s access to secrets: %v", ecsTaskExecutionRoleName, err)
+	}
+
+	return nil
+}
+
+// secretResource returns the policy statement and resource which grants read
+// access to a secret's source. Secrets Manager ARNs may name a JSON key and
+// version after the secret [e.g. ...:secret:db-AbCdEf:password::], which the
+// resource leaves off, and SSM parameters given by name are matched in any
+// region of any account, as the name alone doesn't say which.
+func secretResource(valueFrom string) (string, string) {
+	if strings.HasPrefix(valueFrom, "arn:") {
+		fields := strings.Split(valueFrom, ":")
+
+		if len(fields) < 3 || fields[2] != "secretsmanager" {
+			return ssmStatementId, valueFrom
+		}
+
+		if len(fields) > 7 {
+			valueFrom = strings.Join(fields[:7], ":")
+		}
+
+		return secretsManagerStatementId, valueFrom
+	}
+
+	return ssmStatementId, "arn:aws:ssm:*:*:parameter/" + strings.TrimPrefix(valueFrom, "/")
+}
+
+func uniqueSorted(values []string) []string {
+	var unique []string
+
+	seen := make(map[string]bool)
+
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+
+	sort.Strings(unique)
+
+	return unique
+}
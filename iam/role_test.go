@@ -0,0 +1,51 @@
+package iam
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSecretResource(t *testing.T) {
+	var tests = []struct {
+		valueFrom string
+		sid       string
+		resource  string
+	}{
+		{
+			"arn:aws:secretsmanager:us-east-1:123456789012:secret:db-AbCdEf",
+			secretsManagerStatementId,
+			"arn:aws:secretsmanager:us-east-1:123456789012:secret:db-AbCdEf",
+		},
+		{
+			"arn:aws:secretsmanager:us-east-1:123456789012:secret:db-AbCdEf:password::",
+			secretsManagerStatementId,
+			"arn:aws:secretsmanager:us-east-1:123456789012:secret:db-AbCdEf",
+		},
+		{
+			"arn:aws:ssm:us-east-1:123456789012:parameter/prod/api-key",
+			ssmStatementId,
+			"arn:aws:ssm:us-east-1:123456789012:parameter/prod/api-key",
+		},
+		{
+			"/prod/api-key",
+			ssmStatementId,
+			"arn:aws:ssm:*:*:parameter/prod/api-key",
+		},
+	}
+
+	for _, test := range tests {
+		sid, resource := secretResource(test.valueFrom)
+
+		if sid != test.sid || resource != test.resource {
+			t.Errorf("expected %s %s for %s, got %s %s", test.sid, test.resource, test.valueFrom, sid, resource)
+		}
+	}
+}
+
+func TestUniqueSorted(t *testing.T) {
+	expected := []string{"a", "b", "c"}
+
+	if unique := uniqueSorted([]string{"c", "a", "b", "a"}); !reflect.DeepEqual(unique, expected) {
+		t.Errorf("expected %v, got %v", expected, unique)
+	}
+}
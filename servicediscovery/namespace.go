@@ -0,0 +1,132 @@
+package servicediscovery
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsservicediscovery "github.com/aws/aws-sdk-go/service/servicediscovery"
+)
+
+const (
+	namespaceNamePattern   = `^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)*[a-z0-9]([a-z0-9-]*[a-z0-9])?$`
+	namespaceNameMaxLength = 253
+	operationPollInterval  = 2 * time.Second
+	operationTimeout       = 5 * time.Minute
+)
+
+// ValidateNamespaceName returns an error unless the name is a valid DNS
+// domain name for a private namespace [e.g. myapp.internal].
+func ValidateNamespaceName(name string) error {
+	if len(name) > namespaceNameMaxLength || !regexp.MustCompile(namespaceNamePattern).MatchString(name) {
+		return fmt.Errorf("invalid namespace %q: must be a lowercase DNS domain name [e.g. myapp.internal]", name)
+	}
+
+	return nil
+}
+
+// CreatePrivateDnsNamespace returns the ID of the private DNS namespace with
+// the given name, creating it in the VPC if it doesn't already exist.
+func (sd *ServiceDiscovery) CreatePrivateDnsNamespace(name, vpcId string) (string, error) {
+	namespaceId, err := sd.findPrivateDnsNamespace(name)
+
+	if err != nil || namespaceId != "" {
+		return namespaceId, err
+	}
+
+	resp, err := sd.svc.CreatePrivateDnsNamespace(
+		&awsservicediscovery.CreatePrivateDnsNamespaceInput{
+			Name: aws.String(name),
+			Vpc:  aws.String(vpcId),
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("could not create namespace %s: %v", name, err)
+	}
+
+	operation, err := sd.waitForOperation(aws.StringValue(resp.OperationId))
+
+	if err != nil {
+		return "", fmt.Errorf("could not create namespace %s: %v", name, err)
+	}
+
+	return aws.StringValue(operation.Targets[awsservicediscovery.OperationTargetTypeNamespace]), nil
+}
+
+// DescribeNamespaceName returns the name of the namespace with the given ID.
+func (sd *ServiceDiscovery) DescribeNamespaceName(namespaceId string) (string, error) {
+	resp, err := sd.svc.GetNamespace(
+		&awsservicediscovery.GetNamespaceInput{
+			Id: aws.String(namespaceId),
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("could not describe namespace %s: %v", namespaceId, err)
+	}
+
+	return aws.StringValue(resp.Namespace.Name), nil
+}
+
+func (sd *ServiceDiscovery) findPrivateDnsNamespace(name string) (string, error) {
+	var namespaceId string
+
+	err := sd.svc.ListNamespacesPages(
+		&awsservicediscovery.ListNamespacesInput{
+			Filters: []*awsservicediscovery.NamespaceFilter{
+				&awsservicediscovery.NamespaceFilter{
+					Condition: aws.String(awsservicediscovery.FilterConditionEq),
+					Name:      aws.String(awsservicediscovery.NamespaceFilterNameType),
+					Values:    aws.StringSlice([]string{awsservicediscovery.NamespaceTypeDnsPrivate}),
+				},
+			},
+		},
+		func(resp *awsservicediscovery.ListNamespacesOutput, lastPage bool) bool {
+			for _, namespace := range resp.Namespaces {
+				if aws.StringValue(namespace.Name) == name {
+					namespaceId = aws.StringValue(namespace.Id)
+					return false
+				}
+			}
+
+			return true
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("could not list namespaces: %v", err)
+	}
+
+	return namespaceId, nil
+}
+
+func (sd *ServiceDiscovery) waitForOperation(operationId string) (*awsservicediscovery.Operation, error) {
+	deadline := time.Now().Add(operationTimeout)
+
+	for {
+		resp, err := sd.svc.GetOperation(
+			&awsservicediscovery.GetOperationInput{
+				OperationId: aws.String(operationId),
+			},
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch aws.StringValue(resp.Operation.Status) {
+		case awsservicediscovery.OperationStatusSuccess:
+			return resp.Operation, nil
+		case awsservicediscovery.OperationStatusFail:
+			return nil, fmt.Errorf("%s", aws.StringValue(resp.Operation.ErrorMessage))
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for operation %s", operationId)
+		}
+
+		time.Sleep(operationPollInterval)
+	}
+}
@@ -0,0 +1,28 @@
+package servicediscovery
+
+import (
+	"testing"
+)
+
+func TestValidateNamespaceName(t *testing.T) {
+	var tests = []struct {
+		name  string
+		valid bool
+	}{
+		{"myapp.internal", true},
+		{"local", true},
+		{"my-app.corp.internal", true},
+		{"MyApp.internal", false},
+		{"-myapp.internal", false},
+		{"myapp..internal", false},
+		{"myapp.internal.", false},
+		{"my_app.internal", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if err := ValidateNamespaceName(test.name); (err == nil) != test.valid {
+			t.Errorf("expected valid %t for %q, got %v", test.valid, test.name, err)
+		}
+	}
+}
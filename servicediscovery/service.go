@@ -0,0 +1,135 @@
+package servicediscovery
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsservicediscovery "github.com/aws/aws-sdk-go/service/servicediscovery"
+)
+
+const (
+	dnsRecordTTL           = 60
+	healthFailureThreshold = 1
+)
+
+// CreateService returns the ARN of the service registry with the given name in
+// the namespace, creating it if it doesn't already exist. ECS registers each
+// of a service's tasks in the registry as an A record, so that they can be
+// resolved at name.namespace [e.g. web.myapp.internal].
+func (sd *ServiceDiscovery) CreateService(namespaceId, name string) (string, error) {
+	resp, err := sd.svc.CreateService(
+		&awsservicediscovery.CreateServiceInput{
+			DnsConfig: &awsservicediscovery.DnsConfig{
+				DnsRecords: []*awsservicediscovery.DnsRecord{
+					&awsservicediscovery.DnsRecord{
+						TTL:  aws.Int64(dnsRecordTTL),
+						Type: aws.String(awsservicediscovery.RecordTypeA),
+					},
+				},
+				RoutingPolicy: aws.String(awsservicediscovery.RoutingPolicyMultivalue),
+			},
+			HealthCheckCustomConfig: &awsservicediscovery.HealthCheckCustomConfig{
+				FailureThreshold: aws.Int64(healthFailureThreshold),
+			},
+			Name:        aws.String(name),
+			NamespaceId: aws.String(namespaceId),
+		},
+	)
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == awsservicediscovery.ErrCodeServiceAlreadyExists {
+			return sd.findService(namespaceId, name)
+		}
+
+		return "", fmt.Errorf("could not create service registry %s: %v", name, err)
+	}
+
+	return aws.StringValue(resp.Service.Arn), nil
+}
+
+// Hostname returns the DNS name at which the tasks registered in the service
+// registry can be resolved [e.g. web.myapp.internal].
+func (sd *ServiceDiscovery) Hostname(serviceArn string) (string, error) {
+	resp, err := sd.svc.GetService(
+		&awsservicediscovery.GetServiceInput{
+			Id: aws.String(serviceIdFromArn(serviceArn)),
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("could not describe service registry %s: %v", serviceArn, err)
+	}
+
+	namespaceName, err := sd.DescribeNamespaceName(aws.StringValue(resp.Service.NamespaceId))
+
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s.%s", aws.StringValue(resp.Service.Name), namespaceName), nil
+}
+
+// DeleteService deletes the service registry. Registries which don't exist
+// are ignored; the namespace is left in place for other services.
+func (sd *ServiceDiscovery) DeleteService(serviceArn string) error {
+	_, err := sd.svc.DeleteService(
+		&awsservicediscovery.DeleteServiceInput{
+			Id: aws.String(serviceIdFromArn(serviceArn)),
+		},
+	)
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == awsservicediscovery.ErrCodeServiceNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("could not delete service registry %s: %v", serviceArn, err)
+	}
+
+	return nil
+}
+
+func (sd *ServiceDiscovery) findService(namespaceId, name string) (string, error) {
+	var serviceArn string
+
+	err := sd.svc.ListServicesPages(
+		&awsservicediscovery.ListServicesInput{
+			Filters: []*awsservicediscovery.ServiceFilter{
+				&awsservicediscovery.ServiceFilter{
+					Condition: aws.String(awsservicediscovery.FilterConditionEq),
+					Name:      aws.String(awsservicediscovery.ServiceFilterNameNamespaceId),
+					Values:    aws.StringSlice([]string{namespaceId}),
+				},
+			},
+		},
+		func(resp *awsservicediscovery.ListServicesOutput, lastPage bool) bool {
+			for _, service := range resp.Services {
+				if aws.StringValue(service.Name) == name {
+					serviceArn = aws.StringValue(service.Arn)
+					return false
+				}
+			}
+
+			return true
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("could not list service registries: %v", err)
+	}
+
+	if serviceArn == "" {
+		return "", fmt.Errorf("could not find service registry %s", name)
+	}
+
+	return serviceArn, nil
+}
+
+// serviceIdFromArn returns the ID from a service registry ARN [e.g.
+// arn:aws:servicediscovery:us-east-1:123456789012:service/srv-abc123 is
+// srv-abc123].
+func serviceIdFromArn(serviceArn string) string {
+	return serviceArn[strings.LastIndex(serviceArn, "/")+1:]
+}
@@ -0,0 +1,13 @@
+package servicediscovery
+
+import (
+	"testing"
+)
+
+func TestServiceIdFromArn(t *testing.T) {
+	arn := "arn:aws:servicediscovery:us-east-1:123456789012:service/srv-abc123"
+
+	if id := serviceIdFromArn(arn); id != "srv-abc123" {
+		t.Errorf("expected srv-abc123, got %s", id)
+	}
+}